@@ -76,11 +76,67 @@ func (JobStatus) EnumDescriptor() ([]byte, []int) {
 	return file_services_proto_rawDescGZIP(), []int{0}
 }
 
+type JobPriority int32
+
+const (
+	JobPriority_PRIORITY_NORMAL JobPriority = 0
+	JobPriority_PRIORITY_LOW    JobPriority = 1
+	JobPriority_PRIORITY_HIGH   JobPriority = 2
+	JobPriority_PRIORITY_URGENT JobPriority = 3
+)
+
+// Enum value maps for JobPriority.
+var (
+	JobPriority_name = map[int32]string{
+		0: "PRIORITY_NORMAL",
+		1: "PRIORITY_LOW",
+		2: "PRIORITY_HIGH",
+		3: "PRIORITY_URGENT",
+	}
+	JobPriority_value = map[string]int32{
+		"PRIORITY_NORMAL": 0,
+		"PRIORITY_LOW":    1,
+		"PRIORITY_HIGH":   2,
+		"PRIORITY_URGENT": 3,
+	}
+)
+
+func (x JobPriority) Enum() *JobPriority {
+	p := new(JobPriority)
+	*p = x
+	return p
+}
+
+func (x JobPriority) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (JobPriority) Descriptor() protoreflect.EnumDescriptor {
+	return file_services_proto_enumTypes[1].Descriptor()
+}
+
+func (JobPriority) Type() protoreflect.EnumType {
+	return &file_services_proto_enumTypes[1]
+}
+
+func (x JobPriority) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use JobPriority.Descriptor instead.
+func (JobPriority) EnumDescriptor() ([]byte, []int) {
+	return file_services_proto_rawDescGZIP(), []int{1}
+}
+
 type CreateJobRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	AoiGeojson    string                 `protobuf:"bytes,1,opt,name=aoi_geojson,json=aoiGeojson,proto3" json:"aoi_geojson,omitempty"` // GeoJSON string for the area of interest
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	AoiGeojson     string                 `protobuf:"bytes,1,opt,name=aoi_geojson,json=aoiGeojson,proto3" json:"aoi_geojson,omitempty"`             // GeoJSON string for the area of interest
+	ForceRefresh   bool                   `protobuf:"varint,2,opt,name=force_refresh,json=forceRefresh,proto3" json:"force_refresh,omitempty"`      // Skip result reuse and always run a fresh assessment
+	RiskParameters *RiskParameters        `protobuf:"bytes,3,opt,name=risk_parameters,json=riskParameters,proto3" json:"risk_parameters,omitempty"` // Optional; defaults to the README formula
+	Priority       JobPriority            `protobuf:"varint,4,opt,name=priority,proto3,enum=riskplatform.JobPriority" json:"priority,omitempty"`
+	Submitter      string                 `protobuf:"bytes,5,opt,name=submitter,proto3" json:"submitter,omitempty"` // Identifies the client for fair scheduling
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
 }
 
 func (x *CreateJobRequest) Reset() {
@@ -120,18 +176,238 @@ func (x *CreateJobRequest) GetAoiGeojson() string {
 	return ""
 }
 
-type CreateJobResponse struct {
+func (x *CreateJobRequest) GetForceRefresh() bool {
+	if x != nil {
+		return x.ForceRefresh
+	}
+	return false
+}
+
+func (x *CreateJobRequest) GetRiskParameters() *RiskParameters {
+	if x != nil {
+		return x.RiskParameters
+	}
+	return nil
+}
+
+func (x *CreateJobRequest) GetPriority() JobPriority {
+	if x != nil {
+		return x.Priority
+	}
+	return JobPriority_PRIORITY_NORMAL
+}
+
+func (x *CreateJobRequest) GetSubmitter() string {
+	if x != nil {
+		return x.Submitter
+	}
+	return ""
+}
+
+type RiskParameters struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	JobId         string                 `protobuf:"bytes,1,opt,name=job_id,json=jobId,proto3" json:"job_id,omitempty"`
-	Status        JobStatus              `protobuf:"varint,2,opt,name=status,proto3,enum=riskplatform.JobStatus" json:"status,omitempty"`
-	Message       string                 `protobuf:"bytes,3,opt,name=message,proto3" json:"message,omitempty"`
+	Weights       *RiskWeights           `protobuf:"bytes,1,opt,name=weights,proto3" json:"weights,omitempty"`
+	Thresholds    *RiskThresholds        `protobuf:"bytes,2,opt,name=thresholds,proto3" json:"thresholds,omitempty"`
+	AssetTypes    []string               `protobuf:"bytes,3,rep,name=asset_types,json=assetTypes,proto3" json:"asset_types,omitempty"` // Asset types to score; empty means all
+	Model         string                 `protobuf:"bytes,4,opt,name=model,proto3" json:"model,omitempty"`                             // Registered risk model name, e.g. "weighted-v1"
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RiskParameters) Reset() {
+	*x = RiskParameters{}
+	mi := &file_services_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RiskParameters) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RiskParameters) ProtoMessage() {}
+
+func (x *RiskParameters) ProtoReflect() protoreflect.Message {
+	mi := &file_services_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RiskParameters.ProtoReflect.Descriptor instead.
+func (*RiskParameters) Descriptor() ([]byte, []int) {
+	return file_services_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *RiskParameters) GetWeights() *RiskWeights {
+	if x != nil {
+		return x.Weights
+	}
+	return nil
+}
+
+func (x *RiskParameters) GetThresholds() *RiskThresholds {
+	if x != nil {
+		return x.Thresholds
+	}
+	return nil
+}
+
+func (x *RiskParameters) GetAssetTypes() []string {
+	if x != nil {
+		return x.AssetTypes
+	}
+	return nil
+}
+
+func (x *RiskParameters) GetModel() string {
+	if x != nil {
+		return x.Model
+	}
+	return ""
+}
+
+// Component weights, must sum to 1
+type RiskWeights struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Vegetation    float64                `protobuf:"fixed64,1,opt,name=vegetation,proto3" json:"vegetation,omitempty"`
+	Slope         float64                `protobuf:"fixed64,2,opt,name=slope,proto3" json:"slope,omitempty"`
+	Wind          float64                `protobuf:"fixed64,3,opt,name=wind,proto3" json:"wind,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RiskWeights) Reset() {
+	*x = RiskWeights{}
+	mi := &file_services_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RiskWeights) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RiskWeights) ProtoMessage() {}
+
+func (x *RiskWeights) ProtoReflect() protoreflect.Message {
+	mi := &file_services_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RiskWeights.ProtoReflect.Descriptor instead.
+func (*RiskWeights) Descriptor() ([]byte, []int) {
+	return file_services_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *RiskWeights) GetVegetation() float64 {
+	if x != nil {
+		return x.Vegetation
+	}
+	return 0
+}
+
+func (x *RiskWeights) GetSlope() float64 {
+	if x != nil {
+		return x.Slope
+	}
+	return 0
+}
+
+func (x *RiskWeights) GetWind() float64 {
+	if x != nil {
+		return x.Wind
+	}
+	return 0
+}
+
+// Lower bounds of each risk class on the 0.0-1.0 score scale
+type RiskThresholds struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Low           float64                `protobuf:"fixed64,1,opt,name=low,proto3" json:"low,omitempty"`
+	Medium        float64                `protobuf:"fixed64,2,opt,name=medium,proto3" json:"medium,omitempty"`
+	High          float64                `protobuf:"fixed64,3,opt,name=high,proto3" json:"high,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
+func (x *RiskThresholds) Reset() {
+	*x = RiskThresholds{}
+	mi := &file_services_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RiskThresholds) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RiskThresholds) ProtoMessage() {}
+
+func (x *RiskThresholds) ProtoReflect() protoreflect.Message {
+	mi := &file_services_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RiskThresholds.ProtoReflect.Descriptor instead.
+func (*RiskThresholds) Descriptor() ([]byte, []int) {
+	return file_services_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *RiskThresholds) GetLow() float64 {
+	if x != nil {
+		return x.Low
+	}
+	return 0
+}
+
+func (x *RiskThresholds) GetMedium() float64 {
+	if x != nil {
+		return x.Medium
+	}
+	return 0
+}
+
+func (x *RiskThresholds) GetHigh() float64 {
+	if x != nil {
+		return x.High
+	}
+	return 0
+}
+
+type CreateJobResponse struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	JobId           string                 `protobuf:"bytes,1,opt,name=job_id,json=jobId,proto3" json:"job_id,omitempty"`
+	Status          JobStatus              `protobuf:"varint,2,opt,name=status,proto3,enum=riskplatform.JobStatus" json:"status,omitempty"`
+	Message         string                 `protobuf:"bytes,3,opt,name=message,proto3" json:"message,omitempty"`
+	ResultsReused   bool                   `protobuf:"varint,4,opt,name=results_reused,json=resultsReused,proto3" json:"results_reused,omitempty"`          // True when the job points at cached results
+	ReusedFromJobId string                 `protobuf:"bytes,5,opt,name=reused_from_job_id,json=reusedFromJobId,proto3" json:"reused_from_job_id,omitempty"` // Job whose results were reused, if any
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
 func (x *CreateJobResponse) Reset() {
 	*x = CreateJobResponse{}
-	mi := &file_services_proto_msgTypes[1]
+	mi := &file_services_proto_msgTypes[4]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -143,7 +419,7 @@ func (x *CreateJobResponse) String() string {
 func (*CreateJobResponse) ProtoMessage() {}
 
 func (x *CreateJobResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_services_proto_msgTypes[1]
+	mi := &file_services_proto_msgTypes[4]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -156,7 +432,7 @@ func (x *CreateJobResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use CreateJobResponse.ProtoReflect.Descriptor instead.
 func (*CreateJobResponse) Descriptor() ([]byte, []int) {
-	return file_services_proto_rawDescGZIP(), []int{1}
+	return file_services_proto_rawDescGZIP(), []int{4}
 }
 
 func (x *CreateJobResponse) GetJobId() string {
@@ -180,6 +456,20 @@ func (x *CreateJobResponse) GetMessage() string {
 	return ""
 }
 
+func (x *CreateJobResponse) GetResultsReused() bool {
+	if x != nil {
+		return x.ResultsReused
+	}
+	return false
+}
+
+func (x *CreateJobResponse) GetReusedFromJobId() string {
+	if x != nil {
+		return x.ReusedFromJobId
+	}
+	return ""
+}
+
 type GetJobStatusRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	JobId         string                 `protobuf:"bytes,1,opt,name=job_id,json=jobId,proto3" json:"job_id,omitempty"`
@@ -189,7 +479,7 @@ type GetJobStatusRequest struct {
 
 func (x *GetJobStatusRequest) Reset() {
 	*x = GetJobStatusRequest{}
-	mi := &file_services_proto_msgTypes[2]
+	mi := &file_services_proto_msgTypes[5]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -201,7 +491,7 @@ func (x *GetJobStatusRequest) String() string {
 func (*GetJobStatusRequest) ProtoMessage() {}
 
 func (x *GetJobStatusRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_services_proto_msgTypes[2]
+	mi := &file_services_proto_msgTypes[5]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -214,7 +504,7 @@ func (x *GetJobStatusRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetJobStatusRequest.ProtoReflect.Descriptor instead.
 func (*GetJobStatusRequest) Descriptor() ([]byte, []int) {
-	return file_services_proto_rawDescGZIP(), []int{2}
+	return file_services_proto_rawDescGZIP(), []int{5}
 }
 
 func (x *GetJobStatusRequest) GetJobId() string {
@@ -225,103 +515,3002 @@ func (x *GetJobStatusRequest) GetJobId() string {
 }
 
 type GetJobStatusResponse struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	JobId         string                 `protobuf:"bytes,1,opt,name=job_id,json=jobId,proto3" json:"job_id,omitempty"`
-	Status        JobStatus              `protobuf:"varint,2,opt,name=status,proto3,enum=riskplatform.JobStatus" json:"status,omitempty"`
-	Message       string                 `protobuf:"bytes,3,opt,name=message,proto3" json:"message,omitempty"`
-	CreatedAt     int64                  `protobuf:"varint,4,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
-	UpdatedAt     int64                  `protobuf:"varint,5,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+	state             protoimpl.MessageState `protogen:"open.v1"`
+	JobId             string                 `protobuf:"bytes,1,opt,name=job_id,json=jobId,proto3" json:"job_id,omitempty"`
+	Status            JobStatus              `protobuf:"varint,2,opt,name=status,proto3,enum=riskplatform.JobStatus" json:"status,omitempty"`
+	Message           string                 `protobuf:"bytes,3,opt,name=message,proto3" json:"message,omitempty"`
+	CreatedAt         int64                  `protobuf:"varint,4,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	UpdatedAt         int64                  `protobuf:"varint,5,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	QueuePosition     int32                  `protobuf:"varint,6,opt,name=queue_position,json=queuePosition,proto3" json:"queue_position,omitempty"` // 1-based position among queued jobs, 0 once started
+	Priority          JobPriority            `protobuf:"varint,7,opt,name=priority,proto3,enum=riskplatform.JobPriority" json:"priority,omitempty"`
+	ChildJobsTotal    int32                  `protobuf:"varint,8,opt,name=child_jobs_total,json=childJobsTotal,proto3" json:"child_jobs_total,omitempty"` // Grid cells a large AOI was split into, 0 if not split
+	ChildJobsComplete int32                  `protobuf:"varint,9,opt,name=child_jobs_complete,json=childJobsComplete,proto3" json:"child_jobs_complete,omitempty"`
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
 }
 
 func (x *GetJobStatusResponse) Reset() {
 	*x = GetJobStatusResponse{}
-	mi := &file_services_proto_msgTypes[3]
+	mi := &file_services_proto_msgTypes[6]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetJobStatusResponse) String() string {
-	return protoimpl.X.MessageStringOf(x)
+func (x *GetJobStatusResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetJobStatusResponse) ProtoMessage() {}
+
+func (x *GetJobStatusResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_services_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetJobStatusResponse.ProtoReflect.Descriptor instead.
+func (*GetJobStatusResponse) Descriptor() ([]byte, []int) {
+	return file_services_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *GetJobStatusResponse) GetJobId() string {
+	if x != nil {
+		return x.JobId
+	}
+	return ""
+}
+
+func (x *GetJobStatusResponse) GetStatus() JobStatus {
+	if x != nil {
+		return x.Status
+	}
+	return JobStatus_PENDING
+}
+
+func (x *GetJobStatusResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *GetJobStatusResponse) GetCreatedAt() int64 {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return 0
+}
+
+func (x *GetJobStatusResponse) GetUpdatedAt() int64 {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return 0
+}
+
+func (x *GetJobStatusResponse) GetQueuePosition() int32 {
+	if x != nil {
+		return x.QueuePosition
+	}
+	return 0
+}
+
+func (x *GetJobStatusResponse) GetPriority() JobPriority {
+	if x != nil {
+		return x.Priority
+	}
+	return JobPriority_PRIORITY_NORMAL
+}
+
+func (x *GetJobStatusResponse) GetChildJobsTotal() int32 {
+	if x != nil {
+		return x.ChildJobsTotal
+	}
+	return 0
+}
+
+func (x *GetJobStatusResponse) GetChildJobsComplete() int32 {
+	if x != nil {
+		return x.ChildJobsComplete
+	}
+	return 0
+}
+
+type GetJobResultsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	JobId         string                 `protobuf:"bytes,1,opt,name=job_id,json=jobId,proto3" json:"job_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetJobResultsRequest) Reset() {
+	*x = GetJobResultsRequest{}
+	mi := &file_services_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetJobResultsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetJobResultsRequest) ProtoMessage() {}
+
+func (x *GetJobResultsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_services_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetJobResultsRequest.ProtoReflect.Descriptor instead.
+func (*GetJobResultsRequest) Descriptor() ([]byte, []int) {
+	return file_services_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *GetJobResultsRequest) GetJobId() string {
+	if x != nil {
+		return x.JobId
+	}
+	return ""
+}
+
+// Risk class counts once the job is complete, and the attribution for the
+// data behind them
+type GetJobResultsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	JobId         string                 `protobuf:"bytes,1,opt,name=job_id,json=jobId,proto3" json:"job_id,omitempty"`
+	Status        JobStatus              `protobuf:"varint,2,opt,name=status,proto3,enum=riskplatform.JobStatus" json:"status,omitempty"`
+	Message       string                 `protobuf:"bytes,3,opt,name=message,proto3" json:"message,omitempty"`
+	Summary       *JobResultSummary      `protobuf:"bytes,4,opt,name=summary,proto3" json:"summary,omitempty"` // Unset until the job is complete
+	Attribution   *Attribution           `protobuf:"bytes,5,opt,name=attribution,proto3" json:"attribution,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetJobResultsResponse) Reset() {
+	*x = GetJobResultsResponse{}
+	mi := &file_services_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetJobResultsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetJobResultsResponse) ProtoMessage() {}
+
+func (x *GetJobResultsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_services_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetJobResultsResponse.ProtoReflect.Descriptor instead.
+func (*GetJobResultsResponse) Descriptor() ([]byte, []int) {
+	return file_services_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *GetJobResultsResponse) GetJobId() string {
+	if x != nil {
+		return x.JobId
+	}
+	return ""
+}
+
+func (x *GetJobResultsResponse) GetStatus() JobStatus {
+	if x != nil {
+		return x.Status
+	}
+	return JobStatus_PENDING
+}
+
+func (x *GetJobResultsResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *GetJobResultsResponse) GetSummary() *JobResultSummary {
+	if x != nil {
+		return x.Summary
+	}
+	return nil
+}
+
+func (x *GetJobResultsResponse) GetAttribution() *Attribution {
+	if x != nil {
+		return x.Attribution
+	}
+	return nil
+}
+
+type JobResultSummary struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	TotalAssets      int32                  `protobuf:"varint,1,opt,name=total_assets,json=totalAssets,proto3" json:"total_assets,omitempty"`
+	HighRiskAssets   int32                  `protobuf:"varint,2,opt,name=high_risk_assets,json=highRiskAssets,proto3" json:"high_risk_assets,omitempty"`
+	MediumRiskAssets int32                  `protobuf:"varint,3,opt,name=medium_risk_assets,json=mediumRiskAssets,proto3" json:"medium_risk_assets,omitempty"`
+	LowRiskAssets    int32                  `protobuf:"varint,4,opt,name=low_risk_assets,json=lowRiskAssets,proto3" json:"low_risk_assets,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *JobResultSummary) Reset() {
+	*x = JobResultSummary{}
+	mi := &file_services_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *JobResultSummary) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*JobResultSummary) ProtoMessage() {}
+
+func (x *JobResultSummary) ProtoReflect() protoreflect.Message {
+	mi := &file_services_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use JobResultSummary.ProtoReflect.Descriptor instead.
+func (*JobResultSummary) Descriptor() ([]byte, []int) {
+	return file_services_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *JobResultSummary) GetTotalAssets() int32 {
+	if x != nil {
+		return x.TotalAssets
+	}
+	return 0
+}
+
+func (x *JobResultSummary) GetHighRiskAssets() int32 {
+	if x != nil {
+		return x.HighRiskAssets
+	}
+	return 0
+}
+
+func (x *JobResultSummary) GetMediumRiskAssets() int32 {
+	if x != nil {
+		return x.MediumRiskAssets
+	}
+	return 0
+}
+
+func (x *JobResultSummary) GetLowRiskAssets() int32 {
+	if x != nil {
+		return x.LowRiskAssets
+	}
+	return 0
+}
+
+type GetAssetHistoryRequest struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	CanonicalAssetId int64                  `protobuf:"varint,1,opt,name=canonical_asset_id,json=canonicalAssetId,proto3" json:"canonical_asset_id,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *GetAssetHistoryRequest) Reset() {
+	*x = GetAssetHistoryRequest{}
+	mi := &file_services_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetAssetHistoryRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetAssetHistoryRequest) ProtoMessage() {}
+
+func (x *GetAssetHistoryRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_services_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetAssetHistoryRequest.ProtoReflect.Descriptor instead.
+func (*GetAssetHistoryRequest) Descriptor() ([]byte, []int) {
+	return file_services_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *GetAssetHistoryRequest) GetCanonicalAssetId() int64 {
+	if x != nil {
+		return x.CanonicalAssetId
+	}
+	return 0
+}
+
+// An asset's registry entry, how it changed between jobs and every score it
+// was given
+type GetAssetHistoryResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Asset         *CanonicalAsset        `protobuf:"bytes,1,opt,name=asset,proto3" json:"asset,omitempty"`
+	Changes       []*AssetChange         `protobuf:"bytes,2,rep,name=changes,proto3" json:"changes,omitempty"`                            // Oldest first
+	RiskHistory   []*AssetRiskPoint      `protobuf:"bytes,3,rep,name=risk_history,json=riskHistory,proto3" json:"risk_history,omitempty"` // Oldest first
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetAssetHistoryResponse) Reset() {
+	*x = GetAssetHistoryResponse{}
+	mi := &file_services_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetAssetHistoryResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetAssetHistoryResponse) ProtoMessage() {}
+
+func (x *GetAssetHistoryResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_services_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetAssetHistoryResponse.ProtoReflect.Descriptor instead.
+func (*GetAssetHistoryResponse) Descriptor() ([]byte, []int) {
+	return file_services_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *GetAssetHistoryResponse) GetAsset() *CanonicalAsset {
+	if x != nil {
+		return x.Asset
+	}
+	return nil
+}
+
+func (x *GetAssetHistoryResponse) GetChanges() []*AssetChange {
+	if x != nil {
+		return x.Changes
+	}
+	return nil
+}
+
+func (x *GetAssetHistoryResponse) GetRiskHistory() []*AssetRiskPoint {
+	if x != nil {
+		return x.RiskHistory
+	}
+	return nil
+}
+
+// Registry entry shared by every job that saw the asset, as last seen
+type CanonicalAsset struct {
+	state                protoimpl.MessageState `protogen:"open.v1"`
+	CanonicalAssetId     int64                  `protobuf:"varint,1,opt,name=canonical_asset_id,json=canonicalAssetId,proto3" json:"canonical_asset_id,omitempty"`
+	IdentityKey          string                 `protobuf:"bytes,2,opt,name=identity_key,json=identityKey,proto3" json:"identity_key,omitempty"` // "way/123" for OSM features, a geometry hash otherwise
+	AssetType            string                 `protobuf:"bytes,3,opt,name=asset_type,json=assetType,proto3" json:"asset_type,omitempty"`
+	AssetGeometryGeojson string                 `protobuf:"bytes,4,opt,name=asset_geometry_geojson,json=assetGeometryGeojson,proto3" json:"asset_geometry_geojson,omitempty"`
+	Tags                 map[string]string      `protobuf:"bytes,5,rep,name=tags,proto3" json:"tags,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	Version              int32                  `protobuf:"varint,6,opt,name=version,proto3" json:"version,omitempty"`
+	FirstSeenJobId       string                 `protobuf:"bytes,7,opt,name=first_seen_job_id,json=firstSeenJobId,proto3" json:"first_seen_job_id,omitempty"`
+	LastSeenJobId        string                 `protobuf:"bytes,8,opt,name=last_seen_job_id,json=lastSeenJobId,proto3" json:"last_seen_job_id,omitempty"`
+	FirstSeenAt          int64                  `protobuf:"varint,9,opt,name=first_seen_at,json=firstSeenAt,proto3" json:"first_seen_at,omitempty"`
+	LastSeenAt           int64                  `protobuf:"varint,10,opt,name=last_seen_at,json=lastSeenAt,proto3" json:"last_seen_at,omitempty"`
+	unknownFields        protoimpl.UnknownFields
+	sizeCache            protoimpl.SizeCache
+}
+
+func (x *CanonicalAsset) Reset() {
+	*x = CanonicalAsset{}
+	mi := &file_services_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CanonicalAsset) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CanonicalAsset) ProtoMessage() {}
+
+func (x *CanonicalAsset) ProtoReflect() protoreflect.Message {
+	mi := &file_services_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CanonicalAsset.ProtoReflect.Descriptor instead.
+func (*CanonicalAsset) Descriptor() ([]byte, []int) {
+	return file_services_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *CanonicalAsset) GetCanonicalAssetId() int64 {
+	if x != nil {
+		return x.CanonicalAssetId
+	}
+	return 0
+}
+
+func (x *CanonicalAsset) GetIdentityKey() string {
+	if x != nil {
+		return x.IdentityKey
+	}
+	return ""
+}
+
+func (x *CanonicalAsset) GetAssetType() string {
+	if x != nil {
+		return x.AssetType
+	}
+	return ""
+}
+
+func (x *CanonicalAsset) GetAssetGeometryGeojson() string {
+	if x != nil {
+		return x.AssetGeometryGeojson
+	}
+	return ""
+}
+
+func (x *CanonicalAsset) GetTags() map[string]string {
+	if x != nil {
+		return x.Tags
+	}
+	return nil
+}
+
+func (x *CanonicalAsset) GetVersion() int32 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+func (x *CanonicalAsset) GetFirstSeenJobId() string {
+	if x != nil {
+		return x.FirstSeenJobId
+	}
+	return ""
+}
+
+func (x *CanonicalAsset) GetLastSeenJobId() string {
+	if x != nil {
+		return x.LastSeenJobId
+	}
+	return ""
+}
+
+func (x *CanonicalAsset) GetFirstSeenAt() int64 {
+	if x != nil {
+		return x.FirstSeenAt
+	}
+	return 0
+}
+
+func (x *CanonicalAsset) GetLastSeenAt() int64 {
+	if x != nil {
+		return x.LastSeenAt
+	}
+	return 0
+}
+
+type AssetChange struct {
+	state                   protoimpl.MessageState `protogen:"open.v1"`
+	JobId                   string                 `protobuf:"bytes,1,opt,name=job_id,json=jobId,proto3" json:"job_id,omitempty"`
+	ChangeType              string                 `protobuf:"bytes,2,opt,name=change_type,json=changeType,proto3" json:"change_type,omitempty"` // CREATED, GEOMETRY or TAGS
+	Version                 int32                  `protobuf:"varint,3,opt,name=version,proto3" json:"version,omitempty"`
+	PreviousGeometryGeojson string                 `protobuf:"bytes,4,opt,name=previous_geometry_geojson,json=previousGeometryGeojson,proto3" json:"previous_geometry_geojson,omitempty"` // Empty for CREATED
+	PreviousTags            map[string]string      `protobuf:"bytes,5,rep,name=previous_tags,json=previousTags,proto3" json:"previous_tags,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	ChangedTags             []string               `protobuf:"bytes,6,rep,name=changed_tags,json=changedTags,proto3" json:"changed_tags,omitempty"`
+	ChangedAt               int64                  `protobuf:"varint,7,opt,name=changed_at,json=changedAt,proto3" json:"changed_at,omitempty"`
+	unknownFields           protoimpl.UnknownFields
+	sizeCache               protoimpl.SizeCache
+}
+
+func (x *AssetChange) Reset() {
+	*x = AssetChange{}
+	mi := &file_services_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AssetChange) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AssetChange) ProtoMessage() {}
+
+func (x *AssetChange) ProtoReflect() protoreflect.Message {
+	mi := &file_services_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AssetChange.ProtoReflect.Descriptor instead.
+func (*AssetChange) Descriptor() ([]byte, []int) {
+	return file_services_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *AssetChange) GetJobId() string {
+	if x != nil {
+		return x.JobId
+	}
+	return ""
+}
+
+func (x *AssetChange) GetChangeType() string {
+	if x != nil {
+		return x.ChangeType
+	}
+	return ""
+}
+
+func (x *AssetChange) GetVersion() int32 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+func (x *AssetChange) GetPreviousGeometryGeojson() string {
+	if x != nil {
+		return x.PreviousGeometryGeojson
+	}
+	return ""
+}
+
+func (x *AssetChange) GetPreviousTags() map[string]string {
+	if x != nil {
+		return x.PreviousTags
+	}
+	return nil
+}
+
+func (x *AssetChange) GetChangedTags() []string {
+	if x != nil {
+		return x.ChangedTags
+	}
+	return nil
+}
+
+func (x *AssetChange) GetChangedAt() int64 {
+	if x != nil {
+		return x.ChangedAt
+	}
+	return 0
+}
+
+// One job's score for the asset. Child jobs are reported as their parent.
+type AssetRiskPoint struct {
+	state             protoimpl.MessageState `protogen:"open.v1"`
+	JobId             string                 `protobuf:"bytes,1,opt,name=job_id,json=jobId,proto3" json:"job_id,omitempty"`
+	OverallRiskScore  float64                `protobuf:"fixed64,2,opt,name=overall_risk_score,json=overallRiskScore,proto3" json:"overall_risk_score,omitempty"`
+	ModelName         string                 `protobuf:"bytes,3,opt,name=model_name,json=modelName,proto3" json:"model_name,omitempty"`
+	ModelVersion      string                 `protobuf:"bytes,4,opt,name=model_version,json=modelVersion,proto3" json:"model_version,omitempty"`
+	AnalysisTimestamp int64                  `protobuf:"varint,5,opt,name=analysis_timestamp,json=analysisTimestamp,proto3" json:"analysis_timestamp,omitempty"`
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
+}
+
+func (x *AssetRiskPoint) Reset() {
+	*x = AssetRiskPoint{}
+	mi := &file_services_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AssetRiskPoint) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AssetRiskPoint) ProtoMessage() {}
+
+func (x *AssetRiskPoint) ProtoReflect() protoreflect.Message {
+	mi := &file_services_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AssetRiskPoint.ProtoReflect.Descriptor instead.
+func (*AssetRiskPoint) Descriptor() ([]byte, []int) {
+	return file_services_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *AssetRiskPoint) GetJobId() string {
+	if x != nil {
+		return x.JobId
+	}
+	return ""
+}
+
+func (x *AssetRiskPoint) GetOverallRiskScore() float64 {
+	if x != nil {
+		return x.OverallRiskScore
+	}
+	return 0
+}
+
+func (x *AssetRiskPoint) GetModelName() string {
+	if x != nil {
+		return x.ModelName
+	}
+	return ""
+}
+
+func (x *AssetRiskPoint) GetModelVersion() string {
+	if x != nil {
+		return x.ModelVersion
+	}
+	return ""
+}
+
+func (x *AssetRiskPoint) GetAnalysisTimestamp() int64 {
+	if x != nil {
+		return x.AnalysisTimestamp
+	}
+	return 0
+}
+
+type GetAssetsRequest struct {
+	state                       protoimpl.MessageState `protogen:"open.v1"`
+	AoiGeojson                  string                 `protobuf:"bytes,1,opt,name=aoi_geojson,json=aoiGeojson,proto3" json:"aoi_geojson,omitempty"`
+	AssetTypes                  []string               `protobuf:"bytes,2,rep,name=asset_types,json=assetTypes,proto3" json:"asset_types,omitempty"`                                                                           // Asset catalog types to fetch; empty fetches every type
+	TagFilters                  map[string]string      `protobuf:"bytes,3,rep,name=tag_filters,json=tagFilters,proto3" json:"tag_filters,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"` // Tags every asset must carry; "*" matches any value
+	ExcludeMinorRoads           bool                   `protobuf:"varint,4,opt,name=exclude_minor_roads,json=excludeMinorRoads,proto3" json:"exclude_minor_roads,omitempty"`                                                   // Leave out tracks, paths, service roads and the like
+	IncludeSuppressionDistances bool                   `protobuf:"varint,5,opt,name=include_suppression_distances,json=includeSuppressionDistances,proto3" json:"include_suppression_distances,omitempty"`                     // Set water and fire station distances, at the cost of a second query around the AOI
+	unknownFields               protoimpl.UnknownFields
+	sizeCache                   protoimpl.SizeCache
+}
+
+func (x *GetAssetsRequest) Reset() {
+	*x = GetAssetsRequest{}
+	mi := &file_services_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetAssetsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetAssetsRequest) ProtoMessage() {}
+
+func (x *GetAssetsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_services_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetAssetsRequest.ProtoReflect.Descriptor instead.
+func (*GetAssetsRequest) Descriptor() ([]byte, []int) {
+	return file_services_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *GetAssetsRequest) GetAoiGeojson() string {
+	if x != nil {
+		return x.AoiGeojson
+	}
+	return ""
+}
+
+func (x *GetAssetsRequest) GetAssetTypes() []string {
+	if x != nil {
+		return x.AssetTypes
+	}
+	return nil
+}
+
+func (x *GetAssetsRequest) GetTagFilters() map[string]string {
+	if x != nil {
+		return x.TagFilters
+	}
+	return nil
+}
+
+func (x *GetAssetsRequest) GetExcludeMinorRoads() bool {
+	if x != nil {
+		return x.ExcludeMinorRoads
+	}
+	return false
+}
+
+func (x *GetAssetsRequest) GetIncludeSuppressionDistances() bool {
+	if x != nil {
+		return x.IncludeSuppressionDistances
+	}
+	return false
+}
+
+type Asset struct {
+	state                protoimpl.MessageState `protogen:"open.v1"`
+	AssetType            string                 `protobuf:"bytes,1,opt,name=asset_type,json=assetType,proto3" json:"asset_type,omitempty"` // "building", "road", "power_line", "fire_hydrant", ...
+	AssetGeometryGeojson string                 `protobuf:"bytes,2,opt,name=asset_geometry_geojson,json=assetGeometryGeojson,proto3" json:"asset_geometry_geojson,omitempty"`
+	Properties           map[string]string      `protobuf:"bytes,3,rep,name=properties,proto3" json:"properties,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"` // Additional OSM properties
+	WithinAoi            bool                   `protobuf:"varint,4,opt,name=within_aoi,json=withinAoi,proto3" json:"within_aoi,omitempty"`                                                           // False when the asset only intersects the AOI boundary
+	VulnerabilityWeight  float64                `protobuf:"fixed64,5,opt,name=vulnerability_weight,json=vulnerabilityWeight,proto3" json:"vulnerability_weight,omitempty"`                            // Default weight of the asset type from the asset catalog
+	// Geodesic measures derived from the geometry
+	AreaM2      float64      `protobuf:"fixed64,6,opt,name=area_m2,json=areaM2,proto3" json:"area_m2,omitempty"`    // Polygons only
+	LengthM     float64      `protobuf:"fixed64,7,opt,name=length_m,json=lengthM,proto3" json:"length_m,omitempty"` // Lines only
+	CentroidLon float64      `protobuf:"fixed64,8,opt,name=centroid_lon,json=centroidLon,proto3" json:"centroid_lon,omitempty"`
+	CentroidLat float64      `protobuf:"fixed64,9,opt,name=centroid_lat,json=centroidLat,proto3" json:"centroid_lat,omitempty"`
+	Bbox        *BoundingBox `protobuf:"bytes,10,opt,name=bbox,proto3" json:"bbox,omitempty"`
+	// Numeric OSM tags, unset when the tag is missing or unreadable
+	BuildingLevels *float64 `protobuf:"fixed64,11,opt,name=building_levels,json=buildingLevels,proto3,oneof" json:"building_levels,omitempty"`
+	HeightM        *float64 `protobuf:"fixed64,12,opt,name=height_m,json=heightM,proto3,oneof" json:"height_m,omitempty"`
+	VoltageV       *float64 `protobuf:"fixed64,13,opt,name=voltage_v,json=voltageV,proto3,oneof" json:"voltage_v,omitempty"`
+	// Distance to the nearest firefighting resource, unset when there is none
+	// within the service's search radius or include_suppression_distances was
+	// not set
+	WaterDistanceM       *float64    `protobuf:"fixed64,14,opt,name=water_distance_m,json=waterDistanceM,proto3,oneof" json:"water_distance_m,omitempty"` // Hydrant, water tank, suction point or water body
+	FireStationDistanceM *float64    `protobuf:"fixed64,15,opt,name=fire_station_distance_m,json=fireStationDistanceM,proto3,oneof" json:"fire_station_distance_m,omitempty"`
+	Provenance           *Provenance `protobuf:"bytes,16,opt,name=provenance,proto3" json:"provenance,omitempty"`
+	unknownFields        protoimpl.UnknownFields
+	sizeCache            protoimpl.SizeCache
+}
+
+func (x *Asset) Reset() {
+	*x = Asset{}
+	mi := &file_services_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Asset) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Asset) ProtoMessage() {}
+
+func (x *Asset) ProtoReflect() protoreflect.Message {
+	mi := &file_services_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Asset.ProtoReflect.Descriptor instead.
+func (*Asset) Descriptor() ([]byte, []int) {
+	return file_services_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *Asset) GetAssetType() string {
+	if x != nil {
+		return x.AssetType
+	}
+	return ""
+}
+
+func (x *Asset) GetAssetGeometryGeojson() string {
+	if x != nil {
+		return x.AssetGeometryGeojson
+	}
+	return ""
+}
+
+func (x *Asset) GetProperties() map[string]string {
+	if x != nil {
+		return x.Properties
+	}
+	return nil
+}
+
+func (x *Asset) GetWithinAoi() bool {
+	if x != nil {
+		return x.WithinAoi
+	}
+	return false
+}
+
+func (x *Asset) GetVulnerabilityWeight() float64 {
+	if x != nil {
+		return x.VulnerabilityWeight
+	}
+	return 0
+}
+
+func (x *Asset) GetAreaM2() float64 {
+	if x != nil {
+		return x.AreaM2
+	}
+	return 0
+}
+
+func (x *Asset) GetLengthM() float64 {
+	if x != nil {
+		return x.LengthM
+	}
+	return 0
+}
+
+func (x *Asset) GetCentroidLon() float64 {
+	if x != nil {
+		return x.CentroidLon
+	}
+	return 0
+}
+
+func (x *Asset) GetCentroidLat() float64 {
+	if x != nil {
+		return x.CentroidLat
+	}
+	return 0
+}
+
+func (x *Asset) GetBbox() *BoundingBox {
+	if x != nil {
+		return x.Bbox
+	}
+	return nil
+}
+
+func (x *Asset) GetBuildingLevels() float64 {
+	if x != nil && x.BuildingLevels != nil {
+		return *x.BuildingLevels
+	}
+	return 0
+}
+
+func (x *Asset) GetHeightM() float64 {
+	if x != nil && x.HeightM != nil {
+		return *x.HeightM
+	}
+	return 0
+}
+
+func (x *Asset) GetVoltageV() float64 {
+	if x != nil && x.VoltageV != nil {
+		return *x.VoltageV
+	}
+	return 0
+}
+
+func (x *Asset) GetWaterDistanceM() float64 {
+	if x != nil && x.WaterDistanceM != nil {
+		return *x.WaterDistanceM
+	}
+	return 0
+}
+
+func (x *Asset) GetFireStationDistanceM() float64 {
+	if x != nil && x.FireStationDistanceM != nil {
+		return *x.FireStationDistanceM
+	}
+	return 0
+}
+
+func (x *Asset) GetProvenance() *Provenance {
+	if x != nil {
+		return x.Provenance
+	}
+	return nil
+}
+
+// Where an asset came from: its OSM edit metadata and the source it was
+// fetched from
+type Provenance struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Version       int32                  `protobuf:"varint,1,opt,name=version,proto3" json:"version,omitempty"`
+	Timestamp     string                 `protobuf:"bytes,2,opt,name=timestamp,proto3" json:"timestamp,omitempty"` // Last edit, RFC 3339
+	Changeset     int64                  `protobuf:"varint,3,opt,name=changeset,proto3" json:"changeset,omitempty"`
+	User          string                 `protobuf:"bytes,4,opt,name=user,proto3" json:"user,omitempty"`
+	Source        string                 `protobuf:"bytes,5,opt,name=source,proto3" json:"source,omitempty"`                                    // Overpass API URL or PBF extract path
+	DataTimestamp string                 `protobuf:"bytes,6,opt,name=data_timestamp,json=dataTimestamp,proto3" json:"data_timestamp,omitempty"` // OSM data as of this time
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Provenance) Reset() {
+	*x = Provenance{}
+	mi := &file_services_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Provenance) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Provenance) ProtoMessage() {}
+
+func (x *Provenance) ProtoReflect() protoreflect.Message {
+	mi := &file_services_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Provenance.ProtoReflect.Descriptor instead.
+func (*Provenance) Descriptor() ([]byte, []int) {
+	return file_services_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *Provenance) GetVersion() int32 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+func (x *Provenance) GetTimestamp() string {
+	if x != nil {
+		return x.Timestamp
+	}
+	return ""
+}
+
+func (x *Provenance) GetChangeset() int64 {
+	if x != nil {
+		return x.Changeset
+	}
+	return 0
+}
+
+func (x *Provenance) GetUser() string {
+	if x != nil {
+		return x.User
+	}
+	return ""
+}
+
+func (x *Provenance) GetSource() string {
+	if x != nil {
+		return x.Source
+	}
+	return ""
+}
+
+func (x *Provenance) GetDataTimestamp() string {
+	if x != nil {
+		return x.DataTimestamp
+	}
+	return ""
+}
+
+// Credit the ODbL requires wherever OpenStreetMap data is used, and every
+// source behind the response
+type Attribution struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Text          string                 `protobuf:"bytes,1,opt,name=text,proto3" json:"text,omitempty"`       // "© OpenStreetMap contributors"
+	License       string                 `protobuf:"bytes,2,opt,name=license,proto3" json:"license,omitempty"` // "ODbL-1.0"
+	LicenseUrl    string                 `protobuf:"bytes,3,opt,name=license_url,json=licenseUrl,proto3" json:"license_url,omitempty"`
+	Sources       []*DataSource          `protobuf:"bytes,4,rep,name=sources,proto3" json:"sources,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Attribution) Reset() {
+	*x = Attribution{}
+	mi := &file_services_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Attribution) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Attribution) ProtoMessage() {}
+
+func (x *Attribution) ProtoReflect() protoreflect.Message {
+	mi := &file_services_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Attribution.ProtoReflect.Descriptor instead.
+func (*Attribution) Descriptor() ([]byte, []int) {
+	return file_services_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *Attribution) GetText() string {
+	if x != nil {
+		return x.Text
+	}
+	return ""
+}
+
+func (x *Attribution) GetLicense() string {
+	if x != nil {
+		return x.License
+	}
+	return ""
+}
+
+func (x *Attribution) GetLicenseUrl() string {
+	if x != nil {
+		return x.LicenseUrl
+	}
+	return ""
+}
+
+func (x *Attribution) GetSources() []*DataSource {
+	if x != nil {
+		return x.Sources
+	}
+	return nil
+}
+
+type DataSource struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Endpoint      string                 `protobuf:"bytes,1,opt,name=endpoint,proto3" json:"endpoint,omitempty"`
+	DataTimestamp string                 `protobuf:"bytes,2,opt,name=data_timestamp,json=dataTimestamp,proto3" json:"data_timestamp,omitempty"` // osm3s.timestamp_osm_base, or the extract's replication time
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DataSource) Reset() {
+	*x = DataSource{}
+	mi := &file_services_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DataSource) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DataSource) ProtoMessage() {}
+
+func (x *DataSource) ProtoReflect() protoreflect.Message {
+	mi := &file_services_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DataSource.ProtoReflect.Descriptor instead.
+func (*DataSource) Descriptor() ([]byte, []int) {
+	return file_services_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *DataSource) GetEndpoint() string {
+	if x != nil {
+		return x.Endpoint
+	}
+	return ""
+}
+
+func (x *DataSource) GetDataTimestamp() string {
+	if x != nil {
+		return x.DataTimestamp
+	}
+	return ""
+}
+
+type GetAssetsResponse struct {
+	state               protoimpl.MessageState `protogen:"open.v1"`
+	Assets              []*Asset               `protobuf:"bytes,1,rep,name=assets,proto3" json:"assets,omitempty"`
+	TotalCount          int32                  `protobuf:"varint,2,opt,name=total_count,json=totalCount,proto3" json:"total_count,omitempty"`
+	Coverage            *Coverage              `protobuf:"bytes,3,opt,name=coverage,proto3" json:"coverage,omitempty"`
+	Attribution         *Attribution           `protobuf:"bytes,4,opt,name=attribution,proto3" json:"attribution,omitempty"`
+	SuppressionCoverage *Coverage              `protobuf:"bytes,5,opt,name=suppression_coverage,json=suppressionCoverage,proto3" json:"suppression_coverage,omitempty"` // Set when suppression distances were requested
+	unknownFields       protoimpl.UnknownFields
+	sizeCache           protoimpl.SizeCache
+}
+
+func (x *GetAssetsResponse) Reset() {
+	*x = GetAssetsResponse{}
+	mi := &file_services_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetAssetsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetAssetsResponse) ProtoMessage() {}
+
+func (x *GetAssetsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_services_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetAssetsResponse.ProtoReflect.Descriptor instead.
+func (*GetAssetsResponse) Descriptor() ([]byte, []int) {
+	return file_services_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *GetAssetsResponse) GetAssets() []*Asset {
+	if x != nil {
+		return x.Assets
+	}
+	return nil
+}
+
+func (x *GetAssetsResponse) GetTotalCount() int32 {
+	if x != nil {
+		return x.TotalCount
+	}
+	return 0
+}
+
+func (x *GetAssetsResponse) GetCoverage() *Coverage {
+	if x != nil {
+		return x.Coverage
+	}
+	return nil
+}
+
+func (x *GetAssetsResponse) GetAttribution() *Attribution {
+	if x != nil {
+		return x.Attribution
+	}
+	return nil
+}
+
+func (x *GetAssetsResponse) GetSuppressionCoverage() *Coverage {
+	if x != nil {
+		return x.SuppressionCoverage
+	}
+	return nil
+}
+
+// StreamAssetsInAOI sends batches of assets as they are decoded, then a
+// single summary as the last message
+type StreamAssetsResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Types that are valid to be assigned to Payload:
+	//
+	//	*StreamAssetsResponse_Batch
+	//	*StreamAssetsResponse_Summary
+	Payload       isStreamAssetsResponse_Payload `protobuf_oneof:"payload"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StreamAssetsResponse) Reset() {
+	*x = StreamAssetsResponse{}
+	mi := &file_services_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StreamAssetsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StreamAssetsResponse) ProtoMessage() {}
+
+func (x *StreamAssetsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_services_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StreamAssetsResponse.ProtoReflect.Descriptor instead.
+func (*StreamAssetsResponse) Descriptor() ([]byte, []int) {
+	return file_services_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *StreamAssetsResponse) GetPayload() isStreamAssetsResponse_Payload {
+	if x != nil {
+		return x.Payload
+	}
+	return nil
+}
+
+func (x *StreamAssetsResponse) GetBatch() *AssetBatch {
+	if x != nil {
+		if x, ok := x.Payload.(*StreamAssetsResponse_Batch); ok {
+			return x.Batch
+		}
+	}
+	return nil
+}
+
+func (x *StreamAssetsResponse) GetSummary() *AssetSummary {
+	if x != nil {
+		if x, ok := x.Payload.(*StreamAssetsResponse_Summary); ok {
+			return x.Summary
+		}
+	}
+	return nil
+}
+
+type isStreamAssetsResponse_Payload interface {
+	isStreamAssetsResponse_Payload()
+}
+
+type StreamAssetsResponse_Batch struct {
+	Batch *AssetBatch `protobuf:"bytes,1,opt,name=batch,proto3,oneof"`
+}
+
+type StreamAssetsResponse_Summary struct {
+	Summary *AssetSummary `protobuf:"bytes,2,opt,name=summary,proto3,oneof"`
+}
+
+func (*StreamAssetsResponse_Batch) isStreamAssetsResponse_Payload() {}
+
+func (*StreamAssetsResponse_Summary) isStreamAssetsResponse_Payload() {}
+
+type AssetBatch struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Assets        []*Asset               `protobuf:"bytes,1,rep,name=assets,proto3" json:"assets,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AssetBatch) Reset() {
+	*x = AssetBatch{}
+	mi := &file_services_proto_msgTypes[22]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AssetBatch) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AssetBatch) ProtoMessage() {}
+
+func (x *AssetBatch) ProtoReflect() protoreflect.Message {
+	mi := &file_services_proto_msgTypes[22]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AssetBatch.ProtoReflect.Descriptor instead.
+func (*AssetBatch) Descriptor() ([]byte, []int) {
+	return file_services_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *AssetBatch) GetAssets() []*Asset {
+	if x != nil {
+		return x.Assets
+	}
+	return nil
+}
+
+type AssetSummary struct {
+	state               protoimpl.MessageState `protogen:"open.v1"`
+	TotalCount          int32                  `protobuf:"varint,1,opt,name=total_count,json=totalCount,proto3" json:"total_count,omitempty"`
+	CountsByType        map[string]int32       `protobuf:"bytes,2,rep,name=counts_by_type,json=countsByType,proto3" json:"counts_by_type,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"varint,2,opt,name=value"`
+	Coverage            *Coverage              `protobuf:"bytes,3,opt,name=coverage,proto3" json:"coverage,omitempty"`
+	Attribution         *Attribution           `protobuf:"bytes,4,opt,name=attribution,proto3" json:"attribution,omitempty"`
+	SuppressionCoverage *Coverage              `protobuf:"bytes,5,opt,name=suppression_coverage,json=suppressionCoverage,proto3" json:"suppression_coverage,omitempty"` // Set when suppression distances were requested
+	unknownFields       protoimpl.UnknownFields
+	sizeCache           protoimpl.SizeCache
+}
+
+func (x *AssetSummary) Reset() {
+	*x = AssetSummary{}
+	mi := &file_services_proto_msgTypes[23]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AssetSummary) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AssetSummary) ProtoMessage() {}
+
+func (x *AssetSummary) ProtoReflect() protoreflect.Message {
+	mi := &file_services_proto_msgTypes[23]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AssetSummary.ProtoReflect.Descriptor instead.
+func (*AssetSummary) Descriptor() ([]byte, []int) {
+	return file_services_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *AssetSummary) GetTotalCount() int32 {
+	if x != nil {
+		return x.TotalCount
+	}
+	return 0
+}
+
+func (x *AssetSummary) GetCountsByType() map[string]int32 {
+	if x != nil {
+		return x.CountsByType
+	}
+	return nil
+}
+
+func (x *AssetSummary) GetCoverage() *Coverage {
+	if x != nil {
+		return x.Coverage
+	}
+	return nil
+}
+
+func (x *AssetSummary) GetAttribution() *Attribution {
+	if x != nil {
+		return x.Attribution
+	}
+	return nil
+}
+
+func (x *AssetSummary) GetSuppressionCoverage() *Coverage {
+	if x != nil {
+		return x.SuppressionCoverage
+	}
+	return nil
+}
+
+// How much of the AOI was actually fetched. Large AOIs are queried in tiles;
+// tiles that fail are reported here instead of failing the whole request.
+type Coverage struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	TilesTotal    int32                  `protobuf:"varint,1,opt,name=tiles_total,json=tilesTotal,proto3" json:"tiles_total,omitempty"`
+	TilesFailed   int32                  `protobuf:"varint,2,opt,name=tiles_failed,json=tilesFailed,proto3" json:"tiles_failed,omitempty"`
+	Partial       bool                   `protobuf:"varint,3,opt,name=partial,proto3" json:"partial,omitempty"`
+	FailedTiles   []*BoundingBox         `protobuf:"bytes,4,rep,name=failed_tiles,json=failedTiles,proto3" json:"failed_tiles,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Coverage) Reset() {
+	*x = Coverage{}
+	mi := &file_services_proto_msgTypes[24]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Coverage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Coverage) ProtoMessage() {}
+
+func (x *Coverage) ProtoReflect() protoreflect.Message {
+	mi := &file_services_proto_msgTypes[24]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Coverage.ProtoReflect.Descriptor instead.
+func (*Coverage) Descriptor() ([]byte, []int) {
+	return file_services_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *Coverage) GetTilesTotal() int32 {
+	if x != nil {
+		return x.TilesTotal
+	}
+	return 0
+}
+
+func (x *Coverage) GetTilesFailed() int32 {
+	if x != nil {
+		return x.TilesFailed
+	}
+	return 0
+}
+
+func (x *Coverage) GetPartial() bool {
+	if x != nil {
+		return x.Partial
+	}
+	return false
+}
+
+func (x *Coverage) GetFailedTiles() []*BoundingBox {
+	if x != nil {
+		return x.FailedTiles
+	}
+	return nil
+}
+
+type BoundingBox struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	MinLon        float64                `protobuf:"fixed64,1,opt,name=min_lon,json=minLon,proto3" json:"min_lon,omitempty"`
+	MinLat        float64                `protobuf:"fixed64,2,opt,name=min_lat,json=minLat,proto3" json:"min_lat,omitempty"`
+	MaxLon        float64                `protobuf:"fixed64,3,opt,name=max_lon,json=maxLon,proto3" json:"max_lon,omitempty"`
+	MaxLat        float64                `protobuf:"fixed64,4,opt,name=max_lat,json=maxLat,proto3" json:"max_lat,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BoundingBox) Reset() {
+	*x = BoundingBox{}
+	mi := &file_services_proto_msgTypes[25]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BoundingBox) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BoundingBox) ProtoMessage() {}
+
+func (x *BoundingBox) ProtoReflect() protoreflect.Message {
+	mi := &file_services_proto_msgTypes[25]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BoundingBox.ProtoReflect.Descriptor instead.
+func (*BoundingBox) Descriptor() ([]byte, []int) {
+	return file_services_proto_rawDescGZIP(), []int{25}
+}
+
+func (x *BoundingBox) GetMinLon() float64 {
+	if x != nil {
+		return x.MinLon
+	}
+	return 0
+}
+
+func (x *BoundingBox) GetMinLat() float64 {
+	if x != nil {
+		return x.MinLat
+	}
+	return 0
+}
+
+func (x *BoundingBox) GetMaxLon() float64 {
+	if x != nil {
+		return x.MaxLon
+	}
+	return 0
+}
+
+func (x *BoundingBox) GetMaxLat() float64 {
+	if x != nil {
+		return x.MaxLat
+	}
+	return 0
+}
+
+// Drops cached Overpass responses for tiles overlapping the region, e.g. after
+// a known OSM edit. An empty region_geojson clears the whole cache.
+type InvalidateCacheRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	RegionGeojson string                 `protobuf:"bytes,1,opt,name=region_geojson,json=regionGeojson,proto3" json:"region_geojson,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *InvalidateCacheRequest) Reset() {
+	*x = InvalidateCacheRequest{}
+	mi := &file_services_proto_msgTypes[26]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *InvalidateCacheRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*InvalidateCacheRequest) ProtoMessage() {}
+
+func (x *InvalidateCacheRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_services_proto_msgTypes[26]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use InvalidateCacheRequest.ProtoReflect.Descriptor instead.
+func (*InvalidateCacheRequest) Descriptor() ([]byte, []int) {
+	return file_services_proto_rawDescGZIP(), []int{26}
+}
+
+func (x *InvalidateCacheRequest) GetRegionGeojson() string {
+	if x != nil {
+		return x.RegionGeojson
+	}
+	return ""
+}
+
+type InvalidateCacheResponse struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	EntriesRemoved int32                  `protobuf:"varint,1,opt,name=entries_removed,json=entriesRemoved,proto3" json:"entries_removed,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *InvalidateCacheResponse) Reset() {
+	*x = InvalidateCacheResponse{}
+	mi := &file_services_proto_msgTypes[27]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *InvalidateCacheResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*InvalidateCacheResponse) ProtoMessage() {}
+
+func (x *InvalidateCacheResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_services_proto_msgTypes[27]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use InvalidateCacheResponse.ProtoReflect.Descriptor instead.
+func (*InvalidateCacheResponse) Descriptor() ([]byte, []int) {
+	return file_services_proto_rawDescGZIP(), []int{27}
+}
+
+func (x *InvalidateCacheResponse) GetEntriesRemoved() int32 {
+	if x != nil {
+		return x.EntriesRemoved
+	}
+	return 0
+}
+
+// Landcover polygons in the AOI, grouped into fuel classes by the service's
+// fuel table
+type GetLandcoverRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	AoiGeojson    string                 `protobuf:"bytes,1,opt,name=aoi_geojson,json=aoiGeojson,proto3" json:"aoi_geojson,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetLandcoverRequest) Reset() {
+	*x = GetLandcoverRequest{}
+	mi := &file_services_proto_msgTypes[28]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetLandcoverRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetLandcoverRequest) ProtoMessage() {}
+
+func (x *GetLandcoverRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_services_proto_msgTypes[28]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetLandcoverRequest.ProtoReflect.Descriptor instead.
+func (*GetLandcoverRequest) Descriptor() ([]byte, []int) {
+	return file_services_proto_rawDescGZIP(), []int{28}
+}
+
+func (x *GetLandcoverRequest) GetAoiGeojson() string {
+	if x != nil {
+		return x.AoiGeojson
+	}
+	return ""
+}
+
+type GetLandcoverResponse struct {
+	state             protoimpl.MessageState `protogen:"open.v1"`
+	Classes           []*FuelClassShare      `protobuf:"bytes,1,rep,name=classes,proto3" json:"classes,omitempty"` // In fuel table order, most hazardous first
+	AoiAreaM2         float64                `protobuf:"fixed64,2,opt,name=aoi_area_m2,json=aoiAreaM2,proto3" json:"aoi_area_m2,omitempty"`
+	UnclassifiedShare float64                `protobuf:"fixed64,3,opt,name=unclassified_share,json=unclassifiedShare,proto3" json:"unclassified_share,omitempty"` // Share of the AOI no landcover polygon covers
+	Polygons          []*LandcoverPolygon    `protobuf:"bytes,4,rep,name=polygons,proto3" json:"polygons,omitempty"`
+	Coverage          *Coverage              `protobuf:"bytes,5,opt,name=coverage,proto3" json:"coverage,omitempty"`
+	Attribution       *Attribution           `protobuf:"bytes,6,opt,name=attribution,proto3" json:"attribution,omitempty"`
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
+}
+
+func (x *GetLandcoverResponse) Reset() {
+	*x = GetLandcoverResponse{}
+	mi := &file_services_proto_msgTypes[29]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetLandcoverResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetLandcoverResponse) ProtoMessage() {}
+
+func (x *GetLandcoverResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_services_proto_msgTypes[29]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetLandcoverResponse.ProtoReflect.Descriptor instead.
+func (*GetLandcoverResponse) Descriptor() ([]byte, []int) {
+	return file_services_proto_rawDescGZIP(), []int{29}
+}
+
+func (x *GetLandcoverResponse) GetClasses() []*FuelClassShare {
+	if x != nil {
+		return x.Classes
+	}
+	return nil
+}
+
+func (x *GetLandcoverResponse) GetAoiAreaM2() float64 {
+	if x != nil {
+		return x.AoiAreaM2
+	}
+	return 0
+}
+
+func (x *GetLandcoverResponse) GetUnclassifiedShare() float64 {
+	if x != nil {
+		return x.UnclassifiedShare
+	}
+	return 0
+}
+
+func (x *GetLandcoverResponse) GetPolygons() []*LandcoverPolygon {
+	if x != nil {
+		return x.Polygons
+	}
+	return nil
+}
+
+func (x *GetLandcoverResponse) GetCoverage() *Coverage {
+	if x != nil {
+		return x.Coverage
+	}
+	return nil
+}
+
+func (x *GetLandcoverResponse) GetAttribution() *Attribution {
+	if x != nil {
+		return x.Attribution
+	}
+	return nil
+}
+
+// Where polygons of different classes overlap, the ground counts towards the
+// most hazardous one, so shares and unclassified_share sum to 1
+type FuelClassShare struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	FuelClass     string                 `protobuf:"bytes,1,opt,name=fuel_class,json=fuelClass,proto3" json:"fuel_class,omitempty"` // e.g. "high", "moderate", "low"
+	Share         float64                `protobuf:"fixed64,2,opt,name=share,proto3" json:"share,omitempty"`                        // Fraction of the AOI, 0.0-1.0
+	AreaM2        float64                `protobuf:"fixed64,3,opt,name=area_m2,json=areaM2,proto3" json:"area_m2,omitempty"`
+	PolygonCount  int32                  `protobuf:"varint,4,opt,name=polygon_count,json=polygonCount,proto3" json:"polygon_count,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *FuelClassShare) Reset() {
+	*x = FuelClassShare{}
+	mi := &file_services_proto_msgTypes[30]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FuelClassShare) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FuelClassShare) ProtoMessage() {}
+
+func (x *FuelClassShare) ProtoReflect() protoreflect.Message {
+	mi := &file_services_proto_msgTypes[30]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FuelClassShare.ProtoReflect.Descriptor instead.
+func (*FuelClassShare) Descriptor() ([]byte, []int) {
+	return file_services_proto_rawDescGZIP(), []int{30}
+}
+
+func (x *FuelClassShare) GetFuelClass() string {
+	if x != nil {
+		return x.FuelClass
+	}
+	return ""
+}
+
+func (x *FuelClassShare) GetShare() float64 {
+	if x != nil {
+		return x.Share
+	}
+	return 0
+}
+
+func (x *FuelClassShare) GetAreaM2() float64 {
+	if x != nil {
+		return x.AreaM2
+	}
+	return 0
+}
+
+func (x *FuelClassShare) GetPolygonCount() int32 {
+	if x != nil {
+		return x.PolygonCount
+	}
+	return 0
+}
+
+type LandcoverPolygon struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	FuelClass       string                 `protobuf:"bytes,1,opt,name=fuel_class,json=fuelClass,proto3" json:"fuel_class,omitempty"`
+	GeometryGeojson string                 `protobuf:"bytes,2,opt,name=geometry_geojson,json=geometryGeojson,proto3" json:"geometry_geojson,omitempty"`
+	Properties      map[string]string      `protobuf:"bytes,3,rep,name=properties,proto3" json:"properties,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"` // OSM tags
+	WithinAoi       bool                   `protobuf:"varint,4,opt,name=within_aoi,json=withinAoi,proto3" json:"within_aoi,omitempty"`
+	AreaM2          float64                `protobuf:"fixed64,5,opt,name=area_m2,json=areaM2,proto3" json:"area_m2,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *LandcoverPolygon) Reset() {
+	*x = LandcoverPolygon{}
+	mi := &file_services_proto_msgTypes[31]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LandcoverPolygon) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LandcoverPolygon) ProtoMessage() {}
+
+func (x *LandcoverPolygon) ProtoReflect() protoreflect.Message {
+	mi := &file_services_proto_msgTypes[31]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LandcoverPolygon.ProtoReflect.Descriptor instead.
+func (*LandcoverPolygon) Descriptor() ([]byte, []int) {
+	return file_services_proto_rawDescGZIP(), []int{31}
+}
+
+func (x *LandcoverPolygon) GetFuelClass() string {
+	if x != nil {
+		return x.FuelClass
+	}
+	return ""
+}
+
+func (x *LandcoverPolygon) GetGeometryGeojson() string {
+	if x != nil {
+		return x.GeometryGeojson
+	}
+	return ""
+}
+
+func (x *LandcoverPolygon) GetProperties() map[string]string {
+	if x != nil {
+		return x.Properties
+	}
+	return nil
+}
+
+func (x *LandcoverPolygon) GetWithinAoi() bool {
+	if x != nil {
+		return x.WithinAoi
+	}
+	return false
+}
+
+func (x *LandcoverPolygon) GetAreaM2() float64 {
+	if x != nil {
+		return x.AreaM2
+	}
+	return 0
+}
+
+// Builds a routable graph from the roads in the AOI and reports where
+// evacuation is constrained
+type RoadNetworkRequest struct {
+	state      protoimpl.MessageState `protogen:"open.v1"`
+	AoiGeojson string                 `protobuf:"bytes,1,opt,name=aoi_geojson,json=aoiGeojson,proto3" json:"aoi_geojson,omitempty"`
+	// Polygon or MultiPolygon areas evacuees head for. Only roads touching the
+	// AOI are fetched, so safe zones should lie within it. When empty, leaving
+	// the AOI counts as reaching safety.
+	SafeZonesGeojson string   `protobuf:"bytes,2,opt,name=safe_zones_geojson,json=safeZonesGeojson,proto3" json:"safe_zones_geojson,omitempty"`
+	DeadEndMinM      *float64 `protobuf:"fixed64,3,opt,name=dead_end_min_m,json=deadEndMinM,proto3,oneof" json:"dead_end_min_m,omitempty"` // Overrides the service default
+	IncludeGraph     bool     `protobuf:"varint,4,opt,name=include_graph,json=includeGraph,proto3" json:"include_graph,omitempty"`         // Return every edge of the graph
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *RoadNetworkRequest) Reset() {
+	*x = RoadNetworkRequest{}
+	mi := &file_services_proto_msgTypes[32]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RoadNetworkRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RoadNetworkRequest) ProtoMessage() {}
+
+func (x *RoadNetworkRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_services_proto_msgTypes[32]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RoadNetworkRequest.ProtoReflect.Descriptor instead.
+func (*RoadNetworkRequest) Descriptor() ([]byte, []int) {
+	return file_services_proto_rawDescGZIP(), []int{32}
+}
+
+func (x *RoadNetworkRequest) GetAoiGeojson() string {
+	if x != nil {
+		return x.AoiGeojson
+	}
+	return ""
+}
+
+func (x *RoadNetworkRequest) GetSafeZonesGeojson() string {
+	if x != nil {
+		return x.SafeZonesGeojson
+	}
+	return ""
+}
+
+func (x *RoadNetworkRequest) GetDeadEndMinM() float64 {
+	if x != nil && x.DeadEndMinM != nil {
+		return *x.DeadEndMinM
+	}
+	return 0
+}
+
+func (x *RoadNetworkRequest) GetIncludeGraph() bool {
+	if x != nil {
+		return x.IncludeGraph
+	}
+	return false
+}
+
+type RoadNetworkResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	NodeCount     int32                  `protobuf:"varint,1,opt,name=node_count,json=nodeCount,proto3" json:"node_count,omitempty"`
+	EdgeCount     int32                  `protobuf:"varint,2,opt,name=edge_count,json=edgeCount,proto3" json:"edge_count,omitempty"`
+	Edges         []*RoadEdge            `protobuf:"bytes,3,rep,name=edges,proto3" json:"edges,omitempty"` // Only with include_graph
+	SingleEgress  []*EgressNeighbourhood `protobuf:"bytes,4,rep,name=single_egress,json=singleEgress,proto3" json:"single_egress,omitempty"`
+	DeadEnds      []*DeadEndRoad         `protobuf:"bytes,5,rep,name=dead_ends,json=deadEnds,proto3" json:"dead_ends,omitempty"`
+	Buildings     []*BuildingEgress      `protobuf:"bytes,6,rep,name=buildings,proto3" json:"buildings,omitempty"`
+	Coverage      *Coverage              `protobuf:"bytes,7,opt,name=coverage,proto3" json:"coverage,omitempty"`
+	Attribution   *Attribution           `protobuf:"bytes,8,opt,name=attribution,proto3" json:"attribution,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RoadNetworkResponse) Reset() {
+	*x = RoadNetworkResponse{}
+	mi := &file_services_proto_msgTypes[33]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RoadNetworkResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RoadNetworkResponse) ProtoMessage() {}
+
+func (x *RoadNetworkResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_services_proto_msgTypes[33]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RoadNetworkResponse.ProtoReflect.Descriptor instead.
+func (*RoadNetworkResponse) Descriptor() ([]byte, []int) {
+	return file_services_proto_rawDescGZIP(), []int{33}
+}
+
+func (x *RoadNetworkResponse) GetNodeCount() int32 {
+	if x != nil {
+		return x.NodeCount
+	}
+	return 0
+}
+
+func (x *RoadNetworkResponse) GetEdgeCount() int32 {
+	if x != nil {
+		return x.EdgeCount
+	}
+	return 0
+}
+
+func (x *RoadNetworkResponse) GetEdges() []*RoadEdge {
+	if x != nil {
+		return x.Edges
+	}
+	return nil
+}
+
+func (x *RoadNetworkResponse) GetSingleEgress() []*EgressNeighbourhood {
+	if x != nil {
+		return x.SingleEgress
+	}
+	return nil
+}
+
+func (x *RoadNetworkResponse) GetDeadEnds() []*DeadEndRoad {
+	if x != nil {
+		return x.DeadEnds
+	}
+	return nil
+}
+
+func (x *RoadNetworkResponse) GetBuildings() []*BuildingEgress {
+	if x != nil {
+		return x.Buildings
+	}
+	return nil
+}
+
+func (x *RoadNetworkResponse) GetCoverage() *Coverage {
+	if x != nil {
+		return x.Coverage
+	}
+	return nil
+}
+
+func (x *RoadNetworkResponse) GetAttribution() *Attribution {
+	if x != nil {
+		return x.Attribution
+	}
+	return nil
+}
+
+// A stretch of road between two graph nodes, which are OSM node IDs
+type RoadEdge struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	FromNode        int64                  `protobuf:"varint,1,opt,name=from_node,json=fromNode,proto3" json:"from_node,omitempty"`
+	ToNode          int64                  `protobuf:"varint,2,opt,name=to_node,json=toNode,proto3" json:"to_node,omitempty"`
+	WayId           int64                  `protobuf:"varint,3,opt,name=way_id,json=wayId,proto3" json:"way_id,omitempty"`
+	RoadClass       string                 `protobuf:"bytes,4,opt,name=road_class,json=roadClass,proto3" json:"road_class,omitempty"` // highway tag value
+	SpeedKph        float64                `protobuf:"fixed64,5,opt,name=speed_kph,json=speedKph,proto3" json:"speed_kph,omitempty"`  // maxspeed, or a default for the class
+	Oneway          bool                   `protobuf:"varint,6,opt,name=oneway,proto3" json:"oneway,omitempty"`                       // Only driven from from_node to to_node
+	LengthM         float64                `protobuf:"fixed64,7,opt,name=length_m,json=lengthM,proto3" json:"length_m,omitempty"`
+	GeometryGeojson string                 `protobuf:"bytes,8,opt,name=geometry_geojson,json=geometryGeojson,proto3" json:"geometry_geojson,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *RoadEdge) Reset() {
+	*x = RoadEdge{}
+	mi := &file_services_proto_msgTypes[34]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RoadEdge) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RoadEdge) ProtoMessage() {}
+
+func (x *RoadEdge) ProtoReflect() protoreflect.Message {
+	mi := &file_services_proto_msgTypes[34]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RoadEdge.ProtoReflect.Descriptor instead.
+func (*RoadEdge) Descriptor() ([]byte, []int) {
+	return file_services_proto_rawDescGZIP(), []int{34}
+}
+
+func (x *RoadEdge) GetFromNode() int64 {
+	if x != nil {
+		return x.FromNode
+	}
+	return 0
+}
+
+func (x *RoadEdge) GetToNode() int64 {
+	if x != nil {
+		return x.ToNode
+	}
+	return 0
+}
+
+func (x *RoadEdge) GetWayId() int64 {
+	if x != nil {
+		return x.WayId
+	}
+	return 0
+}
+
+func (x *RoadEdge) GetRoadClass() string {
+	if x != nil {
+		return x.RoadClass
+	}
+	return ""
+}
+
+func (x *RoadEdge) GetSpeedKph() float64 {
+	if x != nil {
+		return x.SpeedKph
+	}
+	return 0
+}
+
+func (x *RoadEdge) GetOneway() bool {
+	if x != nil {
+		return x.Oneway
+	}
+	return false
+}
+
+func (x *RoadEdge) GetLengthM() float64 {
+	if x != nil {
+		return x.LengthM
+	}
+	return 0
+}
+
+func (x *RoadEdge) GetGeometryGeojson() string {
+	if x != nil {
+		return x.GeometryGeojson
+	}
+	return ""
+}
+
+// A part of the network with at most one route to safety. Route counting
+// ignores oneway restrictions.
+type EgressNeighbourhood struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	RoutesOut     int32                  `protobuf:"varint,1,opt,name=routes_out,json=routesOut,proto3" json:"routes_out,omitempty"`   // 0 or 1
+	ExitNode      int64                  `protobuf:"varint,2,opt,name=exit_node,json=exitNode,proto3" json:"exit_node,omitempty"`      // Node every route out passes, 0 when routes_out is 0
+	ExitWayId     int64                  `protobuf:"varint,3,opt,name=exit_way_id,json=exitWayId,proto3" json:"exit_way_id,omitempty"` // Way carrying the route out, 0 if not a single road
+	NodeCount     int32                  `protobuf:"varint,4,opt,name=node_count,json=nodeCount,proto3" json:"node_count,omitempty"`
+	BuildingIds   []int64                `protobuf:"varint,5,rep,packed,name=building_ids,json=buildingIds,proto3" json:"building_ids,omitempty"` // OSM IDs of the buildings served
+	Bbox          *BoundingBox           `protobuf:"bytes,6,opt,name=bbox,proto3" json:"bbox,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *EgressNeighbourhood) Reset() {
+	*x = EgressNeighbourhood{}
+	mi := &file_services_proto_msgTypes[35]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *EgressNeighbourhood) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EgressNeighbourhood) ProtoMessage() {}
+
+func (x *EgressNeighbourhood) ProtoReflect() protoreflect.Message {
+	mi := &file_services_proto_msgTypes[35]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EgressNeighbourhood.ProtoReflect.Descriptor instead.
+func (*EgressNeighbourhood) Descriptor() ([]byte, []int) {
+	return file_services_proto_rawDescGZIP(), []int{35}
+}
+
+func (x *EgressNeighbourhood) GetRoutesOut() int32 {
+	if x != nil {
+		return x.RoutesOut
+	}
+	return 0
+}
+
+func (x *EgressNeighbourhood) GetExitNode() int64 {
+	if x != nil {
+		return x.ExitNode
+	}
+	return 0
+}
+
+func (x *EgressNeighbourhood) GetExitWayId() int64 {
+	if x != nil {
+		return x.ExitWayId
+	}
+	return 0
+}
+
+func (x *EgressNeighbourhood) GetNodeCount() int32 {
+	if x != nil {
+		return x.NodeCount
+	}
+	return 0
+}
+
+func (x *EgressNeighbourhood) GetBuildingIds() []int64 {
+	if x != nil {
+		return x.BuildingIds
+	}
+	return nil
+}
+
+func (x *EgressNeighbourhood) GetBbox() *BoundingBox {
+	if x != nil {
+		return x.Bbox
+	}
+	return nil
+}
+
+type DeadEndRoad struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	WayIds          []int64                `protobuf:"varint,1,rep,packed,name=way_ids,json=wayIds,proto3" json:"way_ids,omitempty"`
+	LengthM         float64                `protobuf:"fixed64,2,opt,name=length_m,json=lengthM,proto3" json:"length_m,omitempty"`
+	GeometryGeojson string                 `protobuf:"bytes,3,opt,name=geometry_geojson,json=geometryGeojson,proto3" json:"geometry_geojson,omitempty"` // LineString from the closed end to the first junction
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *DeadEndRoad) Reset() {
+	*x = DeadEndRoad{}
+	mi := &file_services_proto_msgTypes[36]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeadEndRoad) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeadEndRoad) ProtoMessage() {}
+
+func (x *DeadEndRoad) ProtoReflect() protoreflect.Message {
+	mi := &file_services_proto_msgTypes[36]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeadEndRoad.ProtoReflect.Descriptor instead.
+func (*DeadEndRoad) Descriptor() ([]byte, []int) {
+	return file_services_proto_rawDescGZIP(), []int{36}
+}
+
+func (x *DeadEndRoad) GetWayIds() []int64 {
+	if x != nil {
+		return x.WayIds
+	}
+	return nil
+}
+
+func (x *DeadEndRoad) GetLengthM() float64 {
+	if x != nil {
+		return x.LengthM
+	}
+	return 0
+}
+
+func (x *DeadEndRoad) GetGeometryGeojson() string {
+	if x != nil {
+		return x.GeometryGeojson
+	}
+	return ""
+}
+
+type BuildingEgress struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	OsmType          string                 `protobuf:"bytes,1,opt,name=osm_type,json=osmType,proto3" json:"osm_type,omitempty"`
+	OsmId            int64                  `protobuf:"varint,2,opt,name=osm_id,json=osmId,proto3" json:"osm_id,omitempty"`
+	NetworkDistanceM *float64               `protobuf:"fixed64,3,opt,name=network_distance_m,json=networkDistanceM,proto3,oneof" json:"network_distance_m,omitempty"` // Along roads to safety; unset when unreachable
+	SnapDistanceM    float64                `protobuf:"fixed64,4,opt,name=snap_distance_m,json=snapDistanceM,proto3" json:"snap_distance_m,omitempty"`                // From the building to its nearest road
+	SingleEgress     bool                   `protobuf:"varint,5,opt,name=single_egress,json=singleEgress,proto3" json:"single_egress,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *BuildingEgress) Reset() {
+	*x = BuildingEgress{}
+	mi := &file_services_proto_msgTypes[37]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BuildingEgress) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BuildingEgress) ProtoMessage() {}
+
+func (x *BuildingEgress) ProtoReflect() protoreflect.Message {
+	mi := &file_services_proto_msgTypes[37]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BuildingEgress.ProtoReflect.Descriptor instead.
+func (*BuildingEgress) Descriptor() ([]byte, []int) {
+	return file_services_proto_rawDescGZIP(), []int{37}
+}
+
+func (x *BuildingEgress) GetOsmType() string {
+	if x != nil {
+		return x.OsmType
+	}
+	return ""
+}
+
+func (x *BuildingEgress) GetOsmId() int64 {
+	if x != nil {
+		return x.OsmId
+	}
+	return 0
+}
+
+func (x *BuildingEgress) GetNetworkDistanceM() float64 {
+	if x != nil && x.NetworkDistanceM != nil {
+		return *x.NetworkDistanceM
+	}
+	return 0
+}
+
+func (x *BuildingEgress) GetSnapDistanceM() float64 {
+	if x != nil {
+		return x.SnapDistanceM
+	}
+	return 0
+}
+
+func (x *BuildingEgress) GetSingleEgress() bool {
+	if x != nil {
+		return x.SingleEgress
+	}
+	return false
+}
+
+// Estimated drive time from the nearest fire station to every building in
+// the AOI, routed over roads fetched within the service's buffer around it
+type ResponseCoverageRequest struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	AoiGeojson      string                 `protobuf:"bytes,1,opt,name=aoi_geojson,json=aoiGeojson,proto3" json:"aoi_geojson,omitempty"`
+	BandMinutes     []float64              `protobuf:"fixed64,2,rep,packed,name=band_minutes,json=bandMinutes,proto3" json:"band_minutes,omitempty"`            // Upper bounds of the bands, ascending; empty uses the service default
+	CoverageMinutes *float64               `protobuf:"fixed64,3,opt,name=coverage_minutes,json=coverageMinutes,proto3,oneof" json:"coverage_minutes,omitempty"` // N-minute coverage target; unset uses the service default
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *ResponseCoverageRequest) Reset() {
+	*x = ResponseCoverageRequest{}
+	mi := &file_services_proto_msgTypes[38]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ResponseCoverageRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ResponseCoverageRequest) ProtoMessage() {}
+
+func (x *ResponseCoverageRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_services_proto_msgTypes[38]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ResponseCoverageRequest.ProtoReflect.Descriptor instead.
+func (*ResponseCoverageRequest) Descriptor() ([]byte, []int) {
+	return file_services_proto_rawDescGZIP(), []int{38}
+}
+
+func (x *ResponseCoverageRequest) GetAoiGeojson() string {
+	if x != nil {
+		return x.AoiGeojson
+	}
+	return ""
+}
+
+func (x *ResponseCoverageRequest) GetBandMinutes() []float64 {
+	if x != nil {
+		return x.BandMinutes
+	}
+	return nil
+}
+
+func (x *ResponseCoverageRequest) GetCoverageMinutes() float64 {
+	if x != nil && x.CoverageMinutes != nil {
+		return *x.CoverageMinutes
+	}
+	return 0
+}
+
+type ResponseCoverageResponse struct {
+	state                protoimpl.MessageState `protogen:"open.v1"`
+	Buildings            []*BuildingResponse    `protobuf:"bytes,1,rep,name=buildings,proto3" json:"buildings,omitempty"`
+	Isochrones           []*Isochrone           `protobuf:"bytes,2,rep,name=isochrones,proto3" json:"isochrones,omitempty"`                          // One per band, shortest first
+	FireStations         int32                  `protobuf:"varint,3,opt,name=fire_stations,json=fireStations,proto3" json:"fire_stations,omitempty"` // Stations placed on the road network
+	CoverageMinutes      float64                `protobuf:"fixed64,4,opt,name=coverage_minutes,json=coverageMinutes,proto3" json:"coverage_minutes,omitempty"`
+	OutsideCoverageCount int32                  `protobuf:"varint,5,opt,name=outside_coverage_count,json=outsideCoverageCount,proto3" json:"outside_coverage_count,omitempty"`
+	Coverage             *Coverage              `protobuf:"bytes,6,opt,name=coverage,proto3" json:"coverage,omitempty"`
+	Attribution          *Attribution           `protobuf:"bytes,7,opt,name=attribution,proto3" json:"attribution,omitempty"`
+	unknownFields        protoimpl.UnknownFields
+	sizeCache            protoimpl.SizeCache
+}
+
+func (x *ResponseCoverageResponse) Reset() {
+	*x = ResponseCoverageResponse{}
+	mi := &file_services_proto_msgTypes[39]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ResponseCoverageResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ResponseCoverageResponse) ProtoMessage() {}
+
+func (x *ResponseCoverageResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_services_proto_msgTypes[39]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ResponseCoverageResponse.ProtoReflect.Descriptor instead.
+func (*ResponseCoverageResponse) Descriptor() ([]byte, []int) {
+	return file_services_proto_rawDescGZIP(), []int{39}
+}
+
+func (x *ResponseCoverageResponse) GetBuildings() []*BuildingResponse {
+	if x != nil {
+		return x.Buildings
+	}
+	return nil
+}
+
+func (x *ResponseCoverageResponse) GetIsochrones() []*Isochrone {
+	if x != nil {
+		return x.Isochrones
+	}
+	return nil
+}
+
+func (x *ResponseCoverageResponse) GetFireStations() int32 {
+	if x != nil {
+		return x.FireStations
+	}
+	return 0
+}
+
+func (x *ResponseCoverageResponse) GetCoverageMinutes() float64 {
+	if x != nil {
+		return x.CoverageMinutes
+	}
+	return 0
+}
+
+func (x *ResponseCoverageResponse) GetOutsideCoverageCount() int32 {
+	if x != nil {
+		return x.OutsideCoverageCount
+	}
+	return 0
+}
+
+func (x *ResponseCoverageResponse) GetCoverage() *Coverage {
+	if x != nil {
+		return x.Coverage
+	}
+	return nil
+}
+
+func (x *ResponseCoverageResponse) GetAttribution() *Attribution {
+	if x != nil {
+		return x.Attribution
+	}
+	return nil
+}
+
+type BuildingResponse struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	OsmType         string                 `protobuf:"bytes,1,opt,name=osm_type,json=osmType,proto3" json:"osm_type,omitempty"`
+	OsmId           int64                  `protobuf:"varint,2,opt,name=osm_id,json=osmId,proto3" json:"osm_id,omitempty"`
+	DriveTimeS      *float64               `protobuf:"fixed64,3,opt,name=drive_time_s,json=driveTimeS,proto3,oneof" json:"drive_time_s,omitempty"`       // Unset when no station can reach the building
+	Band            string                 `protobuf:"bytes,4,opt,name=band,proto3" json:"band,omitempty"`                                               // e.g. "0-4", "4-8", "20+" or "unreachable"
+	OutsideCoverage bool                   `protobuf:"varint,5,opt,name=outside_coverage,json=outsideCoverage,proto3" json:"outside_coverage,omitempty"` // Slower than coverage_minutes or unreachable; an extra vulnerability factor
+	StationId       int64                  `protobuf:"varint,6,opt,name=station_id,json=stationId,proto3" json:"station_id,omitempty"`                   // OSM ID of the fastest station, 0 when unreachable
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *BuildingResponse) Reset() {
+	*x = BuildingResponse{}
+	mi := &file_services_proto_msgTypes[40]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BuildingResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BuildingResponse) ProtoMessage() {}
+
+func (x *BuildingResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_services_proto_msgTypes[40]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BuildingResponse.ProtoReflect.Descriptor instead.
+func (*BuildingResponse) Descriptor() ([]byte, []int) {
+	return file_services_proto_rawDescGZIP(), []int{40}
+}
+
+func (x *BuildingResponse) GetOsmType() string {
+	if x != nil {
+		return x.OsmType
+	}
+	return ""
+}
+
+func (x *BuildingResponse) GetOsmId() int64 {
+	if x != nil {
+		return x.OsmId
+	}
+	return 0
+}
+
+func (x *BuildingResponse) GetDriveTimeS() float64 {
+	if x != nil && x.DriveTimeS != nil {
+		return *x.DriveTimeS
+	}
+	return 0
+}
+
+func (x *BuildingResponse) GetBand() string {
+	if x != nil {
+		return x.Band
+	}
+	return ""
+}
+
+func (x *BuildingResponse) GetOutsideCoverage() bool {
+	if x != nil {
+		return x.OutsideCoverage
+	}
+	return false
+}
+
+func (x *BuildingResponse) GetStationId() int64 {
+	if x != nil {
+		return x.StationId
+	}
+	return 0
+}
+
+type Isochrone struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	Minutes         float64                `protobuf:"fixed64,1,opt,name=minutes,proto3" json:"minutes,omitempty"`
+	GeometryGeojson string                 `protobuf:"bytes,2,opt,name=geometry_geojson,json=geometryGeojson,proto3" json:"geometry_geojson,omitempty"` // MultiPolygon reachable within minutes
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *Isochrone) Reset() {
+	*x = Isochrone{}
+	mi := &file_services_proto_msgTypes[41]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Isochrone) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Isochrone) ProtoMessage() {}
+
+func (x *Isochrone) ProtoReflect() protoreflect.Message {
+	mi := &file_services_proto_msgTypes[41]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Isochrone.ProtoReflect.Descriptor instead.
+func (*Isochrone) Descriptor() ([]byte, []int) {
+	return file_services_proto_rawDescGZIP(), []int{41}
+}
+
+func (x *Isochrone) GetMinutes() float64 {
+	if x != nil {
+		return x.Minutes
+	}
+	return 0
+}
+
+func (x *Isochrone) GetGeometryGeojson() string {
+	if x != nil {
+		return x.GeometryGeojson
+	}
+	return ""
+}
+
+// Power line spans between towers and poles in the AOI, ranked by ignition
+// exposure
+type PowerSpanRequest struct {
+	state             protoimpl.MessageState `protogen:"open.v1"`
+	AoiGeojson        string                 `protobuf:"bytes,1,opt,name=aoi_geojson,json=aoiGeojson,proto3" json:"aoi_geojson,omitempty"`
+	WindFromDeg       *float64               `protobuf:"fixed64,2,opt,name=wind_from_deg,json=windFromDeg,proto3,oneof" json:"wind_from_deg,omitempty"`                   // Direction the wind blows from; unset counts buildings in every direction
+	DownwindDistanceM *float64               `protobuf:"fixed64,3,opt,name=downwind_distance_m,json=downwindDistanceM,proto3,oneof" json:"downwind_distance_m,omitempty"` // Unset uses the service default
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
+}
+
+func (x *PowerSpanRequest) Reset() {
+	*x = PowerSpanRequest{}
+	mi := &file_services_proto_msgTypes[42]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PowerSpanRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PowerSpanRequest) ProtoMessage() {}
+
+func (x *PowerSpanRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_services_proto_msgTypes[42]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PowerSpanRequest.ProtoReflect.Descriptor instead.
+func (*PowerSpanRequest) Descriptor() ([]byte, []int) {
+	return file_services_proto_rawDescGZIP(), []int{42}
+}
+
+func (x *PowerSpanRequest) GetAoiGeojson() string {
+	if x != nil {
+		return x.AoiGeojson
+	}
+	return ""
+}
+
+func (x *PowerSpanRequest) GetWindFromDeg() float64 {
+	if x != nil && x.WindFromDeg != nil {
+		return *x.WindFromDeg
+	}
+	return 0
+}
+
+func (x *PowerSpanRequest) GetDownwindDistanceM() float64 {
+	if x != nil && x.DownwindDistanceM != nil {
+		return *x.DownwindDistanceM
+	}
+	return 0
+}
+
+type PowerSpanResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Spans         []*PowerSpan           `protobuf:"bytes,1,rep,name=spans,proto3" json:"spans,omitempty"` // Most exposed first
+	Coverage      *Coverage              `protobuf:"bytes,2,opt,name=coverage,proto3" json:"coverage,omitempty"`
+	Attribution   *Attribution           `protobuf:"bytes,3,opt,name=attribution,proto3" json:"attribution,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PowerSpanResponse) Reset() {
+	*x = PowerSpanResponse{}
+	mi := &file_services_proto_msgTypes[43]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PowerSpanResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PowerSpanResponse) ProtoMessage() {}
+
+func (x *PowerSpanResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_services_proto_msgTypes[43]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PowerSpanResponse.ProtoReflect.Descriptor instead.
+func (*PowerSpanResponse) Descriptor() ([]byte, []int) {
+	return file_services_proto_rawDescGZIP(), []int{43}
+}
+
+func (x *PowerSpanResponse) GetSpans() []*PowerSpan {
+	if x != nil {
+		return x.Spans
+	}
+	return nil
+}
+
+func (x *PowerSpanResponse) GetCoverage() *Coverage {
+	if x != nil {
+		return x.Coverage
+	}
+	return nil
+}
+
+func (x *PowerSpanResponse) GetAttribution() *Attribution {
+	if x != nil {
+		return x.Attribution
+	}
+	return nil
+}
+
+type PowerSpan struct {
+	state             protoimpl.MessageState `protogen:"open.v1"`
+	Rank              int32                  `protobuf:"varint,1,opt,name=rank,proto3" json:"rank,omitempty"`
+	WayId             int64                  `protobuf:"varint,2,opt,name=way_id,json=wayId,proto3" json:"way_id,omitempty"`
+	FromSupport       int64                  `protobuf:"varint,3,opt,name=from_support,json=fromSupport,proto3" json:"from_support,omitempty"` // OSM node IDs of the supports, 0 when unknown
+	ToSupport         int64                  `protobuf:"varint,4,opt,name=to_support,json=toSupport,proto3" json:"to_support,omitempty"`
+	GeometryGeojson   string                 `protobuf:"bytes,5,opt,name=geometry_geojson,json=geometryGeojson,proto3" json:"geometry_geojson,omitempty"`
+	LengthM           float64                `protobuf:"fixed64,6,opt,name=length_m,json=lengthM,proto3" json:"length_m,omitempty"`
+	VoltageV          *float64               `protobuf:"fixed64,7,opt,name=voltage_v,json=voltageV,proto3,oneof" json:"voltage_v,omitempty"`
+	Operator          string                 `protobuf:"bytes,8,opt,name=operator,proto3" json:"operator,omitempty"`
+	FuelShares        map[string]float64     `protobuf:"bytes,9,rep,name=fuel_shares,json=fuelShares,proto3" json:"fuel_shares,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"fixed64,2,opt,name=value"` // Share of corridor samples per fuel class
+	FuelClassUnder    string                 `protobuf:"bytes,10,opt,name=fuel_class_under,json=fuelClassUnder,proto3" json:"fuel_class_under,omitempty"`                                                              // Dominant class directly below, empty when unclassified
+	SlopeDeg          *float64               `protobuf:"fixed64,11,opt,name=slope_deg,json=slopeDeg,proto3,oneof" json:"slope_deg,omitempty"`                                                                          // Unset where the DEM has no data; the score then weighs fuel and downwind buildings only
+	BuildingsDownwind int32                  `protobuf:"varint,12,opt,name=buildings_downwind,json=buildingsDownwind,proto3" json:"buildings_downwind,omitempty"`
+	ExposureScore     float64                `protobuf:"fixed64,13,opt,name=exposure_score,json=exposureScore,proto3" json:"exposure_score,omitempty"` // 0.0-1.0
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
+}
+
+func (x *PowerSpan) Reset() {
+	*x = PowerSpan{}
+	mi := &file_services_proto_msgTypes[44]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PowerSpan) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PowerSpan) ProtoMessage() {}
+
+func (x *PowerSpan) ProtoReflect() protoreflect.Message {
+	mi := &file_services_proto_msgTypes[44]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PowerSpan.ProtoReflect.Descriptor instead.
+func (*PowerSpan) Descriptor() ([]byte, []int) {
+	return file_services_proto_rawDescGZIP(), []int{44}
+}
+
+func (x *PowerSpan) GetRank() int32 {
+	if x != nil {
+		return x.Rank
+	}
+	return 0
+}
+
+func (x *PowerSpan) GetWayId() int64 {
+	if x != nil {
+		return x.WayId
+	}
+	return 0
+}
+
+func (x *PowerSpan) GetFromSupport() int64 {
+	if x != nil {
+		return x.FromSupport
+	}
+	return 0
+}
+
+func (x *PowerSpan) GetToSupport() int64 {
+	if x != nil {
+		return x.ToSupport
+	}
+	return 0
+}
+
+func (x *PowerSpan) GetGeometryGeojson() string {
+	if x != nil {
+		return x.GeometryGeojson
+	}
+	return ""
 }
 
-func (*GetJobStatusResponse) ProtoMessage() {}
-
-func (x *GetJobStatusResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_services_proto_msgTypes[3]
+func (x *PowerSpan) GetLengthM() float64 {
 	if x != nil {
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		if ms.LoadMessageInfo() == nil {
-			ms.StoreMessageInfo(mi)
-		}
-		return ms
+		return x.LengthM
 	}
-	return mi.MessageOf(x)
+	return 0
 }
 
-// Deprecated: Use GetJobStatusResponse.ProtoReflect.Descriptor instead.
-func (*GetJobStatusResponse) Descriptor() ([]byte, []int) {
-	return file_services_proto_rawDescGZIP(), []int{3}
+func (x *PowerSpan) GetVoltageV() float64 {
+	if x != nil && x.VoltageV != nil {
+		return *x.VoltageV
+	}
+	return 0
 }
 
-func (x *GetJobStatusResponse) GetJobId() string {
+func (x *PowerSpan) GetOperator() string {
 	if x != nil {
-		return x.JobId
+		return x.Operator
 	}
 	return ""
 }
 
-func (x *GetJobStatusResponse) GetStatus() JobStatus {
+func (x *PowerSpan) GetFuelShares() map[string]float64 {
 	if x != nil {
-		return x.Status
+		return x.FuelShares
 	}
-	return JobStatus_PENDING
+	return nil
 }
 
-func (x *GetJobStatusResponse) GetMessage() string {
+func (x *PowerSpan) GetFuelClassUnder() string {
 	if x != nil {
-		return x.Message
+		return x.FuelClassUnder
 	}
 	return ""
 }
 
-func (x *GetJobStatusResponse) GetCreatedAt() int64 {
+func (x *PowerSpan) GetSlopeDeg() float64 {
+	if x != nil && x.SlopeDeg != nil {
+		return *x.SlopeDeg
+	}
+	return 0
+}
+
+func (x *PowerSpan) GetBuildingsDownwind() int32 {
 	if x != nil {
-		return x.CreatedAt
+		return x.BuildingsDownwind
 	}
 	return 0
 }
 
-func (x *GetJobStatusResponse) GetUpdatedAt() int64 {
+func (x *PowerSpan) GetExposureScore() float64 {
 	if x != nil {
-		return x.UpdatedAt
+		return x.ExposureScore
 	}
 	return 0
 }
 
-type GetAssetsRequest struct {
+type GetDemRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	AoiGeojson    string                 `protobuf:"bytes,1,opt,name=aoi_geojson,json=aoiGeojson,proto3" json:"aoi_geojson,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *GetAssetsRequest) Reset() {
-	*x = GetAssetsRequest{}
-	mi := &file_services_proto_msgTypes[4]
+func (x *GetDemRequest) Reset() {
+	*x = GetDemRequest{}
+	mi := &file_services_proto_msgTypes[45]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetAssetsRequest) String() string {
+func (x *GetDemRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetAssetsRequest) ProtoMessage() {}
+func (*GetDemRequest) ProtoMessage() {}
 
-func (x *GetAssetsRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_services_proto_msgTypes[4]
+func (x *GetDemRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_services_proto_msgTypes[45]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -332,42 +3521,41 @@ func (x *GetAssetsRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetAssetsRequest.ProtoReflect.Descriptor instead.
-func (*GetAssetsRequest) Descriptor() ([]byte, []int) {
-	return file_services_proto_rawDescGZIP(), []int{4}
+// Deprecated: Use GetDemRequest.ProtoReflect.Descriptor instead.
+func (*GetDemRequest) Descriptor() ([]byte, []int) {
+	return file_services_proto_rawDescGZIP(), []int{45}
 }
 
-func (x *GetAssetsRequest) GetAoiGeojson() string {
+func (x *GetDemRequest) GetAoiGeojson() string {
 	if x != nil {
 		return x.AoiGeojson
 	}
 	return ""
 }
 
-type Asset struct {
-	state                protoimpl.MessageState `protogen:"open.v1"`
-	AssetType            string                 `protobuf:"bytes,1,opt,name=asset_type,json=assetType,proto3" json:"asset_type,omitempty"` // "building", "road", "power_line"
-	AssetGeometryGeojson string                 `protobuf:"bytes,2,opt,name=asset_geometry_geojson,json=assetGeometryGeojson,proto3" json:"asset_geometry_geojson,omitempty"`
-	Properties           map[string]string      `protobuf:"bytes,3,rep,name=properties,proto3" json:"properties,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"` // Additional OSM properties
-	unknownFields        protoimpl.UnknownFields
-	sizeCache            protoimpl.SizeCache
+type GetDemResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	LocalDemPath  string                 `protobuf:"bytes,1,opt,name=local_dem_path,json=localDemPath,proto3" json:"local_dem_path,omitempty"` // Path on shared volume where DEM is stored
+	Metadata      *DemMetadata           `protobuf:"bytes,3,opt,name=metadata,proto3" json:"metadata,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
 }
 
-func (x *Asset) Reset() {
-	*x = Asset{}
-	mi := &file_services_proto_msgTypes[5]
+func (x *GetDemResponse) Reset() {
+	*x = GetDemResponse{}
+	mi := &file_services_proto_msgTypes[46]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *Asset) String() string {
+func (x *GetDemResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*Asset) ProtoMessage() {}
+func (*GetDemResponse) ProtoMessage() {}
 
-func (x *Asset) ProtoReflect() protoreflect.Message {
-	mi := &file_services_proto_msgTypes[5]
+func (x *GetDemResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_services_proto_msgTypes[46]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -378,55 +3566,61 @@ func (x *Asset) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use Asset.ProtoReflect.Descriptor instead.
-func (*Asset) Descriptor() ([]byte, []int) {
-	return file_services_proto_rawDescGZIP(), []int{5}
-}
-
-func (x *Asset) GetAssetType() string {
-	if x != nil {
-		return x.AssetType
-	}
-	return ""
+// Deprecated: Use GetDemResponse.ProtoReflect.Descriptor instead.
+func (*GetDemResponse) Descriptor() ([]byte, []int) {
+	return file_services_proto_rawDescGZIP(), []int{46}
 }
 
-func (x *Asset) GetAssetGeometryGeojson() string {
+func (x *GetDemResponse) GetLocalDemPath() string {
 	if x != nil {
-		return x.AssetGeometryGeojson
+		return x.LocalDemPath
 	}
 	return ""
 }
 
-func (x *Asset) GetProperties() map[string]string {
+func (x *GetDemResponse) GetMetadata() *DemMetadata {
 	if x != nil {
-		return x.Properties
+		return x.Metadata
 	}
 	return nil
 }
 
-type GetAssetsResponse struct {
+// Describes a DEM cropped to an AOI. Elevations are in metres.
+type DemMetadata struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Assets        []*Asset               `protobuf:"bytes,1,rep,name=assets,proto3" json:"assets,omitempty"`
-	TotalCount    int32                  `protobuf:"varint,2,opt,name=total_count,json=totalCount,proto3" json:"total_count,omitempty"`
+	Width         int32                  `protobuf:"varint,1,opt,name=width,proto3" json:"width,omitempty"`
+	Height        int32                  `protobuf:"varint,2,opt,name=height,proto3" json:"height,omitempty"`
+	Crs           string                 `protobuf:"bytes,3,opt,name=crs,proto3" json:"crs,omitempty"` // e.g. "EPSG:4269"
+	Epsg          int32                  `protobuf:"varint,4,opt,name=epsg,proto3" json:"epsg,omitempty"`
+	PixelWidth    float64                `protobuf:"fixed64,5,opt,name=pixel_width,json=pixelWidth,proto3" json:"pixel_width,omitempty"` // In CRS units: degrees or metres
+	PixelHeight   float64                `protobuf:"fixed64,6,opt,name=pixel_height,json=pixelHeight,proto3" json:"pixel_height,omitempty"`
+	ResolutionM   float64                `protobuf:"fixed64,7,opt,name=resolution_m,json=resolutionM,proto3" json:"resolution_m,omitempty"`  // Approximate ground size of a pixel
+	Bounds        *BoundingBox           `protobuf:"bytes,8,opt,name=bounds,proto3" json:"bounds,omitempty"`                                 // WGS 84 extent
+	NativeBounds  *Extent                `protobuf:"bytes,9,opt,name=native_bounds,json=nativeBounds,proto3" json:"native_bounds,omitempty"` // Extent in the DEM's CRS
+	Nodata        *float64               `protobuf:"fixed64,10,opt,name=nodata,proto3,oneof" json:"nodata,omitempty"`
+	MinElevationM float64                `protobuf:"fixed64,11,opt,name=min_elevation_m,json=minElevationM,proto3" json:"min_elevation_m,omitempty"`
+	MaxElevationM float64                `protobuf:"fixed64,12,opt,name=max_elevation_m,json=maxElevationM,proto3" json:"max_elevation_m,omitempty"`
+	ValidShare    float64                `protobuf:"fixed64,13,opt,name=valid_share,json=validShare,proto3" json:"valid_share,omitempty"` // Share of pixels with an elevation
+	SourceTiles   []string               `protobuf:"bytes,14,rep,name=source_tiles,json=sourceTiles,proto3" json:"source_tiles,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *GetAssetsResponse) Reset() {
-	*x = GetAssetsResponse{}
-	mi := &file_services_proto_msgTypes[6]
+func (x *DemMetadata) Reset() {
+	*x = DemMetadata{}
+	mi := &file_services_proto_msgTypes[47]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetAssetsResponse) String() string {
+func (x *DemMetadata) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetAssetsResponse) ProtoMessage() {}
+func (*DemMetadata) ProtoMessage() {}
 
-func (x *GetAssetsResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_services_proto_msgTypes[6]
+func (x *DemMetadata) ProtoReflect() protoreflect.Message {
+	mi := &file_services_proto_msgTypes[47]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -437,92 +3631,134 @@ func (x *GetAssetsResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetAssetsResponse.ProtoReflect.Descriptor instead.
-func (*GetAssetsResponse) Descriptor() ([]byte, []int) {
-	return file_services_proto_rawDescGZIP(), []int{6}
+// Deprecated: Use DemMetadata.ProtoReflect.Descriptor instead.
+func (*DemMetadata) Descriptor() ([]byte, []int) {
+	return file_services_proto_rawDescGZIP(), []int{47}
 }
 
-func (x *GetAssetsResponse) GetAssets() []*Asset {
+func (x *DemMetadata) GetWidth() int32 {
 	if x != nil {
-		return x.Assets
+		return x.Width
 	}
-	return nil
+	return 0
 }
 
-func (x *GetAssetsResponse) GetTotalCount() int32 {
+func (x *DemMetadata) GetHeight() int32 {
 	if x != nil {
-		return x.TotalCount
+		return x.Height
 	}
 	return 0
 }
 
-type GetDemRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	AoiGeojson    string                 `protobuf:"bytes,1,opt,name=aoi_geojson,json=aoiGeojson,proto3" json:"aoi_geojson,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+func (x *DemMetadata) GetCrs() string {
+	if x != nil {
+		return x.Crs
+	}
+	return ""
 }
 
-func (x *GetDemRequest) Reset() {
-	*x = GetDemRequest{}
-	mi := &file_services_proto_msgTypes[7]
-	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-	ms.StoreMessageInfo(mi)
+func (x *DemMetadata) GetEpsg() int32 {
+	if x != nil {
+		return x.Epsg
+	}
+	return 0
 }
 
-func (x *GetDemRequest) String() string {
-	return protoimpl.X.MessageStringOf(x)
+func (x *DemMetadata) GetPixelWidth() float64 {
+	if x != nil {
+		return x.PixelWidth
+	}
+	return 0
 }
 
-func (*GetDemRequest) ProtoMessage() {}
+func (x *DemMetadata) GetPixelHeight() float64 {
+	if x != nil {
+		return x.PixelHeight
+	}
+	return 0
+}
 
-func (x *GetDemRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_services_proto_msgTypes[7]
+func (x *DemMetadata) GetResolutionM() float64 {
 	if x != nil {
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		if ms.LoadMessageInfo() == nil {
-			ms.StoreMessageInfo(mi)
-		}
-		return ms
+		return x.ResolutionM
 	}
-	return mi.MessageOf(x)
+	return 0
 }
 
-// Deprecated: Use GetDemRequest.ProtoReflect.Descriptor instead.
-func (*GetDemRequest) Descriptor() ([]byte, []int) {
-	return file_services_proto_rawDescGZIP(), []int{7}
+func (x *DemMetadata) GetBounds() *BoundingBox {
+	if x != nil {
+		return x.Bounds
+	}
+	return nil
 }
 
-func (x *GetDemRequest) GetAoiGeojson() string {
+func (x *DemMetadata) GetNativeBounds() *Extent {
 	if x != nil {
-		return x.AoiGeojson
+		return x.NativeBounds
 	}
-	return ""
+	return nil
 }
 
-type GetDemResponse struct {
+func (x *DemMetadata) GetNodata() float64 {
+	if x != nil && x.Nodata != nil {
+		return *x.Nodata
+	}
+	return 0
+}
+
+func (x *DemMetadata) GetMinElevationM() float64 {
+	if x != nil {
+		return x.MinElevationM
+	}
+	return 0
+}
+
+func (x *DemMetadata) GetMaxElevationM() float64 {
+	if x != nil {
+		return x.MaxElevationM
+	}
+	return 0
+}
+
+func (x *DemMetadata) GetValidShare() float64 {
+	if x != nil {
+		return x.ValidShare
+	}
+	return 0
+}
+
+func (x *DemMetadata) GetSourceTiles() []string {
+	if x != nil {
+		return x.SourceTiles
+	}
+	return nil
+}
+
+type Extent struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	LocalDemPath  string                 `protobuf:"bytes,1,opt,name=local_dem_path,json=localDemPath,proto3" json:"local_dem_path,omitempty"` // Path on shared volume where DEM is stored
-	Metadata      string                 `protobuf:"bytes,2,opt,name=metadata,proto3" json:"metadata,omitempty"`                               // DEM metadata (resolution, projection, etc.)
+	MinX          float64                `protobuf:"fixed64,1,opt,name=min_x,json=minX,proto3" json:"min_x,omitempty"`
+	MinY          float64                `protobuf:"fixed64,2,opt,name=min_y,json=minY,proto3" json:"min_y,omitempty"`
+	MaxX          float64                `protobuf:"fixed64,3,opt,name=max_x,json=maxX,proto3" json:"max_x,omitempty"`
+	MaxY          float64                `protobuf:"fixed64,4,opt,name=max_y,json=maxY,proto3" json:"max_y,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *GetDemResponse) Reset() {
-	*x = GetDemResponse{}
-	mi := &file_services_proto_msgTypes[8]
+func (x *Extent) Reset() {
+	*x = Extent{}
+	mi := &file_services_proto_msgTypes[48]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetDemResponse) String() string {
+func (x *Extent) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetDemResponse) ProtoMessage() {}
+func (*Extent) ProtoMessage() {}
 
-func (x *GetDemResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_services_proto_msgTypes[8]
+func (x *Extent) ProtoReflect() protoreflect.Message {
+	mi := &file_services_proto_msgTypes[48]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -533,39 +3769,77 @@ func (x *GetDemResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetDemResponse.ProtoReflect.Descriptor instead.
-func (*GetDemResponse) Descriptor() ([]byte, []int) {
-	return file_services_proto_rawDescGZIP(), []int{8}
+// Deprecated: Use Extent.ProtoReflect.Descriptor instead.
+func (*Extent) Descriptor() ([]byte, []int) {
+	return file_services_proto_rawDescGZIP(), []int{48}
 }
 
-func (x *GetDemResponse) GetLocalDemPath() string {
+func (x *Extent) GetMinX() float64 {
 	if x != nil {
-		return x.LocalDemPath
+		return x.MinX
 	}
-	return ""
+	return 0
 }
 
-func (x *GetDemResponse) GetMetadata() string {
+func (x *Extent) GetMinY() float64 {
 	if x != nil {
-		return x.Metadata
+		return x.MinY
 	}
-	return ""
+	return 0
+}
+
+func (x *Extent) GetMaxX() float64 {
+	if x != nil {
+		return x.MaxX
+	}
+	return 0
+}
+
+func (x *Extent) GetMaxY() float64 {
+	if x != nil {
+		return x.MaxY
+	}
+	return 0
 }
 
 var File_services_proto protoreflect.FileDescriptor
 
 const file_services_proto_rawDesc = "" +
 	"\n" +
-	"\x0eservices.proto\x12\friskplatform\"3\n" +
+	"\x0eservices.proto\x12\friskplatform\"\xf4\x01\n" +
 	"\x10CreateJobRequest\x12\x1f\n" +
 	"\vaoi_geojson\x18\x01 \x01(\tR\n" +
-	"aoiGeojson\"u\n" +
+	"aoiGeojson\x12#\n" +
+	"\rforce_refresh\x18\x02 \x01(\bR\fforceRefresh\x12E\n" +
+	"\x0frisk_parameters\x18\x03 \x01(\v2\x1c.riskplatform.RiskParametersR\x0eriskParameters\x125\n" +
+	"\bpriority\x18\x04 \x01(\x0e2\x19.riskplatform.JobPriorityR\bpriority\x12\x1c\n" +
+	"\tsubmitter\x18\x05 \x01(\tR\tsubmitter\"\xba\x01\n" +
+	"\x0eRiskParameters\x123\n" +
+	"\aweights\x18\x01 \x01(\v2\x19.riskplatform.RiskWeightsR\aweights\x12<\n" +
+	"\n" +
+	"thresholds\x18\x02 \x01(\v2\x1c.riskplatform.RiskThresholdsR\n" +
+	"thresholds\x12\x1f\n" +
+	"\vasset_types\x18\x03 \x03(\tR\n" +
+	"assetTypes\x12\x14\n" +
+	"\x05model\x18\x04 \x01(\tR\x05model\"W\n" +
+	"\vRiskWeights\x12\x1e\n" +
+	"\n" +
+	"vegetation\x18\x01 \x01(\x01R\n" +
+	"vegetation\x12\x14\n" +
+	"\x05slope\x18\x02 \x01(\x01R\x05slope\x12\x12\n" +
+	"\x04wind\x18\x03 \x01(\x01R\x04wind\"N\n" +
+	"\x0eRiskThresholds\x12\x10\n" +
+	"\x03low\x18\x01 \x01(\x01R\x03low\x12\x16\n" +
+	"\x06medium\x18\x02 \x01(\x01R\x06medium\x12\x12\n" +
+	"\x04high\x18\x03 \x01(\x01R\x04high\"\xc9\x01\n" +
 	"\x11CreateJobResponse\x12\x15\n" +
 	"\x06job_id\x18\x01 \x01(\tR\x05jobId\x12/\n" +
 	"\x06status\x18\x02 \x01(\x0e2\x17.riskplatform.JobStatusR\x06status\x12\x18\n" +
-	"\amessage\x18\x03 \x01(\tR\amessage\",\n" +
+	"\amessage\x18\x03 \x01(\tR\amessage\x12%\n" +
+	"\x0eresults_reused\x18\x04 \x01(\bR\rresultsReused\x12+\n" +
+	"\x12reused_from_job_id\x18\x05 \x01(\tR\x0freusedFromJobId\",\n" +
 	"\x13GetJobStatusRequest\x12\x15\n" +
-	"\x06job_id\x18\x01 \x01(\tR\x05jobId\"\xb6\x01\n" +
+	"\x06job_id\x18\x01 \x01(\tR\x05jobId\"\xee\x02\n" +
 	"\x14GetJobStatusResponse\x12\x15\n" +
 	"\x06job_id\x18\x01 \x01(\tR\x05jobId\x12/\n" +
 	"\x06status\x18\x02 \x01(\x0e2\x17.riskplatform.JobStatusR\x06status\x12\x18\n" +
@@ -573,30 +3847,340 @@ const file_services_proto_rawDesc = "" +
 	"\n" +
 	"created_at\x18\x04 \x01(\x03R\tcreatedAt\x12\x1d\n" +
 	"\n" +
-	"updated_at\x18\x05 \x01(\x03R\tupdatedAt\"3\n" +
+	"updated_at\x18\x05 \x01(\x03R\tupdatedAt\x12%\n" +
+	"\x0equeue_position\x18\x06 \x01(\x05R\rqueuePosition\x125\n" +
+	"\bpriority\x18\a \x01(\x0e2\x19.riskplatform.JobPriorityR\bpriority\x12(\n" +
+	"\x10child_jobs_total\x18\b \x01(\x05R\x0echildJobsTotal\x12.\n" +
+	"\x13child_jobs_complete\x18\t \x01(\x05R\x11childJobsComplete\"-\n" +
+	"\x14GetJobResultsRequest\x12\x15\n" +
+	"\x06job_id\x18\x01 \x01(\tR\x05jobId\"\xf0\x01\n" +
+	"\x15GetJobResultsResponse\x12\x15\n" +
+	"\x06job_id\x18\x01 \x01(\tR\x05jobId\x12/\n" +
+	"\x06status\x18\x02 \x01(\x0e2\x17.riskplatform.JobStatusR\x06status\x12\x18\n" +
+	"\amessage\x18\x03 \x01(\tR\amessage\x128\n" +
+	"\asummary\x18\x04 \x01(\v2\x1e.riskplatform.JobResultSummaryR\asummary\x12;\n" +
+	"\vattribution\x18\x05 \x01(\v2\x19.riskplatform.AttributionR\vattribution\"\xb5\x01\n" +
+	"\x10JobResultSummary\x12!\n" +
+	"\ftotal_assets\x18\x01 \x01(\x05R\vtotalAssets\x12(\n" +
+	"\x10high_risk_assets\x18\x02 \x01(\x05R\x0ehighRiskAssets\x12,\n" +
+	"\x12medium_risk_assets\x18\x03 \x01(\x05R\x10mediumRiskAssets\x12&\n" +
+	"\x0flow_risk_assets\x18\x04 \x01(\x05R\rlowRiskAssets\"F\n" +
+	"\x16GetAssetHistoryRequest\x12,\n" +
+	"\x12canonical_asset_id\x18\x01 \x01(\x03R\x10canonicalAssetId\"\xc3\x01\n" +
+	"\x17GetAssetHistoryResponse\x122\n" +
+	"\x05asset\x18\x01 \x01(\v2\x1c.riskplatform.CanonicalAssetR\x05asset\x123\n" +
+	"\achanges\x18\x02 \x03(\v2\x19.riskplatform.AssetChangeR\achanges\x12?\n" +
+	"\frisk_history\x18\x03 \x03(\v2\x1c.riskplatform.AssetRiskPointR\vriskHistory\"\xdf\x03\n" +
+	"\x0eCanonicalAsset\x12,\n" +
+	"\x12canonical_asset_id\x18\x01 \x01(\x03R\x10canonicalAssetId\x12!\n" +
+	"\fidentity_key\x18\x02 \x01(\tR\videntityKey\x12\x1d\n" +
+	"\n" +
+	"asset_type\x18\x03 \x01(\tR\tassetType\x124\n" +
+	"\x16asset_geometry_geojson\x18\x04 \x01(\tR\x14assetGeometryGeojson\x12:\n" +
+	"\x04tags\x18\x05 \x03(\v2&.riskplatform.CanonicalAsset.TagsEntryR\x04tags\x12\x18\n" +
+	"\aversion\x18\x06 \x01(\x05R\aversion\x12)\n" +
+	"\x11first_seen_job_id\x18\a \x01(\tR\x0efirstSeenJobId\x12'\n" +
+	"\x10last_seen_job_id\x18\b \x01(\tR\rlastSeenJobId\x12\"\n" +
+	"\rfirst_seen_at\x18\t \x01(\x03R\vfirstSeenAt\x12 \n" +
+	"\flast_seen_at\x18\n" +
+	" \x01(\x03R\n" +
+	"lastSeenAt\x1a7\n" +
+	"\tTagsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"\xf0\x02\n" +
+	"\vAssetChange\x12\x15\n" +
+	"\x06job_id\x18\x01 \x01(\tR\x05jobId\x12\x1f\n" +
+	"\vchange_type\x18\x02 \x01(\tR\n" +
+	"changeType\x12\x18\n" +
+	"\aversion\x18\x03 \x01(\x05R\aversion\x12:\n" +
+	"\x19previous_geometry_geojson\x18\x04 \x01(\tR\x17previousGeometryGeojson\x12P\n" +
+	"\rprevious_tags\x18\x05 \x03(\v2+.riskplatform.AssetChange.PreviousTagsEntryR\fpreviousTags\x12!\n" +
+	"\fchanged_tags\x18\x06 \x03(\tR\vchangedTags\x12\x1d\n" +
+	"\n" +
+	"changed_at\x18\a \x01(\x03R\tchangedAt\x1a?\n" +
+	"\x11PreviousTagsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"\xc8\x01\n" +
+	"\x0eAssetRiskPoint\x12\x15\n" +
+	"\x06job_id\x18\x01 \x01(\tR\x05jobId\x12,\n" +
+	"\x12overall_risk_score\x18\x02 \x01(\x01R\x10overallRiskScore\x12\x1d\n" +
+	"\n" +
+	"model_name\x18\x03 \x01(\tR\tmodelName\x12#\n" +
+	"\rmodel_version\x18\x04 \x01(\tR\fmodelVersion\x12-\n" +
+	"\x12analysis_timestamp\x18\x05 \x01(\x03R\x11analysisTimestamp\"\xd8\x02\n" +
 	"\x10GetAssetsRequest\x12\x1f\n" +
 	"\vaoi_geojson\x18\x01 \x01(\tR\n" +
-	"aoiGeojson\"\xe0\x01\n" +
+	"aoiGeojson\x12\x1f\n" +
+	"\vasset_types\x18\x02 \x03(\tR\n" +
+	"assetTypes\x12O\n" +
+	"\vtag_filters\x18\x03 \x03(\v2..riskplatform.GetAssetsRequest.TagFiltersEntryR\n" +
+	"tagFilters\x12.\n" +
+	"\x13exclude_minor_roads\x18\x04 \x01(\bR\x11excludeMinorRoads\x12B\n" +
+	"\x1dinclude_suppression_distances\x18\x05 \x01(\bR\x1bincludeSuppressionDistances\x1a=\n" +
+	"\x0fTagFiltersEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"\xd0\x06\n" +
 	"\x05Asset\x12\x1d\n" +
 	"\n" +
 	"asset_type\x18\x01 \x01(\tR\tassetType\x124\n" +
 	"\x16asset_geometry_geojson\x18\x02 \x01(\tR\x14assetGeometryGeojson\x12C\n" +
 	"\n" +
 	"properties\x18\x03 \x03(\v2#.riskplatform.Asset.PropertiesEntryR\n" +
-	"properties\x1a=\n" +
+	"properties\x12\x1d\n" +
+	"\n" +
+	"within_aoi\x18\x04 \x01(\bR\twithinAoi\x121\n" +
+	"\x14vulnerability_weight\x18\x05 \x01(\x01R\x13vulnerabilityWeight\x12\x17\n" +
+	"\aarea_m2\x18\x06 \x01(\x01R\x06areaM2\x12\x19\n" +
+	"\blength_m\x18\a \x01(\x01R\alengthM\x12!\n" +
+	"\fcentroid_lon\x18\b \x01(\x01R\vcentroidLon\x12!\n" +
+	"\fcentroid_lat\x18\t \x01(\x01R\vcentroidLat\x12-\n" +
+	"\x04bbox\x18\n" +
+	" \x01(\v2\x19.riskplatform.BoundingBoxR\x04bbox\x12,\n" +
+	"\x0fbuilding_levels\x18\v \x01(\x01H\x00R\x0ebuildingLevels\x88\x01\x01\x12\x1e\n" +
+	"\bheight_m\x18\f \x01(\x01H\x01R\aheightM\x88\x01\x01\x12 \n" +
+	"\tvoltage_v\x18\r \x01(\x01H\x02R\bvoltageV\x88\x01\x01\x12-\n" +
+	"\x10water_distance_m\x18\x0e \x01(\x01H\x03R\x0ewaterDistanceM\x88\x01\x01\x12:\n" +
+	"\x17fire_station_distance_m\x18\x0f \x01(\x01H\x04R\x14fireStationDistanceM\x88\x01\x01\x128\n" +
+	"\n" +
+	"provenance\x18\x10 \x01(\v2\x18.riskplatform.ProvenanceR\n" +
+	"provenance\x1a=\n" +
 	"\x0fPropertiesEntry\x12\x10\n" +
 	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
-	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"a\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01B\x12\n" +
+	"\x10_building_levelsB\v\n" +
+	"\t_height_mB\f\n" +
+	"\n" +
+	"_voltage_vB\x13\n" +
+	"\x11_water_distance_mB\x1a\n" +
+	"\x18_fire_station_distance_m\"\xb5\x01\n" +
+	"\n" +
+	"Provenance\x12\x18\n" +
+	"\aversion\x18\x01 \x01(\x05R\aversion\x12\x1c\n" +
+	"\ttimestamp\x18\x02 \x01(\tR\ttimestamp\x12\x1c\n" +
+	"\tchangeset\x18\x03 \x01(\x03R\tchangeset\x12\x12\n" +
+	"\x04user\x18\x04 \x01(\tR\x04user\x12\x16\n" +
+	"\x06source\x18\x05 \x01(\tR\x06source\x12%\n" +
+	"\x0edata_timestamp\x18\x06 \x01(\tR\rdataTimestamp\"\x90\x01\n" +
+	"\vAttribution\x12\x12\n" +
+	"\x04text\x18\x01 \x01(\tR\x04text\x12\x18\n" +
+	"\alicense\x18\x02 \x01(\tR\alicense\x12\x1f\n" +
+	"\vlicense_url\x18\x03 \x01(\tR\n" +
+	"licenseUrl\x122\n" +
+	"\asources\x18\x04 \x03(\v2\x18.riskplatform.DataSourceR\asources\"O\n" +
+	"\n" +
+	"DataSource\x12\x1a\n" +
+	"\bendpoint\x18\x01 \x01(\tR\bendpoint\x12%\n" +
+	"\x0edata_timestamp\x18\x02 \x01(\tR\rdataTimestamp\"\x9d\x02\n" +
 	"\x11GetAssetsResponse\x12+\n" +
 	"\x06assets\x18\x01 \x03(\v2\x13.riskplatform.AssetR\x06assets\x12\x1f\n" +
 	"\vtotal_count\x18\x02 \x01(\x05R\n" +
-	"totalCount\"0\n" +
+	"totalCount\x122\n" +
+	"\bcoverage\x18\x03 \x01(\v2\x16.riskplatform.CoverageR\bcoverage\x12;\n" +
+	"\vattribution\x18\x04 \x01(\v2\x19.riskplatform.AttributionR\vattribution\x12I\n" +
+	"\x14suppression_coverage\x18\x05 \x01(\v2\x16.riskplatform.CoverageR\x13suppressionCoverage\"\x8b\x01\n" +
+	"\x14StreamAssetsResponse\x120\n" +
+	"\x05batch\x18\x01 \x01(\v2\x18.riskplatform.AssetBatchH\x00R\x05batch\x126\n" +
+	"\asummary\x18\x02 \x01(\v2\x1a.riskplatform.AssetSummaryH\x00R\asummaryB\t\n" +
+	"\apayload\"9\n" +
+	"\n" +
+	"AssetBatch\x12+\n" +
+	"\x06assets\x18\x01 \x03(\v2\x13.riskplatform.AssetR\x06assets\"\x80\x03\n" +
+	"\fAssetSummary\x12\x1f\n" +
+	"\vtotal_count\x18\x01 \x01(\x05R\n" +
+	"totalCount\x12R\n" +
+	"\x0ecounts_by_type\x18\x02 \x03(\v2,.riskplatform.AssetSummary.CountsByTypeEntryR\fcountsByType\x122\n" +
+	"\bcoverage\x18\x03 \x01(\v2\x16.riskplatform.CoverageR\bcoverage\x12;\n" +
+	"\vattribution\x18\x04 \x01(\v2\x19.riskplatform.AttributionR\vattribution\x12I\n" +
+	"\x14suppression_coverage\x18\x05 \x01(\v2\x16.riskplatform.CoverageR\x13suppressionCoverage\x1a?\n" +
+	"\x11CountsByTypeEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\x05R\x05value:\x028\x01\"\xa6\x01\n" +
+	"\bCoverage\x12\x1f\n" +
+	"\vtiles_total\x18\x01 \x01(\x05R\n" +
+	"tilesTotal\x12!\n" +
+	"\ftiles_failed\x18\x02 \x01(\x05R\vtilesFailed\x12\x18\n" +
+	"\apartial\x18\x03 \x01(\bR\apartial\x12<\n" +
+	"\ffailed_tiles\x18\x04 \x03(\v2\x19.riskplatform.BoundingBoxR\vfailedTiles\"q\n" +
+	"\vBoundingBox\x12\x17\n" +
+	"\amin_lon\x18\x01 \x01(\x01R\x06minLon\x12\x17\n" +
+	"\amin_lat\x18\x02 \x01(\x01R\x06minLat\x12\x17\n" +
+	"\amax_lon\x18\x03 \x01(\x01R\x06maxLon\x12\x17\n" +
+	"\amax_lat\x18\x04 \x01(\x01R\x06maxLat\"?\n" +
+	"\x16InvalidateCacheRequest\x12%\n" +
+	"\x0eregion_geojson\x18\x01 \x01(\tR\rregionGeojson\"B\n" +
+	"\x17InvalidateCacheResponse\x12'\n" +
+	"\x0fentries_removed\x18\x01 \x01(\x05R\x0eentriesRemoved\"6\n" +
+	"\x13GetLandcoverRequest\x12\x1f\n" +
+	"\vaoi_geojson\x18\x01 \x01(\tR\n" +
+	"aoiGeojson\"\xca\x02\n" +
+	"\x14GetLandcoverResponse\x126\n" +
+	"\aclasses\x18\x01 \x03(\v2\x1c.riskplatform.FuelClassShareR\aclasses\x12\x1e\n" +
+	"\vaoi_area_m2\x18\x02 \x01(\x01R\taoiAreaM2\x12-\n" +
+	"\x12unclassified_share\x18\x03 \x01(\x01R\x11unclassifiedShare\x12:\n" +
+	"\bpolygons\x18\x04 \x03(\v2\x1e.riskplatform.LandcoverPolygonR\bpolygons\x122\n" +
+	"\bcoverage\x18\x05 \x01(\v2\x16.riskplatform.CoverageR\bcoverage\x12;\n" +
+	"\vattribution\x18\x06 \x01(\v2\x19.riskplatform.AttributionR\vattribution\"\x83\x01\n" +
+	"\x0eFuelClassShare\x12\x1d\n" +
+	"\n" +
+	"fuel_class\x18\x01 \x01(\tR\tfuelClass\x12\x14\n" +
+	"\x05share\x18\x02 \x01(\x01R\x05share\x12\x17\n" +
+	"\aarea_m2\x18\x03 \x01(\x01R\x06areaM2\x12#\n" +
+	"\rpolygon_count\x18\x04 \x01(\x05R\fpolygonCount\"\xa3\x02\n" +
+	"\x10LandcoverPolygon\x12\x1d\n" +
+	"\n" +
+	"fuel_class\x18\x01 \x01(\tR\tfuelClass\x12)\n" +
+	"\x10geometry_geojson\x18\x02 \x01(\tR\x0fgeometryGeojson\x12N\n" +
+	"\n" +
+	"properties\x18\x03 \x03(\v2..riskplatform.LandcoverPolygon.PropertiesEntryR\n" +
+	"properties\x12\x1d\n" +
+	"\n" +
+	"within_aoi\x18\x04 \x01(\bR\twithinAoi\x12\x17\n" +
+	"\aarea_m2\x18\x05 \x01(\x01R\x06areaM2\x1a=\n" +
+	"\x0fPropertiesEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"\xc5\x01\n" +
+	"\x12RoadNetworkRequest\x12\x1f\n" +
+	"\vaoi_geojson\x18\x01 \x01(\tR\n" +
+	"aoiGeojson\x12,\n" +
+	"\x12safe_zones_geojson\x18\x02 \x01(\tR\x10safeZonesGeojson\x12(\n" +
+	"\x0edead_end_min_m\x18\x03 \x01(\x01H\x00R\vdeadEndMinM\x88\x01\x01\x12#\n" +
+	"\rinclude_graph\x18\x04 \x01(\bR\fincludeGraphB\x11\n" +
+	"\x0f_dead_end_min_m\"\xae\x03\n" +
+	"\x13RoadNetworkResponse\x12\x1d\n" +
+	"\n" +
+	"node_count\x18\x01 \x01(\x05R\tnodeCount\x12\x1d\n" +
+	"\n" +
+	"edge_count\x18\x02 \x01(\x05R\tedgeCount\x12,\n" +
+	"\x05edges\x18\x03 \x03(\v2\x16.riskplatform.RoadEdgeR\x05edges\x12F\n" +
+	"\rsingle_egress\x18\x04 \x03(\v2!.riskplatform.EgressNeighbourhoodR\fsingleEgress\x126\n" +
+	"\tdead_ends\x18\x05 \x03(\v2\x19.riskplatform.DeadEndRoadR\bdeadEnds\x12:\n" +
+	"\tbuildings\x18\x06 \x03(\v2\x1c.riskplatform.BuildingEgressR\tbuildings\x122\n" +
+	"\bcoverage\x18\a \x01(\v2\x16.riskplatform.CoverageR\bcoverage\x12;\n" +
+	"\vattribution\x18\b \x01(\v2\x19.riskplatform.AttributionR\vattribution\"\xf1\x01\n" +
+	"\bRoadEdge\x12\x1b\n" +
+	"\tfrom_node\x18\x01 \x01(\x03R\bfromNode\x12\x17\n" +
+	"\ato_node\x18\x02 \x01(\x03R\x06toNode\x12\x15\n" +
+	"\x06way_id\x18\x03 \x01(\x03R\x05wayId\x12\x1d\n" +
+	"\n" +
+	"road_class\x18\x04 \x01(\tR\troadClass\x12\x1b\n" +
+	"\tspeed_kph\x18\x05 \x01(\x01R\bspeedKph\x12\x16\n" +
+	"\x06oneway\x18\x06 \x01(\bR\x06oneway\x12\x19\n" +
+	"\blength_m\x18\a \x01(\x01R\alengthM\x12)\n" +
+	"\x10geometry_geojson\x18\b \x01(\tR\x0fgeometryGeojson\"\xe2\x01\n" +
+	"\x13EgressNeighbourhood\x12\x1d\n" +
+	"\n" +
+	"routes_out\x18\x01 \x01(\x05R\troutesOut\x12\x1b\n" +
+	"\texit_node\x18\x02 \x01(\x03R\bexitNode\x12\x1e\n" +
+	"\vexit_way_id\x18\x03 \x01(\x03R\texitWayId\x12\x1d\n" +
+	"\n" +
+	"node_count\x18\x04 \x01(\x05R\tnodeCount\x12!\n" +
+	"\fbuilding_ids\x18\x05 \x03(\x03R\vbuildingIds\x12-\n" +
+	"\x04bbox\x18\x06 \x01(\v2\x19.riskplatform.BoundingBoxR\x04bbox\"l\n" +
+	"\vDeadEndRoad\x12\x17\n" +
+	"\away_ids\x18\x01 \x03(\x03R\x06wayIds\x12\x19\n" +
+	"\blength_m\x18\x02 \x01(\x01R\alengthM\x12)\n" +
+	"\x10geometry_geojson\x18\x03 \x01(\tR\x0fgeometryGeojson\"\xd9\x01\n" +
+	"\x0eBuildingEgress\x12\x19\n" +
+	"\bosm_type\x18\x01 \x01(\tR\aosmType\x12\x15\n" +
+	"\x06osm_id\x18\x02 \x01(\x03R\x05osmId\x121\n" +
+	"\x12network_distance_m\x18\x03 \x01(\x01H\x00R\x10networkDistanceM\x88\x01\x01\x12&\n" +
+	"\x0fsnap_distance_m\x18\x04 \x01(\x01R\rsnapDistanceM\x12#\n" +
+	"\rsingle_egress\x18\x05 \x01(\bR\fsingleEgressB\x15\n" +
+	"\x13_network_distance_m\"\xa2\x01\n" +
+	"\x17ResponseCoverageRequest\x12\x1f\n" +
+	"\vaoi_geojson\x18\x01 \x01(\tR\n" +
+	"aoiGeojson\x12!\n" +
+	"\fband_minutes\x18\x02 \x03(\x01R\vbandMinutes\x12.\n" +
+	"\x10coverage_minutes\x18\x03 \x01(\x01H\x00R\x0fcoverageMinutes\x88\x01\x01B\x13\n" +
+	"\x11_coverage_minutes\"\x88\x03\n" +
+	"\x18ResponseCoverageResponse\x12<\n" +
+	"\tbuildings\x18\x01 \x03(\v2\x1e.riskplatform.BuildingResponseR\tbuildings\x127\n" +
+	"\n" +
+	"isochrones\x18\x02 \x03(\v2\x17.riskplatform.IsochroneR\n" +
+	"isochrones\x12#\n" +
+	"\rfire_stations\x18\x03 \x01(\x05R\ffireStations\x12)\n" +
+	"\x10coverage_minutes\x18\x04 \x01(\x01R\x0fcoverageMinutes\x124\n" +
+	"\x16outside_coverage_count\x18\x05 \x01(\x05R\x14outsideCoverageCount\x122\n" +
+	"\bcoverage\x18\x06 \x01(\v2\x16.riskplatform.CoverageR\bcoverage\x12;\n" +
+	"\vattribution\x18\a \x01(\v2\x19.riskplatform.AttributionR\vattribution\"\xda\x01\n" +
+	"\x10BuildingResponse\x12\x19\n" +
+	"\bosm_type\x18\x01 \x01(\tR\aosmType\x12\x15\n" +
+	"\x06osm_id\x18\x02 \x01(\x03R\x05osmId\x12%\n" +
+	"\fdrive_time_s\x18\x03 \x01(\x01H\x00R\n" +
+	"driveTimeS\x88\x01\x01\x12\x12\n" +
+	"\x04band\x18\x04 \x01(\tR\x04band\x12)\n" +
+	"\x10outside_coverage\x18\x05 \x01(\bR\x0foutsideCoverage\x12\x1d\n" +
+	"\n" +
+	"station_id\x18\x06 \x01(\x03R\tstationIdB\x0f\n" +
+	"\r_drive_time_s\"P\n" +
+	"\tIsochrone\x12\x18\n" +
+	"\aminutes\x18\x01 \x01(\x01R\aminutes\x12)\n" +
+	"\x10geometry_geojson\x18\x02 \x01(\tR\x0fgeometryGeojson\"\xbb\x01\n" +
+	"\x10PowerSpanRequest\x12\x1f\n" +
+	"\vaoi_geojson\x18\x01 \x01(\tR\n" +
+	"aoiGeojson\x12'\n" +
+	"\rwind_from_deg\x18\x02 \x01(\x01H\x00R\vwindFromDeg\x88\x01\x01\x123\n" +
+	"\x13downwind_distance_m\x18\x03 \x01(\x01H\x01R\x11downwindDistanceM\x88\x01\x01B\x10\n" +
+	"\x0e_wind_from_degB\x16\n" +
+	"\x14_downwind_distance_m\"\xb3\x01\n" +
+	"\x11PowerSpanResponse\x12-\n" +
+	"\x05spans\x18\x01 \x03(\v2\x17.riskplatform.PowerSpanR\x05spans\x122\n" +
+	"\bcoverage\x18\x02 \x01(\v2\x16.riskplatform.CoverageR\bcoverage\x12;\n" +
+	"\vattribution\x18\x03 \x01(\v2\x19.riskplatform.AttributionR\vattribution\"\xc3\x04\n" +
+	"\tPowerSpan\x12\x12\n" +
+	"\x04rank\x18\x01 \x01(\x05R\x04rank\x12\x15\n" +
+	"\x06way_id\x18\x02 \x01(\x03R\x05wayId\x12!\n" +
+	"\ffrom_support\x18\x03 \x01(\x03R\vfromSupport\x12\x1d\n" +
+	"\n" +
+	"to_support\x18\x04 \x01(\x03R\ttoSupport\x12)\n" +
+	"\x10geometry_geojson\x18\x05 \x01(\tR\x0fgeometryGeojson\x12\x19\n" +
+	"\blength_m\x18\x06 \x01(\x01R\alengthM\x12 \n" +
+	"\tvoltage_v\x18\a \x01(\x01H\x00R\bvoltageV\x88\x01\x01\x12\x1a\n" +
+	"\boperator\x18\b \x01(\tR\boperator\x12H\n" +
+	"\vfuel_shares\x18\t \x03(\v2'.riskplatform.PowerSpan.FuelSharesEntryR\n" +
+	"fuelShares\x12(\n" +
+	"\x10fuel_class_under\x18\n" +
+	" \x01(\tR\x0efuelClassUnder\x12 \n" +
+	"\tslope_deg\x18\v \x01(\x01H\x01R\bslopeDeg\x88\x01\x01\x12-\n" +
+	"\x12buildings_downwind\x18\f \x01(\x05R\x11buildingsDownwind\x12%\n" +
+	"\x0eexposure_score\x18\r \x01(\x01R\rexposureScore\x1a=\n" +
+	"\x0fFuelSharesEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\x01R\x05value:\x028\x01B\f\n" +
+	"\n" +
+	"_voltage_vB\f\n" +
+	"\n" +
+	"_slope_deg\"0\n" +
 	"\rGetDemRequest\x12\x1f\n" +
 	"\vaoi_geojson\x18\x01 \x01(\tR\n" +
-	"aoiGeojson\"R\n" +
+	"aoiGeojson\"s\n" +
 	"\x0eGetDemResponse\x12$\n" +
-	"\x0elocal_dem_path\x18\x01 \x01(\tR\flocalDemPath\x12\x1a\n" +
-	"\bmetadata\x18\x02 \x01(\tR\bmetadata*V\n" +
+	"\x0elocal_dem_path\x18\x01 \x01(\tR\flocalDemPath\x125\n" +
+	"\bmetadata\x18\x03 \x01(\v2\x19.riskplatform.DemMetadataR\bmetadataJ\x04\b\x02\x10\x03\"\xf2\x03\n" +
+	"\vDemMetadata\x12\x14\n" +
+	"\x05width\x18\x01 \x01(\x05R\x05width\x12\x16\n" +
+	"\x06height\x18\x02 \x01(\x05R\x06height\x12\x10\n" +
+	"\x03crs\x18\x03 \x01(\tR\x03crs\x12\x12\n" +
+	"\x04epsg\x18\x04 \x01(\x05R\x04epsg\x12\x1f\n" +
+	"\vpixel_width\x18\x05 \x01(\x01R\n" +
+	"pixelWidth\x12!\n" +
+	"\fpixel_height\x18\x06 \x01(\x01R\vpixelHeight\x12!\n" +
+	"\fresolution_m\x18\a \x01(\x01R\vresolutionM\x121\n" +
+	"\x06bounds\x18\b \x01(\v2\x19.riskplatform.BoundingBoxR\x06bounds\x129\n" +
+	"\rnative_bounds\x18\t \x01(\v2\x14.riskplatform.ExtentR\fnativeBounds\x12\x1b\n" +
+	"\x06nodata\x18\n" +
+	" \x01(\x01H\x00R\x06nodata\x88\x01\x01\x12&\n" +
+	"\x0fmin_elevation_m\x18\v \x01(\x01R\rminElevationM\x12&\n" +
+	"\x0fmax_elevation_m\x18\f \x01(\x01R\rmaxElevationM\x12\x1f\n" +
+	"\vvalid_share\x18\r \x01(\x01R\n" +
+	"validShare\x12!\n" +
+	"\fsource_tiles\x18\x0e \x03(\tR\vsourceTilesB\t\n" +
+	"\a_nodata\"\\\n" +
+	"\x06Extent\x12\x13\n" +
+	"\x05min_x\x18\x01 \x01(\x01R\x04minX\x12\x13\n" +
+	"\x05min_y\x18\x02 \x01(\x01R\x04minY\x12\x13\n" +
+	"\x05max_x\x18\x03 \x01(\x01R\x04maxX\x12\x13\n" +
+	"\x05max_y\x18\x04 \x01(\x01R\x04maxY*V\n" +
 	"\tJobStatus\x12\v\n" +
 	"\aPENDING\x10\x00\x12\x12\n" +
 	"\x0eGATHERING_DATA\x10\x01\x12\x0e\n" +
@@ -604,12 +4188,25 @@ const file_services_proto_rawDesc = "" +
 	"PROCESSING\x10\x02\x12\f\n" +
 	"\bCOMPLETE\x10\x03\x12\n" +
 	"\n" +
-	"\x06FAILED\x10\x042\xc8\x01\n" +
+	"\x06FAILED\x10\x04*\\\n" +
+	"\vJobPriority\x12\x13\n" +
+	"\x0fPRIORITY_NORMAL\x10\x00\x12\x10\n" +
+	"\fPRIORITY_LOW\x10\x01\x12\x11\n" +
+	"\rPRIORITY_HIGH\x10\x02\x12\x13\n" +
+	"\x0fPRIORITY_URGENT\x10\x032\x82\x03\n" +
 	"\x13OrchestratorService\x12Z\n" +
 	"\x17CreateRiskAssessmentJob\x12\x1e.riskplatform.CreateJobRequest\x1a\x1f.riskplatform.CreateJobResponse\x12U\n" +
-	"\fGetJobStatus\x12!.riskplatform.GetJobStatusRequest\x1a\".riskplatform.GetJobStatusResponse2j\n" +
+	"\fGetJobStatus\x12!.riskplatform.GetJobStatusRequest\x1a\".riskplatform.GetJobStatusResponse\x12X\n" +
+	"\rGetJobResults\x12\".riskplatform.GetJobResultsRequest\x1a#.riskplatform.GetJobResultsResponse\x12^\n" +
+	"\x0fGetAssetHistory\x12$.riskplatform.GetAssetHistoryRequest\x1a%.riskplatform.GetAssetHistoryResponse2\xa0\x05\n" +
 	"\x15InfrastructureService\x12Q\n" +
-	"\x0eGetAssetsInAOI\x12\x1e.riskplatform.GetAssetsRequest\x1a\x1f.riskplatform.GetAssetsResponse2^\n" +
+	"\x0eGetAssetsInAOI\x12\x1e.riskplatform.GetAssetsRequest\x1a\x1f.riskplatform.GetAssetsResponse\x12Y\n" +
+	"\x11StreamAssetsInAOI\x12\x1e.riskplatform.GetAssetsRequest\x1a\".riskplatform.StreamAssetsResponse0\x01\x12^\n" +
+	"\x0fInvalidateCache\x12$.riskplatform.InvalidateCacheRequest\x1a%.riskplatform.InvalidateCacheResponse\x12Z\n" +
+	"\x11GetLandcoverInAOI\x12!.riskplatform.GetLandcoverRequest\x1a\".riskplatform.GetLandcoverResponse\x12Y\n" +
+	"\x12AnalyzeRoadNetwork\x12 .riskplatform.RoadNetworkRequest\x1a!.riskplatform.RoadNetworkResponse\x12h\n" +
+	"\x17AnalyzeResponseCoverage\x12%.riskplatform.ResponseCoverageRequest\x1a&.riskplatform.ResponseCoverageResponse\x12X\n" +
+	"\x15AnalyzePowerLineSpans\x12\x1e.riskplatform.PowerSpanRequest\x1a\x1f.riskplatform.PowerSpanResponse2^\n" +
 	"\x11TopographyService\x12I\n" +
 	"\fGetDemForAOI\x12\x1b.riskplatform.GetDemRequest\x1a\x1c.riskplatform.GetDemResponseB5Z3wildfire-ignition-risk-platform/api/proto/generatedb\x06proto3"
 
@@ -625,39 +4222,153 @@ func file_services_proto_rawDescGZIP() []byte {
 	return file_services_proto_rawDescData
 }
 
-var file_services_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
-var file_services_proto_msgTypes = make([]protoimpl.MessageInfo, 10)
+var file_services_proto_enumTypes = make([]protoimpl.EnumInfo, 2)
+var file_services_proto_msgTypes = make([]protoimpl.MessageInfo, 56)
 var file_services_proto_goTypes = []any{
-	(JobStatus)(0),               // 0: riskplatform.JobStatus
-	(*CreateJobRequest)(nil),     // 1: riskplatform.CreateJobRequest
-	(*CreateJobResponse)(nil),    // 2: riskplatform.CreateJobResponse
-	(*GetJobStatusRequest)(nil),  // 3: riskplatform.GetJobStatusRequest
-	(*GetJobStatusResponse)(nil), // 4: riskplatform.GetJobStatusResponse
-	(*GetAssetsRequest)(nil),     // 5: riskplatform.GetAssetsRequest
-	(*Asset)(nil),                // 6: riskplatform.Asset
-	(*GetAssetsResponse)(nil),    // 7: riskplatform.GetAssetsResponse
-	(*GetDemRequest)(nil),        // 8: riskplatform.GetDemRequest
-	(*GetDemResponse)(nil),       // 9: riskplatform.GetDemResponse
-	nil,                          // 10: riskplatform.Asset.PropertiesEntry
+	(JobStatus)(0),                   // 0: riskplatform.JobStatus
+	(JobPriority)(0),                 // 1: riskplatform.JobPriority
+	(*CreateJobRequest)(nil),         // 2: riskplatform.CreateJobRequest
+	(*RiskParameters)(nil),           // 3: riskplatform.RiskParameters
+	(*RiskWeights)(nil),              // 4: riskplatform.RiskWeights
+	(*RiskThresholds)(nil),           // 5: riskplatform.RiskThresholds
+	(*CreateJobResponse)(nil),        // 6: riskplatform.CreateJobResponse
+	(*GetJobStatusRequest)(nil),      // 7: riskplatform.GetJobStatusRequest
+	(*GetJobStatusResponse)(nil),     // 8: riskplatform.GetJobStatusResponse
+	(*GetJobResultsRequest)(nil),     // 9: riskplatform.GetJobResultsRequest
+	(*GetJobResultsResponse)(nil),    // 10: riskplatform.GetJobResultsResponse
+	(*JobResultSummary)(nil),         // 11: riskplatform.JobResultSummary
+	(*GetAssetHistoryRequest)(nil),   // 12: riskplatform.GetAssetHistoryRequest
+	(*GetAssetHistoryResponse)(nil),  // 13: riskplatform.GetAssetHistoryResponse
+	(*CanonicalAsset)(nil),           // 14: riskplatform.CanonicalAsset
+	(*AssetChange)(nil),              // 15: riskplatform.AssetChange
+	(*AssetRiskPoint)(nil),           // 16: riskplatform.AssetRiskPoint
+	(*GetAssetsRequest)(nil),         // 17: riskplatform.GetAssetsRequest
+	(*Asset)(nil),                    // 18: riskplatform.Asset
+	(*Provenance)(nil),               // 19: riskplatform.Provenance
+	(*Attribution)(nil),              // 20: riskplatform.Attribution
+	(*DataSource)(nil),               // 21: riskplatform.DataSource
+	(*GetAssetsResponse)(nil),        // 22: riskplatform.GetAssetsResponse
+	(*StreamAssetsResponse)(nil),     // 23: riskplatform.StreamAssetsResponse
+	(*AssetBatch)(nil),               // 24: riskplatform.AssetBatch
+	(*AssetSummary)(nil),             // 25: riskplatform.AssetSummary
+	(*Coverage)(nil),                 // 26: riskplatform.Coverage
+	(*BoundingBox)(nil),              // 27: riskplatform.BoundingBox
+	(*InvalidateCacheRequest)(nil),   // 28: riskplatform.InvalidateCacheRequest
+	(*InvalidateCacheResponse)(nil),  // 29: riskplatform.InvalidateCacheResponse
+	(*GetLandcoverRequest)(nil),      // 30: riskplatform.GetLandcoverRequest
+	(*GetLandcoverResponse)(nil),     // 31: riskplatform.GetLandcoverResponse
+	(*FuelClassShare)(nil),           // 32: riskplatform.FuelClassShare
+	(*LandcoverPolygon)(nil),         // 33: riskplatform.LandcoverPolygon
+	(*RoadNetworkRequest)(nil),       // 34: riskplatform.RoadNetworkRequest
+	(*RoadNetworkResponse)(nil),      // 35: riskplatform.RoadNetworkResponse
+	(*RoadEdge)(nil),                 // 36: riskplatform.RoadEdge
+	(*EgressNeighbourhood)(nil),      // 37: riskplatform.EgressNeighbourhood
+	(*DeadEndRoad)(nil),              // 38: riskplatform.DeadEndRoad
+	(*BuildingEgress)(nil),           // 39: riskplatform.BuildingEgress
+	(*ResponseCoverageRequest)(nil),  // 40: riskplatform.ResponseCoverageRequest
+	(*ResponseCoverageResponse)(nil), // 41: riskplatform.ResponseCoverageResponse
+	(*BuildingResponse)(nil),         // 42: riskplatform.BuildingResponse
+	(*Isochrone)(nil),                // 43: riskplatform.Isochrone
+	(*PowerSpanRequest)(nil),         // 44: riskplatform.PowerSpanRequest
+	(*PowerSpanResponse)(nil),        // 45: riskplatform.PowerSpanResponse
+	(*PowerSpan)(nil),                // 46: riskplatform.PowerSpan
+	(*GetDemRequest)(nil),            // 47: riskplatform.GetDemRequest
+	(*GetDemResponse)(nil),           // 48: riskplatform.GetDemResponse
+	(*DemMetadata)(nil),              // 49: riskplatform.DemMetadata
+	(*Extent)(nil),                   // 50: riskplatform.Extent
+	nil,                              // 51: riskplatform.CanonicalAsset.TagsEntry
+	nil,                              // 52: riskplatform.AssetChange.PreviousTagsEntry
+	nil,                              // 53: riskplatform.GetAssetsRequest.TagFiltersEntry
+	nil,                              // 54: riskplatform.Asset.PropertiesEntry
+	nil,                              // 55: riskplatform.AssetSummary.CountsByTypeEntry
+	nil,                              // 56: riskplatform.LandcoverPolygon.PropertiesEntry
+	nil,                              // 57: riskplatform.PowerSpan.FuelSharesEntry
 }
 var file_services_proto_depIdxs = []int32{
-	0,  // 0: riskplatform.CreateJobResponse.status:type_name -> riskplatform.JobStatus
-	0,  // 1: riskplatform.GetJobStatusResponse.status:type_name -> riskplatform.JobStatus
-	10, // 2: riskplatform.Asset.properties:type_name -> riskplatform.Asset.PropertiesEntry
-	6,  // 3: riskplatform.GetAssetsResponse.assets:type_name -> riskplatform.Asset
-	1,  // 4: riskplatform.OrchestratorService.CreateRiskAssessmentJob:input_type -> riskplatform.CreateJobRequest
-	3,  // 5: riskplatform.OrchestratorService.GetJobStatus:input_type -> riskplatform.GetJobStatusRequest
-	5,  // 6: riskplatform.InfrastructureService.GetAssetsInAOI:input_type -> riskplatform.GetAssetsRequest
-	8,  // 7: riskplatform.TopographyService.GetDemForAOI:input_type -> riskplatform.GetDemRequest
-	2,  // 8: riskplatform.OrchestratorService.CreateRiskAssessmentJob:output_type -> riskplatform.CreateJobResponse
-	4,  // 9: riskplatform.OrchestratorService.GetJobStatus:output_type -> riskplatform.GetJobStatusResponse
-	7,  // 10: riskplatform.InfrastructureService.GetAssetsInAOI:output_type -> riskplatform.GetAssetsResponse
-	9,  // 11: riskplatform.TopographyService.GetDemForAOI:output_type -> riskplatform.GetDemResponse
-	8,  // [8:12] is the sub-list for method output_type
-	4,  // [4:8] is the sub-list for method input_type
-	4,  // [4:4] is the sub-list for extension type_name
-	4,  // [4:4] is the sub-list for extension extendee
-	0,  // [0:4] is the sub-list for field type_name
+	3,  // 0: riskplatform.CreateJobRequest.risk_parameters:type_name -> riskplatform.RiskParameters
+	1,  // 1: riskplatform.CreateJobRequest.priority:type_name -> riskplatform.JobPriority
+	4,  // 2: riskplatform.RiskParameters.weights:type_name -> riskplatform.RiskWeights
+	5,  // 3: riskplatform.RiskParameters.thresholds:type_name -> riskplatform.RiskThresholds
+	0,  // 4: riskplatform.CreateJobResponse.status:type_name -> riskplatform.JobStatus
+	0,  // 5: riskplatform.GetJobStatusResponse.status:type_name -> riskplatform.JobStatus
+	1,  // 6: riskplatform.GetJobStatusResponse.priority:type_name -> riskplatform.JobPriority
+	0,  // 7: riskplatform.GetJobResultsResponse.status:type_name -> riskplatform.JobStatus
+	11, // 8: riskplatform.GetJobResultsResponse.summary:type_name -> riskplatform.JobResultSummary
+	20, // 9: riskplatform.GetJobResultsResponse.attribution:type_name -> riskplatform.Attribution
+	14, // 10: riskplatform.GetAssetHistoryResponse.asset:type_name -> riskplatform.CanonicalAsset
+	15, // 11: riskplatform.GetAssetHistoryResponse.changes:type_name -> riskplatform.AssetChange
+	16, // 12: riskplatform.GetAssetHistoryResponse.risk_history:type_name -> riskplatform.AssetRiskPoint
+	51, // 13: riskplatform.CanonicalAsset.tags:type_name -> riskplatform.CanonicalAsset.TagsEntry
+	52, // 14: riskplatform.AssetChange.previous_tags:type_name -> riskplatform.AssetChange.PreviousTagsEntry
+	53, // 15: riskplatform.GetAssetsRequest.tag_filters:type_name -> riskplatform.GetAssetsRequest.TagFiltersEntry
+	54, // 16: riskplatform.Asset.properties:type_name -> riskplatform.Asset.PropertiesEntry
+	27, // 17: riskplatform.Asset.bbox:type_name -> riskplatform.BoundingBox
+	19, // 18: riskplatform.Asset.provenance:type_name -> riskplatform.Provenance
+	21, // 19: riskplatform.Attribution.sources:type_name -> riskplatform.DataSource
+	18, // 20: riskplatform.GetAssetsResponse.assets:type_name -> riskplatform.Asset
+	26, // 21: riskplatform.GetAssetsResponse.coverage:type_name -> riskplatform.Coverage
+	20, // 22: riskplatform.GetAssetsResponse.attribution:type_name -> riskplatform.Attribution
+	26, // 23: riskplatform.GetAssetsResponse.suppression_coverage:type_name -> riskplatform.Coverage
+	24, // 24: riskplatform.StreamAssetsResponse.batch:type_name -> riskplatform.AssetBatch
+	25, // 25: riskplatform.StreamAssetsResponse.summary:type_name -> riskplatform.AssetSummary
+	18, // 26: riskplatform.AssetBatch.assets:type_name -> riskplatform.Asset
+	55, // 27: riskplatform.AssetSummary.counts_by_type:type_name -> riskplatform.AssetSummary.CountsByTypeEntry
+	26, // 28: riskplatform.AssetSummary.coverage:type_name -> riskplatform.Coverage
+	20, // 29: riskplatform.AssetSummary.attribution:type_name -> riskplatform.Attribution
+	26, // 30: riskplatform.AssetSummary.suppression_coverage:type_name -> riskplatform.Coverage
+	27, // 31: riskplatform.Coverage.failed_tiles:type_name -> riskplatform.BoundingBox
+	32, // 32: riskplatform.GetLandcoverResponse.classes:type_name -> riskplatform.FuelClassShare
+	33, // 33: riskplatform.GetLandcoverResponse.polygons:type_name -> riskplatform.LandcoverPolygon
+	26, // 34: riskplatform.GetLandcoverResponse.coverage:type_name -> riskplatform.Coverage
+	20, // 35: riskplatform.GetLandcoverResponse.attribution:type_name -> riskplatform.Attribution
+	56, // 36: riskplatform.LandcoverPolygon.properties:type_name -> riskplatform.LandcoverPolygon.PropertiesEntry
+	36, // 37: riskplatform.RoadNetworkResponse.edges:type_name -> riskplatform.RoadEdge
+	37, // 38: riskplatform.RoadNetworkResponse.single_egress:type_name -> riskplatform.EgressNeighbourhood
+	38, // 39: riskplatform.RoadNetworkResponse.dead_ends:type_name -> riskplatform.DeadEndRoad
+	39, // 40: riskplatform.RoadNetworkResponse.buildings:type_name -> riskplatform.BuildingEgress
+	26, // 41: riskplatform.RoadNetworkResponse.coverage:type_name -> riskplatform.Coverage
+	20, // 42: riskplatform.RoadNetworkResponse.attribution:type_name -> riskplatform.Attribution
+	27, // 43: riskplatform.EgressNeighbourhood.bbox:type_name -> riskplatform.BoundingBox
+	42, // 44: riskplatform.ResponseCoverageResponse.buildings:type_name -> riskplatform.BuildingResponse
+	43, // 45: riskplatform.ResponseCoverageResponse.isochrones:type_name -> riskplatform.Isochrone
+	26, // 46: riskplatform.ResponseCoverageResponse.coverage:type_name -> riskplatform.Coverage
+	20, // 47: riskplatform.ResponseCoverageResponse.attribution:type_name -> riskplatform.Attribution
+	46, // 48: riskplatform.PowerSpanResponse.spans:type_name -> riskplatform.PowerSpan
+	26, // 49: riskplatform.PowerSpanResponse.coverage:type_name -> riskplatform.Coverage
+	20, // 50: riskplatform.PowerSpanResponse.attribution:type_name -> riskplatform.Attribution
+	57, // 51: riskplatform.PowerSpan.fuel_shares:type_name -> riskplatform.PowerSpan.FuelSharesEntry
+	49, // 52: riskplatform.GetDemResponse.metadata:type_name -> riskplatform.DemMetadata
+	27, // 53: riskplatform.DemMetadata.bounds:type_name -> riskplatform.BoundingBox
+	50, // 54: riskplatform.DemMetadata.native_bounds:type_name -> riskplatform.Extent
+	2,  // 55: riskplatform.OrchestratorService.CreateRiskAssessmentJob:input_type -> riskplatform.CreateJobRequest
+	7,  // 56: riskplatform.OrchestratorService.GetJobStatus:input_type -> riskplatform.GetJobStatusRequest
+	9,  // 57: riskplatform.OrchestratorService.GetJobResults:input_type -> riskplatform.GetJobResultsRequest
+	12, // 58: riskplatform.OrchestratorService.GetAssetHistory:input_type -> riskplatform.GetAssetHistoryRequest
+	17, // 59: riskplatform.InfrastructureService.GetAssetsInAOI:input_type -> riskplatform.GetAssetsRequest
+	17, // 60: riskplatform.InfrastructureService.StreamAssetsInAOI:input_type -> riskplatform.GetAssetsRequest
+	28, // 61: riskplatform.InfrastructureService.InvalidateCache:input_type -> riskplatform.InvalidateCacheRequest
+	30, // 62: riskplatform.InfrastructureService.GetLandcoverInAOI:input_type -> riskplatform.GetLandcoverRequest
+	34, // 63: riskplatform.InfrastructureService.AnalyzeRoadNetwork:input_type -> riskplatform.RoadNetworkRequest
+	40, // 64: riskplatform.InfrastructureService.AnalyzeResponseCoverage:input_type -> riskplatform.ResponseCoverageRequest
+	44, // 65: riskplatform.InfrastructureService.AnalyzePowerLineSpans:input_type -> riskplatform.PowerSpanRequest
+	47, // 66: riskplatform.TopographyService.GetDemForAOI:input_type -> riskplatform.GetDemRequest
+	6,  // 67: riskplatform.OrchestratorService.CreateRiskAssessmentJob:output_type -> riskplatform.CreateJobResponse
+	8,  // 68: riskplatform.OrchestratorService.GetJobStatus:output_type -> riskplatform.GetJobStatusResponse
+	10, // 69: riskplatform.OrchestratorService.GetJobResults:output_type -> riskplatform.GetJobResultsResponse
+	13, // 70: riskplatform.OrchestratorService.GetAssetHistory:output_type -> riskplatform.GetAssetHistoryResponse
+	22, // 71: riskplatform.InfrastructureService.GetAssetsInAOI:output_type -> riskplatform.GetAssetsResponse
+	23, // 72: riskplatform.InfrastructureService.StreamAssetsInAOI:output_type -> riskplatform.StreamAssetsResponse
+	29, // 73: riskplatform.InfrastructureService.InvalidateCache:output_type -> riskplatform.InvalidateCacheResponse
+	31, // 74: riskplatform.InfrastructureService.GetLandcoverInAOI:output_type -> riskplatform.GetLandcoverResponse
+	35, // 75: riskplatform.InfrastructureService.AnalyzeRoadNetwork:output_type -> riskplatform.RoadNetworkResponse
+	41, // 76: riskplatform.InfrastructureService.AnalyzeResponseCoverage:output_type -> riskplatform.ResponseCoverageResponse
+	45, // 77: riskplatform.InfrastructureService.AnalyzePowerLineSpans:output_type -> riskplatform.PowerSpanResponse
+	48, // 78: riskplatform.TopographyService.GetDemForAOI:output_type -> riskplatform.GetDemResponse
+	67, // [67:79] is the sub-list for method output_type
+	55, // [55:67] is the sub-list for method input_type
+	55, // [55:55] is the sub-list for extension type_name
+	55, // [55:55] is the sub-list for extension extendee
+	0,  // [0:55] is the sub-list for field type_name
 }
 
 func init() { file_services_proto_init() }
@@ -665,13 +4376,25 @@ func file_services_proto_init() {
 	if File_services_proto != nil {
 		return
 	}
+	file_services_proto_msgTypes[16].OneofWrappers = []any{}
+	file_services_proto_msgTypes[21].OneofWrappers = []any{
+		(*StreamAssetsResponse_Batch)(nil),
+		(*StreamAssetsResponse_Summary)(nil),
+	}
+	file_services_proto_msgTypes[32].OneofWrappers = []any{}
+	file_services_proto_msgTypes[37].OneofWrappers = []any{}
+	file_services_proto_msgTypes[38].OneofWrappers = []any{}
+	file_services_proto_msgTypes[40].OneofWrappers = []any{}
+	file_services_proto_msgTypes[42].OneofWrappers = []any{}
+	file_services_proto_msgTypes[44].OneofWrappers = []any{}
+	file_services_proto_msgTypes[47].OneofWrappers = []any{}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_services_proto_rawDesc), len(file_services_proto_rawDesc)),
-			NumEnums:      1,
-			NumMessages:   10,
+			NumEnums:      2,
+			NumMessages:   56,
 			NumExtensions: 0,
 			NumServices:   3,
 		},
@@ -21,6 +21,8 @@ const _ = grpc.SupportPackageIsVersion9
 const (
 	OrchestratorService_CreateRiskAssessmentJob_FullMethodName = "/riskplatform.OrchestratorService/CreateRiskAssessmentJob"
 	OrchestratorService_GetJobStatus_FullMethodName            = "/riskplatform.OrchestratorService/GetJobStatus"
+	OrchestratorService_GetJobResults_FullMethodName           = "/riskplatform.OrchestratorService/GetJobResults"
+	OrchestratorService_GetAssetHistory_FullMethodName         = "/riskplatform.OrchestratorService/GetAssetHistory"
 )
 
 // OrchestratorServiceClient is the client API for OrchestratorService service.
@@ -31,6 +33,8 @@ const (
 type OrchestratorServiceClient interface {
 	CreateRiskAssessmentJob(ctx context.Context, in *CreateJobRequest, opts ...grpc.CallOption) (*CreateJobResponse, error)
 	GetJobStatus(ctx context.Context, in *GetJobStatusRequest, opts ...grpc.CallOption) (*GetJobStatusResponse, error)
+	GetJobResults(ctx context.Context, in *GetJobResultsRequest, opts ...grpc.CallOption) (*GetJobResultsResponse, error)
+	GetAssetHistory(ctx context.Context, in *GetAssetHistoryRequest, opts ...grpc.CallOption) (*GetAssetHistoryResponse, error)
 }
 
 type orchestratorServiceClient struct {
@@ -61,6 +65,26 @@ func (c *orchestratorServiceClient) GetJobStatus(ctx context.Context, in *GetJob
 	return out, nil
 }
 
+func (c *orchestratorServiceClient) GetJobResults(ctx context.Context, in *GetJobResultsRequest, opts ...grpc.CallOption) (*GetJobResultsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetJobResultsResponse)
+	err := c.cc.Invoke(ctx, OrchestratorService_GetJobResults_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *orchestratorServiceClient) GetAssetHistory(ctx context.Context, in *GetAssetHistoryRequest, opts ...grpc.CallOption) (*GetAssetHistoryResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetAssetHistoryResponse)
+	err := c.cc.Invoke(ctx, OrchestratorService_GetAssetHistory_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // OrchestratorServiceServer is the server API for OrchestratorService service.
 // All implementations must embed UnimplementedOrchestratorServiceServer
 // for forward compatibility.
@@ -69,6 +93,8 @@ func (c *orchestratorServiceClient) GetJobStatus(ctx context.Context, in *GetJob
 type OrchestratorServiceServer interface {
 	CreateRiskAssessmentJob(context.Context, *CreateJobRequest) (*CreateJobResponse, error)
 	GetJobStatus(context.Context, *GetJobStatusRequest) (*GetJobStatusResponse, error)
+	GetJobResults(context.Context, *GetJobResultsRequest) (*GetJobResultsResponse, error)
+	GetAssetHistory(context.Context, *GetAssetHistoryRequest) (*GetAssetHistoryResponse, error)
 	mustEmbedUnimplementedOrchestratorServiceServer()
 }
 
@@ -85,6 +111,12 @@ func (UnimplementedOrchestratorServiceServer) CreateRiskAssessmentJob(context.Co
 func (UnimplementedOrchestratorServiceServer) GetJobStatus(context.Context, *GetJobStatusRequest) (*GetJobStatusResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetJobStatus not implemented")
 }
+func (UnimplementedOrchestratorServiceServer) GetJobResults(context.Context, *GetJobResultsRequest) (*GetJobResultsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetJobResults not implemented")
+}
+func (UnimplementedOrchestratorServiceServer) GetAssetHistory(context.Context, *GetAssetHistoryRequest) (*GetAssetHistoryResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetAssetHistory not implemented")
+}
 func (UnimplementedOrchestratorServiceServer) mustEmbedUnimplementedOrchestratorServiceServer() {}
 func (UnimplementedOrchestratorServiceServer) testEmbeddedByValue()                             {}
 
@@ -142,6 +174,42 @@ func _OrchestratorService_GetJobStatus_Handler(srv interface{}, ctx context.Cont
 	return interceptor(ctx, in, info, handler)
 }
 
+func _OrchestratorService_GetJobResults_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetJobResultsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OrchestratorServiceServer).GetJobResults(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: OrchestratorService_GetJobResults_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OrchestratorServiceServer).GetJobResults(ctx, req.(*GetJobResultsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _OrchestratorService_GetAssetHistory_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetAssetHistoryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OrchestratorServiceServer).GetAssetHistory(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: OrchestratorService_GetAssetHistory_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OrchestratorServiceServer).GetAssetHistory(ctx, req.(*GetAssetHistoryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // OrchestratorService_ServiceDesc is the grpc.ServiceDesc for OrchestratorService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -157,13 +225,27 @@ var OrchestratorService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "GetJobStatus",
 			Handler:    _OrchestratorService_GetJobStatus_Handler,
 		},
+		{
+			MethodName: "GetJobResults",
+			Handler:    _OrchestratorService_GetJobResults_Handler,
+		},
+		{
+			MethodName: "GetAssetHistory",
+			Handler:    _OrchestratorService_GetAssetHistory_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "services.proto",
 }
 
 const (
-	InfrastructureService_GetAssetsInAOI_FullMethodName = "/riskplatform.InfrastructureService/GetAssetsInAOI"
+	InfrastructureService_GetAssetsInAOI_FullMethodName          = "/riskplatform.InfrastructureService/GetAssetsInAOI"
+	InfrastructureService_StreamAssetsInAOI_FullMethodName       = "/riskplatform.InfrastructureService/StreamAssetsInAOI"
+	InfrastructureService_InvalidateCache_FullMethodName         = "/riskplatform.InfrastructureService/InvalidateCache"
+	InfrastructureService_GetLandcoverInAOI_FullMethodName       = "/riskplatform.InfrastructureService/GetLandcoverInAOI"
+	InfrastructureService_AnalyzeRoadNetwork_FullMethodName      = "/riskplatform.InfrastructureService/AnalyzeRoadNetwork"
+	InfrastructureService_AnalyzeResponseCoverage_FullMethodName = "/riskplatform.InfrastructureService/AnalyzeResponseCoverage"
+	InfrastructureService_AnalyzePowerLineSpans_FullMethodName   = "/riskplatform.InfrastructureService/AnalyzePowerLineSpans"
 )
 
 // InfrastructureServiceClient is the client API for InfrastructureService service.
@@ -173,6 +255,12 @@ const (
 // Service to fetch infrastructure data
 type InfrastructureServiceClient interface {
 	GetAssetsInAOI(ctx context.Context, in *GetAssetsRequest, opts ...grpc.CallOption) (*GetAssetsResponse, error)
+	StreamAssetsInAOI(ctx context.Context, in *GetAssetsRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[StreamAssetsResponse], error)
+	InvalidateCache(ctx context.Context, in *InvalidateCacheRequest, opts ...grpc.CallOption) (*InvalidateCacheResponse, error)
+	GetLandcoverInAOI(ctx context.Context, in *GetLandcoverRequest, opts ...grpc.CallOption) (*GetLandcoverResponse, error)
+	AnalyzeRoadNetwork(ctx context.Context, in *RoadNetworkRequest, opts ...grpc.CallOption) (*RoadNetworkResponse, error)
+	AnalyzeResponseCoverage(ctx context.Context, in *ResponseCoverageRequest, opts ...grpc.CallOption) (*ResponseCoverageResponse, error)
+	AnalyzePowerLineSpans(ctx context.Context, in *PowerSpanRequest, opts ...grpc.CallOption) (*PowerSpanResponse, error)
 }
 
 type infrastructureServiceClient struct {
@@ -193,6 +281,75 @@ func (c *infrastructureServiceClient) GetAssetsInAOI(ctx context.Context, in *Ge
 	return out, nil
 }
 
+func (c *infrastructureServiceClient) StreamAssetsInAOI(ctx context.Context, in *GetAssetsRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[StreamAssetsResponse], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &InfrastructureService_ServiceDesc.Streams[0], InfrastructureService_StreamAssetsInAOI_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[GetAssetsRequest, StreamAssetsResponse]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type InfrastructureService_StreamAssetsInAOIClient = grpc.ServerStreamingClient[StreamAssetsResponse]
+
+func (c *infrastructureServiceClient) InvalidateCache(ctx context.Context, in *InvalidateCacheRequest, opts ...grpc.CallOption) (*InvalidateCacheResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(InvalidateCacheResponse)
+	err := c.cc.Invoke(ctx, InfrastructureService_InvalidateCache_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *infrastructureServiceClient) GetLandcoverInAOI(ctx context.Context, in *GetLandcoverRequest, opts ...grpc.CallOption) (*GetLandcoverResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetLandcoverResponse)
+	err := c.cc.Invoke(ctx, InfrastructureService_GetLandcoverInAOI_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *infrastructureServiceClient) AnalyzeRoadNetwork(ctx context.Context, in *RoadNetworkRequest, opts ...grpc.CallOption) (*RoadNetworkResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RoadNetworkResponse)
+	err := c.cc.Invoke(ctx, InfrastructureService_AnalyzeRoadNetwork_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *infrastructureServiceClient) AnalyzeResponseCoverage(ctx context.Context, in *ResponseCoverageRequest, opts ...grpc.CallOption) (*ResponseCoverageResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ResponseCoverageResponse)
+	err := c.cc.Invoke(ctx, InfrastructureService_AnalyzeResponseCoverage_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *infrastructureServiceClient) AnalyzePowerLineSpans(ctx context.Context, in *PowerSpanRequest, opts ...grpc.CallOption) (*PowerSpanResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(PowerSpanResponse)
+	err := c.cc.Invoke(ctx, InfrastructureService_AnalyzePowerLineSpans_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // InfrastructureServiceServer is the server API for InfrastructureService service.
 // All implementations must embed UnimplementedInfrastructureServiceServer
 // for forward compatibility.
@@ -200,6 +357,12 @@ func (c *infrastructureServiceClient) GetAssetsInAOI(ctx context.Context, in *Ge
 // Service to fetch infrastructure data
 type InfrastructureServiceServer interface {
 	GetAssetsInAOI(context.Context, *GetAssetsRequest) (*GetAssetsResponse, error)
+	StreamAssetsInAOI(*GetAssetsRequest, grpc.ServerStreamingServer[StreamAssetsResponse]) error
+	InvalidateCache(context.Context, *InvalidateCacheRequest) (*InvalidateCacheResponse, error)
+	GetLandcoverInAOI(context.Context, *GetLandcoverRequest) (*GetLandcoverResponse, error)
+	AnalyzeRoadNetwork(context.Context, *RoadNetworkRequest) (*RoadNetworkResponse, error)
+	AnalyzeResponseCoverage(context.Context, *ResponseCoverageRequest) (*ResponseCoverageResponse, error)
+	AnalyzePowerLineSpans(context.Context, *PowerSpanRequest) (*PowerSpanResponse, error)
 	mustEmbedUnimplementedInfrastructureServiceServer()
 }
 
@@ -213,6 +376,24 @@ type UnimplementedInfrastructureServiceServer struct{}
 func (UnimplementedInfrastructureServiceServer) GetAssetsInAOI(context.Context, *GetAssetsRequest) (*GetAssetsResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetAssetsInAOI not implemented")
 }
+func (UnimplementedInfrastructureServiceServer) StreamAssetsInAOI(*GetAssetsRequest, grpc.ServerStreamingServer[StreamAssetsResponse]) error {
+	return status.Errorf(codes.Unimplemented, "method StreamAssetsInAOI not implemented")
+}
+func (UnimplementedInfrastructureServiceServer) InvalidateCache(context.Context, *InvalidateCacheRequest) (*InvalidateCacheResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method InvalidateCache not implemented")
+}
+func (UnimplementedInfrastructureServiceServer) GetLandcoverInAOI(context.Context, *GetLandcoverRequest) (*GetLandcoverResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetLandcoverInAOI not implemented")
+}
+func (UnimplementedInfrastructureServiceServer) AnalyzeRoadNetwork(context.Context, *RoadNetworkRequest) (*RoadNetworkResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AnalyzeRoadNetwork not implemented")
+}
+func (UnimplementedInfrastructureServiceServer) AnalyzeResponseCoverage(context.Context, *ResponseCoverageRequest) (*ResponseCoverageResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AnalyzeResponseCoverage not implemented")
+}
+func (UnimplementedInfrastructureServiceServer) AnalyzePowerLineSpans(context.Context, *PowerSpanRequest) (*PowerSpanResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AnalyzePowerLineSpans not implemented")
+}
 func (UnimplementedInfrastructureServiceServer) mustEmbedUnimplementedInfrastructureServiceServer() {}
 func (UnimplementedInfrastructureServiceServer) testEmbeddedByValue()                               {}
 
@@ -252,6 +433,107 @@ func _InfrastructureService_GetAssetsInAOI_Handler(srv interface{}, ctx context.
 	return interceptor(ctx, in, info, handler)
 }
 
+func _InfrastructureService_StreamAssetsInAOI_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(GetAssetsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(InfrastructureServiceServer).StreamAssetsInAOI(m, &grpc.GenericServerStream[GetAssetsRequest, StreamAssetsResponse]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type InfrastructureService_StreamAssetsInAOIServer = grpc.ServerStreamingServer[StreamAssetsResponse]
+
+func _InfrastructureService_InvalidateCache_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(InvalidateCacheRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(InfrastructureServiceServer).InvalidateCache(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: InfrastructureService_InvalidateCache_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(InfrastructureServiceServer).InvalidateCache(ctx, req.(*InvalidateCacheRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _InfrastructureService_GetLandcoverInAOI_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetLandcoverRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(InfrastructureServiceServer).GetLandcoverInAOI(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: InfrastructureService_GetLandcoverInAOI_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(InfrastructureServiceServer).GetLandcoverInAOI(ctx, req.(*GetLandcoverRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _InfrastructureService_AnalyzeRoadNetwork_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RoadNetworkRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(InfrastructureServiceServer).AnalyzeRoadNetwork(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: InfrastructureService_AnalyzeRoadNetwork_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(InfrastructureServiceServer).AnalyzeRoadNetwork(ctx, req.(*RoadNetworkRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _InfrastructureService_AnalyzeResponseCoverage_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ResponseCoverageRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(InfrastructureServiceServer).AnalyzeResponseCoverage(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: InfrastructureService_AnalyzeResponseCoverage_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(InfrastructureServiceServer).AnalyzeResponseCoverage(ctx, req.(*ResponseCoverageRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _InfrastructureService_AnalyzePowerLineSpans_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PowerSpanRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(InfrastructureServiceServer).AnalyzePowerLineSpans(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: InfrastructureService_AnalyzePowerLineSpans_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(InfrastructureServiceServer).AnalyzePowerLineSpans(ctx, req.(*PowerSpanRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // InfrastructureService_ServiceDesc is the grpc.ServiceDesc for InfrastructureService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -263,8 +545,34 @@ var InfrastructureService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "GetAssetsInAOI",
 			Handler:    _InfrastructureService_GetAssetsInAOI_Handler,
 		},
+		{
+			MethodName: "InvalidateCache",
+			Handler:    _InfrastructureService_InvalidateCache_Handler,
+		},
+		{
+			MethodName: "GetLandcoverInAOI",
+			Handler:    _InfrastructureService_GetLandcoverInAOI_Handler,
+		},
+		{
+			MethodName: "AnalyzeRoadNetwork",
+			Handler:    _InfrastructureService_AnalyzeRoadNetwork_Handler,
+		},
+		{
+			MethodName: "AnalyzeResponseCoverage",
+			Handler:    _InfrastructureService_AnalyzeResponseCoverage_Handler,
+		},
+		{
+			MethodName: "AnalyzePowerLineSpans",
+			Handler:    _InfrastructureService_AnalyzePowerLineSpans_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "StreamAssetsInAOI",
+			Handler:       _InfrastructureService_StreamAssetsInAOI_Handler,
+			ServerStreams: true,
+		},
 	},
-	Streams:  []grpc.StreamDesc{},
 	Metadata: "services.proto",
 }
 
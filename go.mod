@@ -9,6 +9,7 @@ require (
 	github.com/joho/godotenv v1.4.0
 	github.com/lib/pq v1.10.9
 	github.com/segmentio/kafka-go v0.4.47
+	golang.org/x/image v0.25.0
 	google.golang.org/grpc v1.73.0
 	google.golang.org/protobuf v1.36.6
 )
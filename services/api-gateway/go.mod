@@ -10,6 +10,8 @@ require (
 	google.golang.org/grpc v1.59.0
 )
 
+require github.com/google/uuid v1.3.1
+
 require (
 	github.com/bytedance/sonic v1.9.1 // indirect
 	github.com/chenzhuoyu/base64x v0.0.0-20221115062448-fe3a3abad311 // indirect
@@ -5,16 +5,22 @@ import (
 	"encoding/json"
 	"log"
 	"net/http"
+	"strconv"
+	"strings"
 	"sync"
 	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
 
 	"api-gateway/config"
 	"wildfire-risk-platform/api/proto/generated"
+	"wildfire-risk-platform/shared/database/models"
+	"wildfire-risk-platform/shared/risk"
 )
 
 type RiskAssessmentHandler struct {
@@ -27,7 +33,30 @@ type RiskAssessmentHandler struct {
 
 // Request/Response structures for REST API
 type CreateJobRequest struct {
-	AOI AOIGeometry `json:"aoi" binding:"required"`
+	AOI            AOIGeometry     `json:"aoi" binding:"required"`
+	ForceRefresh   bool            `json:"force_refresh"`
+	RiskParameters *RiskParameters `json:"risk_parameters"`
+	Priority       string          `json:"priority"` // low, normal, high or urgent
+}
+
+// RiskParameters overrides the default scoring; omitted blocks keep their defaults
+type RiskParameters struct {
+	Model      string          `json:"model"`
+	Weights    *RiskWeights    `json:"weights"`
+	Thresholds *RiskThresholds `json:"thresholds"`
+	AssetTypes []string        `json:"asset_types"`
+}
+
+type RiskWeights struct {
+	Vegetation float64 `json:"vegetation"`
+	Slope      float64 `json:"slope"`
+	Wind       float64 `json:"wind"`
+}
+
+type RiskThresholds struct {
+	Low    float64 `json:"low"`
+	Medium float64 `json:"medium"`
+	High   float64 `json:"high"`
 }
 
 type AOIGeometry struct {
@@ -36,17 +65,79 @@ type AOIGeometry struct {
 }
 
 type CreateJobResponse struct {
-	JobID   string `json:"job_id"`
-	Status  string `json:"status"`
-	Message string `json:"message"`
+	JobID           string `json:"job_id"`
+	Status          string `json:"status"`
+	Message         string `json:"message"`
+	ResultsReused   bool   `json:"results_reused"`
+	ReusedFromJobID string `json:"reused_from_job_id,omitempty"`
 }
 
 type JobStatusResponse struct {
-	JobID     string `json:"job_id"`
-	Status    string `json:"status"`
-	Message   string `json:"message"`
-	CreatedAt int64  `json:"created_at"`
-	UpdatedAt int64  `json:"updated_at"`
+	JobID         string       `json:"job_id"`
+	Status        string       `json:"status"`
+	Message       string       `json:"message"`
+	CreatedAt     int64        `json:"created_at"`
+	UpdatedAt     int64        `json:"updated_at"`
+	QueuePosition int32        `json:"queue_position"`
+	Priority      string       `json:"priority"`
+	Progress      *JobProgress `json:"progress,omitempty"`
+}
+
+// JobProgress is reported for large AOIs that were split into child jobs
+type JobProgress struct {
+	ChildJobsTotal    int32 `json:"child_jobs_total"`
+	ChildJobsComplete int32 `json:"child_jobs_complete"`
+}
+
+type JobResultsResponse struct {
+	JobID       string             `json:"job_id"`
+	Status      string             `json:"status"`
+	Message     string             `json:"message"`
+	Results     *JobResults        `json:"results"` // null until the job is complete
+	Attribution models.Attribution `json:"attribution"`
+}
+
+type JobResults struct {
+	TotalAssets      int32   `json:"total_assets"`
+	HighRiskAssets   int32   `json:"high_risk_assets"`
+	MediumRiskAssets int32   `json:"medium_risk_assets"`
+	LowRiskAssets    int32   `json:"low_risk_assets"`
+	DownloadURL      *string `json:"download_url"`
+}
+
+// AssetHistoryResponse is a registry asset with its changes and its risk
+// scores across jobs, oldest first
+type AssetHistoryResponse struct {
+	CanonicalAssetID int64             `json:"canonical_asset_id"`
+	IdentityKey      string            `json:"identity_key"`
+	AssetType        string            `json:"asset_type"`
+	Geometry         json.RawMessage   `json:"geometry"`
+	Tags             map[string]string `json:"tags"`
+	Version          int32             `json:"version"`
+	FirstSeenJobID   string            `json:"first_seen_job_id,omitempty"`
+	LastSeenJobID    string            `json:"last_seen_job_id,omitempty"`
+	FirstSeenAt      int64             `json:"first_seen_at"`
+	LastSeenAt       int64             `json:"last_seen_at"`
+	Changes          []AssetChange     `json:"changes"`
+	RiskHistory      []AssetRiskPoint  `json:"risk_history"`
+}
+
+type AssetChange struct {
+	JobID            string            `json:"job_id,omitempty"`
+	ChangeType       string            `json:"change_type"`
+	Version          int32             `json:"version"`
+	PreviousGeometry json.RawMessage   `json:"previous_geometry,omitempty"`
+	PreviousTags     map[string]string `json:"previous_tags,omitempty"`
+	ChangedTags      []string          `json:"changed_tags,omitempty"`
+	ChangedAt        int64             `json:"changed_at"`
+}
+
+type AssetRiskPoint struct {
+	JobID             string  `json:"job_id"`
+	OverallRiskScore  float64 `json:"overall_risk_score"`
+	ModelName         string  `json:"model_name"`
+	ModelVersion      string  `json:"model_version"`
+	AnalysisTimestamp int64   `json:"analysis_timestamp"`
 }
 
 type ErrorResponse struct {
@@ -55,6 +146,50 @@ type ErrorResponse struct {
 	Code    int    `json:"code"`
 }
 
+func (p *RiskParameters) toProto() *generated.RiskParameters {
+	if p == nil {
+		return nil
+	}
+
+	params := &generated.RiskParameters{
+		Model:      p.Model,
+		AssetTypes: p.AssetTypes,
+	}
+	if p.Weights != nil {
+		params.Weights = &generated.RiskWeights{
+			Vegetation: p.Weights.Vegetation,
+			Slope:      p.Weights.Slope,
+			Wind:       p.Weights.Wind,
+		}
+	}
+	if p.Thresholds != nil {
+		params.Thresholds = &generated.RiskThresholds{
+			Low:    p.Thresholds.Low,
+			Medium: p.Thresholds.Medium,
+			High:   p.Thresholds.High,
+		}
+	}
+
+	return params
+}
+
+func parsePriority(p string) (generated.JobPriority, bool) {
+	if p == "" {
+		return generated.JobPriority_PRIORITY_NORMAL, true
+	}
+	v, ok := generated.JobPriority_value["PRIORITY_"+strings.ToUpper(p)]
+	return generated.JobPriority(v), ok
+}
+
+// submitterID identifies the client for fair scheduling. Callers can name
+// themselves with X-Submitter-ID; otherwise the client IP is used.
+func submitterID(c *gin.Context) string {
+	if id := c.GetHeader("X-Submitter-ID"); id != "" {
+		return id
+	}
+	return c.ClientIP()
+}
+
 func NewRiskAssessmentHandler(cfg *config.Config) *RiskAssessmentHandler {
 	// Lazy start up to handle orchestrator down error
 	return &RiskAssessmentHandler{
@@ -119,6 +254,26 @@ func (h *RiskAssessmentHandler) CreateJob(c *gin.Context) {
 	aoiGeoJSON := string(aoiBytes)
 	log.Printf("Received job request with AOI: %s", aoiGeoJSON)
 
+	priority, ok := parsePriority(req.Priority)
+	if !ok {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid priority",
+			Message: "priority must be one of low, normal, high or urgent",
+			Code:    http.StatusBadRequest,
+		})
+		return
+	}
+
+	riskParams := req.RiskParameters.toProto()
+	if err := risk.ParametersFromProto(riskParams).Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid risk parameters",
+			Message: err.Error(),
+			Code:    http.StatusBadRequest,
+		})
+		return
+	}
+
 	if err := h.ensureGRPCConnection(); err != nil {
 		log.Printf("Failed to connect to orchestrator: %v", err)
 
@@ -136,7 +291,11 @@ func (h *RiskAssessmentHandler) CreateJob(c *gin.Context) {
 	}
 
 	grpcReq := &generated.CreateJobRequest{
-		AoiGeojson: aoiGeoJSON,
+		AoiGeojson:     aoiGeoJSON,
+		ForceRefresh:   req.ForceRefresh,
+		RiskParameters: riskParams,
+		Priority:       priority,
+		Submitter:      submitterID(c),
 	}
 
 	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
@@ -144,19 +303,16 @@ func (h *RiskAssessmentHandler) CreateJob(c *gin.Context) {
 
 	grpcResp, err := h.orchestratorClient.CreateRiskAssessmentJob(ctx, grpcReq)
 	if err != nil {
-		log.Printf("gRPC call failed: %v", err)
-		c.JSON(http.StatusInternalServerError, ErrorResponse{
-			Error:   "Internal server error",
-			Message: "Failed to create risk assessment job",
-			Code:    http.StatusInternalServerError,
-		})
+		writeGRPCError(c, err, "Failed to create risk assessment job")
 		return
 	}
 
 	response := CreateJobResponse{
-		JobID:   grpcResp.JobId,
-		Status:  grpcResp.Status.String(),
-		Message: grpcResp.Message,
+		JobID:           grpcResp.JobId,
+		Status:          grpcResp.Status.String(),
+		Message:         grpcResp.Message,
+		ResultsReused:   grpcResp.ResultsReused,
+		ReusedFromJobID: grpcResp.ReusedFromJobId,
 	}
 
 	c.JSON(http.StatusAccepted, response)
@@ -186,6 +342,7 @@ func (h *RiskAssessmentHandler) GetJobStatus(c *gin.Context) {
 			Message:   "Job is being processed (MOCK MODE - orchestrator unavailable)",
 			CreatedAt: time.Now().Unix(),
 			UpdatedAt: time.Now().Unix(),
+			Priority:  "normal",
 		}
 
 		c.JSON(http.StatusOK, response)
@@ -203,22 +360,26 @@ func (h *RiskAssessmentHandler) GetJobStatus(c *gin.Context) {
 
 	grpcResp, err := h.orchestratorClient.GetJobStatus(ctx, grpcReq)
 	if err != nil {
-		log.Printf("gRPC call failed: %v", err)
-		c.JSON(http.StatusInternalServerError, ErrorResponse{
-			Error:   "Internal server error",
-			Message: "Failed to get job status",
-			Code:    http.StatusInternalServerError,
-		})
+		writeGRPCError(c, err, "Failed to get job status")
 		return
 	}
 
 	// Convert gRPC response to REST response
 	response := JobStatusResponse{
-		JobID:     grpcResp.JobId,
-		Status:    grpcResp.Status.String(),
-		Message:   grpcResp.Message,
-		CreatedAt: grpcResp.CreatedAt,
-		UpdatedAt: grpcResp.UpdatedAt,
+		JobID:         grpcResp.JobId,
+		Status:        grpcResp.Status.String(),
+		Message:       grpcResp.Message,
+		CreatedAt:     grpcResp.CreatedAt,
+		UpdatedAt:     grpcResp.UpdatedAt,
+		QueuePosition: grpcResp.QueuePosition,
+		Priority:      strings.ToLower(strings.TrimPrefix(grpcResp.Priority.String(), "PRIORITY_")),
+	}
+
+	if grpcResp.ChildJobsTotal > 0 {
+		response.Progress = &JobProgress{
+			ChildJobsTotal:    grpcResp.ChildJobsTotal,
+			ChildJobsComplete: grpcResp.ChildJobsComplete,
+		}
 	}
 
 	c.JSON(http.StatusOK, response)
@@ -237,18 +398,188 @@ func (h *RiskAssessmentHandler) GetJobResults(c *gin.Context) {
 		return
 	}
 
-	// For now, always return mock results
-	c.JSON(http.StatusOK, gin.H{
-		"job_id":  jobID,
-		"status":  "COMPLETE",
-		"message": "Mock results (orchestrator not implemented yet)",
-		"results": gin.H{
-			"total_assets":       42,
-			"high_risk_assets":   8,
-			"medium_risk_assets": 15,
-			"low_risk_assets":    19,
-			"download_url":       nil,
-		},
+	if err := h.ensureGRPCConnection(); err != nil {
+		log.Printf("Failed to connect to orchestrator: %v", err)
+
+		// Return mock results when orchestrator is not available
+		c.JSON(http.StatusOK, gin.H{
+			"job_id":  jobID,
+			"status":  "COMPLETE",
+			"message": "Mock results (MOCK MODE - orchestrator unavailable)",
+			"results": gin.H{
+				"total_assets":       42,
+				"high_risk_assets":   8,
+				"medium_risk_assets": 15,
+				"low_risk_assets":    19,
+				"download_url":       nil,
+			},
+			"attribution": models.NewAttribution(nil),
+		})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	grpcResp, err := h.orchestratorClient.GetJobResults(ctx, &generated.GetJobResultsRequest{JobId: jobID})
+	if err != nil {
+		writeGRPCError(c, err, "Failed to get job results")
+		return
+	}
+
+	response := JobResultsResponse{
+		JobID:       grpcResp.JobId,
+		Status:      grpcResp.Status.String(),
+		Message:     grpcResp.Message,
+		Attribution: attributionFromProto(grpcResp.Attribution),
+	}
+	if summary := grpcResp.Summary; summary != nil {
+		response.Results = &JobResults{
+			TotalAssets:      summary.TotalAssets,
+			HighRiskAssets:   summary.HighRiskAssets,
+			MediumRiskAssets: summary.MediumRiskAssets,
+			LowRiskAssets:    summary.LowRiskAssets,
+		}
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// attributionFromProto rebuilds the attribution block, which always credits
+// OpenStreetMap even if the orchestrator sent none
+func attributionFromProto(a *generated.Attribution) models.Attribution {
+	var sources []models.DataSource
+	for _, source := range a.GetSources() {
+		sources = append(sources, models.DataSource{
+			Endpoint:      source.Endpoint,
+			DataTimestamp: source.DataTimestamp,
+		})
+	}
+	return models.NewAttribution(sources)
+}
+
+// GET /api/v1/assets/:canonical_asset_id/history
+func (h *RiskAssessmentHandler) GetAssetHistory(c *gin.Context) {
+	assetID, err := strconv.ParseInt(c.Param("canonical_asset_id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid asset ID",
+			Message: "canonical_asset_id must be an integer",
+			Code:    http.StatusBadRequest,
+		})
+		return
+	}
+
+	// History lives in the registry, so there is nothing to mock
+	if err := h.ensureGRPCConnection(); err != nil {
+		log.Printf("Failed to connect to orchestrator: %v", err)
+		c.JSON(http.StatusServiceUnavailable, ErrorResponse{
+			Error:   "Service unavailable",
+			Message: "Orchestrator is unavailable",
+			Code:    http.StatusServiceUnavailable,
+		})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	grpcResp, err := h.orchestratorClient.GetAssetHistory(ctx, &generated.GetAssetHistoryRequest{CanonicalAssetId: assetID})
+	if err != nil {
+		writeGRPCError(c, err, "Failed to get asset history")
+		return
+	}
+
+	asset := grpcResp.Asset
+	response := AssetHistoryResponse{
+		CanonicalAssetID: asset.CanonicalAssetId,
+		IdentityKey:      asset.IdentityKey,
+		AssetType:        asset.AssetType,
+		Geometry:         rawGeometry(asset.AssetGeometryGeojson),
+		Tags:             asset.Tags,
+		Version:          asset.Version,
+		FirstSeenJobID:   asset.FirstSeenJobId,
+		LastSeenJobID:    asset.LastSeenJobId,
+		FirstSeenAt:      asset.FirstSeenAt,
+		LastSeenAt:       asset.LastSeenAt,
+		Changes:          make([]AssetChange, 0, len(grpcResp.Changes)),
+		RiskHistory:      make([]AssetRiskPoint, 0, len(grpcResp.RiskHistory)),
+	}
+	for _, change := range grpcResp.Changes {
+		response.Changes = append(response.Changes, AssetChange{
+			JobID:            change.JobId,
+			ChangeType:       change.ChangeType,
+			Version:          change.Version,
+			PreviousGeometry: rawGeometry(change.PreviousGeometryGeojson),
+			PreviousTags:     change.PreviousTags,
+			ChangedTags:      change.ChangedTags,
+			ChangedAt:        change.ChangedAt,
+		})
+	}
+	for _, point := range grpcResp.RiskHistory {
+		response.RiskHistory = append(response.RiskHistory, AssetRiskPoint{
+			JobID:             point.JobId,
+			OverallRiskScore:  point.OverallRiskScore,
+			ModelName:         point.ModelName,
+			ModelVersion:      point.ModelVersion,
+			AnalysisTimestamp: point.AnalysisTimestamp,
+		})
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// rawGeometry embeds a GeoJSON string as an object, or omits it when empty
+func rawGeometry(geojson string) json.RawMessage {
+	if geojson == "" {
+		return nil
+	}
+	return json.RawMessage(geojson)
+}
+
+// grpcErrors maps orchestrator status codes that describe the request to
+// HTTP; their messages are meant for the client. Anything else is reported
+// as an internal error.
+var grpcErrors = map[codes.Code]struct {
+	status int
+	error  string
+}{
+	codes.InvalidArgument:    {http.StatusBadRequest, "Invalid request"},
+	codes.NotFound:           {http.StatusNotFound, "Not found"},
+	codes.FailedPrecondition: {http.StatusConflict, "Request conflicts with current state"},
+	codes.ResourceExhausted:  {http.StatusTooManyRequests, "Too many requests"},
+}
+
+// writeGRPCError reports a failed orchestrator call to the client, with
+// message as the fallback for internal errors
+func writeGRPCError(c *gin.Context, err error, message string) {
+	log.Printf("gRPC call failed: %v", err)
+
+	st := status.Convert(err)
+	switch st.Code() {
+	case codes.Unavailable, codes.DeadlineExceeded:
+		c.JSON(http.StatusServiceUnavailable, ErrorResponse{
+			Error:   "Service unavailable",
+			Message: message,
+			Code:    http.StatusServiceUnavailable,
+		})
+		return
+	}
+
+	mapped, ok := grpcErrors[st.Code()]
+	if !ok {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error:   "Internal server error",
+			Message: message,
+			Code:    http.StatusInternalServerError,
+		})
+		return
+	}
+
+	c.JSON(mapped.status, ErrorResponse{
+		Error:   mapped.error,
+		Message: st.Message(),
+		Code:    mapped.status,
 	})
 }
 
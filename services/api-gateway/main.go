@@ -42,6 +42,7 @@ func main() {
 		v1.POST("/wildfire-risk-jobs", riskHandler.CreateJob)
 		v1.GET("/wildfire-risk-jobs/:job_id", riskHandler.GetJobStatus)
 		v1.GET("/wildfire-risk-jobs/:job_id/results", riskHandler.GetJobResults)
+		v1.GET("/assets/:canonical_asset_id/history", riskHandler.GetAssetHistory)
 	}
 
 	// Starting server
@@ -2,16 +2,88 @@ package config
 
 import (
 	"strconv"
+	"strings"
 	"time"
 	"wildfire-risk-platform/shared/config"
 )
 
 type Config struct {
-	GRPCPort       string
+	GRPCPort string
+
+	// AssetSource selects where assets come from: "overpass" for the live
+	// API, or "pbf" to serve them offline from the extract at PBFPath, indexed
+	// in cells of PBFIndexCellDeg degrees
+	AssetSource     string
+	PBFPath         string
+	PBFIndexCellDeg float64
+
+	// StreamBatchSize is the number of assets per StreamAssetsInAOI message
+	StreamBatchSize int
+
+	// AssetCatalogPath points to a JSON asset catalog; empty uses the built-in one
+	AssetCatalogPath string
+
+	// FuelTablePath points to a JSON table mapping landcover tags to fuel
+	// classes; empty uses the built-in one. Class shares are measured on a
+	// grid of LandcoverSampleM metres.
+	FuelTablePath    string
+	LandcoverSampleM float64
+
+	// SuppressionSearchM is how far around the AOI water sources and fire
+	// stations are looked up when a request asks for asset distances; 0
+	// turns the lookup off
+	SuppressionSearchM float64
+
+	// Road network analysis reports dead ends of at least DeadEndMinM and
+	// routes buildings within RoadSnapMaxM of a road
+	DeadEndMinM  float64
+	RoadSnapMaxM float64
+
+	// Fire station response coverage routes over roads up to ResponseBufferM
+	// around the AOI. Drive times are grouped into ResponseBandsMin bands and
+	// buildings beyond ResponseCoverageMin are flagged. Isochrones are traced
+	// on a grid of IsochroneCellM metres.
+	ResponseBufferM     float64
+	ResponseBandsMin    []float64
+	ResponseCoverageMin float64
+	IsochroneCellM      float64
+
+	// Power line spans are scored on fuel sampled every SpanSampleM along
+	// them and SpanCorridorM either side, and on the buildings up to
+	// DownwindDistanceM away within DownwindHalfAngleDeg of downwind
+	SpanCorridorM        float64
+	SpanSampleM          float64
+	DownwindDistanceM    float64
+	DownwindHalfAngleDeg float64
+
+	// Span slopes are read from the DEM the Topography Service at
+	// TopographyServiceURL builds for the AOI; empty turns span analysis off
+	TopographyServiceURL string
+
 	OverpassAPIURL string
 	HTTPTimeout    time.Duration
 	MaxRetries     int
 	RetryDelay     time.Duration
+
+	// OverpassMirrors are tried after OverpassAPIURL when it is unhealthy.
+	// Retries back off exponentially from RetryDelay up to RetryMaxDelay.
+	OverpassMirrors     []string
+	RetryMaxDelay       time.Duration
+	OverpassStatusCheck bool
+
+	// Large AOIs are fetched as tiles of TileSizeDeg degrees, up to
+	// MaxConcurrentTiles at once, with requests spaced OverpassMinInterval apart
+	TileSizeDeg         float64
+	MaxConcurrentTiles  int
+	OverpassMinInterval time.Duration
+
+	// Overpass responses are cached per AOI and tile under CacheDir for CacheTTL.
+	// The disk store is capped at CacheMaxBytes and up to CacheMemoryBytes
+	// are also kept in memory. An empty CacheDir disables caching.
+	CacheDir         string
+	CacheTTL         time.Duration
+	CacheMaxBytes    int64
+	CacheMemoryBytes int64
 }
 
 func LoadConfig() (*Config, error) {
@@ -30,13 +102,164 @@ func LoadConfig() (*Config, error) {
 		return &Config{}, err
 	}
 
+	retry_max_delay, err := time.ParseDuration(config.GetEnv("RETRY_MAX_DELAY", "60s"))
+	if err != nil {
+		return &Config{}, err
+	}
+	status_check, err := strconv.ParseBool(config.GetEnv("OVERPASS_STATUS_CHECK", "true"))
+	if err != nil {
+		return &Config{}, err
+	}
+
+	var mirrors []string
+	for _, url := range strings.Split(config.GetEnv("OVERPASS_MIRRORS", ""), ",") {
+		if url = strings.TrimSpace(url); url != "" {
+			mirrors = append(mirrors, url)
+		}
+	}
+
+	tile_size, err := strconv.ParseFloat(config.GetEnv("TILE_SIZE_DEG", "0.05"), 64)
+	if err != nil {
+		return &Config{}, err
+	}
+	max_concurrent_tiles, err := strconv.Atoi(config.GetEnv("MAX_CONCURRENT_TILES", "4"))
+	if err != nil {
+		return &Config{}, err
+	}
+	min_interval, err := time.ParseDuration(config.GetEnv("OVERPASS_MIN_INTERVAL", "1s"))
+	if err != nil {
+		return &Config{}, err
+	}
+
+	cache_ttl, err := time.ParseDuration(config.GetEnv("CACHE_TTL", "24h"))
+	if err != nil {
+		return &Config{}, err
+	}
+	cache_max_bytes, err := strconv.ParseInt(config.GetEnv("CACHE_MAX_BYTES", "1073741824"), 10, 64)
+	if err != nil {
+		return &Config{}, err
+	}
+	cache_memory_bytes, err := strconv.ParseInt(config.GetEnv("CACHE_MEMORY_BYTES", "67108864"), 10, 64)
+	if err != nil {
+		return &Config{}, err
+	}
+
+	pbf_cell_size, err := strconv.ParseFloat(config.GetEnv("PBF_INDEX_CELL_DEG", "0.01"), 64)
+	if err != nil {
+		return &Config{}, err
+	}
+
+	stream_batch_size, err := strconv.Atoi(config.GetEnv("STREAM_BATCH_SIZE", "500"))
+	if err != nil {
+		return &Config{}, err
+	}
+
+	landcover_sample, err := strconv.ParseFloat(config.GetEnv("LANDCOVER_SAMPLE_M", "30"), 64)
+	if err != nil {
+		return &Config{}, err
+	}
+
+	suppression_search, err := strconv.ParseFloat(config.GetEnv("SUPPRESSION_SEARCH_M", "5000"), 64)
+	if err != nil {
+		return &Config{}, err
+	}
+
+	dead_end_min, err := strconv.ParseFloat(config.GetEnv("DEAD_END_MIN_M", "500"), 64)
+	if err != nil {
+		return &Config{}, err
+	}
+	road_snap_max, err := strconv.ParseFloat(config.GetEnv("ROAD_SNAP_MAX_M", "500"), 64)
+	if err != nil {
+		return &Config{}, err
+	}
+
+	response_buffer, err := strconv.ParseFloat(config.GetEnv("RESPONSE_BUFFER_M", "5000"), 64)
+	if err != nil {
+		return &Config{}, err
+	}
+	var response_bands []float64
+	for _, band := range strings.Split(config.GetEnv("RESPONSE_BANDS_MIN", "4,8,12,20"), ",") {
+		minutes, err := strconv.ParseFloat(strings.TrimSpace(band), 64)
+		if err != nil {
+			return &Config{}, err
+		}
+		response_bands = append(response_bands, minutes)
+	}
+	response_coverage, err := strconv.ParseFloat(config.GetEnv("RESPONSE_COVERAGE_MIN", "8"), 64)
+	if err != nil {
+		return &Config{}, err
+	}
+	isochrone_cell, err := strconv.ParseFloat(config.GetEnv("ISOCHRONE_CELL_M", "100"), 64)
+	if err != nil {
+		return &Config{}, err
+	}
+
+	span_corridor, err := strconv.ParseFloat(config.GetEnv("SPAN_CORRIDOR_M", "15"), 64)
+	if err != nil {
+		return &Config{}, err
+	}
+	span_sample, err := strconv.ParseFloat(config.GetEnv("SPAN_SAMPLE_M", "10"), 64)
+	if err != nil {
+		return &Config{}, err
+	}
+	downwind_distance, err := strconv.ParseFloat(config.GetEnv("DOWNWIND_DISTANCE_M", "2000"), 64)
+	if err != nil {
+		return &Config{}, err
+	}
+	downwind_half_angle, err := strconv.ParseFloat(config.GetEnv("DOWNWIND_HALF_ANGLE_DEG", "30"), 64)
+	if err != nil {
+		return &Config{}, err
+	}
+
 	cfg := &Config{
 
-		GRPCPort:       config.GetEnv("GRPC_PORT", "50052"),
+		GRPCPort: config.GetEnv("GRPC_PORT", "50052"),
+
+		AssetSource:     config.GetEnv("ASSET_SOURCE", "overpass"),
+		PBFPath:         config.GetEnv("PBF_PATH", "/data/osm/extract.osm.pbf"),
+		PBFIndexCellDeg: pbf_cell_size,
+
+		StreamBatchSize: stream_batch_size,
+
+		AssetCatalogPath: config.GetEnv("ASSET_CATALOG_PATH", ""),
+
+		FuelTablePath:    config.GetEnv("FUEL_TABLE_PATH", ""),
+		LandcoverSampleM: landcover_sample,
+
+		SuppressionSearchM: suppression_search,
+
+		DeadEndMinM:  dead_end_min,
+		RoadSnapMaxM: road_snap_max,
+
+		ResponseBufferM:     response_buffer,
+		ResponseBandsMin:    response_bands,
+		ResponseCoverageMin: response_coverage,
+		IsochroneCellM:      isochrone_cell,
+
+		SpanCorridorM:        span_corridor,
+		SpanSampleM:          span_sample,
+		DownwindDistanceM:    downwind_distance,
+		DownwindHalfAngleDeg: downwind_half_angle,
+
+		TopographyServiceURL: config.GetEnv("TOPOGRAPHY_SERVICE_URL", "localhost:9002"),
+
 		OverpassAPIURL: config.GetEnv("OVERPASS_API_URL", "https://overpass-api.de/api/interpreter"),
 		HTTPTimeout:    http_timeout,
 		MaxRetries:     max_retries,
 		RetryDelay:     retry_delay,
+
+		OverpassMirrors:     mirrors,
+		RetryMaxDelay:       retry_max_delay,
+		OverpassStatusCheck: status_check,
+
+		TileSizeDeg:         tile_size,
+		MaxConcurrentTiles:  max_concurrent_tiles,
+		OverpassMinInterval: min_interval,
+
+		CacheDir:         config.GetEnv("CACHE_DIR", "/data/overpass-cache"),
+		CacheTTL:         cache_ttl,
+		CacheMaxBytes:    cache_max_bytes,
+		CacheMemoryBytes: cache_memory_bytes,
 	}
 
 	return cfg, nil
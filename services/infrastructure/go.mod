@@ -4,10 +4,14 @@ go 1.23.0
 
 require (
 	github.com/paulmach/go.geojson v1.5.0
+	github.com/paulmach/osm v0.8.0
 	google.golang.org/grpc v1.73.0
 )
 
 require (
+	github.com/datadog/czlib v0.0.0-20160811164712-4bc9a24e37f2 // indirect
+	github.com/paulmach/orb v0.1.3 // indirect
+	github.com/paulmach/protoscan v0.2.1 // indirect
 	golang.org/x/net v0.41.0 // indirect
 	golang.org/x/sys v0.33.0 // indirect
 	golang.org/x/text v0.26.0 // indirect
@@ -7,6 +7,7 @@ import (
 	"net"
 	"os"
 	"os/signal"
+	"strings"
 	"syscall"
 
 	"google.golang.org/grpc"
@@ -29,11 +30,15 @@ func main() {
 	log.Println("Starting Infrastructure Service...")
 
 	// Create the infrastructure server
-	infrastructureServer := server.NewInfrastructureServer(cfg)
+	infrastructureServer, err := server.NewInfrastructureServer(cfg)
+	if err != nil {
+		log.Fatalf("Failed to create infrastructure server: %v", err)
+	}
 
 	// Create gRPC server with interceptors
 	grpcServer := grpc.NewServer(
 		grpc.UnaryInterceptor(loggingInterceptor),
+		grpc.StreamInterceptor(streamLoggingInterceptor),
 	)
 
 	// Register the infrastructure service
@@ -63,7 +68,14 @@ func main() {
 
 	// Start serving
 	log.Printf("Infrastructure Service listening on port %s", cfg.GRPCPort)
-	log.Printf("Using Overpass API at: %s", cfg.OverpassAPIURL)
+	if cfg.AssetSource == "pbf" {
+		log.Printf("Serving assets offline from: %s", cfg.PBFPath)
+	} else {
+		log.Printf("Using Overpass API at: %s", cfg.OverpassAPIURL)
+		if len(cfg.OverpassMirrors) > 0 {
+			log.Printf("Overpass mirrors for failover: %s", strings.Join(cfg.OverpassMirrors, ", "))
+		}
+	}
 
 	if err := grpcServer.Serve(listener); err != nil {
 		log.Fatalf("Failed to serve: %v", err)
@@ -78,3 +90,12 @@ func loggingInterceptor(ctx context.Context, req interface{}, info *grpc.UnarySe
 	}
 	return resp, err
 }
+
+func streamLoggingInterceptor(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	log.Printf("gRPC stream: %s", info.FullMethod)
+	err := handler(srv, ss)
+	if err != nil {
+		log.Printf("gRPC stream failed: %s - %v", info.FullMethod, err)
+	}
+	return err
+}
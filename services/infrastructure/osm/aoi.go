@@ -0,0 +1,237 @@
+package osm
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	geojson "github.com/paulmach/go.geojson"
+
+	"wildfire-risk-platform/shared/geo"
+)
+
+// AOI is a parsed area of interest with the Overpass query filters and
+// asset tests built on it
+type AOI struct {
+	*geo.AOI
+}
+
+// ParseAOI parses a GeoJSON Polygon or MultiPolygon with geo.ParseAOI
+func ParseAOI(geoJSONStr string) (*AOI, error) {
+	aoi, err := geo.ParseAOI(geoJSONStr)
+	if err != nil {
+		return nil, err
+	}
+	return &AOI{aoi}, nil
+}
+
+// aroundGeoJSON encodes the AOI's regions grown by metres as a GeoJSON
+// MultiPolygon, for querying a source around it
+func aroundGeoJSON(aoi *AOI, metres float64) string {
+	boxes := make([]string, len(aoi.Regions()))
+	for i, r := range aoi.Regions() {
+		b := r.Expand(metres)
+		boxes[i] = fmt.Sprintf(`[[[%[1]f,%[2]f],[%[3]f,%[2]f],[%[3]f,%[4]f],[%[1]f,%[4]f],[%[1]f,%[2]f]]]`,
+			b.MinLon, b.MinLat, b.MaxLon, b.MaxLat)
+	}
+	return `{"type":"MultiPolygon","coordinates":[` + strings.Join(boxes, ",") + `]}`
+}
+
+// PolyQuery returns Overpass QL statements that select the features matching
+// any selector inside the AOI and the bbox filter, leaving them in the
+// default set.
+//
+// Each polygon is queried by its exterior ring, then each hole is subtracted.
+// A plain difference against the hole would also drop features that only
+// partly overlap it, so ways touching the hole that have a node outside it
+// are added back, as are relations, whose members Overpass cannot test
+// here. Relate settles those exactly when the results are post-filtered.
+func (a *AOI) PolyQuery(selectors []string, bbox string) string {
+	// Filters in a canonical order, so the query does not depend on how the
+	// client ordered polygons and holes
+	polygons := make([][]string, len(a.Polygons))
+	for i, polygon := range a.Polygons {
+		filters := make([]string, len(polygon))
+		for j, ring := range polygon {
+			filters[j] = polyFilter(ring)
+		}
+		sort.Strings(filters[1:])
+		polygons[i] = filters
+	}
+	sort.Slice(polygons, func(i, j int) bool { return polygons[i][0] < polygons[j][0] })
+
+	var q strings.Builder
+	sets := make([]string, len(polygons))
+	for i, filters := range polygons {
+		set := fmt.Sprintf("p%d", i)
+		sets[i] = "." + set + ";"
+		writeUnion(&q, selectors, filters[0], bbox, set)
+
+		for j, filter := range filters[1:] {
+			h := fmt.Sprintf("%sh%d", set, j)
+			writeUnion(&q, selectors, filter, bbox, h)
+			fmt.Fprintf(&q, "\t\tnode(w.%[1]s)->.%[1]sn;\n", h)
+			fmt.Fprintf(&q, "\t\tnode.%[1]sn(%[2]s)->.%[1]sin;\n", h, filter)
+			fmt.Fprintf(&q, "\t\t(.%[1]sn; - .%[1]sin;)->.%[1]sout;\n", h)
+			fmt.Fprintf(&q, "\t\t(.%[1]s; - .%[2]s;)->.%[1]s;\n", set, h)
+			fmt.Fprintf(&q, "\t\t(.%[1]s; way.%[2]s(bn.%[2]sout); rel.%[2]s;)->.%[1]s;\n", set, h)
+		}
+	}
+	fmt.Fprintf(&q, "\t\t(%s);\n", strings.Join(sets, " "))
+	return q.String()
+}
+
+// writeUnion writes a statement collecting every selector within the poly and
+// bbox filters into the named set
+func writeUnion(q *strings.Builder, selectors []string, poly, bbox, set string) {
+	q.WriteString("\t\t(\n")
+	for _, selector := range selectors {
+		fmt.Fprintf(q, "\t\t\t%s(%s)(%s);\n", selector, poly, bbox)
+	}
+	fmt.Fprintf(q, "\t\t)->.%s;\n", set)
+}
+
+// polyFilter formats a ring as an Overpass poly: filter
+func polyFilter(ring geo.Ring) string {
+	if len(ring) > 1 && ring[0] == ring[len(ring)-1] {
+		ring = ring[:len(ring)-1]
+	}
+
+	// Overpass ignores winding and start vertex; fix both so equal rings
+	// give equal filters
+	var area float64
+	start := 0
+	for i, p := range ring {
+		q := ring[(i+1)%len(ring)]
+		area += p.Lon()*q.Lat() - q.Lon()*p.Lat()
+		if p.Lon() < ring[start].Lon() || (p.Lon() == ring[start].Lon() && p.Lat() < ring[start].Lat()) {
+			start = i
+		}
+	}
+	step := 1
+	if area < 0 {
+		step = len(ring) - 1
+	}
+
+	coords := make([]string, len(ring))
+	for i := range ring {
+		p := ring[(start+i*step)%len(ring)]
+		coords[i] = fmt.Sprintf("%.7f %.7f", p.Lat(), p.Lon())
+	}
+	return fmt.Sprintf(`poly:"%s"`, strings.Join(coords, " "))
+}
+
+// Admit reports whether an asset touches the AOI, flagging whether it lies
+// entirely within it. Overpass, cached tiles and the PBF index all return
+// features by bounding box or poly filter, which can include features that
+// only touch a hole of the AOI.
+func (a *AOI) Admit(asset *Asset) bool {
+	intersects, within := a.Relate(asset.Geometry)
+	asset.WithinAOI = within
+	return intersects
+}
+
+// Relate reports whether a geometry intersects the AOI at all, and whether it
+// lies entirely within it
+func (a *AOI) Relate(g *geojson.Geometry) (intersects, within bool) {
+	vertices, segments, areas := decompose(g)
+	if len(vertices) == 0 {
+		return false, false
+	}
+
+	bounds := geo.BoundsOf(vertices)
+	if !bounds.Intersects(a.Bounds()) {
+		return false, false
+	}
+
+	inside := 0
+	for _, v := range vertices {
+		if a.Contains(v) {
+			inside++
+		}
+	}
+
+	crosses := false
+	for _, s := range segments {
+		sb := geo.BoundsOf([]geo.Point{s.A, s.B})
+		for _, edge := range a.Segments() {
+			if !sb.Intersects(geo.BoundsOf([]geo.Point{edge.A, edge.B})) {
+				continue
+			}
+			if s.Intersects(edge) {
+				crosses = true
+				break
+			}
+		}
+		if crosses {
+			break
+		}
+	}
+
+	if inside == len(vertices) && !crosses {
+		return true, true
+	}
+	if inside > 0 || crosses {
+		return true, false
+	}
+
+	// An areal asset can still contain the whole AOI without any edge contact
+	for _, area := range areas {
+		for _, polygon := range a.Polygons {
+			if area.Contains(polygon[0][0]) {
+				return true, false
+			}
+		}
+	}
+
+	return false, false
+}
+
+// decompose flattens a geometry into its vertices, its edges and, for areal
+// types, its polygons
+func decompose(g *geojson.Geometry) ([]geo.Point, []geo.Segment, []geo.Polygon) {
+	if g == nil {
+		return nil, nil, nil
+	}
+
+	var vertices []geo.Point
+	var segments []geo.Segment
+	var areas []geo.Polygon
+
+	addLine := func(coords [][]float64) {
+		points := geo.PointsFromCoords(coords)
+		vertices = append(vertices, points...)
+		for i := 0; i+1 < len(points); i++ {
+			segments = append(segments, geo.Segment{A: points[i], B: points[i+1]})
+		}
+	}
+	addPolygon := func(coords [][][]float64) {
+		polygon := geo.PolygonFromCoords(coords)
+		for _, ring := range polygon {
+			vertices = append(vertices, ring...)
+		}
+		segments = append(segments, polygon.Segments()...)
+		areas = append(areas, polygon)
+	}
+
+	switch g.Type {
+	case geojson.GeometryPoint:
+		if len(g.Point) >= 2 {
+			vertices = append(vertices, geo.Point{g.Point[0], g.Point[1]})
+		}
+	case geojson.GeometryLineString:
+		addLine(g.LineString)
+	case geojson.GeometryMultiLineString:
+		for _, line := range g.MultiLineString {
+			addLine(line)
+		}
+	case geojson.GeometryPolygon:
+		addPolygon(g.Polygon)
+	case geojson.GeometryMultiPolygon:
+		for _, polygon := range g.MultiPolygon {
+			addPolygon(polygon)
+		}
+	}
+
+	return vertices, segments, areas
+}
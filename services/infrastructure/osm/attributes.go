@@ -0,0 +1,141 @@
+package osm
+
+import (
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+
+	geojson "github.com/paulmach/go.geojson"
+
+	"wildfire-risk-platform/shared/geo"
+)
+
+// Measures are geometric attributes derived from an asset's geometry
+type Measures struct {
+	AreaM2   float64 // Polygons only
+	LengthM  float64 // Lines only
+	Centroid geo.Point
+	Bounds   geo.BBox
+}
+
+// NumericTags are OSM tags parsed into numbers. Each is nil when the tag is
+// missing or cannot be read.
+type NumericTags struct {
+	BuildingLevels *float64
+	HeightM        *float64
+	VoltageV       *float64 // Highest circuit for lines carrying several
+}
+
+// measure computes the asset's area or length, centroid and bounds. Parts of
+// multi-geometries are weighted by their area or length.
+func measure(g *geojson.Geometry) Measures {
+	var m Measures
+	if g == nil {
+		return m
+	}
+
+	var cx, cy, total float64
+	add := func(c geo.Point, weight float64) {
+		cx += c.Lon() * weight
+		cy += c.Lat() * weight
+		total += weight
+	}
+
+	var vertices []geo.Point
+	switch g.Type {
+	case geojson.GeometryPoint:
+		if len(g.Point) >= 2 {
+			vertices = []geo.Point{{g.Point[0], g.Point[1]}}
+			add(vertices[0], 1)
+		}
+
+	case geojson.GeometryLineString, geojson.GeometryMultiLineString:
+		lines := g.MultiLineString
+		if g.Type == geojson.GeometryLineString {
+			lines = [][][]float64{g.LineString}
+		}
+		for _, line := range lines {
+			points := geo.PointsFromCoords(line)
+			m.LengthM += geo.LineLength(points)
+			c, w := geo.LineCentroid(points)
+			add(c, math.Max(w, 1e-12))
+			vertices = append(vertices, points...)
+		}
+
+	case geojson.GeometryPolygon, geojson.GeometryMultiPolygon:
+		polygons := g.MultiPolygon
+		if g.Type == geojson.GeometryPolygon {
+			polygons = [][][][]float64{g.Polygon}
+		}
+		for _, coords := range polygons {
+			polygon := geo.PolygonFromCoords(coords)
+			if len(polygon) == 0 {
+				continue
+			}
+			m.AreaM2 += polygon.Area()
+			c, w := polygon.Centroid()
+			add(c, math.Max(w, 1e-12))
+			vertices = append(vertices, polygon[0]...)
+		}
+	}
+
+	if total > 0 {
+		m.Centroid = geo.Point{cx / total, cy / total}
+	}
+	m.Bounds = geo.BoundsOf(vertices)
+	return m
+}
+
+// parseNumericTags reads building:levels, height and voltage. Tags listing
+// several values separated by ";" yield the largest.
+func parseNumericTags(tags map[string]string) NumericTags {
+	return NumericTags{
+		BuildingLevels: parseMax(tags["building:levels"], parseNumber),
+		HeightM:        parseMax(tags["height"], parseLength),
+		VoltageV:       parseMax(tags["voltage"], parseNumber),
+	}
+}
+
+func parseMax(value string, parse func(string) (float64, bool)) *float64 {
+	var best *float64
+	for _, part := range strings.Split(value, ";") {
+		v, ok := parse(strings.TrimSpace(part))
+		if !ok || v < 0 {
+			continue
+		}
+		if best == nil || v > *best {
+			best = &v
+		}
+	}
+	return best
+}
+
+func parseNumber(value string) (float64, bool) {
+	v, err := strconv.ParseFloat(value, 64)
+	return v, err == nil && !math.IsNaN(v) && !math.IsInf(v, 0)
+}
+
+var (
+	metresRe = regexp.MustCompile(`^(\d+(?:\.\d+)?)\s*(m|metres?|meters?)?$`)
+	feetRe   = regexp.MustCompile(`^(\d+(?:\.\d+)?)\s*(?:'|ft|feet)\s*(?:(\d+(?:\.\d+)?)\s*(?:"|in))?$`)
+)
+
+// parseLength reads an OSM length, which is metres unless a unit says
+// otherwise: "12", "12 m", "40 ft", "12'6\""
+func parseLength(value string) (float64, bool) {
+	if match := metresRe.FindStringSubmatch(value); match != nil {
+		return parseNumber(match[1])
+	}
+
+	if match := feetRe.FindStringSubmatch(value); match != nil {
+		feet, _ := strconv.ParseFloat(match[1], 64)
+		var inches float64
+		if match[2] != "" {
+			inches, _ = strconv.ParseFloat(match[2], 64)
+		}
+		return feet*0.3048 + inches*0.0254, true
+	}
+
+	return 0, false
+}
@@ -0,0 +1,294 @@
+package osm
+
+import (
+	"container/list"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"wildfire-risk-platform/shared/geo"
+)
+
+// ResponseCache keeps raw Overpass responses on disk, keyed by the
+// normalised query and tile, with a small in-memory LRU in front. Entries
+// expire after ttl, and the least recently used are evicted once the disk
+// store grows past maxBytes.
+type ResponseCache struct {
+	dir      string
+	ttl      time.Duration
+	maxBytes int64
+	memLimit int64
+
+	mu        sync.Mutex
+	entries   map[string]*cacheEntry
+	diskBytes int64
+
+	mem      *list.List // Front is most recently used
+	memIndex map[string]*list.Element
+	memBytes int64
+}
+
+// cacheEntry is the metadata stored next to each response body
+type cacheEntry struct {
+	Key      string    `json:"key"`
+	Bounds   geo.BBox  `json:"bounds"`
+	Endpoint string    `json:"endpoint,omitempty"` // Overpass API the response came from
+	Created  time.Time `json:"created"`
+	Size     int64     `json:"size"`
+	LastUsed time.Time `json:"-"`
+}
+
+type memItem struct {
+	key  string
+	body []byte
+}
+
+// NewResponseCache opens or creates a cache in dir and indexes the entries
+// already on disk
+func NewResponseCache(dir string, ttl time.Duration, maxBytes, memLimit int64) (*ResponseCache, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create cache directory: %w", err)
+	}
+
+	c := &ResponseCache{
+		dir:      dir,
+		ttl:      ttl,
+		maxBytes: maxBytes,
+		memLimit: memLimit,
+		entries:  make(map[string]*cacheEntry),
+		mem:      list.New(),
+		memIndex: make(map[string]*list.Element),
+	}
+
+	metas, err := filepath.Glob(filepath.Join(dir, "*.meta.json"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list cache entries: %w", err)
+	}
+
+	for _, path := range metas {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			continue
+		}
+		var entry cacheEntry
+		if err := json.Unmarshal(data, &entry); err != nil || entry.Key == "" {
+			log.Printf("Removing unreadable cache entry %s", path)
+			os.Remove(path)
+			continue
+		}
+		entry.LastUsed = entry.Created
+		c.entries[entry.Key] = &entry
+		c.diskBytes += entry.Size
+	}
+
+	c.mu.Lock()
+	c.evictLocked()
+	c.mu.Unlock()
+
+	log.Printf("Overpass cache at %s: %d entries, %d bytes", dir, len(c.entries), c.diskBytes)
+	return c, nil
+}
+
+// CacheKey identifies a tile's query regardless of whitespace differences.
+// Queries embed the selectors and the AOI's canonical poly filters, so a
+// response is only reused for the same AOI, selectors and tile.
+func CacheKey(query string, tile Tile) string {
+	normalised := strings.Join(strings.Fields(query), " ")
+	sum := sha256.Sum256([]byte(normalised + "\n" + tile.bboxFilter()))
+	return hex.EncodeToString(sum[:])
+}
+
+// Get returns a cached response body and the endpoint it came from if it
+// exists and has not expired
+func (c *ResponseCache) Get(key string) ([]byte, string, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.entries[key]
+	if !ok {
+		return nil, "", false
+	}
+	if c.ttl > 0 && time.Since(entry.Created) > c.ttl {
+		c.removeLocked(key)
+		return nil, "", false
+	}
+	entry.LastUsed = time.Now()
+
+	if el, ok := c.memIndex[key]; ok {
+		c.mem.MoveToFront(el)
+		return el.Value.(*memItem).body, entry.Endpoint, true
+	}
+
+	body, err := os.ReadFile(c.bodyPath(key))
+	if err != nil {
+		log.Printf("Cache entry %s is missing its body, dropping it: %v", key, err)
+		c.removeLocked(key)
+		return nil, "", false
+	}
+	c.rememberLocked(key, body)
+
+	return body, entry.Endpoint, true
+}
+
+// Put stores a response body for a tile fetched from endpoint
+func (c *ResponseCache) Put(key string, bounds geo.BBox, endpoint string, body []byte) error {
+	entry := &cacheEntry{
+		Key:      key,
+		Bounds:   bounds,
+		Endpoint: endpoint,
+		Created:  time.Now(),
+		Size:     int64(len(body)),
+		LastUsed: time.Now(),
+	}
+
+	meta, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("failed to encode cache metadata: %w", err)
+	}
+
+	// The metadata file marks the entry as complete, so write it last
+	if err := writeFileAtomic(c.bodyPath(key), body); err != nil {
+		return err
+	}
+	if err := writeFileAtomic(c.metaPath(key), meta); err != nil {
+		os.Remove(c.bodyPath(key))
+		return err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if old, ok := c.entries[key]; ok {
+		c.diskBytes -= old.Size
+	}
+	c.entries[key] = entry
+	c.diskBytes += entry.Size
+	c.rememberLocked(key, body)
+	c.evictLocked()
+
+	return nil
+}
+
+// InvalidateRegion removes every entry whose tile overlaps the AOI and
+// returns how many were removed. A nil AOI clears the whole cache.
+func (c *ResponseCache) InvalidateRegion(aoi *AOI) int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	removed := 0
+	for key, entry := range c.entries {
+		if aoi == nil || aoi.IntersectsBox(entry.Bounds) {
+			c.removeLocked(key)
+			removed++
+		}
+	}
+
+	return removed
+}
+
+func (c *ResponseCache) rememberLocked(key string, body []byte) {
+	if int64(len(body)) > c.memLimit {
+		return
+	}
+
+	if el, ok := c.memIndex[key]; ok {
+		c.memBytes -= int64(len(el.Value.(*memItem).body))
+		el.Value.(*memItem).body = body
+		c.memBytes += int64(len(body))
+		c.mem.MoveToFront(el)
+	} else {
+		c.memIndex[key] = c.mem.PushFront(&memItem{key: key, body: body})
+		c.memBytes += int64(len(body))
+	}
+
+	for c.memBytes > c.memLimit {
+		oldest := c.mem.Back()
+		item := oldest.Value.(*memItem)
+		c.mem.Remove(oldest)
+		delete(c.memIndex, item.key)
+		c.memBytes -= int64(len(item.body))
+	}
+}
+
+// evictLocked drops expired entries, then least recently used ones until
+// the disk store fits in maxBytes
+func (c *ResponseCache) evictLocked() {
+	if c.ttl > 0 {
+		for key, entry := range c.entries {
+			if time.Since(entry.Created) > c.ttl {
+				c.removeLocked(key)
+			}
+		}
+	}
+
+	if c.maxBytes <= 0 || c.diskBytes <= c.maxBytes {
+		return
+	}
+
+	byUse := make([]*cacheEntry, 0, len(c.entries))
+	for _, entry := range c.entries {
+		byUse = append(byUse, entry)
+	}
+	sort.Slice(byUse, func(i, j int) bool {
+		return byUse[i].LastUsed.Before(byUse[j].LastUsed)
+	})
+
+	for _, entry := range byUse {
+		if c.diskBytes <= c.maxBytes {
+			break
+		}
+		c.removeLocked(entry.Key)
+	}
+}
+
+func (c *ResponseCache) removeLocked(key string) {
+	if entry, ok := c.entries[key]; ok {
+		c.diskBytes -= entry.Size
+		delete(c.entries, key)
+	}
+	if el, ok := c.memIndex[key]; ok {
+		c.memBytes -= int64(len(el.Value.(*memItem).body))
+		c.mem.Remove(el)
+		delete(c.memIndex, key)
+	}
+
+	os.Remove(c.metaPath(key))
+	os.Remove(c.bodyPath(key))
+}
+
+func (c *ResponseCache) bodyPath(key string) string {
+	return filepath.Join(c.dir, key+".json")
+}
+
+func (c *ResponseCache) metaPath(key string) string {
+	return filepath.Join(c.dir, key+".meta.json")
+}
+
+func writeFileAtomic(path string, data []byte) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".tmp-*")
+	if err != nil {
+		return fmt.Errorf("failed to create cache file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write cache file: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write cache file: %w", err)
+	}
+
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("failed to commit cache file: %w", err)
+	}
+	return nil
+}
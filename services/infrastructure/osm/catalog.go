@@ -0,0 +1,361 @@
+package osm
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Geometry interpretations for catalog entries. Nodes are always points;
+// the interpretation decides what a way becomes, and relations are always
+// assembled into areas.
+const (
+	GeometryPoint = "point" // Nodes only
+	GeometryLine  = "line"  // Ways become LineStrings
+	GeometryArea  = "area"  // Closed ways become Polygons, open ones stay LineStrings
+)
+
+// Suppression roles mark catalog entries that are firefighting resources.
+// Every asset gets its distance to the nearest resource of each role.
+const (
+	SuppressionWater       = "water"
+	SuppressionFireStation = "fire_station"
+)
+
+// AnyValue as a selector value matches any value of the key
+const AnyValue = "*"
+
+//go:embed catalog.json
+var defaultCatalogJSON []byte
+
+// CatalogEntry defines one asset type. An element belongs to the type if it
+// is one of the listed element kinds, matches any of the selectors and has
+// none of the excluded values; a selector matches when every key in it has
+// the given value.
+type CatalogEntry struct {
+	Type                string              `json:"type"`
+	Selectors           []map[string]string `json:"selectors"`
+	Exclude             map[string][]string `json:"exclude,omitempty"`
+	Elements            []string            `json:"elements"`
+	Geometry            string              `json:"geometry"`
+	VulnerabilityWeight float64             `json:"vulnerability_weight"`
+	Suppression         string              `json:"suppression,omitempty"` // Resource role, if any
+}
+
+// AssetFilter narrows a query to some asset types and tags. The zero value
+// returns every asset in the catalog.
+type AssetFilter struct {
+	AssetTypes        []string          // Empty means every type
+	Tags              map[string]string // Required on every asset; AnyValue only requires the key
+	ExcludeMinorRoads bool
+}
+
+// minorRoadValues are highway values left out by ExcludeMinorRoads: paths,
+// tracks and access ways that matter little for evacuation or exposure
+var minorRoadValues = []string{
+	"service", "track", "path", "footway", "cycleway", "bridleway",
+	"steps", "pedestrian", "corridor", "proposed", "construction",
+}
+
+// Catalog is the ordered list of asset types. When an element matches
+// several entries the first one wins.
+type Catalog struct {
+	Entries []CatalogEntry
+}
+
+// LoadCatalog reads a catalog from a JSON file, or returns the built-in
+// catalog when path is empty
+func LoadCatalog(path string) (*Catalog, error) {
+	data := defaultCatalogJSON
+	if path != "" {
+		var err error
+		data, err = os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read asset catalog: %w", err)
+		}
+	}
+
+	var catalog Catalog
+	if err := json.Unmarshal(data, &catalog.Entries); err != nil {
+		return nil, fmt.Errorf("failed to parse asset catalog: %w", err)
+	}
+	if err := catalog.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid asset catalog: %w", err)
+	}
+
+	return &catalog, nil
+}
+
+// DefaultCatalog returns the built-in catalog
+func DefaultCatalog() *Catalog {
+	catalog, err := LoadCatalog("")
+	if err != nil {
+		panic(err)
+	}
+	return catalog
+}
+
+// Validate checks every entry for a usable definition
+func (c *Catalog) Validate() error {
+	if len(c.Entries) == 0 {
+		return fmt.Errorf("catalog has no entries")
+	}
+
+	seen := make(map[string]bool)
+	for i, entry := range c.Entries {
+		if entry.Type == "" {
+			return fmt.Errorf("entry %d has no type", i)
+		}
+		if seen[entry.Type] {
+			return fmt.Errorf("asset type %s is defined twice", entry.Type)
+		}
+		seen[entry.Type] = true
+
+		if len(entry.Selectors) == 0 {
+			return fmt.Errorf("%s: at least one selector is required", entry.Type)
+		}
+		for _, selector := range entry.Selectors {
+			if len(selector) == 0 {
+				return fmt.Errorf("%s: selectors must not be empty", entry.Type)
+			}
+			for key, value := range selector {
+				if key == "" || value == "" {
+					return fmt.Errorf("%s: selector keys and values must not be empty; use %q for any value", entry.Type, AnyValue)
+				}
+			}
+		}
+
+		for key, values := range entry.Exclude {
+			if key == "" || len(values) == 0 {
+				return fmt.Errorf("%s: exclusions need a key and at least one value", entry.Type)
+			}
+		}
+
+		if len(entry.Elements) == 0 {
+			return fmt.Errorf("%s: at least one element kind is required", entry.Type)
+		}
+		for _, kind := range entry.Elements {
+			if kind != "node" && kind != "way" && kind != "relation" {
+				return fmt.Errorf("%s: unknown element kind %q", entry.Type, kind)
+			}
+		}
+
+		switch entry.Geometry {
+		case GeometryPoint:
+			if len(entry.Elements) != 1 || entry.Elements[0] != "node" {
+				return fmt.Errorf("%s: point geometry is only valid for nodes", entry.Type)
+			}
+		case GeometryLine:
+			if entry.hasElement("relation") {
+				return fmt.Errorf("%s: relations can only be read as areas", entry.Type)
+			}
+		case GeometryArea:
+		default:
+			return fmt.Errorf("%s: unknown geometry %q", entry.Type, entry.Geometry)
+		}
+
+		if entry.VulnerabilityWeight < 0 || entry.VulnerabilityWeight > 1 {
+			return fmt.Errorf("%s: vulnerability weight must be between 0 and 1", entry.Type)
+		}
+
+		switch entry.Suppression {
+		case "", SuppressionWater, SuppressionFireStation:
+		default:
+			return fmt.Errorf("%s: unknown suppression role %q", entry.Type, entry.Suppression)
+		}
+	}
+
+	return nil
+}
+
+// Classify returns the first entry an element of the given kind matches,
+// or nil if it is not an asset
+func (c *Catalog) Classify(kind string, tags map[string]string) *CatalogEntry {
+	for i := range c.Entries {
+		entry := &c.Entries[i]
+		if !entry.hasElement(kind) || entry.excludes(tags) {
+			continue
+		}
+		for _, selector := range entry.Selectors {
+			if selectorMatches(selector, tags) {
+				return entry
+			}
+		}
+	}
+	return nil
+}
+
+// Narrow returns a catalog restricted by the filter. Tag filters are added
+// to every selector, and selectors that contradict them are dropped. It fails
+// if the filter names an unknown type or leaves nothing to query.
+func (c *Catalog) Narrow(filter AssetFilter) (*Catalog, error) {
+	for key, value := range filter.Tags {
+		if key == "" || value == "" {
+			return nil, fmt.Errorf("tag filter keys and values must not be empty; use %q for any value", AnyValue)
+		}
+	}
+
+	wanted := make(map[string]bool)
+	for _, assetType := range filter.AssetTypes {
+		wanted[assetType] = true
+	}
+	for assetType := range wanted {
+		found := false
+		for _, entry := range c.Entries {
+			found = found || entry.Type == assetType
+		}
+		if !found {
+			return nil, fmt.Errorf("unknown asset type %q", assetType)
+		}
+	}
+
+	narrowed := &Catalog{}
+	for _, entry := range c.Entries {
+		if len(wanted) > 0 && !wanted[entry.Type] {
+			continue
+		}
+
+		var selectors []map[string]string
+		for _, selector := range entry.Selectors {
+			if merged, ok := mergeSelector(selector, filter.Tags); ok {
+				selectors = append(selectors, merged)
+			}
+		}
+		if len(selectors) == 0 {
+			continue
+		}
+
+		exclude := make(map[string][]string, len(entry.Exclude)+1)
+		for key, values := range entry.Exclude {
+			exclude[key] = values
+		}
+		if filter.ExcludeMinorRoads && selectsKey(selectors, "highway") {
+			exclude["highway"] = append(append([]string(nil), exclude["highway"]...), minorRoadValues...)
+		}
+
+		entry.Selectors = selectors
+		entry.Exclude = exclude
+		narrowed.Entries = append(narrowed.Entries, entry)
+	}
+
+	if len(narrowed.Entries) == 0 {
+		return nil, fmt.Errorf("the filter excludes every asset type")
+	}
+	return narrowed, nil
+}
+
+// OverpassSelectors returns one Overpass statement per element kind and
+// selector, e.g. way["power"="line"]
+func (c *Catalog) OverpassSelectors() []string {
+	var statements []string
+	for _, entry := range c.Entries {
+		for _, kind := range entry.Elements {
+			for _, selector := range entry.Selectors {
+				statements = append(statements, kind+overpassFilter(selector)+overpassExclusions(entry.Exclude))
+			}
+		}
+	}
+	return statements
+}
+
+func (e *CatalogEntry) hasElement(kind string) bool {
+	for _, k := range e.Elements {
+		if k == kind {
+			return true
+		}
+	}
+	return false
+}
+
+func (e *CatalogEntry) excludes(tags map[string]string) bool {
+	for key, values := range e.Exclude {
+		for _, value := range values {
+			if tags[key] == value {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// mergeSelector adds the required tags to a selector. It reports false if a
+// required value contradicts the selector, so nothing could match.
+func mergeSelector(selector, required map[string]string) (map[string]string, bool) {
+	merged := make(map[string]string, len(selector)+len(required))
+	for key, value := range selector {
+		merged[key] = value
+	}
+	for key, value := range required {
+		current, ok := merged[key]
+		switch {
+		case !ok || current == AnyValue:
+			merged[key] = value
+		case value != AnyValue && value != current:
+			return nil, false
+		}
+	}
+	return merged, true
+}
+
+func selectsKey(selectors []map[string]string, key string) bool {
+	for _, selector := range selectors {
+		if _, ok := selector[key]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+func selectorMatches(selector, tags map[string]string) bool {
+	for key, want := range selector {
+		value, ok := tags[key]
+		if !ok || value == "" || (want != AnyValue && value != want) {
+			return false
+		}
+	}
+	return true
+}
+
+// overpassFilter renders a selector as tag filters, keys sorted so the query
+// (and its cache key) is stable
+func overpassFilter(selector map[string]string) string {
+	keys := make([]string, 0, len(selector))
+	for key := range selector {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var filter strings.Builder
+	for _, key := range keys {
+		if selector[key] == AnyValue {
+			fmt.Fprintf(&filter, "[%q]", key)
+		} else {
+			fmt.Fprintf(&filter, "[%q=%q]", key, selector[key])
+		}
+	}
+	return filter.String()
+}
+
+// overpassExclusions renders exclusions as negated regex filters, e.g.
+// ["highway"!~"^(track|path)$"]
+func overpassExclusions(exclude map[string][]string) string {
+	keys := make([]string, 0, len(exclude))
+	for key := range exclude {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var filter strings.Builder
+	for _, key := range keys {
+		values := make([]string, len(exclude[key]))
+		for i, value := range exclude[key] {
+			values[i] = regexp.QuoteMeta(value)
+		}
+		fmt.Fprintf(&filter, "[%q!~%q]", key, "^("+strings.Join(values, "|")+")$")
+	}
+	return filter.String()
+}
@@ -0,0 +1,427 @@
+package osm
+
+import (
+	"container/heap"
+	"math"
+
+	"wildfire-risk-platform/shared/geo"
+)
+
+// EgressOptions configures the evacuation analysis
+type EgressOptions struct {
+	SafeZones   *AOI    // Nil means leaving the AOI counts as reaching safety
+	DeadEndMinM float64 // Shorter dead ends are not reported
+	SnapMaxM    float64 // Buildings farther than this from any road are not routed
+}
+
+// EgressAnalysis is the road network of an AOI and where evacuation from it
+// is constrained
+type EgressAnalysis struct {
+	Graph        *RoadGraph
+	SingleEgress []Neighbourhood
+	DeadEnds     []DeadEnd
+	Buildings    []BuildingEgress
+}
+
+// Neighbourhood is a part of the network with at most one route to safety.
+// With one route, every path out uses ExitWayID and passes ExitNode.
+type Neighbourhood struct {
+	RoutesOut int   // 0 or 1
+	ExitNode  int64 // 0 when RoutesOut is 0
+	ExitWayID int64 // 0 when there is no single road to name
+	Nodes     int
+	Buildings []int // Positions in EgressAnalysis.Buildings
+	Bounds    geo.BBox
+}
+
+// DeadEnd is a road that leads nowhere, from its closed end back to the
+// first junction
+type DeadEnd struct {
+	WayIDs   []int64
+	LengthM  float64
+	Geometry []geo.Point // Starts at the closed end
+}
+
+// BuildingEgress is how far a building is from safety along the network.
+// NetworkDistanceM is nil when the building is not near a road or no exit
+// can be reached from it.
+type BuildingEgress struct {
+	OSMType          string
+	OSMID            int64
+	NetworkDistanceM *float64
+	SnapDistanceM    float64 // Straight line from the building to its road
+	Neighbourhood    int     // Position in SingleEgress, or -1
+}
+
+// AnalyzeEgress builds the road graph from the road assets and measures
+// evacuation constraints for the building assets. Exits are the nodes in a
+// safe zone, or outside the AOI when there are none. Route counting ignores
+// oneway restrictions, as roads are often opened both ways in an evacuation;
+// network distances respect them.
+func AnalyzeEgress(aoi *AOI, assets []Asset, opts EgressOptions) *EgressAnalysis {
+	isExit := func(p geo.Point) bool { return !aoi.Contains(p) }
+	if opts.SafeZones != nil {
+		isExit = opts.SafeZones.Contains
+	}
+
+	var roads, buildings []Asset
+	for _, asset := range assets {
+		switch asset.Type {
+		case RoadAssetType:
+			roads = append(roads, asset)
+		case BuildingAssetType:
+			buildings = append(buildings, asset)
+		}
+	}
+
+	g := BuildRoadGraph(roads, isExit)
+	analysis := &EgressAnalysis{Graph: g}
+
+	hoods, neighbourhoods := g.singleEgress()
+	analysis.DeadEnds = g.deadEnds(opts.DeadEndMinM)
+	distances := g.exitDistances()
+
+	index := newEdgeIndex(g, opts.SnapMaxM)
+	for _, building := range buildings {
+		result := BuildingEgress{
+			OSMType:       building.Properties["osm_type"].(string),
+			OSMID:         building.Properties["osm_id"].(int64),
+			Neighbourhood: -1,
+		}
+
+		position, offset, snap, ok := index.snap(building.Measures.Centroid, opts.SnapMaxM)
+		if ok {
+			e := &g.Edges[position]
+			result.SnapDistanceM = snap
+
+			// Head for To, or back to From where the way allows it
+			best := offset + distances[e.From]
+			if e.Oneway {
+				best = math.Inf(1)
+			}
+			best = math.Min(best, e.LengthM-offset+distances[e.To])
+			if !math.IsInf(best, 1) {
+				result.NetworkDistanceM = &best
+			}
+
+			// Side of the edge the building is on decides its neighbourhood
+			near := e.From
+			if offset > e.LengthM/2 {
+				near = e.To
+			}
+			if hood, ok := hoods[near]; ok {
+				result.Neighbourhood = hood
+				neighbourhoods[hood].Buildings = append(neighbourhoods[hood].Buildings, len(analysis.Buildings))
+			}
+		}
+
+		analysis.Buildings = append(analysis.Buildings, result)
+	}
+
+	// Only neighbourhoods people live or work in are worth reporting
+	renumber := make(map[int]int)
+	for i, hood := range neighbourhoods {
+		if len(hood.Buildings) > 0 {
+			renumber[i] = len(analysis.SingleEgress)
+			analysis.SingleEgress = append(analysis.SingleEgress, hood)
+		}
+	}
+	for i := range analysis.Buildings {
+		if hood := analysis.Buildings[i].Neighbourhood; hood >= 0 {
+			analysis.Buildings[i].Neighbourhood = renumber[hood]
+		}
+	}
+
+	return analysis
+}
+
+// singleEgress finds the largest parts of the network that are cut off from
+// every exit by removing a single edge, or that reach no exit at all. All
+// exits hang off a virtual root, so a bridge in a depth-first search from the
+// root separates a subtree with no exit. It returns the neighbourhood of
+// each node that is in one.
+func (g *RoadGraph) singleEgress() (map[int64]int, []Neighbourhood) {
+	type half struct{ to, edge int }
+
+	ids := make([]int64, 0, len(g.Nodes))
+	dense := make(map[int64]int, len(g.Nodes))
+	for id := range g.Nodes {
+		dense[id] = len(ids)
+		ids = append(ids, id)
+	}
+
+	root := len(ids)
+	adj := make([][]half, len(ids)+1)
+	for i, e := range g.Edges {
+		from, to := dense[e.From], dense[e.To]
+		adj[from] = append(adj[from], half{to, i})
+		if from != to {
+			adj[to] = append(adj[to], half{from, i})
+		}
+	}
+	for i, id := range ids {
+		if g.Nodes[id].Exit {
+			edge := len(g.Edges) + i
+			adj[root] = append(adj[root], half{i, edge})
+			adj[i] = append(adj[i], half{root, edge})
+		}
+	}
+
+	disc := make([]int, len(adj))
+	low := make([]int, len(adj))
+	parentEdge := make([]int, len(adj))
+	children := make([][]int, len(adj))
+	bridge := make(map[int]bool)
+	timer := 0
+
+	var visit func(u int)
+	visit = func(u int) {
+		timer++
+		disc[u], low[u] = timer, timer
+		for _, h := range adj[u] {
+			if h.edge == parentEdge[u] {
+				continue
+			}
+			if disc[h.to] == 0 {
+				parentEdge[h.to] = h.edge
+				children[u] = append(children[u], h.to)
+				visit(h.to)
+				low[u] = min(low[u], low[h.to])
+				if low[h.to] > disc[u] {
+					bridge[h.edge] = true
+				}
+			} else {
+				low[u] = min(low[u], disc[h.to])
+			}
+		}
+	}
+	parentEdge[root] = -1
+	visit(root)
+
+	hoods := make(map[int64]int)
+	var neighbourhoods []Neighbourhood
+	join := func(hood, node int) {
+		hoods[ids[node]] = hood
+		n := &neighbourhoods[hood]
+		p := g.Nodes[ids[node]].Point
+		if n.Nodes == 0 {
+			n.Bounds = geo.BoundsOf([]geo.Point{p})
+		} else {
+			n.Bounds = geo.BoundsOf([]geo.Point{p, {n.Bounds.MinLon, n.Bounds.MinLat}, {n.Bounds.MaxLon, n.Bounds.MaxLat}})
+		}
+		n.Nodes++
+	}
+
+	// Walk the search tree; the first bridge on the way down starts a neighbourhood
+	type step struct{ node, hood int }
+	stack := []step{{root, -1}}
+	for len(stack) > 0 {
+		s := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		for _, child := range children[s.node] {
+			hood := s.hood
+			if hood < 0 && bridge[parentEdge[child]] {
+				hood = len(neighbourhoods)
+				n := Neighbourhood{RoutesOut: 1}
+				if s.node == root {
+					n.ExitNode = ids[child]
+				} else {
+					n.ExitNode = ids[s.node]
+					n.ExitWayID = g.Edges[parentEdge[child]].WayID
+				}
+				neighbourhoods = append(neighbourhoods, n)
+			}
+			if hood >= 0 {
+				join(hood, child)
+			}
+			stack = append(stack, step{child, hood})
+		}
+	}
+
+	// Components the search never reached have no way out at all
+	for start := range ids {
+		if disc[start] != 0 {
+			continue
+		}
+		hood := len(neighbourhoods)
+		neighbourhoods = append(neighbourhoods, Neighbourhood{})
+		disc[start] = -1
+		queue := []int{start}
+		for len(queue) > 0 {
+			u := queue[0]
+			queue = queue[1:]
+			join(hood, u)
+			for _, h := range adj[u] {
+				if disc[h.to] == 0 {
+					disc[h.to] = -1
+					queue = append(queue, h.to)
+				}
+			}
+		}
+	}
+
+	return hoods, neighbourhoods
+}
+
+// deadEnds follows every road from a closed end back to the first junction
+// and reports those at least minM long. Roads that lead to an exit are not
+// dead ends.
+func (g *RoadGraph) deadEnds(minM float64) []DeadEnd {
+	var deadEnds []DeadEnd
+	walked := make(map[int]bool)
+
+	for id, node := range g.Nodes {
+		if node.Exit || g.degree(id) != 1 {
+			continue
+		}
+
+		d := DeadEnd{Geometry: []geo.Point{node.Point}}
+		current, position := id, g.adjacent[id][0]
+		reachesExit := false
+		for !walked[position] {
+			walked[position] = true
+			e := &g.Edges[position]
+
+			points := e.Geometry
+			if e.From != current {
+				points = reversed(points)
+			}
+			d.Geometry = append(d.Geometry, points[1:]...)
+			d.LengthM += e.LengthM
+			if len(d.WayIDs) == 0 || d.WayIDs[len(d.WayIDs)-1] != e.WayID {
+				d.WayIDs = append(d.WayIDs, e.WayID)
+			}
+
+			current = e.other(current)
+			if g.Nodes[current].Exit {
+				reachesExit = true
+				break
+			}
+			if g.degree(current) != 2 {
+				break
+			}
+			for _, next := range g.adjacent[current] {
+				if next != position {
+					position = next
+				}
+			}
+		}
+
+		if !reachesExit && d.LengthM >= minM {
+			deadEnds = append(deadEnds, d)
+		}
+	}
+
+	return deadEnds
+}
+
+func reversed(points []geo.Point) []geo.Point {
+	out := make([]geo.Point, len(points))
+	for i, p := range points {
+		out[len(points)-1-i] = p
+	}
+	return out
+}
+
+// exitDistances gives each node's shortest drivable distance to an exit.
+// Unreachable nodes get +Inf.
+func (g *RoadGraph) exitDistances() map[int64]float64 {
+	var seeds []seed
+	for id, node := range g.Nodes {
+		if node.Exit {
+			seeds = append(seeds, seed{node: id})
+		}
+	}
+
+	reached := g.shortest(seeds, func(e *RoadEdge) float64 { return e.LengthM }, true)
+	distances := make(map[int64]float64, len(g.Nodes))
+	for id := range g.Nodes {
+		distances[id] = math.Inf(1)
+		if r, ok := reached[id]; ok {
+			distances[id] = r.cost
+		}
+	}
+	return distances
+}
+
+// seed starts a shortest-path search at a node with an initial cost. origin
+// identifies where the seed came from, e.g. a fire station.
+type seed struct {
+	node   int64
+	cost   float64
+	origin int
+}
+
+// reach is the cheapest cost to a node and the seed it came from
+type reach struct {
+	cost   float64
+	origin int
+}
+
+// shortest runs Dijkstra from the seeds, respecting oneway edges. With
+// reverse it follows edges backwards, giving the cost from each node to the
+// nearest seed rather than from it. Unreachable nodes are left out.
+func (g *RoadGraph) shortest(seeds []seed, cost func(*RoadEdge) float64, reverse bool) map[int64]reach {
+	reached := make(map[int64]reach, len(g.Nodes))
+	queue := &distanceQueue{}
+	for _, s := range seeds {
+		if r, ok := reached[s.node]; !ok || s.cost < r.cost {
+			reached[s.node] = reach{s.cost, s.origin}
+			heap.Push(queue, queued{s.node, s.cost})
+		}
+	}
+
+	for queue.Len() > 0 {
+		item := heap.Pop(queue).(queued)
+		current := reached[item.node]
+		if item.distance > current.cost {
+			continue
+		}
+
+		for _, position := range g.adjacent[item.node] {
+			e := &g.Edges[position]
+
+			// Driving along e leaves from From; backwards, it arrives at To
+			head, tail := e.From, e.To
+			if reverse {
+				head, tail = e.To, e.From
+			}
+
+			var next int64
+			switch {
+			case head == item.node:
+				next = tail
+			case !e.Oneway:
+				next = head
+			default:
+				continue
+			}
+
+			d := item.distance + cost(e)
+			if r, ok := reached[next]; !ok || d < r.cost {
+				reached[next] = reach{d, current.origin}
+				heap.Push(queue, queued{next, d})
+			}
+		}
+	}
+
+	return reached
+}
+
+type queued struct {
+	node     int64
+	distance float64
+}
+
+type distanceQueue []queued
+
+func (q distanceQueue) Len() int            { return len(q) }
+func (q distanceQueue) Less(i, j int) bool  { return q[i].distance < q[j].distance }
+func (q distanceQueue) Swap(i, j int)       { q[i], q[j] = q[j], q[i] }
+func (q *distanceQueue) Push(x interface{}) { *q = append(*q, x.(queued)) }
+func (q *distanceQueue) Pop() interface{} {
+	old := *q
+	item := old[len(old)-1]
+	*q = old[:len(old)-1]
+	return item
+}
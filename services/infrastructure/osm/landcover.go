@@ -0,0 +1,259 @@
+package osm
+
+import (
+	"context"
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+
+	"wildfire-risk-platform/shared/geo"
+)
+
+//go:embed fuel_classes.json
+var defaultFuelTableJSON []byte
+
+// maxLandcoverSamples caps the sample grid used to measure class shares;
+// large AOIs get a coarser grid rather than an unbounded one
+const maxLandcoverSamples = 250000
+
+// FuelClass maps OSM landcover tags to a coarse fuel-hazard class. A polygon
+// gets the first class with a matching selector, and where polygons of
+// different classes overlap the earlier class wins.
+type FuelClass struct {
+	Name      string              `json:"fuel_class"`
+	Selectors []map[string]string `json:"selectors"`
+}
+
+// FuelTable is the ordered list of fuel classes, most hazardous first
+type FuelTable struct {
+	Classes []FuelClass
+
+	catalog *Catalog
+}
+
+// LoadFuelTable reads a fuel table from a JSON file, or returns the built-in
+// table when path is empty
+func LoadFuelTable(path string) (*FuelTable, error) {
+	data := defaultFuelTableJSON
+	if path != "" {
+		var err error
+		data, err = os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read fuel table: %w", err)
+		}
+	}
+
+	table := &FuelTable{}
+	if err := json.Unmarshal(data, &table.Classes); err != nil {
+		return nil, fmt.Errorf("failed to parse fuel table: %w", err)
+	}
+
+	// Landcover is fetched and classified like assets, one entry per class
+	table.catalog = &Catalog{}
+	for _, class := range table.Classes {
+		table.catalog.Entries = append(table.catalog.Entries, CatalogEntry{
+			Type:      class.Name,
+			Selectors: class.Selectors,
+			Elements:  []string{"way", "relation"},
+			Geometry:  GeometryArea,
+		})
+	}
+	if err := table.catalog.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid fuel table: %w", err)
+	}
+
+	return table, nil
+}
+
+// LandcoverOptions configures the landcover layer
+type LandcoverOptions struct {
+	Fuel    *FuelTable
+	SampleM float64 // Spacing of the grid used to measure class shares
+}
+
+// FuelShare is how much of the AOI one fuel class covers
+type FuelShare struct {
+	FuelClass string
+	Share     float64 // Fraction of the AOI, 0 to 1
+	AreaM2    float64
+	Features  int // Polygons of this class touching the AOI
+}
+
+// LandcoverResult is the landcover polygons touching the AOI and the share
+// of the AOI each fuel class covers. Features hold only polygonal assets,
+// typed by fuel class.
+type LandcoverResult struct {
+	Features     []Asset
+	Shares       []FuelShare // In fuel table order
+	AOIAreaM2    float64
+	Unclassified float64 // Share of the AOI with no landcover polygon
+	TilesTotal   int
+	FailedTiles  []Tile
+	Sources      []DataSource
+}
+
+// QueryLandcover fetches the landcover polygons in the AOI and measures the
+// share of each fuel class
+func (c *OverpassClient) QueryLandcover(ctx context.Context, areaGeoJSON string) (*LandcoverResult, error) {
+	aoi, err := ParseAOI(areaGeoJSON)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse AOI: %w", err)
+	}
+
+	var features []Asset
+	result, err := c.streamCatalog(ctx, aoi, c.landcover.Fuel.catalog, func(feature Asset) error {
+		features = append(features, feature)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	landcover := c.landcover.Fuel.summarise(aoi, features, c.landcover.SampleM)
+	landcover.TilesTotal = result.TilesTotal
+	landcover.FailedTiles = result.FailedTiles
+	landcover.Sources = result.Sources
+	return landcover, nil
+}
+
+// summarise keeps the polygonal features and estimates each class's share
+// by sampling the AOI on a grid of roughly sampleM metres
+func (t *FuelTable) summarise(aoi *AOI, features []Asset, sampleM float64) *LandcoverResult {
+	result := &LandcoverResult{}
+	for _, class := range t.Classes {
+		result.Shares = append(result.Shares, FuelShare{FuelClass: class.Name})
+	}
+
+	index := t.index(features)
+	for _, a := range index.areas {
+		result.Features = append(result.Features, a.feature)
+		result.Shares[a.rank].Features++
+	}
+
+	for _, polygon := range aoi.Polygons {
+		result.AOIAreaM2 += polygon.Area()
+	}
+
+	// One grid spacing for every region, so each sample stands for the same area
+	b := aoi.Bounds()
+	stepLat := sampleM / metresPerDegree
+	stepLon := stepLat / math.Max(math.Cos((b.MinLat+b.MaxLat)/2*math.Pi/180), 0.01)
+	var n float64
+	for _, r := range aoi.Regions() {
+		n += (r.MaxLon - r.MinLon) / stepLon * (r.MaxLat - r.MinLat) / stepLat
+	}
+	if n > maxLandcoverSamples {
+		scale := math.Sqrt(n / maxLandcoverSamples)
+		stepLat *= scale
+		stepLon *= scale
+	}
+
+	counts := make([]int, len(t.Classes))
+	var inside, unclassified int
+	for _, r := range aoi.Regions() {
+		for lat := r.MinLat + stepLat/2; lat <= r.MaxLat; lat += stepLat {
+			for lon := r.MinLon + stepLon/2; lon <= r.MaxLon; lon += stepLon {
+				pt := geo.Point{lon, lat}
+				if !aoi.Contains(pt) {
+					continue
+				}
+				inside++
+
+				if rank := index.classAt(pt); rank < 0 {
+					unclassified++
+				} else {
+					counts[rank]++
+				}
+			}
+		}
+	}
+
+	if inside > 0 {
+		for i := range result.Shares {
+			result.Shares[i].Share = float64(counts[i]) / float64(inside)
+			result.Shares[i].AreaM2 = result.Shares[i].Share * result.AOIAreaM2
+		}
+		result.Unclassified = float64(unclassified) / float64(inside)
+	}
+
+	return result
+}
+
+// fuelIndexCellDeg is the grid cell size used to look up landcover polygons
+const fuelIndexCellDeg = 0.005
+
+// fuelIndexMaxCells caps the grid cells one feature is entered in, about
+// 16 km square. A forest relation spanning a region would otherwise fill
+// millions of cells; larger features are checked by their bounds instead.
+const fuelIndexMaxCells = 1024
+
+// fuelIndex finds the fuel class at a point from landcover polygons
+type fuelIndex struct {
+	areas []fuelArea
+	cells map[geo.Cell][]int
+	large []int // Areas too big for the grid
+}
+
+type fuelArea struct {
+	feature  Asset
+	rank     int // Position of the class in the fuel table
+	polygons []geo.Polygon
+	bounds   geo.BBox
+}
+
+// index builds a fuel index from landcover features, skipping those that
+// are not polygons
+func (t *FuelTable) index(features []Asset) *fuelIndex {
+	rank := make(map[string]int, len(t.Classes))
+	for i, class := range t.Classes {
+		rank[class.Name] = i
+	}
+
+	idx := &fuelIndex{cells: make(map[geo.Cell][]int)}
+	for _, feature := range features {
+		_, _, polygons := decompose(feature.Geometry)
+		if len(polygons) == 0 {
+			continue // Unclosed ways say nothing about cover
+		}
+
+		b := feature.Measures.Bounds
+		position := len(idx.areas)
+		idx.areas = append(idx.areas, fuelArea{feature: feature, rank: rank[feature.Type], polygons: polygons, bounds: b})
+
+		minX, maxX := int(math.Floor(b.MinLon/fuelIndexCellDeg)), int(math.Floor(b.MaxLon/fuelIndexCellDeg))
+		minY, maxY := int(math.Floor(b.MinLat/fuelIndexCellDeg)), int(math.Floor(b.MaxLat/fuelIndexCellDeg))
+		if (maxX-minX+1)*(maxY-minY+1) > fuelIndexMaxCells {
+			idx.large = append(idx.large, position)
+			continue
+		}
+		for x := minX; x <= maxX; x++ {
+			for y := minY; y <= maxY; y++ {
+				idx.cells[geo.Cell{x, y}] = append(idx.cells[geo.Cell{x, y}], position)
+			}
+		}
+	}
+	return idx
+}
+
+// classAt returns the rank of the most hazardous class covering p, or -1
+func (idx *fuelIndex) classAt(p geo.Point) int {
+	best := -1
+	cell := geo.Cell{int(math.Floor(p.Lon() / fuelIndexCellDeg)), int(math.Floor(p.Lat() / fuelIndexCellDeg))}
+	for _, candidates := range [][]int{idx.cells[cell], idx.large} {
+		for _, i := range candidates {
+			a := &idx.areas[i]
+			if (best >= 0 && a.rank >= best) || !a.bounds.Contains(p) {
+				continue
+			}
+			for _, polygon := range a.polygons {
+				if polygon.Contains(p) {
+					best = a.rank
+					break
+				}
+			}
+		}
+	}
+	return best
+}
@@ -0,0 +1,252 @@
+package osm
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"math/rand"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// RetryOptions controls how failed Overpass requests are retried
+type RetryOptions struct {
+	MaxRetries  int
+	BaseDelay   time.Duration // First backoff step, doubled on every retry
+	MaxDelay    time.Duration // Cap on backoff, Retry-After and slot waits
+	StatusCheck bool          // Ask a mirror's /status for a free slot before querying it
+}
+
+// overpassError is a non-200 answer from an Overpass endpoint
+type overpassError struct {
+	status     int
+	retryAfter time.Duration // From the Retry-After header, if any
+	body       string
+}
+
+func (e *overpassError) Error() string {
+	return fmt.Sprintf("Overpass API returned status %d: %s", e.status, e.body)
+}
+
+// permanent reports whether retrying cannot help. Client errors such as a
+// query syntax error are permanent, except for rate limiting.
+func (e *overpassError) permanent() bool {
+	return e.status >= 400 && e.status < 500 && e.status != http.StatusTooManyRequests
+}
+
+// mirror is one Overpass endpoint. Each has its own rate limiter and is
+// taken out of rotation for a cooldown after failing.
+type mirror struct {
+	url       string
+	statusURL string
+	limiter   *rateLimiter
+
+	failures       int
+	unhealthyUntil time.Time
+	noStatus       atomic.Bool // The endpoint has no usable /status
+}
+
+// mirrorPool hands out mirrors in configured order, skipping unhealthy ones
+type mirrorPool struct {
+	mu      sync.Mutex
+	mirrors []*mirror
+	retry   RetryOptions
+}
+
+func newMirrorPool(urls []string, minInterval time.Duration, retry RetryOptions) *mirrorPool {
+	pool := &mirrorPool{retry: retry}
+	for _, url := range urls {
+		pool.mirrors = append(pool.mirrors, &mirror{
+			url:       url,
+			statusURL: strings.TrimSuffix(strings.TrimSuffix(url, "/"), "/interpreter") + "/status",
+			limiter:   newRateLimiter(minInterval),
+		})
+	}
+	return pool
+}
+
+// pick returns the first healthy mirror. If every mirror is cooling down it
+// returns the one that recovers first and how long until it does.
+func (p *mirrorPool) pick() (*mirror, time.Duration) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	now := time.Now()
+	var soonest *mirror
+	for _, m := range p.mirrors {
+		if !now.Before(m.unhealthyUntil) {
+			return m, 0
+		}
+		if soonest == nil || m.unhealthyUntil.Before(soonest.unhealthyUntil) {
+			soonest = m
+		}
+	}
+	return soonest, soonest.unhealthyUntil.Sub(now)
+}
+
+func (p *mirrorPool) reportSuccess(m *mirror) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	m.failures = 0
+	m.unhealthyUntil = time.Time{}
+}
+
+// reportFailure takes the mirror out of rotation for cooldown, or for an
+// exponential backoff on its consecutive failures when cooldown is zero.
+// Concurrent tiles sent before the mirror went down fail together, so
+// failures while it is already cooling down count towards the same window;
+// they only extend it when the server asks for a longer wait.
+func (p *mirrorPool) reportFailure(m *mirror, cooldown time.Duration) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	now := time.Now()
+	if now.Before(m.unhealthyUntil) {
+		if until := now.Add(cooldown); cooldown > 0 && until.After(m.unhealthyUntil) {
+			m.unhealthyUntil = until
+		}
+		return
+	}
+
+	m.failures++
+	if cooldown <= 0 {
+		cooldown = p.retry.backoff(m.failures)
+	}
+	m.unhealthyUntil = now.Add(cooldown)
+	log.Printf("Overpass mirror %s unhealthy for %v after %d failure(s)", m.url, cooldown.Round(time.Millisecond), m.failures)
+}
+
+// backoff is the delay before retry n (from 1): exponential in n, capped at
+// MaxDelay, with jitter so concurrent tiles do not retry in lockstep
+func (r RetryOptions) backoff(n int) time.Duration {
+	if r.BaseDelay <= 0 {
+		return 0
+	}
+
+	delay := r.BaseDelay
+	for i := 1; i < n && (r.MaxDelay <= 0 || delay < r.MaxDelay); i++ {
+		delay *= 2
+	}
+	if r.MaxDelay > 0 && delay > r.MaxDelay {
+		delay = r.MaxDelay
+	}
+
+	// Equal jitter: at least half the delay, at most all of it
+	return delay/2 + time.Duration(rand.Int63n(int64(delay/2)+1))
+}
+
+var (
+	slotsNowRe = regexp.MustCompile(`^(\d+) slots? available now`)
+	slotInRe   = regexp.MustCompile(`in (\d+) seconds`)
+)
+
+// errNoStatus means the mirror answered but has no /status we can read
+var errNoStatus = errors.New("no usable /status endpoint")
+
+// waitForSlot asks the mirror's /status endpoint whether a query slot is
+// free and waits for one if not. Mirrors without /status are trusted.
+func (c *OverpassClient) waitForSlot(ctx context.Context, m *mirror) error {
+	if !c.retry.StatusCheck || m.noStatus.Load() {
+		return nil
+	}
+
+	wait, err := c.slotWait(ctx, m)
+	if errors.Is(err, errNoStatus) {
+		log.Printf("Overpass mirror %s has no usable /status, not checking it again: %v", m.url, err)
+		m.noStatus.Store(true)
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if wait <= 0 {
+		return nil
+	}
+
+	// A long wait is treated like a 429, so the query fails over instead
+	if c.retry.MaxDelay > 0 && wait > c.retry.MaxDelay {
+		return &overpassError{
+			status:     http.StatusTooManyRequests,
+			retryAfter: wait,
+			body:       fmt.Sprintf("no query slot for %v", wait),
+		}
+	}
+
+	log.Printf("Waiting %v for a slot on Overpass mirror %s", wait, m.url)
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-time.After(wait):
+		return nil
+	}
+}
+
+// slotWait reads /status: "N slots available now." with N > 0 means no wait, otherwise
+// the shortest "Slot available after: ..., in N seconds." line is used
+func (c *OverpassClient) slotWait(ctx context.Context, m *mirror) (time.Duration, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", m.statusURL, nil)
+	if err != nil {
+		return 0, err
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return 0, fmt.Errorf("%w: status %d", errNoStatus, resp.StatusCode)
+	}
+
+	var wait time.Duration = -1
+	scanner := bufio.NewScanner(io.LimitReader(resp.Body, 64*1024))
+	for scanner.Scan() {
+		line := scanner.Text()
+		if match := slotsNowRe.FindStringSubmatch(line); match != nil {
+			if free, _ := strconv.Atoi(match[1]); free > 0 {
+				return 0, nil
+			}
+			continue
+		}
+		if match := slotInRe.FindStringSubmatch(line); match != nil && strings.HasPrefix(line, "Slot available after") {
+			seconds, _ := strconv.Atoi(match[1])
+			if d := time.Duration(seconds) * time.Second; wait < 0 || d < wait {
+				wait = d
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return 0, err
+	}
+	if wait < 0 {
+		return 0, fmt.Errorf("%w: unrecognised response", errNoStatus)
+	}
+
+	return wait, nil
+}
+
+// parseRetryAfter reads a Retry-After header given in seconds or as an HTTP date
+func parseRetryAfter(value string) time.Duration {
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(strings.TrimSpace(value)); err == nil && seconds > 0 {
+		return time.Duration(seconds) * time.Second
+	}
+	if at, err := http.ParseTime(value); err == nil {
+		if d := time.Until(at); d > 0 {
+			return d
+		}
+	}
+	return 0
+}
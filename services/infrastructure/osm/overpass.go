@@ -1,178 +1,326 @@
 package osm
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"log"
 	"net/http"
+	"sort"
 	"strings"
+	"sync"
 	"time"
 
 	geojson "github.com/paulmach/go.geojson"
 )
 
 type OverpassClient struct {
-	apiURL     string
 	httpClient *http.Client
-	maxRetries int
-	retryDelay time.Duration
+	mirrors    *mirrorPool
+	retry      RetryOptions
+	tiling     TilingOptions
+	cache      *ResponseCache
+	catalog    *Catalog
+	landcover  LandcoverOptions
 }
 
-func NewOverpassClient(apiURL string, timeout time.Duration, maxRetries int, retryDelay time.Duration) *OverpassClient {
+// NewOverpassClient creates a client that queries and classifies the asset
+// types in catalog and the fuel classes in landcover. apiURLs are tried in
+// order, failing over to the next when one is unhealthy. cache may be nil to
+// always query the API.
+func NewOverpassClient(apiURLs []string, timeout time.Duration, retry RetryOptions, tiling TilingOptions, cache *ResponseCache, catalog *Catalog, landcover LandcoverOptions) *OverpassClient {
+	if tiling.MaxConcurrent < 1 {
+		tiling.MaxConcurrent = 1
+	}
+
 	return &OverpassClient{
-		apiURL: apiURL,
 		httpClient: &http.Client{
 			Timeout: timeout,
 		},
-		maxRetries: maxRetries,
-		retryDelay: retryDelay,
+		mirrors:   newMirrorPool(apiURLs, tiling.MinInterval, retry),
+		retry:     retry,
+		tiling:    tiling,
+		cache:     cache,
+		catalog:   catalog,
+		landcover: landcover,
 	}
 }
 
 type Asset struct {
-	Type       string                 `json:"type"`
-	Geometry   *geojson.Geometry      `json:"geometry"`
-	Properties map[string]interface{} `json:"properties"`
+	Type                string                 `json:"type"`
+	Geometry            *geojson.Geometry      `json:"geometry"`
+	Properties          map[string]interface{} `json:"properties"`
+	WithinAOI           bool                   `json:"within_aoi"`           // False when the asset only intersects the AOI
+	VulnerabilityWeight float64                `json:"vulnerability_weight"` // Catalog default for the asset type
+	Measures            Measures               `json:"measures"`
+	NumericTags         NumericTags            `json:"numeric_tags"`
+	Suppression         SuppressionDistances   `json:"suppression"`        // Set by Suppression.Annotate
+	NodeIDs             []int64                `json:"node_ids,omitempty"` // Ways only, one per vertex
+	Provenance          Provenance             `json:"provenance"`
+}
+
+// QueryResult holds the merged assets of every tile and the tiles that could
+// not be fetched. A non-empty FailedTiles means coverage is partial.
+type QueryResult struct {
+	Assets      []Asset
+	TilesTotal  int
+	FailedTiles []Tile
+	Sources     []DataSource // Every endpoint and data timestamp the assets came from
 }
 
-func (c *OverpassClient) QueryAssets(ctx context.Context, areaGeoJSON string) ([]Asset, error) {
-	bounds, err := extractBounds(areaGeoJSON)
+// QueryAssets fetches every asset in the AOI that passes the filter into memory. Tiles finish in any
+// order, so the assets are sorted to keep results reproducible.
+func (c *OverpassClient) QueryAssets(ctx context.Context, areaGeoJSON string, filter AssetFilter) (*QueryResult, error) {
+	var assets []Asset
+	result, err := c.StreamAssets(ctx, areaGeoJSON, filter, func(asset Asset) error {
+		assets = append(assets, asset)
+		return nil
+	})
 	if err != nil {
-		return nil, fmt.Errorf("failed to extract bounds: %w", err)
+		return nil, err
 	}
 
-	query := c.buildQuery(bounds)
-
-	var response *OverpassResponse
-	for attempt := 0; attempt <= c.maxRetries; attempt++ {
-		if attempt > 0 {
-			log.Printf("Retrying Overpass API query (attempt %d/%d)", attempt, c.maxRetries)
-			select {
-			case <-ctx.Done():
-				return nil, ctx.Err()
-			case <-time.After(c.retryDelay):
-			}
+	sort.SliceStable(assets, func(i, j int) bool {
+		a, b := assets[i].Properties, assets[j].Properties
+		if a["osm_type"] != b["osm_type"] {
+			return a["osm_type"].(string) < b["osm_type"].(string)
 		}
+		return a["osm_id"].(int64) < b["osm_id"].(int64)
+	})
 
-		response, err = c.executeQuery(ctx, query)
-		if err == nil {
-			break
-		}
+	result.Assets = assets
+	return result, nil
+}
 
-		log.Printf("Overpass API error: %v", err)
+// StreamAssets splits the AOI into tiles and fetches them concurrently under
+// the shared rate limit, decoding each response incrementally. The filter is
+// part of the Overpass query, so excluded assets are never downloaded. Every asset
+// that touches the AOI is passed to emit once, as soon as it is decoded;
+// emit is never called concurrently and an emit error stops the fetch.
+// It only fails outright when no tile could be fetched.
+func (c *OverpassClient) StreamAssets(ctx context.Context, areaGeoJSON string, filter AssetFilter, emit func(Asset) error) (*QueryResult, error) {
+	aoi, err := ParseAOI(areaGeoJSON)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse AOI: %w", err)
 	}
 
+	catalog, err := c.catalog.Narrow(filter)
 	if err != nil {
-		return nil, fmt.Errorf("failed to query Overpass API after %d attempts: %w", c.maxRetries+1, err)
+		return nil, fmt.Errorf("invalid asset filter: %w", err)
 	}
 
-	return c.convertToAssets(response), nil
+	return c.streamCatalog(ctx, aoi, catalog, emit)
 }
 
-type Bounds struct {
-	MinLat, MinLon, MaxLat, MaxLon float64
-}
+// streamCatalog fetches and emits everything in the AOI that the catalog
+// selects. It backs both assets and the landcover layer.
+func (c *OverpassClient) streamCatalog(ctx context.Context, aoi *AOI, catalog *Catalog, emit func(Asset) error) (*QueryResult, error) {
+	tiles := tilesFor(aoi, c.tiling.TileSize)
+	if len(tiles) == 0 {
+		return &QueryResult{}, nil
+	}
+	log.Printf("Fetching AOI in %d tile(s), %d at a time", len(tiles), c.tiling.MaxConcurrent)
+
+	fetchCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var mu sync.Mutex
+	var emitErr error
+	seen := make(map[string]bool)
+	sources := make(map[DataSource]bool)
+
+	visit := func(element OSMElement, source DataSource) {
+		asset := convertElement(element, catalog)
+		if asset == nil {
+			return
+		}
+		asset.Provenance.DataSource = source
+
+		// Cached tiles hold everything in the tile
+		if !aoi.Admit(asset) {
+			return
+		}
+
+		mu.Lock()
+		defer mu.Unlock()
+
+		// Features crossing a tile edge come back from every tile they touch
+		key := fmt.Sprintf("%s/%d", element.Type, element.ID)
+		if emitErr != nil || seen[key] {
+			return
+		}
+		seen[key] = true
+		sources[source] = true
+
+		if err := emit(*asset); err != nil {
+			emitErr = err
+			cancel()
+		}
+	}
 
-func extractBounds(geoJSONStr string) (*Bounds, error) {
-	var geoData map[string]interface{}
-	if err := json.Unmarshal([]byte(geoJSONStr), &geoData); err != nil {
-		return nil, fmt.Errorf("invalid GeoJSON: %w", err)
+	errs := make([]error, len(tiles))
+	sem := make(chan struct{}, c.tiling.MaxConcurrent)
+	var wg sync.WaitGroup
+	for _, tile := range tiles {
+		wg.Add(1)
+		go func(tile Tile) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			errs[tile.Index] = c.queryTile(fetchCtx, buildQuery(aoi, tile, catalog), tile, visit)
+		}(tile)
 	}
+	wg.Wait()
 
-	coords, ok := geoData["coordinates"].([]interface{})
-	if !ok || len(coords) == 0 {
-		return nil, fmt.Errorf("no coordinates found in GeoJSON")
+	if emitErr != nil {
+		return nil, emitErr
+	}
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
 	}
 
-	ring, ok := coords[0].([]interface{})
-	if !ok || len(ring) == 0 {
-		return nil, fmt.Errorf("invalid polygon structure")
+	result := &QueryResult{TilesTotal: len(tiles)}
+	for source := range sources {
+		result.Sources = append(result.Sources, source)
+	}
+	result.Sources = mergeSources(result.Sources)
+	for i, err := range errs {
+		if err != nil {
+			log.Printf("Tile %d failed: %v", i, err)
+			result.FailedTiles = append(result.FailedTiles, tiles[i])
+		}
 	}
 
-	firstCoord, ok := ring[0].([]interface{})
-	if !ok || len(firstCoord) < 2 {
-		return nil, fmt.Errorf("invalid coordinate format")
+	if len(result.FailedTiles) == len(tiles) {
+		return nil, fmt.Errorf("all %d tiles failed: %w", len(tiles), errs[0])
 	}
 
-	minLon, _ := firstCoord[0].(float64)
-	minLat, _ := firstCoord[1].(float64)
-	maxLon, maxLat := minLon, minLat
+	return result, nil
+}
 
-	for _, coord := range ring {
-		c, ok := coord.([]interface{})
-		if !ok || len(c) < 2 {
-			continue
+// queryTile answers one tile's query from the cache if possible, otherwise
+// runs it against the healthiest mirror with retries. A failed attempt may
+// already have visited some elements; the caller deduplicates, so visiting
+// them again on retry is harmless.
+func (c *OverpassClient) queryTile(ctx context.Context, query string, tile Tile, visit func(OSMElement, DataSource)) error {
+	var key string
+	if c.cache != nil {
+		key = CacheKey(query, tile)
+		if body, endpoint, ok := c.cache.Get(key); ok {
+			err := decodeElements(bytes.NewReader(body), sourceVisitor(endpoint, visit))
+			if err == nil {
+				return nil
+			}
+			log.Printf("Ignoring unreadable cached response for tile %d: %v", tile.Index, err)
+		}
+	}
+
+	var err error
+	var last *mirror
+	for attempt := 0; attempt <= c.retry.MaxRetries; attempt++ {
+		m, recovering := c.mirrors.pick()
+
+		// Failing over to another mirror needs no delay; retrying the same
+		// one backs off, and never sooner than it is expected to recover
+		var delay time.Duration
+		if attempt > 0 && m == last {
+			delay = c.retry.backoff(attempt)
+		}
+		if recovering > delay {
+			delay = recovering
+		}
+		if attempt > 0 {
+			log.Printf("Retrying Overpass API query on %s in %v (attempt %d/%d)", m.url, delay.Round(time.Millisecond), attempt, c.retry.MaxRetries)
+		}
+		if delay > 0 {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(delay):
+			}
 		}
-		lon, _ := c[0].(float64)
-		lat, _ := c[1].(float64)
+		last = m
 
-		if lon < minLon {
-			minLon = lon
+		if err := m.limiter.Wait(ctx); err != nil {
+			return err
+		}
+
+		err = c.waitForSlot(ctx, m)
+		if err == nil {
+			err = c.fetchTile(ctx, m, query, key, tile, visit)
+		}
+		if err == nil {
+			c.mirrors.reportSuccess(m)
+			return nil
 		}
-		if lon > maxLon {
-			maxLon = lon
+		if ctx.Err() != nil {
+			return ctx.Err()
 		}
-		if lat < minLat {
-			minLat = lat
+
+		var oe *overpassError
+		if errors.As(err, &oe) && oe.permanent() {
+			return fmt.Errorf("Overpass API rejected the query: %w", err)
 		}
-		if lat > maxLat {
-			maxLat = lat
+
+		log.Printf("Overpass API error from %s: %v", m.url, err)
+		var cooldown time.Duration
+		if oe != nil && oe.retryAfter > 0 {
+			cooldown = oe.retryAfter
+			if c.retry.MaxDelay > 0 && cooldown > c.retry.MaxDelay {
+				cooldown = c.retry.MaxDelay
+			}
 		}
+		c.mirrors.reportFailure(m, cooldown)
 	}
 
-	return &Bounds{
-		MinLat: minLat,
-		MinLon: minLon,
-		MaxLat: maxLat,
-		MaxLon: maxLon,
-	}, nil
+	return fmt.Errorf("failed to query Overpass API after %d attempts: %w", c.retry.MaxRetries+1, err)
 }
 
-func (c *OverpassClient) buildQuery(bounds *Bounds) string {
-	// Build a query that fetches:
-	// - Buildings (way["building"])
-	// - Roads (way["highway"])
-	// - Power lines (way["power"="line"])
-	// - Railways (way["railway"])
+// fetchTile runs one request and decodes it, caching the raw response if it
+// decoded completely
+func (c *OverpassClient) fetchTile(ctx context.Context, m *mirror, query, key string, tile Tile, visit func(OSMElement, DataSource)) error {
+	body, err := c.executeQuery(ctx, m.url, query)
+	if err != nil {
+		return err
+	}
+	defer body.Close()
 
-	bbox := fmt.Sprintf("%f,%f,%f,%f", bounds.MinLat, bounds.MinLon, bounds.MaxLat, bounds.MaxLon)
+	// Keep a copy of the raw response for the cache while decoding
+	var raw bytes.Buffer
+	var r io.Reader = body
+	if c.cache != nil {
+		r = io.TeeReader(body, &raw)
+	}
 
+	if err := decodeElements(r, sourceVisitor(m.url, visit)); err != nil {
+		return err
+	}
+
+	if c.cache != nil {
+		if err := c.cache.Put(key, tile.Bounds, m.url, raw.Bytes()); err != nil {
+			log.Printf("Failed to cache response for tile %d: %v", tile.Index, err)
+		}
+	}
+	return nil
+}
+
+// buildQuery selects the catalog's features inside the AOI in a tile.
+// Overpass applies the AOI's poly filters, so only features near the AOI are
+// downloaded, cached or not. The rings are written canonically, so the same
+// AOI always gives the same query and cache key.
+func buildQuery(aoi *AOI, tile Tile, catalog *Catalog) string {
 	query := fmt.Sprintf(`
 		[out:json][timeout:60];
-		(
-			// Buildings
-			way["building"](%s);
-			relation["building"](%s);
-			
-			// Roads
-			way["highway"](%s);
-			
-			// Power infrastructure
-			way["power"="line"](%s);
-			node["power"="tower"](%s);
-			node["power"="pole"](%s);
-			
-			// Railways
-			way["railway"](%s);
-			
-			// Critical facilities
-			node["amenity"="hospital"](%s);
-			node["amenity"="fire_station"](%s);
-			way["amenity"="hospital"](%s);
-			way["amenity"="fire_station"](%s);
-		);
-		out geom;
-	`, bbox, bbox, bbox, bbox, bbox, bbox, bbox, bbox, bbox, bbox, bbox)
+%s		out meta geom;
+	`, aoi.PolyQuery(catalog.OverpassSelectors(), tile.bboxFilter()))
 
 	return query
 }
 
-type OverpassResponse struct {
-	Elements []OSMElement `json:"elements"`
-}
-
 type OSMElement struct {
 	Type     string            `json:"type"`
 	ID       int64             `json:"id"`
@@ -180,6 +328,14 @@ type OSMElement struct {
 	Lon      float64           `json:"lon,omitempty"`
 	Tags     map[string]string `json:"tags"`
 	Geometry []OSMNode         `json:"geometry,omitempty"`
+	Nodes    []int64           `json:"nodes,omitempty"` // Way node IDs, parallel to Geometry
+	Members  []OSMMember       `json:"members,omitempty"`
+
+	// Edit metadata from "out meta"
+	Version   int    `json:"version,omitempty"`
+	Timestamp string `json:"timestamp,omitempty"`
+	Changeset int64  `json:"changeset,omitempty"`
+	User      string `json:"user,omitempty"`
 }
 
 // OSMNode represents a node in OSM geometry
@@ -188,10 +344,11 @@ type OSMNode struct {
 	Lon float64 `json:"lon"`
 }
 
-// executeQuery executes the Overpass query
-func (c *OverpassClient) executeQuery(ctx context.Context, query string) (*OverpassResponse, error) {
+// executeQuery executes the Overpass query and returns the response body,
+// which the caller must close
+func (c *OverpassClient) executeQuery(ctx context.Context, apiURL, query string) (io.ReadCloser, error) {
 
-	req, err := http.NewRequestWithContext(ctx, "POST", c.apiURL, strings.NewReader(query))
+	req, err := http.NewRequestWithContext(ctx, "POST", apiURL, strings.NewReader(query))
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
@@ -202,43 +359,118 @@ func (c *OverpassClient) executeQuery(ctx context.Context, query string) (*Overp
 	if err != nil {
 		return nil, fmt.Errorf("failed to execute request: %w", err)
 	}
-	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("Overpass API returned status %d: %s", resp.StatusCode, string(body))
+		defer resp.Body.Close()
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		return nil, &overpassError{
+			status:     resp.StatusCode,
+			retryAfter: parseRetryAfter(resp.Header.Get("Retry-After")),
+			body:       string(body),
+		}
 	}
 
-	var overpassResp OverpassResponse
-	if err := json.NewDecoder(resp.Body).Decode(&overpassResp); err != nil {
-		return nil, fmt.Errorf("failed to decode response: %w", err)
+	return resp.Body, nil
+}
+
+// decodeElements reads an Overpass JSON response one element at a time, so
+// only a single element is held in memory however large the response is.
+// Overpass reports queries that time out or run out of memory with a 200 and
+// a runtime error remark after a truncated element list; that is an error.
+// Elements are visited with the response's osm3s.timestamp_osm_base, which
+// Overpass sends before them.
+func decodeElements(r io.Reader, visit func(element OSMElement, osmBase string)) error {
+	decoder := json.NewDecoder(r)
+	var osmBase string
+
+	if err := expectDelim(decoder, '{'); err != nil {
+		return err
 	}
 
-	return &overpassResp, nil
-}
+	for decoder.More() {
+		token, err := decoder.Token()
+		if err != nil {
+			return fmt.Errorf("failed to decode response: %w", err)
+		}
+
+		if token == "remark" {
+			var remark string
+			if err := decoder.Decode(&remark); err != nil {
+				return fmt.Errorf("failed to decode response: %w", err)
+			}
+			if strings.Contains(remark, "runtime error") {
+				return fmt.Errorf("Overpass query incomplete: %s", remark)
+			}
+			continue
+		}
+
+		if token == "osm3s" {
+			var osm3s struct {
+				TimestampOSMBase string `json:"timestamp_osm_base"`
+			}
+			if err := decoder.Decode(&osm3s); err != nil {
+				return fmt.Errorf("failed to decode response: %w", err)
+			}
+			osmBase = osm3s.TimestampOSMBase
+			continue
+		}
 
-func (c *OverpassClient) convertToAssets(response *OverpassResponse) []Asset {
-	assets := make([]Asset, 0, len(response.Elements))
+		if token != "elements" {
+			var skip json.RawMessage
+			if err := decoder.Decode(&skip); err != nil {
+				return fmt.Errorf("failed to decode response: %w", err)
+			}
+			continue
+		}
 
-	for _, element := range response.Elements {
-		asset := c.convertElement(element)
-		if asset != nil {
-			assets = append(assets, *asset)
+		if err := expectDelim(decoder, '['); err != nil {
+			return err
+		}
+		for decoder.More() {
+			var element OSMElement
+			if err := decoder.Decode(&element); err != nil {
+				return fmt.Errorf("failed to decode element: %w", err)
+			}
+			visit(element, osmBase)
+		}
+		if err := expectDelim(decoder, ']'); err != nil {
+			return err
 		}
 	}
 
-	return assets
+	return expectDelim(decoder, '}')
+}
+
+// sourceVisitor tags a response's elements with the endpoint it came from.
+// Responses cached before endpoints were recorded have none.
+func sourceVisitor(endpoint string, visit func(OSMElement, DataSource)) func(OSMElement, string) {
+	return func(element OSMElement, osmBase string) {
+		visit(element, DataSource{Endpoint: endpoint, DataTimestamp: osmBase})
+	}
+}
+
+func expectDelim(decoder *json.Decoder, want json.Delim) error {
+	token, err := decoder.Token()
+	if err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+	if token != want {
+		return fmt.Errorf("failed to decode response: expected %v, got %v", want, token)
+	}
+	return nil
 }
 
-// convertElement converts a single OSM element to an Asset
-func (c *OverpassClient) convertElement(element OSMElement) *Asset {
+// convertElement converts a single OSM element to an Asset, shaping its
+// geometry as the matching catalog entry says
+func convertElement(element OSMElement, catalog *Catalog) *Asset {
 
-	assetType := c.determineAssetType(element.Tags)
-	if assetType == "" {
+	entry := catalog.Classify(element.Type, element.Tags)
+	if entry == nil {
 		return nil
 	}
 
 	var geometry *geojson.Geometry
+	var nodeIDs []int64
 
 	switch element.Type {
 	case "node":
@@ -256,18 +488,27 @@ func (c *OverpassClient) convertElement(element OSMElement) *Asset {
 			coords[i] = []float64{node.Lon, node.Lat}
 		}
 
-		if element.Tags["building"] != "" && len(coords) > 3 &&
+		if len(element.Nodes) == len(element.Geometry) {
+			nodeIDs = element.Nodes
+		}
+
+		if entry.Geometry == GeometryArea && len(coords) > 3 &&
 			coords[0][0] == coords[len(coords)-1][0] &&
 			coords[0][1] == coords[len(coords)-1][1] {
-			// It's a polygon (building)
 			geometry = geojson.NewPolygonGeometry([][][]float64{coords})
 		} else {
-			// It's a line (road, power line, etc.)
 			geometry = geojson.NewLineStringGeometry(coords)
 		}
 
 	case "relation":
-		return nil
+
+		switch element.Tags["type"] {
+		case "multipolygon", "building":
+			geometry = assembleRelation(element)
+		}
+		if geometry == nil {
+			return nil
+		}
 
 	default:
 		return nil
@@ -281,36 +522,18 @@ func (c *OverpassClient) convertElement(element OSMElement) *Asset {
 	properties["osm_type"] = element.Type
 
 	return &Asset{
-		Type:       assetType,
-		Geometry:   geometry,
-		Properties: properties,
-	}
-}
-
-// determineAssetType determines the asset type from OSM tags
-func (c *OverpassClient) determineAssetType(tags map[string]string) string {
-
-	if tags["building"] != "" {
-		return "building"
-	}
-	if tags["highway"] != "" {
-		return "road"
-	}
-	if tags["power"] == "line" {
-		return "power_line"
-	}
-	if tags["power"] == "tower" || tags["power"] == "pole" {
-		return "power_infrastructure"
-	}
-	if tags["railway"] != "" {
-		return "railway"
-	}
-	if tags["amenity"] == "hospital" {
-		return "hospital"
-	}
-	if tags["amenity"] == "fire_station" {
-		return "fire_station"
+		Type:                entry.Type,
+		Geometry:            geometry,
+		Properties:          properties,
+		VulnerabilityWeight: entry.VulnerabilityWeight,
+		Measures:            measure(geometry),
+		NumericTags:         parseNumericTags(element.Tags),
+		NodeIDs:             nodeIDs,
+		Provenance: Provenance{
+			Version:   element.Version,
+			Timestamp: element.Timestamp,
+			Changeset: element.Changeset,
+			User:      element.User,
+		},
 	}
-
-	return ""
 }
@@ -0,0 +1,394 @@
+package osm
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math"
+	"os"
+	"runtime"
+	"sort"
+	"time"
+
+	osmlib "github.com/paulmach/osm"
+	"github.com/paulmach/osm/osmpbf"
+
+	"wildfire-risk-platform/shared/geo"
+)
+
+// PBFSource serves assets and landcover from a local .osm.pbf extract. The
+// extract is read once at startup into memory and each layer is indexed on a
+// regular grid, so results only change when the file does.
+type PBFSource struct {
+	path      string
+	bounds    *geo.BBox // Extract bounds from the file header, if present
+	source    DataSource
+	cellSize  float64
+	assets    *pbfLayer
+	landcover *pbfLayer
+	options   LandcoverOptions
+}
+
+// pbfLayer is the features one catalog selects from the extract
+type pbfLayer struct {
+	catalog  *Catalog
+	features []Asset
+	index    map[[2]int][]int // Grid cell -> positions in features
+}
+
+// NewPBFSource loads every asset in the catalog and every landcover polygon
+// in the fuel table from the extract and builds grid indexes with cells of
+// cellSize degrees
+func NewPBFSource(ctx context.Context, path string, cellSize float64, catalog *Catalog, landcover LandcoverOptions) (*PBFSource, error) {
+	if cellSize <= 0 {
+		return nil, fmt.Errorf("PBF index cell size must be positive, got %v", cellSize)
+	}
+
+	s := &PBFSource{
+		path:      path,
+		cellSize:  cellSize,
+		assets:    &pbfLayer{catalog: catalog, index: make(map[[2]int][]int)},
+		landcover: &pbfLayer{catalog: landcover.Fuel.catalog, index: make(map[[2]int][]int)},
+		options:   landcover,
+		source:    DataSource{Endpoint: path},
+	}
+
+	elements, err := s.load(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, element := range elements {
+		for _, layer := range []*pbfLayer{s.assets, s.landcover} {
+			feature := convertElement(element, layer.catalog)
+			if feature == nil {
+				continue
+			}
+			feature.Provenance.DataSource = s.source
+
+			vertices, _, _ := decompose(feature.Geometry)
+			if len(vertices) == 0 {
+				continue
+			}
+
+			position := len(layer.features)
+			layer.features = append(layer.features, *feature)
+			for _, cell := range s.cells(geo.BoundsOf(vertices)) {
+				layer.index[cell] = append(layer.index[cell], position)
+			}
+		}
+	}
+
+	log.Printf("Loaded %d assets and %d landcover features from %s", len(s.assets.features), len(s.landcover.features), path)
+	return s, nil
+}
+
+// QueryAssets returns the extract's assets that intersect the AOI and pass
+// the filter, in file order. The extract is a single tile that never fails.
+func (s *PBFSource) QueryAssets(ctx context.Context, areaGeoJSON string, filter AssetFilter) (*QueryResult, error) {
+	aoi, err := ParseAOI(areaGeoJSON)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse AOI: %w", err)
+	}
+
+	catalog, err := s.assets.catalog.Narrow(filter)
+	if err != nil {
+		return nil, fmt.Errorf("invalid asset filter: %w", err)
+	}
+
+	assets, err := s.query(ctx, s.assets, aoi, catalog)
+	if err != nil {
+		return nil, err
+	}
+
+	return &QueryResult{
+		Assets:     assets,
+		TilesTotal: 1,
+		Sources:    []DataSource{s.source},
+	}, nil
+}
+
+// StreamAssets emits the same assets as QueryAssets. The extract is already
+// in memory, so this only saves the caller from holding them all.
+func (s *PBFSource) StreamAssets(ctx context.Context, areaGeoJSON string, filter AssetFilter, emit func(Asset) error) (*QueryResult, error) {
+	result, err := s.QueryAssets(ctx, areaGeoJSON, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, asset := range result.Assets {
+		if err := emit(asset); err != nil {
+			return nil, err
+		}
+	}
+
+	result.Assets = nil
+	return result, nil
+}
+
+// QueryLandcover returns the extract's landcover polygons in the AOI and the
+// share of each fuel class
+func (s *PBFSource) QueryLandcover(ctx context.Context, areaGeoJSON string) (*LandcoverResult, error) {
+	aoi, err := ParseAOI(areaGeoJSON)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse AOI: %w", err)
+	}
+
+	features, err := s.query(ctx, s.landcover, aoi, s.landcover.catalog)
+	if err != nil {
+		return nil, err
+	}
+
+	result := s.options.Fuel.summarise(aoi, features, s.options.SampleM)
+	result.TilesTotal = 1
+	result.Sources = []DataSource{s.source}
+	return result, nil
+}
+
+// query returns the layer's features that intersect the AOI and that catalog
+// still classifies with the same type, in file order
+func (s *PBFSource) query(ctx context.Context, layer *pbfLayer, aoi *AOI, catalog *Catalog) ([]Asset, error) {
+	seen := make(map[int]bool)
+	var positions []int
+	for _, b := range aoi.Regions() {
+		if s.bounds != nil && !(s.bounds.Contains(geo.Point{b.MinLon, b.MinLat}) && s.bounds.Contains(geo.Point{b.MaxLon, b.MaxLat})) {
+			log.Printf("AOI extends beyond the extract %s; features outside it are missing", s.path)
+		}
+
+		for _, cell := range s.cells(b) {
+			for _, position := range layer.index[cell] {
+				if !seen[position] {
+					seen[position] = true
+					positions = append(positions, position)
+				}
+			}
+		}
+	}
+	sort.Ints(positions)
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	features := make([]Asset, 0, len(positions))
+	for _, position := range positions {
+		feature := layer.features[position]
+
+		// The index holds every catalog type; a narrowed catalog must agree
+		entry := catalog.Classify(feature.Properties["osm_type"].(string), assetTags(feature))
+		if entry != nil && entry.Type == feature.Type && aoi.Admit(&feature) {
+			features = append(features, feature)
+		}
+	}
+
+	return features, nil
+}
+
+// cells lists the grid cells a box overlaps
+func (s *PBFSource) cells(b geo.BBox) [][2]int {
+	minX, minY := int(math.Floor(b.MinLon/s.cellSize)), int(math.Floor(b.MinLat/s.cellSize))
+	maxX, maxY := int(math.Floor(b.MaxLon/s.cellSize)), int(math.Floor(b.MaxLat/s.cellSize))
+
+	cells := make([][2]int, 0, (maxX-minX+1)*(maxY-minY+1))
+	for x := minX; x <= maxX; x++ {
+		for y := minY; y <= maxY; y++ {
+			cells = append(cells, [2]int{x, y})
+		}
+	}
+	return cells
+}
+
+// load reads the extract in three passes, relations then ways then nodes, so
+// that only the ways and nodes the selected features reference are kept.
+// The result has the same shape as an Overpass "out geom" response.
+func (s *PBFSource) load(ctx context.Context) ([]OSMElement, error) {
+	var relations []*osmlib.Relation
+	wantedWays := make(map[osmlib.WayID]bool)
+
+	err := s.scan(ctx, func(scanner *osmpbf.Scanner) { scanner.SkipNodes, scanner.SkipWays = true, true },
+		func(object osmlib.Object) {
+			relation, ok := object.(*osmlib.Relation)
+			if !ok || !s.selected("relation", relation.Tags.Map()) {
+				return
+			}
+			relations = append(relations, relation)
+			for _, member := range relation.Members {
+				if member.Type == osmlib.TypeWay {
+					wantedWays[osmlib.WayID(member.Ref)] = true
+				}
+			}
+		})
+	if err != nil {
+		return nil, err
+	}
+
+	var ways []*osmlib.Way
+	memberWays := make(map[osmlib.WayID]*osmlib.Way)
+	wantedNodes := make(map[osmlib.NodeID]bool)
+
+	err = s.scan(ctx, func(scanner *osmpbf.Scanner) { scanner.SkipNodes, scanner.SkipRelations = true, true },
+		func(object osmlib.Object) {
+			way, ok := object.(*osmlib.Way)
+			if !ok {
+				return
+			}
+			selected := s.selected("way", way.Tags.Map())
+			if !selected && !wantedWays[way.ID] {
+				return
+			}
+			if selected {
+				ways = append(ways, way)
+			}
+			if wantedWays[way.ID] {
+				memberWays[way.ID] = way
+			}
+			for _, node := range way.Nodes {
+				wantedNodes[node.ID] = true
+			}
+		})
+	if err != nil {
+		return nil, err
+	}
+
+	var elements []OSMElement
+	coords := make(map[osmlib.NodeID]OSMNode, len(wantedNodes))
+
+	err = s.scan(ctx, func(scanner *osmpbf.Scanner) { scanner.SkipWays, scanner.SkipRelations = true, true },
+		func(object osmlib.Object) {
+			node, ok := object.(*osmlib.Node)
+			if !ok {
+				return
+			}
+			if wantedNodes[node.ID] {
+				coords[node.ID] = OSMNode{Lat: node.Lat, Lon: node.Lon}
+			}
+			tags := node.Tags.Map()
+			if s.selected("node", tags) {
+				element := OSMElement{
+					Type: "node",
+					ID:   int64(node.ID),
+					Lat:  node.Lat,
+					Lon:  node.Lon,
+					Tags: tags,
+				}
+				withMeta(&element, node.Version, node.Timestamp, int64(node.ChangesetID), node.User)
+				elements = append(elements, element)
+			}
+		})
+	if err != nil {
+		return nil, err
+	}
+
+	wayGeometry := func(way *osmlib.Way) ([]OSMNode, []int64) {
+		geometry := make([]OSMNode, 0, len(way.Nodes))
+		ids := make([]int64, 0, len(way.Nodes))
+		for _, node := range way.Nodes {
+			// Extracts clipped at their boundary reference nodes they do not contain
+			if c, ok := coords[node.ID]; ok {
+				geometry = append(geometry, c)
+				ids = append(ids, int64(node.ID))
+			}
+		}
+		return geometry, ids
+	}
+
+	for _, way := range ways {
+		geometry, ids := wayGeometry(way)
+		element := OSMElement{
+			Type:     "way",
+			ID:       int64(way.ID),
+			Tags:     way.Tags.Map(),
+			Geometry: geometry,
+			Nodes:    ids,
+		}
+		withMeta(&element, way.Version, way.Timestamp, int64(way.ChangesetID), way.User)
+		elements = append(elements, element)
+	}
+
+	for _, relation := range relations {
+		element := OSMElement{
+			Type: "relation",
+			ID:   int64(relation.ID),
+			Tags: relation.Tags.Map(),
+		}
+		withMeta(&element, relation.Version, relation.Timestamp, int64(relation.ChangesetID), relation.User)
+		for _, member := range relation.Members {
+			m := OSMMember{Type: string(member.Type), Ref: member.Ref, Role: member.Role}
+			if way, ok := memberWays[osmlib.WayID(member.Ref)]; ok && member.Type == osmlib.TypeWay {
+				m.Geometry, _ = wayGeometry(way)
+			}
+			element.Members = append(element.Members, m)
+		}
+		elements = append(elements, element)
+	}
+
+	return elements, nil
+}
+
+// withMeta copies edit metadata onto an element as "out meta" would. Extracts
+// written without metadata leave it empty.
+func withMeta(element *OSMElement, version int, timestamp time.Time, changeset int64, user string) {
+	element.Version = version
+	element.Changeset = changeset
+	element.User = user
+	if !timestamp.IsZero() {
+		element.Timestamp = timestamp.UTC().Format(time.RFC3339)
+	}
+}
+
+// scan makes one pass over the extract, calling visit for every object the
+// configured scanner does not skip
+func (s *PBFSource) scan(ctx context.Context, configure func(*osmpbf.Scanner), visit func(osmlib.Object)) error {
+	f, err := os.Open(s.path)
+	if err != nil {
+		return fmt.Errorf("failed to open PBF extract: %w", err)
+	}
+	defer f.Close()
+
+	scanner := osmpbf.New(ctx, f, runtime.GOMAXPROCS(0))
+	defer scanner.Close()
+	configure(scanner)
+
+	if s.bounds == nil {
+		header, err := scanner.Header()
+		if err != nil {
+			return fmt.Errorf("failed to read PBF header: %w", err)
+		}
+		if !header.ReplicationTimestamp.IsZero() {
+			s.source.DataTimestamp = header.ReplicationTimestamp.UTC().Format(time.RFC3339)
+		}
+		if header.Bounds != nil {
+			s.bounds = &geo.BBox{
+				MinLon: header.Bounds.MinLon,
+				MinLat: header.Bounds.MinLat,
+				MaxLon: header.Bounds.MaxLon,
+				MaxLat: header.Bounds.MaxLat,
+			}
+		}
+	}
+
+	for scanner.Scan() {
+		visit(scanner.Object())
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("failed to read PBF extract: %w", err)
+	}
+
+	return nil
+}
+
+// assetTags recovers the OSM tags from an asset's properties
+func assetTags(asset Asset) map[string]string {
+	tags := make(map[string]string, len(asset.Properties))
+	for key, value := range asset.Properties {
+		if v, ok := value.(string); ok {
+			tags[key] = v
+		}
+	}
+	return tags
+}
+
+// selected reports whether any layer keeps the element
+func (s *PBFSource) selected(kind string, tags map[string]string) bool {
+	return s.assets.catalog.Classify(kind, tags) != nil || s.landcover.catalog.Classify(kind, tags) != nil
+}
@@ -0,0 +1,334 @@
+package osm
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math"
+	"sort"
+
+	geojson "github.com/paulmach/go.geojson"
+
+	"wildfire-risk-platform/shared/geo"
+)
+
+// Catalog types the power line analysis reads
+const (
+	PowerLineAssetType    = "power_line"
+	PowerSupportAssetType = "power_infrastructure"
+)
+
+// Exposure score weights. Slope's share goes to the others for a span with no
+// elevation data under it, which is reported by leaving its slope unset.
+const (
+	fuelExposureWeight     = 0.5
+	slopeExposureWeight    = 0.2
+	downwindExposureWeight = 0.3
+
+	steepSlopeDeg      = 30 // Slopes this steep or steeper score 1
+	downwindBuildingsK = 20 // Buildings downwind at which the score reaches 1 - 1/e
+)
+
+// Elevation gives the ground height in metres. ok is false outside its
+// coverage.
+type Elevation interface {
+	ElevationAt(p geo.Point) (metres float64, ok bool)
+}
+
+// SpanOptions configures the power line exposure analysis
+type SpanOptions struct {
+	CorridorM       float64   // Fuel is sampled this far either side of a span
+	SampleM         float64   // Spacing of samples along a span
+	WindFromDeg     *float64  // Direction the wind blows from; nil counts buildings in every direction
+	DownwindM       float64   // Buildings up to this far from a span count
+	DownwindHalfDeg float64   // Half-width of the downwind sector
+	Elevation       Elevation // Required; slope is part of every span's score
+}
+
+// PowerSpan is the stretch of a power line between two supports and its
+// ignition exposure
+type PowerSpan struct {
+	WayID                  int64
+	FromSupport, ToSupport int64 // OSM node IDs of the towers or poles
+	Geometry               []geo.Point
+	LengthM                float64
+	VoltageV               *float64
+	Operator               string
+
+	FuelShares        map[string]float64 // Share of corridor samples per fuel class
+	FuelUnder         string             // Dominant class directly below, "" when mostly unclassified
+	SlopeDeg          *float64           // Mean ground slope along the span
+	BuildingsDownwind int
+	Score             float64 // 0.0-1.0
+	Rank              int     // 1 is the most exposed span
+}
+
+// SpanAnalysis is every span in the AOI, most exposed first
+type SpanAnalysis struct {
+	Spans       []PowerSpan
+	TilesTotal  int
+	FailedTiles []Tile
+	Sources     []DataSource
+}
+
+// AnalyzePowerSpans splits the power lines in the AOI into spans between
+// supports and ranks them by exposure: fuel under and beside the span, ground
+// slope and the number of buildings downwind.
+func AnalyzePowerSpans(ctx context.Context, source AssetSource, fuel *FuelTable, aoiGeoJSON string, opts SpanOptions) (*SpanAnalysis, error) {
+	if opts.Elevation == nil {
+		return nil, fmt.Errorf("power line analysis needs elevation data")
+	}
+
+	aoi, err := ParseAOI(aoiGeoJSON)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse AOI: %w", err)
+	}
+
+	lines, err := source.QueryAssets(ctx, aoiGeoJSON, AssetFilter{AssetTypes: []string{PowerLineAssetType, PowerSupportAssetType}})
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch power lines: %w", err)
+	}
+	buildings, err := source.QueryAssets(ctx, aroundGeoJSON(aoi, opts.DownwindM), AssetFilter{AssetTypes: []string{BuildingAssetType}})
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch buildings: %w", err)
+	}
+	landcover, err := source.QueryLandcover(ctx, aroundGeoJSON(aoi, opts.CorridorM))
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch landcover: %w", err)
+	}
+
+	analysis := &SpanAnalysis{
+		TilesTotal:  lines.TilesTotal + buildings.TilesTotal + landcover.TilesTotal,
+		FailedTiles: append(append(lines.FailedTiles, buildings.FailedTiles...), landcover.FailedTiles...),
+		Sources:     mergeSources(lines.Sources, buildings.Sources, landcover.Sources),
+	}
+
+	supports := make(map[int64]bool)
+	for _, asset := range lines.Assets {
+		if asset.Type == PowerSupportAssetType && asset.Properties["osm_type"] == "node" {
+			supports[asset.Properties["osm_id"].(int64)] = true
+		}
+	}
+
+	fuelIdx := fuel.index(landcover.Features)
+	downwind := newPointIndex(buildings.Assets, opts.DownwindM)
+
+	var count int
+	for _, line := range lines.Assets {
+		if line.Type != PowerLineAssetType {
+			continue
+		}
+		count++
+		for _, span := range splitSpans(line, supports) {
+			if intersects, _ := aoi.Relate(geojson.NewLineStringGeometry(coordsOf(span.Geometry))); !intersects {
+				continue
+			}
+			span.expose(fuel, fuelIdx, downwind, opts)
+			analysis.Spans = append(analysis.Spans, span)
+		}
+	}
+
+	sort.SliceStable(analysis.Spans, func(i, j int) bool { return analysis.Spans[i].Score > analysis.Spans[j].Score })
+	for i := range analysis.Spans {
+		analysis.Spans[i].Rank = i + 1
+	}
+
+	log.Printf("Ranked %d power line spans from %d lines", len(analysis.Spans), count)
+	return analysis, nil
+}
+
+// splitSpans cuts a line at every vertex that is a known tower or pole. A
+// line with no known supports is cut at every vertex, as lines are usually
+// drawn support to support.
+func splitSpans(line Asset, supports map[int64]bool) []PowerSpan {
+	if line.Geometry == nil || line.Geometry.Type != geojson.GeometryLineString {
+		return nil
+	}
+	points := geo.PointsFromCoords(line.Geometry.LineString)
+	ids := line.NodeIDs
+	if len(ids) != len(points) {
+		ids = make([]int64, len(points)) // Supports cannot be matched without IDs
+	}
+
+	known := false
+	for _, id := range ids {
+		known = known || supports[id]
+	}
+
+	operator, _ := line.Properties["operator"].(string)
+	var spans []PowerSpan
+	start := 0
+	for i := 1; i < len(points); i++ {
+		if known && !supports[ids[i]] && i != len(points)-1 {
+			continue
+		}
+		geometry := points[start : i+1]
+		spans = append(spans, PowerSpan{
+			WayID:       line.Properties["osm_id"].(int64),
+			FromSupport: ids[start],
+			ToSupport:   ids[i],
+			Geometry:    geometry,
+			LengthM:     geo.LineLength(geometry),
+			VoltageV:    line.NumericTags.VoltageV,
+			Operator:    operator,
+		})
+		start = i
+	}
+	return spans
+}
+
+// expose samples the span's corridor and scores it
+func (s *PowerSpan) expose(fuel *FuelTable, fuelIdx *fuelIndex, downwind *pointIndex, opts SpanOptions) {
+	counts := make([]int, len(fuel.Classes))
+	under := make([]int, len(fuel.Classes)+1) // Last slot counts unclassified
+	var samples int
+	var slopes []float64
+
+	for _, sample := range samplesAlong(s.Geometry, opts.SampleM) {
+		for _, offset := range []float64{-opts.CorridorM, 0, opts.CorridorM} {
+			p := sample.point
+			if offset != 0 {
+				p = geo.Offset(p, sample.bearing+90, offset)
+			}
+
+			rank := fuelIdx.classAt(p)
+			samples++
+			if rank >= 0 {
+				counts[rank]++
+			}
+			if offset == 0 {
+				if rank < 0 {
+					rank = len(fuel.Classes)
+				}
+				under[rank]++
+			}
+		}
+
+		if slope, ok := slopeAt(opts.Elevation, sample.point, math.Max(opts.SampleM, 10)); ok {
+			slopes = append(slopes, slope)
+		}
+	}
+
+	// Fuel scores by class, from 1 for the most hazardous down to 1/n
+	var fuelScore float64
+	s.FuelShares = make(map[string]float64)
+	for i, class := range fuel.Classes {
+		share := float64(counts[i]) / float64(samples)
+		s.FuelShares[class.Name] = share
+		fuelScore += share * float64(len(fuel.Classes)-i) / float64(len(fuel.Classes))
+	}
+
+	best := len(fuel.Classes)
+	for i, n := range under {
+		if n > under[best] {
+			best = i
+		}
+	}
+	if best < len(fuel.Classes) {
+		s.FuelUnder = fuel.Classes[best].Name
+	}
+
+	s.BuildingsDownwind = downwind.countInSector(s.Geometry[len(s.Geometry)/2], s.Geometry, opts)
+	peopleScore := 1 - math.Exp(-float64(s.BuildingsDownwind)/downwindBuildingsK)
+
+	if len(slopes) > 0 {
+		var mean float64
+		for _, slope := range slopes {
+			mean += slope / float64(len(slopes))
+		}
+		s.SlopeDeg = &mean
+		s.Score = fuelExposureWeight*fuelScore + slopeExposureWeight*math.Min(mean/steepSlopeDeg, 1) + downwindExposureWeight*peopleScore
+	} else {
+		total := fuelExposureWeight + downwindExposureWeight
+		s.Score = (fuelExposureWeight*fuelScore + downwindExposureWeight*peopleScore) / total
+	}
+}
+
+type spanSample struct {
+	point   geo.Point
+	bearing float64 // Direction of the line at the sample
+}
+
+// samplesAlong places samples every stepM along a polyline, both ends included
+func samplesAlong(points []geo.Point, stepM float64) []spanSample {
+	var samples []spanSample
+	for i := 0; i+1 < len(points); i++ {
+		a, b := points[i], points[i+1]
+		length := geo.Distance(a, b)
+		bearing := geo.Bearing(a, b)
+		n := int(math.Max(1, math.Ceil(length/stepM)))
+		for k := 0; k < n; k++ {
+			t := float64(k) / float64(n)
+			samples = append(samples, spanSample{geo.Point{a[0] + t*(b[0]-a[0]), a[1] + t*(b[1]-a[1])}, bearing})
+		}
+	}
+	if len(points) > 1 {
+		last := len(points) - 1
+		samples = append(samples, spanSample{points[last], geo.Bearing(points[last-1], points[last])})
+	}
+	return samples
+}
+
+// slopeAt estimates the ground slope in degrees from central differences
+// over d metres
+func slopeAt(elevation Elevation, p geo.Point, d float64) (float64, bool) {
+	var h [4]float64
+	for i, bearing := range []float64{90, 270, 0, 180} {
+		var ok bool
+		if h[i], ok = elevation.ElevationAt(geo.Offset(p, bearing, d)); !ok {
+			return 0, false
+		}
+	}
+
+	dzdx := (h[0] - h[1]) / (2 * d)
+	dzdy := (h[2] - h[3]) / (2 * d)
+	return math.Atan(math.Hypot(dzdx, dzdy)) * 180 / math.Pi, true
+}
+
+// pointIndex is a grid of asset centroids for counting nearby assets
+type pointIndex struct {
+	cellDeg float64
+	cells   map[geo.Cell][]geo.Point
+}
+
+func newPointIndex(assets []Asset, cellM float64) *pointIndex {
+	idx := &pointIndex{cellDeg: math.Max(cellM/metresPerDegree, 1e-4), cells: make(map[geo.Cell][]geo.Point)}
+	for _, asset := range assets {
+		c := asset.Measures.Centroid
+		cell := geo.Cell{int(math.Floor(c.Lon() / idx.cellDeg)), int(math.Floor(c.Lat() / idx.cellDeg))}
+		idx.cells[cell] = append(idx.cells[cell], c)
+	}
+	return idx
+}
+
+// countInSector counts points within DownwindM of the span's midpoint and,
+// when the wind is known, within DownwindHalfDeg of the downwind bearing
+func (idx *pointIndex) countInSector(mid geo.Point, span []geo.Point, opts SpanOptions) int {
+	b := geo.BoundsOf(span).Expand(opts.DownwindM)
+	count := 0
+	for x := int(math.Floor(b.MinLon / idx.cellDeg)); x <= int(math.Floor(b.MaxLon/idx.cellDeg)); x++ {
+		for y := int(math.Floor(b.MinLat / idx.cellDeg)); y <= int(math.Floor(b.MaxLat/idx.cellDeg)); y++ {
+			for _, p := range idx.cells[geo.Cell{x, y}] {
+				if geo.Distance(mid, p) > opts.DownwindM {
+					continue
+				}
+				if opts.WindFromDeg != nil {
+					off := math.Abs(math.Mod(geo.Bearing(mid, p)-(*opts.WindFromDeg+180)+540, 360) - 180)
+					if off > opts.DownwindHalfDeg {
+						continue
+					}
+				}
+				count++
+			}
+		}
+	}
+	return count
+}
+
+func coordsOf(points []geo.Point) [][]float64 {
+	coords := make([][]float64, len(points))
+	for i, p := range points {
+		coords[i] = []float64{p.Lon(), p.Lat()}
+	}
+	return coords
+}
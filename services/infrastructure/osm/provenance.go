@@ -0,0 +1,42 @@
+package osm
+
+import "sort"
+
+// DataSource is where a response's elements were read from and how current
+// the data behind it was
+type DataSource struct {
+	Endpoint      string `json:"endpoint"`                 // Overpass API URL or PBF extract path
+	DataTimestamp string `json:"data_timestamp,omitempty"` // osm3s.timestamp_osm_base, or the extract's replication time
+}
+
+// Provenance is an asset's OSM edit metadata from "out meta" and the source
+// it was fetched from
+type Provenance struct {
+	Version   int    `json:"version,omitempty"`
+	Timestamp string `json:"timestamp,omitempty"` // Last edit, RFC 3339
+	Changeset int64  `json:"changeset,omitempty"`
+	User      string `json:"user,omitempty"`
+	DataSource
+}
+
+// mergeSources returns the distinct sources of several results, sorted
+func mergeSources(lists ...[]DataSource) []DataSource {
+	seen := make(map[DataSource]bool)
+	var merged []DataSource
+	for _, list := range lists {
+		for _, source := range list {
+			if !seen[source] {
+				seen[source] = true
+				merged = append(merged, source)
+			}
+		}
+	}
+
+	sort.Slice(merged, func(i, j int) bool {
+		if merged[i].Endpoint != merged[j].Endpoint {
+			return merged[i].Endpoint < merged[j].Endpoint
+		}
+		return merged[i].DataTimestamp < merged[j].DataTimestamp
+	})
+	return merged
+}
@@ -0,0 +1,159 @@
+package osm
+
+import (
+	"log"
+	"math"
+	"sort"
+
+	geojson "github.com/paulmach/go.geojson"
+
+	"wildfire-risk-platform/shared/geo"
+)
+
+// OSMMember is a relation member as returned by "out geom"
+type OSMMember struct {
+	Type     string    `json:"type"`
+	Ref      int64     `json:"ref"`
+	Role     string    `json:"role"`
+	Lat      float64   `json:"lat,omitempty"`
+	Lon      float64   `json:"lon,omitempty"`
+	Geometry []OSMNode `json:"geometry,omitempty"`
+}
+
+// assembleRelation builds a Polygon or MultiPolygon from a multipolygon or
+// building relation. Way members are joined end to end into closed rings,
+// inner rings are attached to the outer ring that contains them, and rings
+// are wound counter-clockwise (outer) and clockwise (inner) per RFC 7946.
+// It returns nil if no closed outer ring can be formed.
+func assembleRelation(element OSMElement) *geojson.Geometry {
+	var outerWays, innerWays [][]geo.Point
+
+	relationType := element.Tags["type"]
+	for _, member := range element.Members {
+		if member.Type != "way" || len(member.Geometry) < 2 {
+			continue
+		}
+
+		points := make([]geo.Point, len(member.Geometry))
+		for i, node := range member.Geometry {
+			points[i] = geo.Point{node.Lon, node.Lat}
+		}
+
+		switch {
+		case relationType == "building" && member.Role == "outline":
+			outerWays = append(outerWays, points)
+		case relationType == "building":
+			// Building relations group their parts; only the outline is the footprint
+		case member.Role == "inner":
+			innerWays = append(innerWays, points)
+		default:
+			// Older multipolygons often leave the outer role empty
+			outerWays = append(outerWays, points)
+		}
+	}
+
+	outers := joinRings(outerWays, element.ID)
+	if len(outers) == 0 {
+		return nil
+	}
+	inners := joinRings(innerWays, element.ID)
+
+	// Attach each inner ring to the smallest outer ring that contains it
+	sort.Slice(outers, func(i, j int) bool {
+		return math.Abs(outers[i].SignedArea()) < math.Abs(outers[j].SignedArea())
+	})
+
+	polygons := make([]geo.Polygon, len(outers))
+	for i, outer := range outers {
+		if outer.SignedArea() < 0 {
+			outer = outer.Reversed()
+		}
+		polygons[i] = geo.Polygon{outer}
+	}
+
+	for _, inner := range inners {
+		if inner.SignedArea() > 0 {
+			inner = inner.Reversed()
+		}
+
+		attached := false
+		for i := range polygons {
+			if polygons[i][0].Contains(interiorProbe(inner)) {
+				polygons[i] = append(polygons[i], inner)
+				attached = true
+				break
+			}
+		}
+		if !attached {
+			log.Printf("Relation %d: inner ring outside every outer ring, skipping", element.ID)
+		}
+	}
+
+	if len(polygons) == 1 {
+		return geojson.NewPolygonGeometry(polygons[0].Coords())
+	}
+
+	coords := make([][][][]float64, len(polygons))
+	for i, polygon := range polygons {
+		coords[i] = polygon.Coords()
+	}
+	return geojson.NewMultiPolygonGeometry(coords...)
+}
+
+// joinRings stitches ways that share end nodes into closed rings, reversing
+// ways where needed. Ways that cannot be closed are dropped.
+func joinRings(ways [][]geo.Point, relationID int64) []geo.Ring {
+	var rings []geo.Ring
+	used := make([]bool, len(ways))
+
+	for start := range ways {
+		if used[start] {
+			continue
+		}
+		used[start] = true
+
+		ring := append([]geo.Point(nil), ways[start]...)
+		for ring[0] != ring[len(ring)-1] {
+			extended := false
+			end := ring[len(ring)-1]
+
+			for i, way := range ways {
+				if used[i] {
+					continue
+				}
+				switch end {
+				case way[0]:
+					ring = append(ring, way[1:]...)
+				case way[len(way)-1]:
+					for k := len(way) - 2; k >= 0; k-- {
+						ring = append(ring, way[k])
+					}
+				default:
+					continue
+				}
+				used[i] = true
+				extended = true
+				break
+			}
+
+			if !extended {
+				break
+			}
+		}
+
+		if ring[0] != ring[len(ring)-1] || len(ring) < 4 {
+			log.Printf("Relation %d: could not close ring from member ways, skipping", relationID)
+			continue
+		}
+		rings = append(rings, geo.Ring(ring))
+	}
+
+	return rings
+}
+
+// interiorProbe returns a point to test ring containment with. Inner rings
+// usually share no vertices with their outer ring, but if they touch, the
+// midpoint of the first edge is less likely to lie on the shared boundary.
+func interiorProbe(ring geo.Ring) geo.Point {
+	return geo.Point{(ring[0][0] + ring[1][0]) / 2, (ring[0][1] + ring[1][1]) / 2}
+}
@@ -0,0 +1,247 @@
+package osm
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math"
+
+	"wildfire-risk-platform/shared/geo"
+)
+
+// maxIsochroneCells caps the grid isochrones are traced on; large areas get
+// coarser cells rather than an unbounded grid
+const maxIsochroneCells = 250000
+
+// ResponseOptions configures the fire station response analysis
+type ResponseOptions struct {
+	BufferM     float64   // Roads and stations are fetched this far around the AOI
+	BandsMin    []float64 // Upper bounds of the response-time bands, ascending
+	CoverageMin float64   // Buildings slower to reach than this are outside coverage
+	SnapMaxM    float64   // Places farther than this from a road cannot be reached
+	CellM       float64   // Isochrone grid spacing
+}
+
+// ResponseAnalysis is the estimated drive time from the nearest fire station
+// to each building in the AOI, and the area reachable within each band
+type ResponseAnalysis struct {
+	Stations    int // Fire stations placed on the network
+	Buildings   []BuildingResponse
+	Isochrones  []Isochrone
+	TilesTotal  int
+	FailedTiles []Tile
+	Sources     []DataSource
+}
+
+// BuildingResponse is the response time to one building. DriveTimeS is nil
+// when no station can reach it.
+type BuildingResponse struct {
+	OSMType         string
+	OSMID           int64
+	DriveTimeS      *float64
+	Band            string
+	OutsideCoverage bool
+	StationID       int64 // OSM ID of the fastest station
+}
+
+// Isochrone is the area reachable from a station within Minutes
+type Isochrone struct {
+	Minutes  float64
+	Polygons []geo.Polygon
+}
+
+// stationSnap is a fire station placed on the road it is nearest to
+type stationSnap struct {
+	offsetM float64
+	origin  int
+}
+
+// AnalyzeResponse fetches the roads and fire stations around the AOI and the
+// buildings in it, then routes from every station at each road's speed.
+// Fire stations are the catalog entries with the fire_station suppression
+// role.
+func AnalyzeResponse(ctx context.Context, source AssetSource, catalog *Catalog, aoiGeoJSON string, opts ResponseOptions) (*ResponseAnalysis, error) {
+	aoi, err := ParseAOI(aoiGeoJSON)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse AOI: %w", err)
+	}
+
+	types := []string{RoadAssetType}
+	for _, entry := range catalog.Entries {
+		if entry.Suppression == SuppressionFireStation {
+			types = append(types, entry.Type)
+		}
+	}
+	if len(types) == 1 {
+		return nil, fmt.Errorf("the asset catalog has no %s entries", SuppressionFireStation)
+	}
+
+	network, err := source.QueryAssets(ctx, aroundGeoJSON(aoi, opts.BufferM), AssetFilter{AssetTypes: types})
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch the road network: %w", err)
+	}
+	buildings, err := source.QueryAssets(ctx, aoiGeoJSON, AssetFilter{AssetTypes: []string{BuildingAssetType}})
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch buildings: %w", err)
+	}
+
+	var roads, stations []Asset
+	for _, asset := range network.Assets {
+		if asset.Type == RoadAssetType {
+			roads = append(roads, asset)
+		} else {
+			stations = append(stations, asset)
+		}
+	}
+
+	g := BuildRoadGraph(roads, func(geo.Point) bool { return false })
+	index := newEdgeIndex(g, opts.SnapMaxM)
+	travel := func(e *RoadEdge) float64 { return e.LengthM / (e.SpeedKPH / 3.6) }
+
+	// Each station starts at both ends of its road, as far as it may drive
+	var seeds []seed
+	var placed int
+	onEdge := make(map[int][]stationSnap)
+	for i, station := range stations {
+		position, offset, _, ok := index.snap(station.Measures.Centroid, opts.SnapMaxM)
+		if !ok {
+			continue
+		}
+		e := &g.Edges[position]
+		speed := e.SpeedKPH / 3.6
+		seeds = append(seeds, seed{node: e.To, cost: (e.LengthM - offset) / speed, origin: i})
+		if !e.Oneway {
+			seeds = append(seeds, seed{node: e.From, cost: offset / speed, origin: i})
+		}
+		onEdge[position] = append(onEdge[position], stationSnap{offset, i})
+		placed++
+	}
+	reached := g.shortest(seeds, travel, false)
+
+	// arrival is the drive time to a point on a road and the station it is from
+	arrival := func(position int, offset float64) (float64, int) {
+		e := &g.Edges[position]
+		speed := e.SpeedKPH / 3.6
+		best, origin := math.Inf(1), -1
+		if r, ok := reached[e.From]; ok {
+			best, origin = r.cost+offset/speed, r.origin
+		}
+		if r, ok := reached[e.To]; ok && !e.Oneway {
+			if t := r.cost + (e.LengthM-offset)/speed; t < best {
+				best, origin = t, r.origin
+			}
+		}
+		for _, s := range onEdge[position] {
+			if offset >= s.offsetM || !e.Oneway {
+				if t := math.Abs(offset-s.offsetM) / speed; t < best {
+					best, origin = t, s.origin
+				}
+			}
+		}
+		return best, origin
+	}
+
+	analysis := &ResponseAnalysis{
+		Stations:    placed,
+		TilesTotal:  network.TilesTotal + buildings.TilesTotal,
+		FailedTiles: append(network.FailedTiles, buildings.FailedTiles...),
+		Sources:     mergeSources(network.Sources, buildings.Sources),
+	}
+
+	for _, building := range buildings.Assets {
+		result := BuildingResponse{
+			OSMType:         building.Properties["osm_type"].(string),
+			OSMID:           building.Properties["osm_id"].(int64),
+			Band:            "unreachable",
+			OutsideCoverage: true,
+		}
+
+		if position, offset, _, ok := index.snap(building.Measures.Centroid, opts.SnapMaxM); ok {
+			if t, origin := arrival(position, offset); origin >= 0 {
+				result.DriveTimeS = &t
+				result.Band = bandOf(t, opts.BandsMin)
+				result.OutsideCoverage = t > opts.CoverageMin*60
+				result.StationID = stations[origin].Properties["osm_id"].(int64)
+			}
+		}
+
+		analysis.Buildings = append(analysis.Buildings, result)
+	}
+
+	timeAt := func(p geo.Point) float64 {
+		position, offset, _, ok := index.snap(p, opts.SnapMaxM)
+		if !ok {
+			return math.Inf(1)
+		}
+		t, _ := arrival(position, offset)
+		return t
+	}
+	for _, region := range aoi.Regions() {
+		for i, isochrone := range isochrones(region.Expand(opts.BufferM), opts, timeAt) {
+			if i == len(analysis.Isochrones) {
+				analysis.Isochrones = append(analysis.Isochrones, Isochrone{Minutes: isochrone.Minutes})
+			}
+			analysis.Isochrones[i].Polygons = append(analysis.Isochrones[i].Polygons, isochrone.Polygons...)
+		}
+	}
+
+	log.Printf("Routed %d buildings from %d fire stations over %d road edges", len(analysis.Buildings), analysis.Stations, len(g.Edges))
+	return analysis, nil
+}
+
+// bandOf labels a drive time with its band, e.g. "4-8" minutes, or "20+"
+// beyond the last one
+func bandOf(seconds float64, bandsMin []float64) string {
+	lower := 0.0
+	for _, upper := range bandsMin {
+		if seconds <= upper*60 {
+			return fmt.Sprintf("%g-%g", lower, upper)
+		}
+		lower = upper
+	}
+	return fmt.Sprintf("%g+", lower)
+}
+
+// isochrones samples the drive time on a grid over the area and outlines
+// the cells reachable within each band
+func isochrones(area geo.BBox, opts ResponseOptions, timeAt func(geo.Point) float64) []Isochrone {
+	stepLat := opts.CellM / metresPerDegree
+	stepLon := stepLat / math.Max(math.Cos((area.MinLat+area.MaxLat)/2*math.Pi/180), 0.01)
+	if n := (area.MaxLon - area.MinLon) / stepLon * (area.MaxLat - area.MinLat) / stepLat; n > maxIsochroneCells {
+		scale := math.Sqrt(n / maxIsochroneCells)
+		stepLat *= scale
+		stepLon *= scale
+	}
+
+	cols := int(math.Ceil((area.MaxLon - area.MinLon) / stepLon))
+	rows := int(math.Ceil((area.MaxLat - area.MinLat) / stepLat))
+	bands := make([]map[geo.Cell]bool, len(opts.BandsMin))
+	for i := range bands {
+		bands[i] = make(map[geo.Cell]bool)
+	}
+
+	for x := 0; x < cols; x++ {
+		for y := 0; y < rows; y++ {
+			t := timeAt(geo.Point{area.MinLon + (float64(x)+0.5)*stepLon, area.MinLat + (float64(y)+0.5)*stepLat})
+			for i, upper := range opts.BandsMin {
+				if t <= upper*60 {
+					bands[i][geo.Cell{x, y}] = true
+				}
+			}
+		}
+	}
+
+	result := make([]Isochrone, len(opts.BandsMin))
+	for i, upper := range opts.BandsMin {
+		result[i].Minutes = upper
+		for _, polygon := range geo.TraceCells(bands[i]) {
+			for _, ring := range polygon {
+				for j, p := range ring {
+					ring[j] = geo.Point{area.MinLon + p[0]*stepLon, area.MinLat + p[1]*stepLat}
+				}
+			}
+			result[i].Polygons = append(result[i].Polygons, polygon)
+		}
+	}
+	return result
+}
@@ -0,0 +1,278 @@
+package osm
+
+import (
+	"math"
+	"regexp"
+	"strconv"
+
+	geojson "github.com/paulmach/go.geojson"
+
+	"wildfire-risk-platform/shared/geo"
+)
+
+// Catalog types the road network analysis reads
+const (
+	RoadAssetType     = "road"
+	BuildingAssetType = "building"
+)
+
+// defaultSpeedKPH is the assumed speed on a highway class without a
+// readable maxspeed tag
+var defaultSpeedKPH = map[string]float64{
+	"motorway":       110,
+	"trunk":          90,
+	"primary":        80,
+	"secondary":      70,
+	"tertiary":       60,
+	"motorway_link":  60,
+	"trunk_link":     50,
+	"primary_link":   50,
+	"secondary_link": 50,
+	"tertiary_link":  40,
+	"unclassified":   50,
+	"residential":    30,
+	"living_street":  10,
+	"service":        20,
+	"track":          15,
+}
+
+// fallbackSpeedKPH is used for classes missing from defaultSpeedKPH
+const fallbackSpeedKPH = 30
+
+var maxspeedRe = regexp.MustCompile(`^(\d+(?:\.\d+)?)\s*(mph|km/h|kmh|kph)?$`)
+
+// RoadGraph is a routable network built from road ways. Graph nodes are the
+// OSM nodes where ways meet or end, plus the vertices where a way crosses
+// into or out of an exit area; everything between them is one edge.
+type RoadGraph struct {
+	Nodes map[int64]RoadNode
+	Edges []RoadEdge
+
+	adjacent map[int64][]int // Node ID -> positions in Edges touching it
+}
+
+// RoadNode is one graph node. Exit nodes are where evacuees count as safe.
+type RoadNode struct {
+	Point geo.Point
+	Exit  bool
+}
+
+// RoadEdge is a stretch of one way between two graph nodes
+type RoadEdge struct {
+	From, To int64 // OSM node IDs; oneway edges are only driven From -> To
+	WayID    int64
+	Class    string // highway value
+	SpeedKPH float64
+	Oneway   bool
+	LengthM  float64
+	Geometry []geo.Point
+
+	offsets []float64 // Distance from From to each vertex
+}
+
+// BuildRoadGraph builds the network from road assets. isExit decides which
+// OSM nodes are exits. Roads without node IDs are skipped.
+func BuildRoadGraph(roads []Asset, isExit func(geo.Point) bool) *RoadGraph {
+	g := &RoadGraph{
+		Nodes:    make(map[int64]RoadNode),
+		adjacent: make(map[int64][]int),
+	}
+
+	type way struct {
+		asset  Asset
+		points []geo.Point
+	}
+	var ways []way
+	uses := make(map[int64]int)
+	exits := make(map[int64]bool)
+	for _, road := range roads {
+		if road.Geometry == nil || road.Geometry.Type != geojson.GeometryLineString {
+			continue
+		}
+		points := geo.PointsFromCoords(road.Geometry.LineString)
+		if len(points) < 2 || len(road.NodeIDs) != len(points) {
+			continue
+		}
+
+		ways = append(ways, way{road, points})
+		for i, id := range road.NodeIDs {
+			uses[id]++
+			if _, ok := exits[id]; !ok {
+				exits[id] = isExit(points[i])
+			}
+		}
+	}
+
+	for _, w := range ways {
+		ids := w.asset.NodeIDs
+		last := len(ids) - 1
+
+		// A vertex is a graph node at way ends, where ways meet and where the
+		// way enters or leaves an exit area
+		isNode := func(i int) bool {
+			return i == 0 || i == last || uses[ids[i]] > 1 ||
+				exits[ids[i]] != exits[ids[i-1]] || exits[ids[i]] != exits[ids[i+1]]
+		}
+
+		tags := assetTags(w.asset)
+		forward, oneway := onewayOf(tags)
+		start := 0
+		for i := 1; i <= last; i++ {
+			if !isNode(i) {
+				continue
+			}
+
+			points := append([]geo.Point(nil), w.points[start:i+1]...)
+			from, to := ids[start], ids[i]
+			if !forward {
+				for a, b := 0, len(points)-1; a < b; a, b = a+1, b-1 {
+					points[a], points[b] = points[b], points[a]
+				}
+				from, to = to, from
+			}
+
+			g.addEdge(RoadEdge{
+				From:     from,
+				To:       to,
+				WayID:    w.asset.Properties["osm_id"].(int64),
+				Class:    tags["highway"],
+				SpeedKPH: speedOf(tags),
+				Oneway:   oneway,
+				Geometry: points,
+			})
+			g.Nodes[from] = RoadNode{Point: points[0], Exit: exits[from]}
+			g.Nodes[to] = RoadNode{Point: points[len(points)-1], Exit: exits[to]}
+			start = i
+		}
+	}
+
+	return g
+}
+
+func (g *RoadGraph) addEdge(e RoadEdge) {
+	e.offsets = make([]float64, len(e.Geometry))
+	for i := 1; i < len(e.Geometry); i++ {
+		e.offsets[i] = e.offsets[i-1] + geo.Distance(e.Geometry[i-1], e.Geometry[i])
+	}
+	e.LengthM = e.offsets[len(e.offsets)-1]
+
+	position := len(g.Edges)
+	g.Edges = append(g.Edges, e)
+	g.adjacent[e.From] = append(g.adjacent[e.From], position)
+	if e.To != e.From {
+		g.adjacent[e.To] = append(g.adjacent[e.To], position)
+	}
+}
+
+// degree is the number of edge ends at the node; a loop counts twice
+func (g *RoadGraph) degree(node int64) int {
+	d := 0
+	for _, position := range g.adjacent[node] {
+		d++
+		if e := g.Edges[position]; e.From == e.To {
+			d++
+		}
+	}
+	return d
+}
+
+// other returns the far end of an edge from node
+func (e *RoadEdge) other(node int64) int64 {
+	if e.From == node {
+		return e.To
+	}
+	return e.From
+}
+
+// onewayOf reads the driving direction: forward is false when the way may
+// only be driven against its drawing order
+func onewayOf(tags map[string]string) (forward, oneway bool) {
+	switch tags["oneway"] {
+	case "yes", "true", "1":
+		return true, true
+	case "-1", "reverse":
+		return false, true
+	case "no", "false", "0":
+		return true, false
+	}
+
+	// Roundabouts and motorways are oneway unless tagged otherwise
+	oneway = tags["junction"] == "roundabout" || tags["junction"] == "circular" || tags["highway"] == "motorway"
+	return true, oneway
+}
+
+// speedOf reads maxspeed in km/h or mph, falling back to the class default
+func speedOf(tags map[string]string) float64 {
+	if match := maxspeedRe.FindStringSubmatch(tags["maxspeed"]); match != nil {
+		if v, err := strconv.ParseFloat(match[1], 64); err == nil && v > 0 {
+			if match[2] == "mph" {
+				return v * 1.609344
+			}
+			return v
+		}
+	}
+
+	if speed, ok := defaultSpeedKPH[tags["highway"]]; ok {
+		return speed
+	}
+	return fallbackSpeedKPH
+}
+
+// edgeIndex finds the road nearest a point, on a grid of cells about as wide
+// as the largest snapping distance
+type edgeIndex struct {
+	graph   *RoadGraph
+	cellDeg float64
+	cells   map[[2]int][][2]int // Cell -> (edge, segment) pairs
+}
+
+func newEdgeIndex(g *RoadGraph, maxM float64) *edgeIndex {
+	idx := &edgeIndex{graph: g, cellDeg: math.Max(maxM/metresPerDegree, 1e-4), cells: make(map[[2]int][][2]int)}
+	for e, edge := range g.Edges {
+		for i := 0; i+1 < len(edge.Geometry); i++ {
+			b := geo.BoundsOf(edge.Geometry[i : i+2])
+			for _, cell := range idx.cellsOf(b) {
+				idx.cells[cell] = append(idx.cells[cell], [2]int{e, i})
+			}
+		}
+	}
+	return idx
+}
+
+func (idx *edgeIndex) cellsOf(b geo.BBox) [][2]int {
+	minX, minY := int(math.Floor(b.MinLon/idx.cellDeg)), int(math.Floor(b.MinLat/idx.cellDeg))
+	maxX, maxY := int(math.Floor(b.MaxLon/idx.cellDeg)), int(math.Floor(b.MaxLat/idx.cellDeg))
+
+	cells := make([][2]int, 0, (maxX-minX+1)*(maxY-minY+1))
+	for x := minX; x <= maxX; x++ {
+		for y := minY; y <= maxY; y++ {
+			cells = append(cells, [2]int{x, y})
+		}
+	}
+	return cells
+}
+
+// snap returns the edge nearest p within maxM, how far along it from From
+// the nearest point lies, and the distance to it. ok is false when no road
+// is that close.
+func (idx *edgeIndex) snap(p geo.Point, maxM float64) (edge int, offsetM, distanceM float64, ok bool) {
+	search := geo.BoundsOf([]geo.Point{p}).Expand(maxM)
+
+	distanceM = math.Inf(1)
+	for _, cell := range idx.cellsOf(search) {
+		for _, candidate := range idx.cells[cell] {
+			e := &idx.graph.Edges[candidate[0]]
+			i := candidate[1]
+			closest, t := geo.ClosestOnSegment(p, geo.Segment{A: e.Geometry[i], B: e.Geometry[i+1]})
+			if d := geo.Distance(p, closest); d < distanceM {
+				edge, distanceM, ok = candidate[0], d, true
+				offsetM = e.offsets[i] + t*(e.offsets[i+1]-e.offsets[i])
+			}
+		}
+	}
+
+	if distanceM > maxM {
+		return 0, 0, 0, false
+	}
+	return edge, offsetM, distanceM, ok
+}
@@ -0,0 +1,20 @@
+package osm
+
+import "context"
+
+// AssetSource fetches the infrastructure assets intersecting an AOI that pass
+// a filter, and the landcover polygons that make up its fuel layer. The live Overpass API and a local PBF extract both implement it.
+type AssetSource interface {
+	QueryAssets(ctx context.Context, aoiGeoJSON string, filter AssetFilter) (*QueryResult, error)
+
+	// StreamAssets passes assets to emit one at a time instead of collecting
+	// them; the returned result has no Assets
+	StreamAssets(ctx context.Context, aoiGeoJSON string, filter AssetFilter, emit func(Asset) error) (*QueryResult, error)
+
+	QueryLandcover(ctx context.Context, aoiGeoJSON string) (*LandcoverResult, error)
+}
+
+var (
+	_ AssetSource = (*OverpassClient)(nil)
+	_ AssetSource = (*PBFSource)(nil)
+)
@@ -0,0 +1,202 @@
+package osm
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math"
+
+	"wildfire-risk-platform/shared/geo"
+)
+
+// metresPerDegree is the length of a degree of latitude
+const metresPerDegree = geo.EarthRadiusM * math.Pi / 180
+
+// SuppressionDistances are the distances in metres from an asset to the
+// nearest water source and fire station. Each is nil when there is none
+// within the search radius.
+type SuppressionDistances struct {
+	WaterM       *float64
+	FireStationM *float64
+}
+
+// Suppression indexes the firefighting resources around an AOI so assets can
+// be annotated with their distance to the nearest of each role
+type Suppression struct {
+	radiusM float64
+	roles   map[string]*resourceIndex
+}
+
+// resourceIndex is a grid of resources of one role, in cells sized to the
+// search radius
+type resourceIndex struct {
+	cellDeg   float64
+	resources []resource
+	cells     map[[2]int][]int
+}
+
+type resource struct {
+	points   []geo.Point
+	segments []geo.Segment
+	polygons []geo.Polygon
+	bounds   geo.BBox
+}
+
+// LocateSuppression fetches every resource the catalog marks with a
+// suppression role within radiusM of the AOI, along with the result of that
+// query so callers can report its coverage. It returns nil when the catalog
+// has no such entries.
+func LocateSuppression(ctx context.Context, source AssetSource, catalog *Catalog, aoiGeoJSON string, radiusM float64) (*Suppression, *QueryResult, error) {
+	aoi, err := ParseAOI(aoiGeoJSON)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to parse AOI: %w", err)
+	}
+
+	s := &Suppression{radiusM: radiusM, roles: make(map[string]*resourceIndex)}
+	var types []string
+	for _, entry := range catalog.Entries {
+		if entry.Suppression != "" {
+			types = append(types, entry.Type)
+		}
+	}
+	if len(types) == 0 {
+		return nil, nil, nil
+	}
+
+	// Resources outside the AOI still count, so search its bounds grown by the radius
+	searchGeoJSON := aroundGeoJSON(aoi, radiusM)
+
+	result, err := source.QueryAssets(ctx, searchGeoJSON, AssetFilter{AssetTypes: types})
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to fetch suppression resources: %w", err)
+	}
+	if len(result.FailedTiles) > 0 {
+		log.Printf("Suppression resources incomplete: %d of %d tiles failed", len(result.FailedTiles), result.TilesTotal)
+	}
+
+	roles := make(map[string]string, len(types))
+	for _, entry := range catalog.Entries {
+		roles[entry.Type] = entry.Suppression
+	}
+
+	for _, asset := range result.Assets {
+		role := roles[asset.Type]
+		index := s.roles[role]
+		if index == nil {
+			index = &resourceIndex{cellDeg: math.Max(radiusM/metresPerDegree, 1e-4), cells: make(map[[2]int][]int)}
+			s.roles[role] = index
+		}
+		index.add(asset)
+	}
+
+	log.Printf("Located %d suppression resources within %.0f m of the AOI", len(result.Assets), radiusM)
+	return s, result, nil
+}
+
+// Annotate sets the asset's distances to the nearest resources. A nil
+// Suppression leaves the asset untouched.
+func (s *Suppression) Annotate(asset *Asset) {
+	if s == nil {
+		return
+	}
+
+	vertices, _, _ := decompose(asset.Geometry)
+	if len(vertices) == 0 {
+		return
+	}
+
+	asset.Suppression = SuppressionDistances{
+		WaterM:       s.roles[SuppressionWater].nearest(vertices, s.radiusM),
+		FireStationM: s.roles[SuppressionFireStation].nearest(vertices, s.radiusM),
+	}
+}
+
+func (idx *resourceIndex) add(asset Asset) {
+	points, segments, polygons := decompose(asset.Geometry)
+	if len(points) == 0 {
+		return
+	}
+
+	r := resource{points: points, segments: segments, polygons: polygons, bounds: geo.BoundsOf(points)}
+	position := len(idx.resources)
+	idx.resources = append(idx.resources, r)
+	for _, cell := range idx.cellsOf(r.bounds) {
+		idx.cells[cell] = append(idx.cells[cell], position)
+	}
+}
+
+func (idx *resourceIndex) cellsOf(b geo.BBox) [][2]int {
+	minX, minY := int(math.Floor(b.MinLon/idx.cellDeg)), int(math.Floor(b.MinLat/idx.cellDeg))
+	maxX, maxY := int(math.Floor(b.MaxLon/idx.cellDeg)), int(math.Floor(b.MaxLat/idx.cellDeg))
+
+	cells := make([][2]int, 0, (maxX-minX+1)*(maxY-minY+1))
+	for x := minX; x <= maxX; x++ {
+		for y := minY; y <= maxY; y++ {
+			cells = append(cells, [2]int{x, y})
+		}
+	}
+	return cells
+}
+
+// nearest returns the shortest distance from any of the vertices to any
+// resource, or nil if none is within radiusM. Vertices inside an areal
+// resource, such as a lake, are at distance zero.
+func (idx *resourceIndex) nearest(vertices []geo.Point, radiusM float64) *float64 {
+	if idx == nil {
+		return nil
+	}
+
+	search := geo.BoundsOf(vertices).Expand(radiusM)
+
+	best := math.Inf(1)
+	seen := make(map[int]bool)
+	for _, cell := range idx.cellsOf(search) {
+		for _, position := range idx.cells[cell] {
+			if seen[position] {
+				continue
+			}
+			seen[position] = true
+
+			r := idx.resources[position]
+			for _, v := range vertices {
+				if d := r.distance(v, best); d < best {
+					best = d
+				}
+			}
+		}
+	}
+
+	if best > radiusM {
+		return nil
+	}
+	return &best
+}
+
+// distance is the distance from p to the resource, or something no smaller
+// than limit when the resource is certainly farther away than that
+func (r resource) distance(p geo.Point, limit float64) float64 {
+	clamped := geo.Point{
+		math.Max(r.bounds.MinLon, math.Min(r.bounds.MaxLon, p.Lon())),
+		math.Max(r.bounds.MinLat, math.Min(r.bounds.MaxLat, p.Lat())),
+	}
+	if geo.Distance(p, clamped) >= limit {
+		return limit
+	}
+
+	for _, polygon := range r.polygons {
+		if polygon.Contains(p) {
+			return 0
+		}
+	}
+
+	d := math.Inf(1)
+	if len(r.segments) == 0 {
+		for _, point := range r.points {
+			d = math.Min(d, geo.Distance(p, point))
+		}
+	}
+	for _, s := range r.segments {
+		d = math.Min(d, geo.DistanceToSegment(p, s))
+	}
+	return d
+}
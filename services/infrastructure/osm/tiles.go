@@ -0,0 +1,108 @@
+package osm
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sync"
+	"time"
+
+	"wildfire-risk-platform/shared/geo"
+)
+
+// Tile is one cell of the grid a large AOI is fetched in
+type Tile struct {
+	Index  int
+	Bounds geo.BBox
+}
+
+// TilingOptions controls how large AOIs are split across Overpass requests
+type TilingOptions struct {
+	TileSize      float64       // Tile edge in degrees
+	MaxConcurrent int           // Tiles fetched at once
+	MinInterval   time.Duration // Minimum gap between any two Overpass requests
+}
+
+// tilesFor covers each of the AOI's regions with tiles and keeps those that
+// actually overlap the AOI polygon. Tiles are cells of one global grid, not
+// of a grid laid over the AOI, so overlapping AOIs request identical tiles
+// and share cached responses.
+func tilesFor(aoi *AOI, tileSize float64) []Tile {
+	var tiles []Tile
+	for _, b := range aoi.Regions() {
+		if tileSize <= 0 {
+			tiles = append(tiles, Tile{Index: len(tiles), Bounds: b})
+			continue
+		}
+
+		minCol, maxCol := gridSpan(b.MinLon, b.MaxLon, tileSize)
+		minRow, maxRow := gridSpan(b.MinLat, b.MaxLat, tileSize)
+		for row := minRow; row <= maxRow; row++ {
+			for col := minCol; col <= maxCol; col++ {
+				cell := geo.BBox{
+					MinLon: math.Max(float64(col)*tileSize, -180),
+					MinLat: math.Max(float64(row)*tileSize, -90),
+					MaxLon: math.Min(float64(col+1)*tileSize, 180),
+					MaxLat: math.Min(float64(row+1)*tileSize, 90),
+				}
+				if !aoi.IntersectsBox(cell) {
+					continue
+				}
+				tiles = append(tiles, Tile{Index: len(tiles), Bounds: cell})
+			}
+		}
+	}
+
+	return tiles
+}
+
+// gridSpan returns the first and last grid cells of the given size that the
+// range [lo, hi] touches. A range ending exactly on a grid line does not
+// reach into the next cell.
+func gridSpan(lo, hi, size float64) (first, last int) {
+	first = int(math.Floor(lo / size))
+	last = int(math.Ceil(hi/size)) - 1
+	if last < first {
+		last = first
+	}
+	return first, last
+}
+
+// bboxFilter formats a tile as an Overpass (south,west,north,east) filter
+func (t Tile) bboxFilter() string {
+	return fmt.Sprintf("%.7f,%.7f,%.7f,%.7f", t.Bounds.MinLat, t.Bounds.MinLon, t.Bounds.MaxLat, t.Bounds.MaxLon)
+}
+
+// rateLimiter spaces requests at least interval apart across all goroutines
+type rateLimiter struct {
+	mu       sync.Mutex
+	interval time.Duration
+	next     time.Time
+}
+
+func newRateLimiter(interval time.Duration) *rateLimiter {
+	return &rateLimiter{interval: interval}
+}
+
+// Wait blocks until the caller may send its request
+func (r *rateLimiter) Wait(ctx context.Context) error {
+	if r.interval <= 0 {
+		return nil
+	}
+
+	r.mu.Lock()
+	now := time.Now()
+	slot := r.next
+	if slot.Before(now) {
+		slot = now
+	}
+	r.next = slot.Add(r.interval)
+	r.mu.Unlock()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-time.After(time.Until(slot)):
+		return nil
+	}
+}
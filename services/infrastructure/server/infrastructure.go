@@ -6,36 +6,107 @@ import (
 	"fmt"
 	"log"
 
+	geojson "github.com/paulmach/go.geojson"
+	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
 	"google.golang.org/grpc/status"
 
 	"infrastructure/config"
 	"infrastructure/osm"
 	pb "wildfire-risk-platform/api/proto/generated"
+	"wildfire-risk-platform/shared/database/models"
+	"wildfire-risk-platform/shared/geo"
+	"wildfire-risk-platform/shared/raster"
 )
 
 // InfrastructureServer implements the InfrastructureService gRPC server
 type InfrastructureServer struct {
 	pb.UnimplementedInfrastructureServiceServer
 
-	config         *config.Config
-	overpassClient *osm.OverpassClient
+	config  *config.Config
+	catalog *osm.Catalog
+	fuel    *osm.FuelTable
+	assets  osm.AssetSource
+	cache   *osm.ResponseCache // Nil unless assets come from Overpass with caching on
+
+	topography pb.TopographyServiceClient // Nil when no Topography Service is configured
 }
 
-// NewInfrastructureServer creates a new infrastructure server
-func NewInfrastructureServer(cfg *config.Config) *InfrastructureServer {
+// NewInfrastructureServer creates a new infrastructure server backed by the
+// configured asset source
+func NewInfrastructureServer(cfg *config.Config) (*InfrastructureServer, error) {
+
+	server := &InfrastructureServer{config: cfg}
+
+	catalog, err := osm.LoadCatalog(cfg.AssetCatalogPath)
+	if err != nil {
+		return nil, err
+	}
+	server.catalog = catalog
 
-	overpassClient := osm.NewOverpassClient(
-		cfg.OverpassAPIURL,
-		cfg.HTTPTimeout,
-		cfg.MaxRetries,
-		cfg.RetryDelay,
-	)
+	fuel, err := osm.LoadFuelTable(cfg.FuelTablePath)
+	if err != nil {
+		return nil, err
+	}
+	server.fuel = fuel
+	if cfg.LandcoverSampleM <= 0 {
+		return nil, fmt.Errorf("landcover sample spacing must be positive, got %v", cfg.LandcoverSampleM)
+	}
+	landcover := osm.LandcoverOptions{Fuel: fuel, SampleM: cfg.LandcoverSampleM}
 
-	return &InfrastructureServer{
-		config:         cfg,
-		overpassClient: overpassClient,
+	// The connection is made lazily, so the Topography Service need not be up yet
+	if cfg.TopographyServiceURL != "" {
+		conn, err := grpc.NewClient(cfg.TopographyServiceURL, grpc.WithTransportCredentials(insecure.NewCredentials()))
+		if err != nil {
+			return nil, fmt.Errorf("failed to create topography client: %w", err)
+		}
+		server.topography = pb.NewTopographyServiceClient(conn)
 	}
+
+	switch cfg.AssetSource {
+	case "overpass":
+		// The service still works without a cache, just with more Overpass traffic
+		if cfg.CacheDir != "" {
+			cache, err := osm.NewResponseCache(cfg.CacheDir, cfg.CacheTTL, cfg.CacheMaxBytes, cfg.CacheMemoryBytes)
+			if err != nil {
+				log.Printf("Overpass cache disabled: %v", err)
+			} else {
+				server.cache = cache
+			}
+		}
+
+		server.assets = osm.NewOverpassClient(
+			append([]string{cfg.OverpassAPIURL}, cfg.OverpassMirrors...),
+			cfg.HTTPTimeout,
+			osm.RetryOptions{
+				MaxRetries:  cfg.MaxRetries,
+				BaseDelay:   cfg.RetryDelay,
+				MaxDelay:    cfg.RetryMaxDelay,
+				StatusCheck: cfg.OverpassStatusCheck,
+			},
+			osm.TilingOptions{
+				TileSize:      cfg.TileSizeDeg,
+				MaxConcurrent: cfg.MaxConcurrentTiles,
+				MinInterval:   cfg.OverpassMinInterval,
+			},
+			server.cache,
+			catalog,
+			landcover,
+		)
+
+	case "pbf":
+		source, err := osm.NewPBFSource(context.Background(), cfg.PBFPath, cfg.PBFIndexCellDeg, catalog, landcover)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load PBF extract %s: %w", cfg.PBFPath, err)
+		}
+		server.assets = source
+
+	default:
+		return nil, fmt.Errorf("unknown asset source %q, expected overpass or pbf", cfg.AssetSource)
+	}
+
+	return server, nil
 }
 
 // GetAssetsInAOI retrieves infrastructure assets within the area of interest
@@ -45,44 +116,46 @@ func (s *InfrastructureServer) GetAssetsInAOI(ctx context.Context, req *pb.GetAs
 		return nil, status.Error(codes.InvalidArgument, "aoi_geojson is required")
 	}
 
-	// Validate GeoJSON structure
-	var geojson map[string]interface{}
-	if err := json.Unmarshal([]byte(req.AoiGeojson), &geojson); err != nil {
-		return nil, status.Error(codes.InvalidArgument, "invalid GeoJSON format")
+	// Validate the AOI before anything is fetched
+	if _, err := osm.ParseAOI(req.AoiGeojson); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid AOI: %v", err)
+	}
+
+	filter, err := s.assetFilter(req)
+	if err != nil {
+		return nil, err
 	}
 
-	log.Printf("Fetching assets for AOI with bounds")
+	log.Printf("Fetching assets for AOI polygon")
 
-	assets, err := s.overpassClient.QueryAssets(ctx, req.AoiGeojson)
+	suppression, suppressionCoverage, err := s.locateSuppression(ctx, req)
 	if err != nil {
-		log.Printf("Failed to query Overpass API: %v", err)
+		return nil, err
+	}
+
+	result, err := s.assets.QueryAssets(ctx, req.AoiGeojson, filter)
+	if err != nil {
+		log.Printf("Failed to query %s asset source: %v", s.config.AssetSource, err)
 		return nil, status.Error(codes.Internal, "failed to fetch assets from OpenStreetMap")
 	}
+	assets := result.Assets
 
-	log.Printf("Retrieved %d assets from Overpass API", len(assets))
+	log.Printf("Retrieved %d assets from %s", len(assets), s.config.AssetSource)
+	if len(result.FailedTiles) > 0 {
+		log.Printf("Partial coverage: %d of %d tiles failed", len(result.FailedTiles), result.TilesTotal)
+	}
 
 	pbAssets := make([]*pb.Asset, 0, len(assets))
 
 	for _, asset := range assets {
 
-		geometryJSON, err := json.Marshal(asset.Geometry)
+		suppression.Annotate(&asset)
+		pbAsset, err := assetToProto(asset)
 		if err != nil {
 			log.Printf("Failed to marshal geometry for asset: %v", err)
 			continue // Skip this asset
 		}
 
-		properties := make(map[string]string)
-		for k, v := range asset.Properties {
-
-			properties[k] = fmt.Sprintf("%v", v)
-		}
-
-		pbAsset := &pb.Asset{
-			AssetType:            asset.Type,
-			AssetGeometryGeojson: string(geometryJSON),
-			Properties:           properties,
-		}
-
 		pbAssets = append(pbAssets, pbAsset)
 	}
 
@@ -93,7 +166,610 @@ func (s *InfrastructureServer) GetAssetsInAOI(ctx context.Context, req *pb.GetAs
 	log.Printf("Asset distribution: %v", typeCount)
 
 	return &pb.GetAssetsResponse{
-		Assets:     pbAssets,
-		TotalCount: int32(len(pbAssets)),
+		Assets:              pbAssets,
+		TotalCount:          int32(len(pbAssets)),
+		Coverage:            coverageToProto(result),
+		Attribution:         attributionToProto(result.Sources),
+		SuppressionCoverage: suppressionCoverage,
+	}, nil
+}
+
+// StreamAssetsInAOI sends assets in batches as they are decoded, followed by
+// a summary with per-type counts, so large AOIs never have to fit in a single
+// message
+func (s *InfrastructureServer) StreamAssetsInAOI(req *pb.GetAssetsRequest, stream grpc.ServerStreamingServer[pb.StreamAssetsResponse]) error {
+
+	if req.AoiGeojson == "" {
+		return status.Error(codes.InvalidArgument, "aoi_geojson is required")
+	}
+
+	if _, err := osm.ParseAOI(req.AoiGeojson); err != nil {
+		return status.Errorf(codes.InvalidArgument, "invalid AOI: %v", err)
+	}
+
+	filter, err := s.assetFilter(req)
+	if err != nil {
+		return err
+	}
+
+	batchSize := s.config.StreamBatchSize
+	if batchSize < 1 {
+		batchSize = 1
+	}
+
+	batch := make([]*pb.Asset, 0, batchSize)
+	flush := func() error {
+		if len(batch) == 0 {
+			return nil
+		}
+		err := stream.Send(&pb.StreamAssetsResponse{
+			Payload: &pb.StreamAssetsResponse_Batch{Batch: &pb.AssetBatch{Assets: batch}},
+		})
+		batch = make([]*pb.Asset, 0, batchSize)
+		return err
+	}
+
+	var total int32
+	typeCount := make(map[string]int32)
+
+	suppression, suppressionCoverage, err := s.locateSuppression(stream.Context(), req)
+	if err != nil {
+		return err
+	}
+
+	result, err := s.assets.StreamAssets(stream.Context(), req.AoiGeojson, filter, func(asset osm.Asset) error {
+		suppression.Annotate(&asset)
+		pbAsset, err := assetToProto(asset)
+		if err != nil {
+			log.Printf("Failed to marshal geometry for asset: %v", err)
+			return nil // Skip this asset
+		}
+
+		total++
+		typeCount[pbAsset.AssetType]++
+		batch = append(batch, pbAsset)
+		if len(batch) >= batchSize {
+			return flush()
+		}
+		return nil
+	})
+	if err != nil {
+		if stream.Context().Err() != nil {
+			return status.FromContextError(stream.Context().Err()).Err()
+		}
+		log.Printf("Failed to stream from %s asset source: %v", s.config.AssetSource, err)
+		return status.Error(codes.Internal, "failed to fetch assets from OpenStreetMap")
+	}
+	if err := flush(); err != nil {
+		return err
+	}
+
+	log.Printf("Streamed %d assets from %s: %v", total, s.config.AssetSource, typeCount)
+
+	return stream.Send(&pb.StreamAssetsResponse{
+		Payload: &pb.StreamAssetsResponse_Summary{Summary: &pb.AssetSummary{
+			TotalCount:          total,
+			CountsByType:        typeCount,
+			Coverage:            coverageToProto(result),
+			Attribution:         attributionToProto(result.Sources),
+			SuppressionCoverage: suppressionCoverage,
+		}},
+	})
+}
+
+// InvalidateCache drops cached Overpass responses overlapping a region
+func (s *InfrastructureServer) InvalidateCache(ctx context.Context, req *pb.InvalidateCacheRequest) (*pb.InvalidateCacheResponse, error) {
+
+	if s.cache == nil {
+		return nil, status.Error(codes.FailedPrecondition, "Overpass cache is disabled")
+	}
+
+	var region *osm.AOI
+	if req.RegionGeojson != "" {
+		var err error
+		region, err = osm.ParseAOI(req.RegionGeojson)
+		if err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid region: %v", err)
+		}
+	}
+
+	removed := s.cache.InvalidateRegion(region)
+	log.Printf("Invalidated %d cached Overpass responses", removed)
+
+	return &pb.InvalidateCacheResponse{EntriesRemoved: int32(removed)}, nil
+}
+
+// GetLandcoverInAOI returns the landcover polygons in the AOI grouped into
+// fuel classes, with the share of the AOI each class covers
+func (s *InfrastructureServer) GetLandcoverInAOI(ctx context.Context, req *pb.GetLandcoverRequest) (*pb.GetLandcoverResponse, error) {
+
+	if req.AoiGeojson == "" {
+		return nil, status.Error(codes.InvalidArgument, "aoi_geojson is required")
+	}
+
+	if _, err := osm.ParseAOI(req.AoiGeojson); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid AOI: %v", err)
+	}
+
+	result, err := s.assets.QueryLandcover(ctx, req.AoiGeojson)
+	if err != nil {
+		log.Printf("Failed to query %s landcover: %v", s.config.AssetSource, err)
+		return nil, status.Error(codes.Internal, "failed to fetch landcover from OpenStreetMap")
+	}
+
+	log.Printf("Retrieved %d landcover polygons from %s", len(result.Features), s.config.AssetSource)
+
+	response := &pb.GetLandcoverResponse{
+		AoiAreaM2:         result.AOIAreaM2,
+		UnclassifiedShare: result.Unclassified,
+		Coverage: coverageToProto(&osm.QueryResult{
+			TilesTotal:  result.TilesTotal,
+			FailedTiles: result.FailedTiles,
+		}),
+		Attribution: attributionToProto(result.Sources),
+	}
+
+	for _, share := range result.Shares {
+		response.Classes = append(response.Classes, &pb.FuelClassShare{
+			FuelClass:    share.FuelClass,
+			Share:        share.Share,
+			AreaM2:       share.AreaM2,
+			PolygonCount: int32(share.Features),
+		})
+	}
+
+	for _, feature := range result.Features {
+		pbAsset, err := assetToProto(feature)
+		if err != nil {
+			log.Printf("Failed to marshal geometry for landcover polygon: %v", err)
+			continue
+		}
+		response.Polygons = append(response.Polygons, &pb.LandcoverPolygon{
+			FuelClass:       pbAsset.AssetType,
+			GeometryGeojson: pbAsset.AssetGeometryGeojson,
+			Properties:      pbAsset.Properties,
+			WithinAoi:       pbAsset.WithinAoi,
+			AreaM2:          pbAsset.AreaM2,
+		})
+	}
+
+	return response, nil
+}
+
+// AnalyzeRoadNetwork builds the road graph of the AOI and flags
+// neighbourhoods with a single route out, long dead ends and each building's
+// distance to safety
+func (s *InfrastructureServer) AnalyzeRoadNetwork(ctx context.Context, req *pb.RoadNetworkRequest) (*pb.RoadNetworkResponse, error) {
+
+	if req.AoiGeojson == "" {
+		return nil, status.Error(codes.InvalidArgument, "aoi_geojson is required")
+	}
+
+	aoi, err := osm.ParseAOI(req.AoiGeojson)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid AOI: %v", err)
+	}
+
+	opts := osm.EgressOptions{
+		DeadEndMinM: s.config.DeadEndMinM,
+		SnapMaxM:    s.config.RoadSnapMaxM,
+	}
+	if req.SafeZonesGeojson != "" {
+		opts.SafeZones, err = osm.ParseAOI(req.SafeZonesGeojson)
+		if err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid safe zones: %v", err)
+		}
+	}
+	if req.DeadEndMinM != nil {
+		opts.DeadEndMinM = *req.DeadEndMinM
+	}
+
+	filter := osm.AssetFilter{AssetTypes: []string{osm.RoadAssetType, osm.BuildingAssetType}}
+	if _, err := s.catalog.Narrow(filter); err != nil {
+		return nil, status.Errorf(codes.FailedPrecondition, "asset catalog does not support road analysis: %v", err)
+	}
+
+	result, err := s.assets.QueryAssets(ctx, req.AoiGeojson, filter)
+	if err != nil {
+		log.Printf("Failed to query %s asset source: %v", s.config.AssetSource, err)
+		return nil, status.Error(codes.Internal, "failed to fetch roads from OpenStreetMap")
+	}
+
+	analysis := osm.AnalyzeEgress(aoi, result.Assets, opts)
+	log.Printf("Road graph has %d nodes and %d edges; %d single-egress neighbourhoods, %d dead ends",
+		len(analysis.Graph.Nodes), len(analysis.Graph.Edges), len(analysis.SingleEgress), len(analysis.DeadEnds))
+
+	response := &pb.RoadNetworkResponse{
+		NodeCount:   int32(len(analysis.Graph.Nodes)),
+		EdgeCount:   int32(len(analysis.Graph.Edges)),
+		Coverage:    coverageToProto(result),
+		Attribution: attributionToProto(result.Sources),
+	}
+
+	if req.IncludeGraph {
+		for _, e := range analysis.Graph.Edges {
+			response.Edges = append(response.Edges, &pb.RoadEdge{
+				FromNode:        e.From,
+				ToNode:          e.To,
+				WayId:           e.WayID,
+				RoadClass:       e.Class,
+				SpeedKph:        e.SpeedKPH,
+				Oneway:          e.Oneway,
+				LengthM:         e.LengthM,
+				GeometryGeojson: lineGeoJSON(e.Geometry),
+			})
+		}
+	}
+
+	for _, hood := range analysis.SingleEgress {
+		pbHood := &pb.EgressNeighbourhood{
+			RoutesOut: int32(hood.RoutesOut),
+			ExitNode:  hood.ExitNode,
+			ExitWayId: hood.ExitWayID,
+			NodeCount: int32(hood.Nodes),
+			Bbox:      bboxToProto(hood.Bounds),
+		}
+		for _, b := range hood.Buildings {
+			pbHood.BuildingIds = append(pbHood.BuildingIds, analysis.Buildings[b].OSMID)
+		}
+		response.SingleEgress = append(response.SingleEgress, pbHood)
+	}
+
+	for _, d := range analysis.DeadEnds {
+		response.DeadEnds = append(response.DeadEnds, &pb.DeadEndRoad{
+			WayIds:          d.WayIDs,
+			LengthM:         d.LengthM,
+			GeometryGeojson: lineGeoJSON(d.Geometry),
+		})
+	}
+
+	for _, b := range analysis.Buildings {
+		response.Buildings = append(response.Buildings, &pb.BuildingEgress{
+			OsmType:          b.OSMType,
+			OsmId:            b.OSMID,
+			NetworkDistanceM: b.NetworkDistanceM,
+			SnapDistanceM:    b.SnapDistanceM,
+			SingleEgress:     b.Neighbourhood >= 0,
+		})
+	}
+
+	return response, nil
+}
+
+// AnalyzeResponseCoverage estimates the drive time from the nearest fire
+// station to every building in the AOI and traces isochrones for each band
+func (s *InfrastructureServer) AnalyzeResponseCoverage(ctx context.Context, req *pb.ResponseCoverageRequest) (*pb.ResponseCoverageResponse, error) {
+
+	if req.AoiGeojson == "" {
+		return nil, status.Error(codes.InvalidArgument, "aoi_geojson is required")
+	}
+	if _, err := osm.ParseAOI(req.AoiGeojson); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid AOI: %v", err)
+	}
+
+	opts := osm.ResponseOptions{
+		BufferM:     s.config.ResponseBufferM,
+		BandsMin:    s.config.ResponseBandsMin,
+		CoverageMin: s.config.ResponseCoverageMin,
+		SnapMaxM:    s.config.RoadSnapMaxM,
+		CellM:       s.config.IsochroneCellM,
+	}
+	if len(req.BandMinutes) > 0 {
+		opts.BandsMin = req.BandMinutes
+	}
+	if req.CoverageMinutes != nil {
+		opts.CoverageMin = *req.CoverageMinutes
+	}
+	for i, minutes := range opts.BandsMin {
+		if minutes <= 0 || (i > 0 && minutes <= opts.BandsMin[i-1]) {
+			return nil, status.Error(codes.InvalidArgument, "band_minutes must be positive and ascending")
+		}
+	}
+	if opts.CoverageMin <= 0 {
+		return nil, status.Error(codes.InvalidArgument, "coverage_minutes must be positive")
+	}
+
+	analysis, err := osm.AnalyzeResponse(ctx, s.assets, s.catalog, req.AoiGeojson, opts)
+	if err != nil {
+		log.Printf("Failed to analyse response coverage from %s: %v", s.config.AssetSource, err)
+		return nil, status.Error(codes.Internal, "failed to analyse fire station response coverage")
+	}
+
+	response := &pb.ResponseCoverageResponse{
+		FireStations:    int32(analysis.Stations),
+		CoverageMinutes: opts.CoverageMin,
+		Coverage: coverageToProto(&osm.QueryResult{
+			TilesTotal:  analysis.TilesTotal,
+			FailedTiles: analysis.FailedTiles,
+		}),
+		Attribution: attributionToProto(analysis.Sources),
+	}
+
+	for _, b := range analysis.Buildings {
+		if b.OutsideCoverage {
+			response.OutsideCoverageCount++
+		}
+		response.Buildings = append(response.Buildings, &pb.BuildingResponse{
+			OsmType:         b.OSMType,
+			OsmId:           b.OSMID,
+			DriveTimeS:      b.DriveTimeS,
+			Band:            b.Band,
+			OutsideCoverage: b.OutsideCoverage,
+			StationId:       b.StationID,
+		})
+	}
+
+	for _, isochrone := range analysis.Isochrones {
+		response.Isochrones = append(response.Isochrones, &pb.Isochrone{
+			Minutes:         isochrone.Minutes,
+			GeometryGeojson: polygonsGeoJSON(isochrone.Polygons),
+		})
+	}
+
+	log.Printf("%d of %d buildings are outside %g-minute fire station coverage",
+		response.OutsideCoverageCount, len(response.Buildings), opts.CoverageMin)
+
+	return response, nil
+}
+
+// AnalyzePowerLineSpans splits the power lines in the AOI into spans between
+// supports and ranks them by fuel, slope and buildings downwind
+func (s *InfrastructureServer) AnalyzePowerLineSpans(ctx context.Context, req *pb.PowerSpanRequest) (*pb.PowerSpanResponse, error) {
+
+	if req.AoiGeojson == "" {
+		return nil, status.Error(codes.InvalidArgument, "aoi_geojson is required")
+	}
+	if _, err := osm.ParseAOI(req.AoiGeojson); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid AOI: %v", err)
+	}
+
+	opts := osm.SpanOptions{
+		CorridorM:       s.config.SpanCorridorM,
+		SampleM:         s.config.SpanSampleM,
+		WindFromDeg:     req.WindFromDeg,
+		DownwindM:       s.config.DownwindDistanceM,
+		DownwindHalfDeg: s.config.DownwindHalfAngleDeg,
+	}
+	if req.DownwindDistanceM != nil {
+		if *req.DownwindDistanceM <= 0 {
+			return nil, status.Error(codes.InvalidArgument, "downwind_distance_m must be positive")
+		}
+		opts.DownwindM = *req.DownwindDistanceM
+	}
+	if opts.SampleM <= 0 {
+		return nil, status.Errorf(codes.FailedPrecondition, "span sample spacing must be positive, got %v", opts.SampleM)
+	}
+
+	filter := osm.AssetFilter{AssetTypes: []string{osm.PowerLineAssetType, osm.PowerSupportAssetType, osm.BuildingAssetType}}
+	if _, err := s.catalog.Narrow(filter); err != nil {
+		return nil, status.Errorf(codes.FailedPrecondition, "asset catalog does not support power line analysis: %v", err)
+	}
+
+	elevation, err := s.elevation(ctx, req.AoiGeojson)
+	if err != nil {
+		return nil, err
+	}
+	opts.Elevation = elevation
+
+	analysis, err := osm.AnalyzePowerSpans(ctx, s.assets, s.fuel, req.AoiGeojson, opts)
+	if err != nil {
+		log.Printf("Failed to analyse power line spans from %s: %v", s.config.AssetSource, err)
+		return nil, status.Error(codes.Internal, "failed to analyse power line spans")
+	}
+
+	response := &pb.PowerSpanResponse{
+		Coverage: coverageToProto(&osm.QueryResult{
+			TilesTotal:  analysis.TilesTotal,
+			FailedTiles: analysis.FailedTiles,
+		}),
+		Attribution: attributionToProto(analysis.Sources),
+	}
+	for _, span := range analysis.Spans {
+		response.Spans = append(response.Spans, &pb.PowerSpan{
+			Rank:              int32(span.Rank),
+			WayId:             span.WayID,
+			FromSupport:       span.FromSupport,
+			ToSupport:         span.ToSupport,
+			GeometryGeojson:   lineGeoJSON(span.Geometry),
+			LengthM:           span.LengthM,
+			VoltageV:          span.VoltageV,
+			Operator:          span.Operator,
+			FuelShares:        span.FuelShares,
+			FuelClassUnder:    span.FuelUnder,
+			SlopeDeg:          span.SlopeDeg,
+			BuildingsDownwind: int32(span.BuildingsDownwind),
+			ExposureScore:     span.Score,
+		})
+	}
+
+	return response, nil
+}
+
+// elevation fetches the AOI's DEM from the Topography Service for span
+// slopes. Spans are not scored without it, since dropping the slope term
+// would make their scores incomparable with scored ones.
+func (s *InfrastructureServer) elevation(ctx context.Context, aoiGeoJSON string) (osm.Elevation, error) {
+	if s.topography == nil {
+		return nil, status.Error(codes.FailedPrecondition, "power line analysis needs elevation data, but no Topography Service is configured")
+	}
+
+	resp, err := s.topography.GetDemForAOI(ctx, &pb.GetDemRequest{AoiGeojson: aoiGeoJSON})
+	if status.Code(err) == codes.InvalidArgument {
+		// Such as an AOI crossing the antimeridian, which one DEM cannot cover
+		return nil, err
+	}
+	if err != nil {
+		log.Printf("Failed to get DEM for power line analysis: %v", err)
+		return nil, status.Errorf(codes.Unavailable, "elevation data unavailable: %s", status.Convert(err).Message())
+	}
+
+	dem, err := raster.ReadFile(resp.LocalDemPath)
+	if err != nil {
+		log.Printf("Failed to read DEM %s: %v", resp.LocalDemPath, err)
+		return nil, status.Error(codes.Internal, "failed to read elevation data")
+	}
+	return demElevation{dem}, nil
+}
+
+// demElevation reads ground heights from the first band of a DEM
+type demElevation struct {
+	dem *raster.Raster
+}
+
+func (d demElevation) ElevationAt(p geo.Point) (float64, bool) {
+	return d.dem.Value(0, p.Lon(), p.Lat())
+}
+
+// locateSuppression looks up the water sources and fire stations around the
+// AOI when the request asks for asset distances, and reports the coverage of
+// that lookup. Assets are still returned without distances if it fails, with
+// the coverage marked partial.
+func (s *InfrastructureServer) locateSuppression(ctx context.Context, req *pb.GetAssetsRequest) (*osm.Suppression, *pb.Coverage, error) {
+	if !req.IncludeSuppressionDistances {
+		return nil, nil, nil
+	}
+	if s.config.SuppressionSearchM <= 0 {
+		return nil, nil, status.Error(codes.FailedPrecondition, "suppression distances are disabled on this server")
+	}
+
+	suppression, result, err := osm.LocateSuppression(ctx, s.assets, s.catalog, req.AoiGeojson, s.config.SuppressionSearchM)
+	if err != nil {
+		log.Printf("Suppression distances unavailable: %v", err)
+		return nil, &pb.Coverage{Partial: true}, nil
+	}
+	if suppression == nil {
+		return nil, nil, status.Error(codes.FailedPrecondition, "the asset catalog has no suppression resources")
+	}
+	if len(result.FailedTiles) > 0 {
+		log.Printf("Partial suppression coverage: %d of %d tiles failed", len(result.FailedTiles), result.TilesTotal)
+	}
+	return suppression, coverageToProto(result), nil
+}
+
+// assetFilter reads the request's filters, rejecting ones the catalog cannot
+// satisfy before anything is fetched
+func (s *InfrastructureServer) assetFilter(req *pb.GetAssetsRequest) (osm.AssetFilter, error) {
+	filter := osm.AssetFilter{
+		AssetTypes:        req.AssetTypes,
+		Tags:              req.TagFilters,
+		ExcludeMinorRoads: req.ExcludeMinorRoads,
+	}
+
+	if _, err := s.catalog.Narrow(filter); err != nil {
+		return osm.AssetFilter{}, status.Errorf(codes.InvalidArgument, "invalid asset filter: %v", err)
+	}
+	return filter, nil
+}
+
+func assetToProto(asset osm.Asset) (*pb.Asset, error) {
+	geometryJSON, err := json.Marshal(asset.Geometry)
+	if err != nil {
+		return nil, err
+	}
+
+	properties := make(map[string]string)
+	for k, v := range asset.Properties {
+		properties[k] = fmt.Sprintf("%v", v)
+	}
+
+	return &pb.Asset{
+		AssetType:            asset.Type,
+		AssetGeometryGeojson: string(geometryJSON),
+		Properties:           properties,
+		WithinAoi:            asset.WithinAOI,
+		VulnerabilityWeight:  asset.VulnerabilityWeight,
+		AreaM2:               asset.Measures.AreaM2,
+		LengthM:              asset.Measures.LengthM,
+		CentroidLon:          asset.Measures.Centroid.Lon(),
+		CentroidLat:          asset.Measures.Centroid.Lat(),
+		Bbox:                 bboxToProto(asset.Measures.Bounds),
+		BuildingLevels:       asset.NumericTags.BuildingLevels,
+		HeightM:              asset.NumericTags.HeightM,
+		VoltageV:             asset.NumericTags.VoltageV,
+		WaterDistanceM:       asset.Suppression.WaterM,
+		FireStationDistanceM: asset.Suppression.FireStationM,
+		Provenance: &pb.Provenance{
+			Version:       int32(asset.Provenance.Version),
+			Timestamp:     asset.Provenance.Timestamp,
+			Changeset:     asset.Provenance.Changeset,
+			User:          asset.Provenance.User,
+			Source:        asset.Provenance.Endpoint,
+			DataTimestamp: asset.Provenance.DataTimestamp,
+		},
 	}, nil
 }
+
+// attributionToProto credits OpenStreetMap under the ODbL along with the
+// sources a response was built from
+func attributionToProto(sources []osm.DataSource) *pb.Attribution {
+	attribution := &pb.Attribution{
+		Text:       models.OSMAttributionText,
+		License:    models.OSMLicense,
+		LicenseUrl: models.OSMLicenseURL,
+	}
+
+	for _, source := range sources {
+		attribution.Sources = append(attribution.Sources, &pb.DataSource{
+			Endpoint:      source.Endpoint,
+			DataTimestamp: source.DataTimestamp,
+		})
+	}
+
+	return attribution
+}
+
+func coverageToProto(result *osm.QueryResult) *pb.Coverage {
+	coverage := &pb.Coverage{
+		TilesTotal:  int32(result.TilesTotal),
+		TilesFailed: int32(len(result.FailedTiles)),
+		Partial:     len(result.FailedTiles) > 0,
+	}
+
+	for _, tile := range result.FailedTiles {
+		coverage.FailedTiles = append(coverage.FailedTiles, bboxToProto(tile.Bounds))
+	}
+
+	return coverage
+}
+
+// lineGeoJSON encodes points as a GeoJSON LineString
+func lineGeoJSON(points []geo.Point) string {
+	coords := make([][]float64, len(points))
+	for i, p := range points {
+		coords[i] = []float64{p.Lon(), p.Lat()}
+	}
+
+	data, err := json.Marshal(geojson.NewLineStringGeometry(coords))
+	if err != nil {
+		return ""
+	}
+	return string(data)
+}
+
+// polygonsGeoJSON encodes polygons as a GeoJSON MultiPolygon
+func polygonsGeoJSON(polygons []geo.Polygon) string {
+	coords := make([][][][]float64, len(polygons))
+	for i, polygon := range polygons {
+		coords[i] = make([][][]float64, len(polygon))
+		for j, ring := range polygon {
+			for _, p := range ring {
+				coords[i][j] = append(coords[i][j], []float64{p.Lon(), p.Lat()})
+			}
+		}
+	}
+
+	data, err := json.Marshal(geojson.NewMultiPolygonGeometry(coords...))
+	if err != nil {
+		return ""
+	}
+	return string(data)
+}
+
+func bboxToProto(b geo.BBox) *pb.BoundingBox {
+	return &pb.BoundingBox{
+		MinLon: b.MinLon,
+		MinLat: b.MinLat,
+		MaxLon: b.MaxLon,
+		MaxLat: b.MaxLat,
+	}
+}
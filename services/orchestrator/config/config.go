@@ -0,0 +1,79 @@
+package config
+
+import (
+	"strconv"
+	"time"
+	"wildfire-risk-platform/shared/config"
+)
+
+type Config struct {
+	GRPCPort     string
+	KafkaBrokers string
+
+	// Data services the pipeline gathers a job's inputs from
+	InfrastructureServiceURL string
+	TopographyServiceURL     string
+
+	// A new job reuses the results of a completed job with the same AOI and
+	// parameters that finished within ResultReuseWindow, unless the client
+	// forces a refresh. Zero disables reuse.
+	ResultReuseWindow time.Duration
+
+	// At most MaxRunningJobs jobs run at once, and at most
+	// MaxRunningJobsPerSubmitter from one submitter. Zero means unlimited.
+	MaxRunningJobs             int
+	MaxRunningJobsPerSubmitter int
+
+	// AOIs larger than MaxAOIAreaKm2 run as a grid of child jobs. Zero
+	// disables the limit; AOIs crossing the antimeridian are still split
+	// there.
+	MaxAOIAreaKm2 float64
+
+	// AOIs that would split into more than MaxAOIChildJobs child jobs are
+	// rejected. Zero means unlimited.
+	MaxAOIChildJobs int
+}
+
+func LoadConfig() (*Config, error) {
+
+	config.LoadEnv()
+	reuse_window, err := time.ParseDuration(config.GetEnv("RESULT_REUSE_WINDOW", "24h"))
+	if err != nil {
+		return &Config{}, err
+	}
+	max_running, err := strconv.Atoi(config.GetEnv("MAX_RUNNING_JOBS", "8"))
+	if err != nil {
+		return &Config{}, err
+	}
+	max_per_submitter, err := strconv.Atoi(config.GetEnv("MAX_RUNNING_JOBS_PER_SUBMITTER", "2"))
+	if err != nil {
+		return &Config{}, err
+	}
+	max_aoi_area, err := strconv.ParseFloat(config.GetEnv("MAX_AOI_AREA_KM2", "250"), 64)
+	if err != nil {
+		return &Config{}, err
+	}
+	max_aoi_children, err := strconv.Atoi(config.GetEnv("MAX_AOI_CHILD_JOBS", "1000"))
+	if err != nil {
+		return &Config{}, err
+	}
+
+	cfg := &Config{
+
+		GRPCPort:     config.GetEnv("GRPC_PORT", "9000"),
+		KafkaBrokers: config.GetEnv("KAFKA_BROKERS", "localhost:9092"),
+
+		InfrastructureServiceURL: config.GetEnv("INFRASTRUCTURE_SERVICE_URL", "localhost:9001"),
+		TopographyServiceURL:     config.GetEnv("TOPOGRAPHY_SERVICE_URL", "localhost:9002"),
+
+		ResultReuseWindow: reuse_window,
+
+		MaxRunningJobs:             max_running,
+		MaxRunningJobsPerSubmitter: max_per_submitter,
+
+		MaxAOIAreaKm2:   max_aoi_area,
+		MaxAOIChildJobs: max_aoi_children,
+	}
+
+	return cfg, nil
+}
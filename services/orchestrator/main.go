@@ -0,0 +1,107 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	"google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/reflection"
+
+	"orchestrator/config"
+	"orchestrator/pipeline"
+	"orchestrator/server"
+	pb "wildfire-risk-platform/api/proto/generated"
+	"wildfire-risk-platform/shared/database"
+	"wildfire-risk-platform/shared/kafka"
+)
+
+func main() {
+
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		log.Fatalf("Failed to load config: %v", err)
+	}
+
+	log.Println("Starting Orchestrator Service...")
+
+	db, err := database.NewConnection()
+	if err != nil {
+		log.Fatalf("Failed to connect to database: %v", err)
+	}
+	defer db.Close()
+
+	kafkaClient := kafka.NewClient(cfg.KafkaBrokers)
+	if err := kafkaClient.CreateTopics(context.Background(), kafka.RequiredTopics); err != nil {
+		log.Printf("Failed to create Kafka topics: %v", err)
+	}
+
+	// Create the pipeline runner that takes jobs through the data services
+	runner, err := pipeline.NewRunner(cfg, db, kafkaClient)
+	if err != nil {
+		log.Fatalf("Failed to create pipeline runner: %v", err)
+	}
+	defer runner.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	if err := runner.Resume(ctx); err != nil {
+		log.Fatalf("Failed to resume unfinished jobs: %v", err)
+	}
+	go runner.ConsumeResults(ctx)
+
+	// Create gRPC server with interceptors
+	grpcServer := grpc.NewServer(
+		grpc.UnaryInterceptor(loggingInterceptor),
+	)
+
+	// Register the orchestrator service
+	pb.RegisterOrchestratorServiceServer(grpcServer, server.NewOrchestratorServer(cfg, db, runner))
+
+	// Register health check service
+	healthServer := health.NewServer()
+	grpc_health_v1.RegisterHealthServer(grpcServer, healthServer)
+	healthServer.SetServingStatus("orchestrator", grpc_health_v1.HealthCheckResponse_SERVING)
+
+	// Register reflection service for debugging
+	reflection.Register(grpcServer)
+
+	listener, err := net.Listen("tcp", fmt.Sprintf(":%s", cfg.GRPCPort))
+	if err != nil {
+		log.Fatalf("Failed to listen on port %s: %v", cfg.GRPCPort, err)
+	}
+
+	go func() {
+		sigChan := make(chan os.Signal, 1)
+		signal.Notify(sigChan, os.Interrupt, syscall.SIGTERM)
+		<-sigChan
+
+		log.Println("Shutting down gracefully...")
+		cancel()
+		grpcServer.GracefulStop()
+	}()
+
+	// Start serving
+	log.Printf("Orchestrator Service listening on port %s", cfg.GRPCPort)
+	log.Printf("Gathering data from %s and %s", cfg.InfrastructureServiceURL, cfg.TopographyServiceURL)
+
+	if err := grpcServer.Serve(listener); err != nil {
+		log.Fatalf("Failed to serve: %v", err)
+	}
+}
+
+func loggingInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	log.Printf("gRPC call: %s", info.FullMethod)
+	resp, err := handler(ctx, req)
+	if err != nil {
+		log.Printf("gRPC call failed: %s - %v", info.FullMethod, err)
+	}
+	return resp, err
+}
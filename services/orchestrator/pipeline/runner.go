@@ -0,0 +1,438 @@
+package pipeline
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	kafkago "github.com/segmentio/kafka-go"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+
+	"orchestrator/config"
+	pb "wildfire-risk-platform/api/proto/generated"
+	"wildfire-risk-platform/shared/database"
+	"wildfire-risk-platform/shared/database/models"
+	"wildfire-risk-platform/shared/kafka"
+	"wildfire-risk-platform/shared/risk"
+	"wildfire-risk-platform/shared/scheduler"
+)
+
+// resultsGroupID is the consumer group the orchestrator reads analytics
+// results in
+const resultsGroupID = "orchestrator"
+
+// gatherTimeout bounds fetching a job's assets and DEM
+const gatherTimeout = 30 * time.Minute
+
+// Runner takes jobs through the pipeline: it gathers the AOI's assets from
+// the Infrastructure Service and its DEM from the Topography Service, stores
+// the assets and hands the job to processing over Kafka. Jobs complete when
+// processing publishes their analytics result. Jobs wait in a fair scheduler
+// and hold a slot from the moment they start until they complete or fail.
+type Runner struct {
+	db             *database.DB
+	infrastructure pb.InfrastructureServiceClient
+	topography     pb.TopographyServiceClient
+	tasks          *kafkago.Writer
+	results        *kafkago.Reader
+	scheduler      *scheduler.FairScheduler
+
+	mu     sync.Mutex
+	queued map[string]*models.RiskAssessmentJob // Jobs in the scheduler's queue by ID
+
+	conns []*grpc.ClientConn
+}
+
+// NewRunner connects to the data services. Connections are made lazily, so
+// the services do not need to be up yet.
+func NewRunner(cfg *config.Config, db *database.DB, kafkaClient *kafka.Client) (*Runner, error) {
+	infrastructureConn, err := grpc.NewClient(cfg.InfrastructureServiceURL, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create infrastructure client: %w", err)
+	}
+	topographyConn, err := grpc.NewClient(cfg.TopographyServiceURL, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		infrastructureConn.Close()
+		return nil, fmt.Errorf("failed to create topography client: %w", err)
+	}
+
+	return &Runner{
+		db:             db,
+		infrastructure: pb.NewInfrastructureServiceClient(infrastructureConn),
+		topography:     pb.NewTopographyServiceClient(topographyConn),
+		tasks:          kafkaClient.NewWriter(kafka.TopicProcessTasks),
+		results:        kafkaClient.NewReader(kafka.TopicAnalyticsResults, resultsGroupID),
+		scheduler:      scheduler.NewFairScheduler(cfg.MaxRunningJobs, cfg.MaxRunningJobsPerSubmitter),
+		queued:         make(map[string]*models.RiskAssessmentJob),
+		conns:          []*grpc.ClientConn{infrastructureConn, topographyConn},
+	}, nil
+}
+
+// Submit queues a PENDING job. It starts once the scheduler gives it a slot.
+func (r *Runner) Submit(job *models.RiskAssessmentJob) error {
+	id := job.JobID.String()
+
+	r.mu.Lock()
+	r.queued[id] = job
+	r.mu.Unlock()
+
+	if err := r.scheduler.Enqueue(scheduler.JobFromModel(job)); err != nil {
+		r.mu.Lock()
+		delete(r.queued, id)
+		r.mu.Unlock()
+		return err
+	}
+
+	r.dispatch()
+	return nil
+}
+
+// QueuePosition returns a waiting job's 1-based place in the queue, or 0 if
+// it is not waiting
+func (r *Runner) QueuePosition(jobID uuid.UUID) int {
+	return r.scheduler.Position(jobID.String())
+}
+
+// Resume rebuilds the queue after a restart. Jobs handed to processing keep
+// their slots and finish through their analytics result; jobs that were
+// waiting or gathering data are queued again.
+func (r *Runner) Resume(ctx context.Context) error {
+	processing, err := r.db.ListJobsByStatus(ctx, models.JobStatusProcessing)
+	if err != nil {
+		return err
+	}
+	for _, job := range processing {
+		r.scheduler.MarkRunning(scheduler.JobFromModel(job))
+	}
+
+	jobs, err := r.db.ListJobsByStatus(ctx, models.JobStatusPending, models.JobStatusGatheringData)
+	if err != nil {
+		return err
+	}
+	for _, job := range jobs {
+		if err := r.Submit(job); err != nil {
+			return err
+		}
+	}
+
+	if len(processing)+len(jobs) > 0 {
+		log.Printf("Resumed %d unfinished jobs, %d of them processing", len(processing)+len(jobs), len(processing))
+	}
+	return nil
+}
+
+// dispatch starts queued jobs while the scheduler has free slots
+func (r *Runner) dispatch() {
+	for {
+		next, ok := r.scheduler.Next()
+		if !ok {
+			return
+		}
+
+		r.mu.Lock()
+		job := r.queued[next.ID]
+		delete(r.queued, next.ID)
+		r.mu.Unlock()
+
+		go r.start(job)
+	}
+}
+
+// start gathers a job's data and hands it to processing
+func (r *Runner) start(job *models.RiskAssessmentJob) {
+	ctx, cancel := context.WithTimeout(context.Background(), gatherTimeout)
+	defer cancel()
+
+	if err := r.run(ctx, job); err != nil {
+		log.Printf("Job %s failed: %v", job.JobID, err)
+		r.finish(context.Background(), job.JobID, job.ParentJobID, models.JobStatusFailed)
+	}
+}
+
+// finish records a job's final status and frees its slot for the next job.
+// parentID is the split parent the job belongs to, if any.
+func (r *Runner) finish(ctx context.Context, jobID uuid.UUID, parentID *uuid.UUID, status models.JobStatus) {
+	r.setStatus(ctx, jobID, status)
+	r.refreshParent(ctx, parentID)
+	r.scheduler.Done(jobID.String())
+	r.dispatch()
+}
+
+// updateStatus moves a running job on, keeping its split parent's status in
+// step
+func (r *Runner) updateStatus(ctx context.Context, job *models.RiskAssessmentJob, status models.JobStatus) error {
+	if err := r.db.UpdateJobStatus(ctx, job.JobID, status); err != nil {
+		return err
+	}
+	r.refreshParent(ctx, job.ParentJobID)
+	return nil
+}
+
+// refreshParent recomputes a split parent's status after one of its
+// children changed. Failures are logged; the next change retries.
+func (r *Runner) refreshParent(ctx context.Context, parentID *uuid.UUID) {
+	if parentID == nil {
+		return
+	}
+	if _, err := r.db.RefreshParentStatus(ctx, *parentID); err != nil {
+		log.Printf("Failed to refresh parent job %s: %v", *parentID, err)
+	}
+}
+
+func (r *Runner) run(ctx context.Context, job *models.RiskAssessmentJob) error {
+	if _, err := jobModel(job); err != nil {
+		return err
+	}
+	if err := r.updateStatus(ctx, job, models.JobStatusGatheringData); err != nil {
+		return err
+	}
+
+	assets, sources, err := r.gatherAssets(ctx, job)
+	if err != nil {
+		return err
+	}
+	if _, err := r.db.RegisterJobAssets(ctx, job.JobID, assets); err != nil {
+		return err
+	}
+	if err := r.db.AddJobDataSources(ctx, job.JobID, sources); err != nil {
+		return err
+	}
+
+	dem, err := r.topography.GetDemForAOI(ctx, &pb.GetDemRequest{AoiGeojson: job.AOIPolygon})
+	if err != nil {
+		return fmt.Errorf("failed to get DEM: %w", err)
+	}
+
+	if err := r.updateStatus(ctx, job, models.JobStatusProcessing); err != nil {
+		return err
+	}
+
+	task, err := json.Marshal(kafka.ProcessTask{
+		JobID:          job.JobID.String(),
+		AOIGeoJSON:     job.AOIPolygon,
+		DEMPath:        dem.LocalDemPath,
+		RiskParameters: job.RiskParameters,
+		ModelName:      job.ModelName,
+		ModelVersion:   job.ModelVersion,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to encode process task: %w", err)
+	}
+	if err := r.tasks.WriteMessages(ctx, kafkago.Message{Key: []byte(job.JobID.String()), Value: task}); err != nil {
+		return fmt.Errorf("failed to publish process task: %w", err)
+	}
+
+	log.Printf("Job %s handed to processing with %d assets", job.JobID, len(assets))
+	return nil
+}
+
+// gatherAssets streams the job's asset types in the AOI, with their
+// suppression distances, from the Infrastructure Service, along with the
+// sources they were read from for attribution. A partial fetch fails the job,
+// since missing tiles would silently leave assets unscored or far from water
+// they are actually near.
+func (r *Runner) gatherAssets(ctx context.Context, job *models.RiskAssessmentJob) ([]models.InfrastructureAsset, []models.DataSource, error) {
+	stream, err := r.infrastructure.StreamAssetsInAOI(ctx, &pb.GetAssetsRequest{
+		AoiGeojson:                  job.AOIPolygon,
+		AssetTypes:                  job.RiskParameters.AssetTypes,
+		IncludeSuppressionDistances: true,
+	})
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to fetch assets: %w", err)
+	}
+
+	var assets []models.InfrastructureAsset
+	var sources []models.DataSource
+	for {
+		msg, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to fetch assets: %w", err)
+		}
+
+		if batch := msg.GetBatch(); batch != nil {
+			for _, asset := range batch.Assets {
+				assets = append(assets, assetFromProto(asset))
+			}
+		}
+		if summary := msg.GetSummary(); summary != nil {
+			if summary.Coverage.GetPartial() {
+				return nil, nil, fmt.Errorf("asset data incomplete: %d of %d tiles failed", summary.Coverage.TilesFailed, summary.Coverage.TilesTotal)
+			}
+			if summary.SuppressionCoverage.GetPartial() {
+				return nil, nil, fmt.Errorf("suppression resources incomplete: %d of %d tiles failed", summary.SuppressionCoverage.TilesFailed, summary.SuppressionCoverage.TilesTotal)
+			}
+			for _, source := range summary.Attribution.GetSources() {
+				sources = append(sources, models.DataSource{Endpoint: source.Endpoint, DataTimestamp: source.DataTimestamp})
+			}
+		}
+	}
+
+	return assets, sources, nil
+}
+
+// ConsumeResults applies analytics results to their jobs until ctx is done
+func (r *Runner) ConsumeResults(ctx context.Context) {
+	for {
+		msg, err := r.results.ReadMessage(ctx)
+		if err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+			log.Printf("Failed to read analytics result: %v", err)
+			time.Sleep(time.Second)
+			continue
+		}
+
+		var result kafka.AnalyticsResult
+		if err := json.Unmarshal(msg.Value, &result); err != nil {
+			log.Printf("Skipping malformed analytics result: %v", err)
+			continue
+		}
+		jobID, err := uuid.Parse(result.JobID)
+		if err != nil {
+			log.Printf("Skipping analytics result for invalid job ID %q", result.JobID)
+			continue
+		}
+
+		status := models.JobStatusComplete
+		if models.JobStatus(result.Status) != models.JobStatusComplete {
+			status = models.JobStatusFailed
+			log.Printf("Processing failed for job %s: %s", jobID, result.Message)
+		}
+
+		job, err := r.db.GetJob(ctx, jobID)
+		if err != nil {
+			log.Printf("Failed to look up job %s: %v", jobID, err)
+		}
+		var parentID *uuid.UUID
+		if job != nil {
+			parentID = job.ParentJobID
+		}
+		if status == models.JobStatusComplete {
+			// Unscored components are not results, so a job that cannot be
+			// scored fails
+			if err := r.score(ctx, jobID, job); err != nil {
+				log.Printf("Failed to score job %s: %v", jobID, err)
+				status = models.JobStatusFailed
+			}
+		}
+		r.finish(ctx, jobID, parentID, status)
+	}
+}
+
+// score computes the overall scores of a processed job's assets through the
+// model pinned when the job was created
+func (r *Runner) score(ctx context.Context, jobID uuid.UUID, job *models.RiskAssessmentJob) error {
+	if job == nil {
+		return fmt.Errorf("job %s could not be loaded", jobID)
+	}
+	model, err := jobModel(job)
+	if err != nil {
+		return err
+	}
+
+	n, err := r.db.ScoreJobAssets(ctx, job, model)
+	if err != nil {
+		return err
+	}
+
+	log.Printf("Scored %d assets of job %s with %s %s", n, job.JobID, model.Name(), model.Version())
+	return nil
+}
+
+// jobModel returns the registered model a job was pinned to. A job whose
+// model version has since been replaced fails rather than mixing scores from
+// two versions.
+func jobModel(job *models.RiskAssessmentJob) (risk.RiskModel, error) {
+	model, err := risk.LookupModel(job.ModelName)
+	if err != nil {
+		return nil, err
+	}
+	if model.Version() != job.ModelVersion {
+		return nil, fmt.Errorf("job uses %s %s but %s is registered", job.ModelName, job.ModelVersion, model.Version())
+	}
+	return model, nil
+}
+
+// setStatus updates a job's status, logging rather than returning failures
+// since there is no caller left to report them to
+func (r *Runner) setStatus(ctx context.Context, jobID uuid.UUID, status models.JobStatus) {
+	if err := r.db.UpdateJobStatus(ctx, jobID, status); err != nil {
+		log.Printf("Failed to mark job %s %s: %v", jobID, status, err)
+	}
+}
+
+// Close stops the Kafka clients and data service connections
+func (r *Runner) Close() error {
+	var errs []error
+	errs = append(errs, r.tasks.Close(), r.results.Close())
+	for _, conn := range r.conns {
+		errs = append(errs, conn.Close())
+	}
+	return errors.Join(errs...)
+}
+
+// assetFromProto converts a fetched asset into its stored form. The derived
+// measures and distances are kept in the properties for scoring.
+func assetFromProto(asset *pb.Asset) models.InfrastructureAsset {
+	properties := make(map[string]interface{}, len(asset.Properties)+8)
+	for k, v := range asset.Properties {
+		properties[k] = v
+	}
+	properties[models.PropertyWithinAOI] = asset.WithinAoi
+	properties[models.PropertyVulnerabilityWeight] = asset.VulnerabilityWeight
+	if asset.AreaM2 > 0 {
+		properties[models.PropertyAreaM2] = asset.AreaM2
+	}
+	if asset.LengthM > 0 {
+		properties[models.PropertyLengthM] = asset.LengthM
+	}
+	if asset.WaterDistanceM != nil {
+		properties[models.PropertyWaterDistanceM] = *asset.WaterDistanceM
+	}
+	if asset.FireStationDistanceM != nil {
+		properties[models.PropertyFireStationDistanceM] = *asset.FireStationDistanceM
+	}
+
+	stored := models.InfrastructureAsset{
+		AssetType:     asset.AssetType,
+		AssetGeometry: asset.AssetGeometryGeojson,
+		Properties:    properties,
+	}
+
+	if p := asset.Provenance; p != nil {
+		stored.Provenance = models.AssetProvenance{
+			OSMVersion:    int(p.Version),
+			OSMTimestamp:  parseTime(p.Timestamp),
+			OSMChangeset:  p.Changeset,
+			OSMUser:       p.User,
+			DataSource:    p.Source,
+			DataTimestamp: parseTime(p.DataTimestamp),
+		}
+	}
+
+	return stored
+}
+
+// parseTime reads an RFC 3339 timestamp, returning nil when it is missing or
+// unreadable
+func parseTime(s string) *time.Time {
+	if s == "" {
+		return nil
+	}
+	t, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		return nil
+	}
+	return &t
+}
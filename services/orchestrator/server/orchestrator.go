@@ -0,0 +1,374 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+
+	"github.com/google/uuid"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"orchestrator/config"
+	"orchestrator/pipeline"
+	pb "wildfire-risk-platform/api/proto/generated"
+	"wildfire-risk-platform/shared/database"
+	"wildfire-risk-platform/shared/database/models"
+	"wildfire-risk-platform/shared/geo"
+	"wildfire-risk-platform/shared/risk"
+	"wildfire-risk-platform/shared/scheduler"
+	"wildfire-risk-platform/shared/utils"
+)
+
+// OrchestratorServer implements the OrchestratorService gRPC server
+type OrchestratorServer struct {
+	pb.UnimplementedOrchestratorServiceServer
+
+	config *config.Config
+	db     *database.DB
+	runner *pipeline.Runner
+}
+
+func NewOrchestratorServer(cfg *config.Config, db *database.DB, runner *pipeline.Runner) *OrchestratorServer {
+	return &OrchestratorServer{
+		config: cfg,
+		db:     db,
+		runner: runner,
+	}
+}
+
+// CreateRiskAssessmentJob stores a new job and queues it. When a job with the
+// same AOI and parameters completed within the reuse window, the new job
+// points at its results instead, unless the client forces a refresh. AOIs
+// over the area limit or crossing the antimeridian are split into child jobs
+// that are queued in its place.
+func (s *OrchestratorServer) CreateRiskAssessmentJob(ctx context.Context, req *pb.CreateJobRequest) (*pb.CreateJobResponse, error) {
+
+	if req.AoiGeojson == "" {
+		return nil, status.Error(codes.InvalidArgument, "aoi_geojson is required")
+	}
+
+	params := risk.ParametersFromProto(req.RiskParameters)
+	if err := params.Validate(); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid risk parameters: %v", err)
+	}
+	model, err := risk.LookupModel(params.Model)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid risk parameters: %v", err)
+	}
+
+	// Parse strictly first so every AOI the pipeline accepts is accepted
+	// here, and nothing else
+	if _, err := geo.ParseAOI(req.AoiGeojson); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid AOI: %v", err)
+	}
+	hash, err := utils.AOIHash(req.AoiGeojson, params.CanonicalJSON())
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid AOI: %v", err)
+	}
+
+	job := models.RiskAssessmentJob{
+		AOIPolygon:     req.AoiGeojson,
+		AOIHash:        hash,
+		RiskParameters: params,
+		ModelName:      model.Name(),
+		ModelVersion:   model.Version(),
+		Priority:       scheduler.PriorityFromProto(req.Priority),
+		Submitter:      req.Submitter,
+	}
+
+	if !req.ForceRefresh && s.config.ResultReuseWindow > 0 {
+		source, err := s.db.FindReusableJob(ctx, hash, model.Version(), s.config.ResultReuseWindow)
+		if err != nil {
+			log.Printf("Failed to look up reusable results: %v", err)
+			return nil, status.Error(codes.Internal, "failed to create job")
+		}
+		if source != nil {
+			reused, err := s.db.CreateReusedJob(ctx, job, source)
+			if err != nil {
+				log.Printf("Failed to create reused job: %v", err)
+				return nil, status.Error(codes.Internal, "failed to create job")
+			}
+
+			log.Printf("Job %s reuses the results of job %s", reused.JobID, source.ResultsJobID())
+			return &pb.CreateJobResponse{
+				JobId:           reused.JobID.String(),
+				Status:          statusToProto(reused.JobStatus),
+				Message:         "Results of a recent identical assessment were reused. Set force_refresh to run it again.",
+				ResultsReused:   true,
+				ReusedFromJobId: reused.ReusedFromJobID.String(),
+			}, nil
+		}
+	}
+
+	children, err := utils.SplitAOI(req.AoiGeojson, s.config.MaxAOIAreaKm2, s.config.MaxAOIChildJobs)
+	if errors.Is(err, utils.ErrTooManyChildren) {
+		return nil, status.Errorf(codes.InvalidArgument, "AOI is too large: %v", err)
+	}
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid AOI: %v", err)
+	}
+	if len(children) > 0 {
+		return s.createSplitJob(ctx, job, children)
+	}
+
+	created, err := s.db.CreateJob(ctx, job)
+	if err != nil {
+		log.Printf("Failed to create job: %v", err)
+		return nil, status.Error(codes.Internal, "failed to create job")
+	}
+	if err := s.runner.Submit(created); err != nil {
+		log.Printf("Failed to queue job %s: %v", created.JobID, err)
+		return nil, status.Error(codes.Internal, "failed to queue job")
+	}
+
+	return &pb.CreateJobResponse{
+		JobId:   created.JobID.String(),
+		Status:  statusToProto(created.JobStatus),
+		Message: "Risk assessment job accepted. Use the job_id to track status.",
+	}, nil
+}
+
+// createSplitJob stores a parent job with one child per AOI piece and queues
+// the children
+func (s *OrchestratorServer) createSplitJob(ctx context.Context, job models.RiskAssessmentJob, childAOIs []string) (*pb.CreateJobResponse, error) {
+	parent, children, err := s.db.CreateSplitJob(ctx, job, childAOIs)
+	if err != nil {
+		log.Printf("Failed to create split job: %v", err)
+		return nil, status.Error(codes.Internal, "failed to create job")
+	}
+	for _, child := range children {
+		if err := s.runner.Submit(child); err != nil {
+			log.Printf("Failed to queue child job %s of %s: %v", child.JobID, parent.JobID, err)
+			return nil, status.Error(codes.Internal, "failed to queue job")
+		}
+	}
+
+	log.Printf("Job %s split into %d child jobs", parent.JobID, len(children))
+	return &pb.CreateJobResponse{
+		JobId:   parent.JobID.String(),
+		Status:  statusToProto(parent.JobStatus),
+		Message: fmt.Sprintf("Risk assessment job accepted and split into %d parts. Use the job_id to track status.", len(children)),
+	}, nil
+}
+
+// GetJobStatus reports a job's progress
+func (s *OrchestratorServer) GetJobStatus(ctx context.Context, req *pb.GetJobStatusRequest) (*pb.GetJobStatusResponse, error) {
+
+	jobID, err := uuid.Parse(req.JobId)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid job_id")
+	}
+
+	job, err := s.db.GetJob(ctx, jobID)
+	if err != nil {
+		log.Printf("Failed to get job: %v", err)
+		return nil, status.Error(codes.Internal, "failed to get job")
+	}
+	if job == nil {
+		return nil, status.Errorf(codes.NotFound, "job %s not found", jobID)
+	}
+
+	resp := &pb.GetJobStatusResponse{
+		JobId:     job.JobID.String(),
+		Status:    statusToProto(job.JobStatus),
+		Message:   statusMessage(job),
+		CreatedAt: job.CreatedAt.Unix(),
+		UpdatedAt: job.UpdatedAt.Unix(),
+		Priority:  scheduler.PriorityToProto(job.Priority),
+	}
+
+	children, err := s.db.ListChildJobs(ctx, job.JobID)
+	if err != nil {
+		log.Printf("Failed to list child jobs: %v", err)
+		return nil, status.Error(codes.Internal, "failed to get job")
+	}
+	if len(children) > 0 {
+		// A split job waits until its first child starts
+		resp.ChildJobsTotal = int32(len(children))
+		for _, child := range children {
+			if child.JobStatus == models.JobStatusComplete {
+				resp.ChildJobsComplete++
+			}
+			if job.JobStatus == models.JobStatusPending && child.JobStatus == models.JobStatusPending {
+				position := int32(s.runner.QueuePosition(child.JobID))
+				if position > 0 && (resp.QueuePosition == 0 || position < resp.QueuePosition) {
+					resp.QueuePosition = position
+				}
+			}
+		}
+	} else if job.JobStatus == models.JobStatusPending {
+		resp.QueuePosition = int32(s.runner.QueuePosition(job.JobID))
+	}
+
+	return resp, nil
+}
+
+// GetJobResults summarises a complete job's scored assets and credits the
+// sources its data came from
+func (s *OrchestratorServer) GetJobResults(ctx context.Context, req *pb.GetJobResultsRequest) (*pb.GetJobResultsResponse, error) {
+
+	jobID, err := uuid.Parse(req.JobId)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid job_id")
+	}
+
+	job, err := s.db.GetJob(ctx, jobID)
+	if err != nil {
+		log.Printf("Failed to get job: %v", err)
+		return nil, status.Error(codes.Internal, "failed to get job results")
+	}
+	if job == nil {
+		return nil, status.Errorf(codes.NotFound, "job %s not found", jobID)
+	}
+
+	attribution, err := s.db.JobAttribution(ctx, job)
+	if err != nil {
+		log.Printf("Failed to build attribution: %v", err)
+		return nil, status.Error(codes.Internal, "failed to get job results")
+	}
+
+	resp := &pb.GetJobResultsResponse{
+		JobId:   job.JobID.String(),
+		Status:  statusToProto(job.JobStatus),
+		Message: statusMessage(job),
+		Attribution: &pb.Attribution{
+			Text:       attribution.Text,
+			License:    attribution.License,
+			LicenseUrl: attribution.LicenseURL,
+		},
+	}
+	for _, source := range attribution.Sources {
+		resp.Attribution.Sources = append(resp.Attribution.Sources, &pb.DataSource{
+			Endpoint:      source.Endpoint,
+			DataTimestamp: source.DataTimestamp,
+		})
+	}
+
+	if job.JobStatus == models.JobStatusComplete {
+		summary, err := s.db.SummariseJobResults(ctx, job)
+		if err != nil {
+			log.Printf("Failed to summarise results: %v", err)
+			return nil, status.Error(codes.Internal, "failed to get job results")
+		}
+		resp.Summary = &pb.JobResultSummary{
+			TotalAssets:      int32(summary.TotalAssets),
+			HighRiskAssets:   int32(summary.HighRiskAssets),
+			MediumRiskAssets: int32(summary.MediumRiskAssets),
+			LowRiskAssets:    int32(summary.LowRiskAssets),
+		}
+	}
+
+	return resp, nil
+}
+
+// GetAssetHistory returns a registry asset with its changes and risk scores
+// across jobs
+func (s *OrchestratorServer) GetAssetHistory(ctx context.Context, req *pb.GetAssetHistoryRequest) (*pb.GetAssetHistoryResponse, error) {
+
+	asset, err := s.db.GetCanonicalAsset(ctx, req.CanonicalAssetId)
+	if err != nil {
+		log.Printf("Failed to get asset: %v", err)
+		return nil, status.Error(codes.Internal, "failed to get asset history")
+	}
+	if asset == nil {
+		return nil, status.Errorf(codes.NotFound, "asset %d not found", req.CanonicalAssetId)
+	}
+
+	changes, err := s.db.ListAssetChanges(ctx, asset.CanonicalAssetID)
+	if err != nil {
+		log.Printf("Failed to list asset changes: %v", err)
+		return nil, status.Error(codes.Internal, "failed to get asset history")
+	}
+	history, err := s.db.ListAssetRiskHistory(ctx, asset.CanonicalAssetID)
+	if err != nil {
+		log.Printf("Failed to list asset risk history: %v", err)
+		return nil, status.Error(codes.Internal, "failed to get asset history")
+	}
+
+	resp := &pb.GetAssetHistoryResponse{
+		Asset: &pb.CanonicalAsset{
+			CanonicalAssetId:     asset.CanonicalAssetID,
+			IdentityKey:          asset.IdentityKey,
+			AssetType:            asset.AssetType,
+			AssetGeometryGeojson: asset.AssetGeometry,
+			Tags:                 tagsToProto(asset.Tags),
+			Version:              int32(asset.Version),
+			FirstSeenJobId:       uuidString(asset.FirstSeenJobID),
+			LastSeenJobId:        uuidString(asset.LastSeenJobID),
+			FirstSeenAt:          asset.FirstSeenAt.Unix(),
+			LastSeenAt:           asset.LastSeenAt.Unix(),
+		},
+	}
+	for _, c := range changes {
+		resp.Changes = append(resp.Changes, &pb.AssetChange{
+			JobId:                   uuidString(c.JobID),
+			ChangeType:              string(c.ChangeType),
+			Version:                 int32(c.Version),
+			PreviousGeometryGeojson: c.PreviousGeometry,
+			PreviousTags:            tagsToProto(c.PreviousTags),
+			ChangedTags:             c.ChangedTags,
+			ChangedAt:               c.ChangedAt.Unix(),
+		})
+	}
+	for _, p := range history {
+		resp.RiskHistory = append(resp.RiskHistory, &pb.AssetRiskPoint{
+			JobId:             p.JobID.String(),
+			OverallRiskScore:  p.OverallRiskScore,
+			ModelName:         p.ModelName,
+			ModelVersion:      p.ModelVersion,
+			AnalysisTimestamp: p.AnalysisTimestamp.Unix(),
+		})
+	}
+
+	return resp, nil
+}
+
+// tagsToProto flattens stored tags to strings. OSM tag values are strings
+// already; anything else is formatted.
+func tagsToProto(tags map[string]interface{}) map[string]string {
+	if len(tags) == 0 {
+		return nil
+	}
+	out := make(map[string]string, len(tags))
+	for k, v := range tags {
+		if s, ok := v.(string); ok {
+			out[k] = s
+		} else {
+			out[k] = fmt.Sprint(v)
+		}
+	}
+	return out
+}
+
+func uuidString(id *uuid.UUID) string {
+	if id == nil {
+		return ""
+	}
+	return id.String()
+}
+
+// statusToProto maps a stored status to the wire enum, which uses the same
+// names
+func statusToProto(s models.JobStatus) pb.JobStatus {
+	return pb.JobStatus(pb.JobStatus_value[string(s)])
+}
+
+func statusMessage(job *models.RiskAssessmentJob) string {
+	switch job.JobStatus {
+	case models.JobStatusPending:
+		return "Waiting to start"
+	case models.JobStatusGatheringData:
+		return "Gathering infrastructure and elevation data"
+	case models.JobStatusProcessing:
+		return "Scoring assets"
+	case models.JobStatusComplete:
+		if job.ReusedFromJobID != nil {
+			return "Complete, reusing the results of job " + job.ReusedFromJobID.String()
+		}
+		return "Complete"
+	default:
+		return "Failed"
+	}
+}
@@ -0,0 +1,56 @@
+package config
+
+import (
+	"strconv"
+	"time"
+	"wildfire-risk-platform/shared/config"
+)
+
+type Config struct {
+	GRPCPort string
+
+	// DEMDir holds the source DEM GeoTIFF tiles, searched recursively, e.g.
+	// USGS 3DEP tiles such as USGS_13_n38w123.tif. The tile index is rebuilt
+	// every DEMIndexTTL so new downloads are picked up.
+	DEMDir      string
+	DEMIndexTTL time.Duration
+
+	// Cropped DEMs are written to DEMOutputDir on the shared volume and
+	// extend DEMBufferM beyond the AOI. Requests needing more than
+	// DEMMaxPixels pixels are refused; a mosaic holds 8 bytes per pixel in
+	// memory while it is built.
+	DEMOutputDir string
+	DEMBufferM   float64
+	DEMMaxPixels int
+}
+
+func LoadConfig() (*Config, error) {
+
+	config.LoadEnv()
+	index_ttl, err := time.ParseDuration(config.GetEnv("DEM_INDEX_TTL", "10m"))
+	if err != nil {
+		return &Config{}, err
+	}
+	buffer, err := strconv.ParseFloat(config.GetEnv("DEM_BUFFER_M", "500"), 64)
+	if err != nil {
+		return &Config{}, err
+	}
+	max_pixels, err := strconv.Atoi(config.GetEnv("DEM_MAX_PIXELS", "16000000"))
+	if err != nil {
+		return &Config{}, err
+	}
+
+	cfg := &Config{
+
+		GRPCPort: config.GetEnv("GRPC_PORT", "9002"),
+
+		DEMDir:      config.GetEnv("DEM_DIR", "/data/dem"),
+		DEMIndexTTL: index_ttl,
+
+		DEMOutputDir: config.GetEnv("DEM_OUTPUT_DIR", "/data/processed/dem"),
+		DEMBufferM:   buffer,
+		DEMMaxPixels: max_pixels,
+	}
+
+	return cfg, nil
+}
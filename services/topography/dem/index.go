@@ -0,0 +1,167 @@
+package dem
+
+import (
+	"fmt"
+	"io/fs"
+	"log"
+	"math"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"wildfire-risk-platform/shared/raster"
+)
+
+// Tile is a DEM GeoTIFF on disk and where it lies
+type Tile struct {
+	Path        string
+	Width       int
+	Height      int
+	EPSG        int
+	Transform   raster.GeoTransform
+	NoData      *float64
+	Bounds      raster.BBox // WGS 84
+	ResolutionM float64
+}
+
+// Index finds the tiles under a directory that cover an area. The directory
+// is rescanned once the index is older than its TTL.
+type Index struct {
+	dir  string
+	skip string // Output directory, when it sits inside dir
+	ttl  time.Duration
+
+	mu    sync.Mutex
+	tiles []Tile
+	built time.Time
+}
+
+// NewIndex creates an index of dir, ignoring anything under skip
+func NewIndex(dir, skip string, ttl time.Duration) *Index {
+	return &Index{dir: filepath.Clean(dir), skip: filepath.Clean(skip), ttl: ttl}
+}
+
+// Covering returns the tiles overlapping a WGS 84 bbox, finest resolution
+// first
+func (i *Index) Covering(b raster.BBox) ([]Tile, error) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	if i.tiles == nil || time.Since(i.built) > i.ttl {
+		tiles, err := scan(i.dir, i.skip)
+		if err != nil {
+			return nil, err
+		}
+		log.Printf("Indexed %d DEM tiles under %s", len(tiles), i.dir)
+		i.tiles, i.built = tiles, time.Now()
+	}
+
+	var covering []Tile
+	for _, t := range i.tiles {
+		if t.Bounds.MinLon < b.MaxLon && t.Bounds.MaxLon > b.MinLon &&
+			t.Bounds.MinLat < b.MaxLat && t.Bounds.MaxLat > b.MinLat {
+			covering = append(covering, t)
+		}
+	}
+	return covering, nil
+}
+
+// scan reads the header of every GeoTIFF under dir. Files that cannot be
+// read or are in an unsupported coordinate system are logged and skipped.
+func scan(dir, skip string) ([]Tile, error) {
+	tiles := []Tile{}
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if path == skip {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		ext := strings.ToLower(filepath.Ext(path))
+		if ext != ".tif" && ext != ".tiff" {
+			return nil
+		}
+
+		tile, err := readTile(path)
+		if err != nil {
+			log.Printf("Skipping DEM tile %s: %v", path, err)
+			return nil
+		}
+		tiles = append(tiles, tile)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan DEM directory %s: %w", dir, err)
+	}
+
+	sort.Slice(tiles, func(a, b int) bool {
+		if tiles[a].ResolutionM != tiles[b].ResolutionM {
+			return tiles[a].ResolutionM < tiles[b].ResolutionM
+		}
+		return tiles[a].Path < tiles[b].Path
+	})
+	return tiles, nil
+}
+
+func readTile(path string) (Tile, error) {
+	f, err := raster.OpenFile(path)
+	if err != nil {
+		return Tile{}, err
+	}
+	defer f.Close()
+
+	if f.EPSG == 0 {
+		return Tile{}, fmt.Errorf("unsupported coordinate system")
+	}
+	bounds, err := wgs84Bounds(f.EPSG, f.Transform, f.Width, f.Height)
+	if err != nil {
+		return Tile{}, err
+	}
+
+	return Tile{
+		Path:        path,
+		Width:       f.Width,
+		Height:      f.Height,
+		EPSG:        f.EPSG,
+		Transform:   f.Transform,
+		NoData:      f.NoData,
+		Bounds:      bounds,
+		ResolutionM: resolutionM(f.EPSG, f.Transform, (bounds.MinLat+bounds.MaxLat)/2),
+	}, nil
+}
+
+// wgs84Bounds returns a grid's WGS 84 extent, sampling its edges so a
+// projected grid's curved outline is covered
+func wgs84Bounds(epsg int, g raster.GeoTransform, width, height int) (raster.BBox, error) {
+	const steps = 16
+	b := raster.BBox{MinLon: math.Inf(1), MinLat: math.Inf(1), MaxLon: math.Inf(-1), MaxLat: math.Inf(-1)}
+	for i := 0; i <= steps; i++ {
+		t := float64(i) / steps
+		col, row := t*float64(width), t*float64(height)
+		for _, p := range [4][2]float64{{col, 0}, {col, float64(height)}, {0, row}, {float64(width), row}} {
+			x, y := g.PixelToWorld(p[0], p[1])
+			lon, lat, err := raster.Unproject(epsg, x, y)
+			if err != nil {
+				return raster.BBox{}, err
+			}
+			b.MinLon, b.MaxLon = math.Min(b.MinLon, lon), math.Max(b.MaxLon, lon)
+			b.MinLat, b.MaxLat = math.Min(b.MinLat, lat), math.Max(b.MaxLat, lat)
+		}
+	}
+	return b, nil
+}
+
+// resolutionM is the geometric mean of a pixel's ground width and height
+func resolutionM(epsg int, g raster.GeoTransform, lat float64) float64 {
+	if _, _, utm := raster.UTMZone(epsg); utm {
+		return math.Sqrt(g.PixelWidth * g.PixelHeight)
+	}
+	const metresPerDegree = 111320.0
+	return math.Sqrt(g.PixelWidth*metresPerDegree*math.Cos(lat*math.Pi/180)) *
+		math.Sqrt(g.PixelHeight*metresPerDegree)
+}
@@ -0,0 +1,183 @@
+package dem
+
+import (
+	"errors"
+	"fmt"
+	"math"
+
+	"wildfire-risk-platform/shared/raster"
+)
+
+// defaultNoData marks missing elevations when the tiles declare no nodata
+// value; it is the value USGS 3DEP uses
+const defaultNoData = -999999.0
+
+// ErrTooLarge is returned when a mosaic would exceed the pixel limit
+var ErrTooLarge = errors.New("DEM for the area is too large")
+
+// Mosaic merges the tiles over a WGS 84 bbox into one Float32 DEM on the
+// grid of the first (finest) tile, resampling others bilinearly. Where tiles
+// overlap the earlier one wins. It returns the DEM and the tiles that
+// contributed to it.
+func Mosaic(tiles []Tile, b raster.BBox, maxPixels int) (*raster.Raster, []string, error) {
+	if len(tiles) == 0 {
+		return nil, nil, fmt.Errorf("no tiles to mosaic")
+	}
+	grid := tiles[0]
+
+	// Snap the bbox outwards to the first tile's pixel grid
+	minX, minY, maxX, maxY, err := projectBBox(grid.EPSG, b)
+	if err != nil {
+		return nil, nil, err
+	}
+	g := grid.Transform
+	col0 := math.Floor((minX - g.OriginX) / g.PixelWidth)
+	col1 := math.Ceil((maxX - g.OriginX) / g.PixelWidth)
+	row0 := math.Floor((g.OriginY - maxY) / g.PixelHeight)
+	row1 := math.Ceil((g.OriginY - minY) / g.PixelHeight)
+	width, height := int(col1-col0), int(row1-row0)
+	if width <= 0 || height <= 0 {
+		return nil, nil, fmt.Errorf("empty DEM grid for %v", b)
+	}
+	if maxPixels > 0 && width*height > maxPixels {
+		return nil, nil, fmt.Errorf("%w: %dx%d pixels exceeds %d", ErrTooLarge, width, height, maxPixels)
+	}
+
+	nodata := defaultNoData
+	if grid.NoData != nil && !math.IsNaN(*grid.NoData) {
+		nodata = *grid.NoData
+	}
+	out := raster.New(width, height, 1, raster.Float32)
+	out.NoData = &nodata
+	out.EPSG = grid.EPSG
+	out.Transform = g
+	out.Transform.OriginX, out.Transform.OriginY = g.PixelToWorld(col0, row0)
+	for i := range out.Bands[0] {
+		out.Bands[0][i] = nodata
+	}
+
+	remaining := width * height
+	var sources []string
+	for _, tile := range tiles {
+		if remaining == 0 {
+			break
+		}
+		filled, err := fill(out, tile, b)
+		if err != nil {
+			return nil, nil, err
+		}
+		if filled > 0 {
+			sources = append(sources, tile.Path)
+			remaining -= filled
+		}
+	}
+
+	return out, sources, nil
+}
+
+// fill copies a tile's elevations into the DEM's missing pixels and returns
+// how many it filled
+func fill(out *raster.Raster, tile Tile, b raster.BBox) (int, error) {
+	f, err := raster.OpenFile(tile.Path)
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+
+	// Read one pixel beyond the bbox so interpolation has neighbours at the edge
+	padLat := 2 * tile.ResolutionM / 111320
+	padLon := padLat / math.Max(math.Cos(math.Max(math.Abs(b.MinLat), math.Abs(b.MaxLat))*math.Pi/180), 0.01)
+	sub, err := f.ReadBBox(raster.BBox{
+		MinLon: b.MinLon - padLon, MinLat: b.MinLat - padLat,
+		MaxLon: b.MaxLon + padLon, MaxLat: b.MaxLat + padLat,
+	})
+	if errors.Is(err, raster.ErrNoOverlap) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to read %s: %w", tile.Path, err)
+	}
+
+	filled := 0
+	for y := 0; y < out.Height; y++ {
+		for x := 0; x < out.Width; x++ {
+			if !out.IsNoData(out.At(0, x, y)) {
+				continue
+			}
+			wx, wy := out.Transform.PixelToWorld(float64(x)+0.5, float64(y)+0.5)
+
+			var v float64
+			var ok bool
+			if sub.EPSG == out.EPSG {
+				v, ok = sub.ValueAt(0, wx, wy)
+			} else {
+				lon, lat, err := raster.Unproject(out.EPSG, wx, wy)
+				if err != nil {
+					return 0, err
+				}
+				v, ok = sub.Value(0, lon, lat)
+			}
+			if ok {
+				out.Set(0, x, y, v)
+				filled++
+			}
+		}
+	}
+	return filled, nil
+}
+
+// projectBBox returns a WGS 84 bbox's extent in a coordinate system
+func projectBBox(epsg int, b raster.BBox) (minX, minY, maxX, maxY float64, err error) {
+	const steps = 16
+	minX, minY = math.Inf(1), math.Inf(1)
+	maxX, maxY = math.Inf(-1), math.Inf(-1)
+	for i := 0; i <= steps; i++ {
+		t := float64(i) / steps
+		lon := b.MinLon + t*(b.MaxLon-b.MinLon)
+		lat := b.MinLat + t*(b.MaxLat-b.MinLat)
+		for _, p := range [4][2]float64{{lon, b.MinLat}, {lon, b.MaxLat}, {b.MinLon, lat}, {b.MaxLon, lat}} {
+			x, y, err := raster.Project(epsg, p[0], p[1])
+			if err != nil {
+				return 0, 0, 0, 0, err
+			}
+			minX, maxX = math.Min(minX, x), math.Max(maxX, x)
+			minY, maxY = math.Min(minY, y), math.Max(maxY, y)
+		}
+	}
+	return minX, minY, maxX, maxY, nil
+}
+
+// Stats summarises a DEM's elevations
+type Stats struct {
+	MinM        float64
+	MaxM        float64
+	ValidShare  float64 // Share of pixels that are not nodata
+	Bounds      raster.BBox
+	ResolutionM float64
+}
+
+// Describe computes a DEM's elevation range, coverage and WGS 84 extent
+func Describe(r *raster.Raster) (Stats, error) {
+	stats := Stats{MinM: math.Inf(1), MaxM: math.Inf(-1)}
+	valid := 0
+	for _, v := range r.Bands[0] {
+		if r.IsNoData(v) {
+			continue
+		}
+		valid++
+		stats.MinM = math.Min(stats.MinM, v)
+		stats.MaxM = math.Max(stats.MaxM, v)
+	}
+	if valid == 0 {
+		stats.MinM, stats.MaxM = 0, 0
+	}
+	stats.ValidShare = float64(valid) / float64(len(r.Bands[0]))
+
+	bounds, err := wgs84Bounds(r.EPSG, r.Transform, r.Width, r.Height)
+	if err != nil {
+		return Stats{}, err
+	}
+	stats.Bounds = bounds
+	stats.ResolutionM = resolutionM(r.EPSG, r.Transform, (bounds.MinLat+bounds.MaxLat)/2)
+	return stats, nil
+}
@@ -0,0 +1,167 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"os"
+	"path/filepath"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"topography/config"
+	"topography/dem"
+	pb "wildfire-risk-platform/api/proto/generated"
+	"wildfire-risk-platform/shared/geo"
+	"wildfire-risk-platform/shared/raster"
+	"wildfire-risk-platform/shared/utils"
+)
+
+const metresPerDegree = 111320.0
+
+// TopographyServer implements the TopographyService gRPC server
+type TopographyServer struct {
+	pb.UnimplementedTopographyServiceServer
+
+	config *config.Config
+	index  *dem.Index
+}
+
+func NewTopographyServer(cfg *config.Config) (*TopographyServer, error) {
+	if err := os.MkdirAll(cfg.DEMOutputDir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create DEM output directory: %w", err)
+	}
+
+	return &TopographyServer{
+		config: cfg,
+		index:  dem.NewIndex(cfg.DEMDir, cfg.DEMOutputDir, cfg.DEMIndexTTL),
+	}, nil
+}
+
+// GetDemForAOI mosaics the local DEM tiles covering the AOI, crops them to
+// the AOI plus a buffer and writes the result to the shared volume
+func (s *TopographyServer) GetDemForAOI(ctx context.Context, req *pb.GetDemRequest) (*pb.GetDemResponse, error) {
+
+	if req.AoiGeojson == "" {
+		return nil, status.Error(codes.InvalidArgument, "aoi_geojson is required")
+	}
+
+	aoi, err := geo.ParseAOI(req.AoiGeojson)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid AOI: %v", err)
+	}
+	// One DEM is one grid, which cannot wrap from 180 to -180. The
+	// orchestrator splits such AOIs at the antimeridian before asking.
+	regions := aoi.Regions()
+	if len(regions) > 1 {
+		return nil, status.Error(codes.InvalidArgument, "invalid AOI: it crosses the antimeridian; request a DEM for each side")
+	}
+	b := regions[0]
+
+	// Buffer in degrees, widened for longitude at the AOI's highest latitude
+	bufferLat := s.config.DEMBufferM / metresPerDegree
+	bufferLon := bufferLat / math.Max(math.Cos(math.Max(math.Abs(b.MinLat), math.Abs(b.MaxLat))*math.Pi/180), 0.01)
+	bbox := raster.BBox{
+		MinLon: math.Max(b.MinLon-bufferLon, -180),
+		MinLat: math.Max(b.MinLat-bufferLat, -90),
+		MaxLon: math.Min(b.MaxLon+bufferLon, 180),
+		MaxLat: math.Min(b.MaxLat+bufferLat, 90),
+	}
+
+	tiles, err := s.index.Covering(bbox)
+	if err != nil {
+		log.Printf("Failed to index DEM tiles: %v", err)
+		return nil, status.Error(codes.Internal, "failed to index DEM tiles")
+	}
+	if len(tiles) == 0 {
+		return nil, status.Errorf(codes.NotFound, "no DEM tiles under %s cover the AOI", s.config.DEMDir)
+	}
+
+	log.Printf("Mosaicking %d DEM tiles for AOI", len(tiles))
+
+	mosaic, sources, err := dem.Mosaic(tiles, bbox, s.config.DEMMaxPixels)
+	if errors.Is(err, dem.ErrTooLarge) {
+		return nil, status.Error(codes.ResourceExhausted, err.Error())
+	}
+	if err != nil {
+		log.Printf("Failed to mosaic DEM tiles: %v", err)
+		return nil, status.Error(codes.Internal, "failed to mosaic DEM tiles")
+	}
+	if ctx.Err() != nil {
+		return nil, status.FromContextError(ctx.Err()).Err()
+	}
+
+	stats, err := dem.Describe(mosaic)
+	if err != nil {
+		log.Printf("Failed to describe DEM: %v", err)
+		return nil, status.Error(codes.Internal, "failed to describe DEM")
+	}
+
+	path, err := s.writeDEM(req.AoiGeojson, mosaic)
+	if err != nil {
+		log.Printf("Failed to write DEM: %v", err)
+		return nil, status.Error(codes.Internal, "failed to write DEM")
+	}
+
+	log.Printf("Wrote %dx%d DEM from %d tiles to %s (%.0f%% valid, %.1f to %.1f m)",
+		mosaic.Width, mosaic.Height, len(sources), path, stats.ValidShare*100, stats.MinM, stats.MaxM)
+
+	minX, minY, maxX, maxY := mosaic.Bounds()
+	return &pb.GetDemResponse{
+		LocalDemPath: path,
+		Metadata: &pb.DemMetadata{
+			Width:         int32(mosaic.Width),
+			Height:        int32(mosaic.Height),
+			Crs:           fmt.Sprintf("EPSG:%d", mosaic.EPSG),
+			Epsg:          int32(mosaic.EPSG),
+			PixelWidth:    mosaic.Transform.PixelWidth,
+			PixelHeight:   mosaic.Transform.PixelHeight,
+			ResolutionM:   stats.ResolutionM,
+			Bounds:        bboxToProto(stats.Bounds),
+			NativeBounds:  &pb.Extent{MinX: minX, MinY: minY, MaxX: maxX, MaxY: maxY},
+			Nodata:        mosaic.NoData,
+			MinElevationM: stats.MinM,
+			MaxElevationM: stats.MaxM,
+			ValidShare:    stats.ValidShare,
+			SourceTiles:   sources,
+		},
+	}, nil
+}
+
+// writeDEM saves a DEM as a COG named after the AOI and buffer, so repeat
+// requests replace the same file. It is written under a temporary name and
+// renamed so readers never see a partial file.
+func (s *TopographyServer) writeDEM(aoiGeoJSON string, mosaic *raster.Raster) (string, error) {
+	hash, err := utils.AOIHash(aoiGeoJSON, fmt.Sprintf("dem buffer=%g", s.config.DEMBufferM))
+	if err != nil {
+		return "", err
+	}
+	path := filepath.Join(s.config.DEMOutputDir, fmt.Sprintf("dem_%s.tif", hash[:16]))
+
+	tmp, err := os.CreateTemp(s.config.DEMOutputDir, ".dem-*.tif")
+	if err != nil {
+		return "", err
+	}
+	tmp.Close()
+	defer os.Remove(tmp.Name())
+
+	if err := raster.WriteFile(tmp.Name(), mosaic, raster.COGOptions(mosaic.DataType)); err != nil {
+		return "", err
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return "", err
+	}
+	return path, nil
+}
+
+func bboxToProto(b raster.BBox) *pb.BoundingBox {
+	return &pb.BoundingBox{
+		MinLon: b.MinLon,
+		MinLat: b.MinLat,
+		MaxLon: b.MaxLon,
+		MaxLat: b.MaxLat,
+	}
+}
@@ -0,0 +1,99 @@
+package server
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"topography/config"
+	pb "wildfire-risk-platform/api/proto/generated"
+	"wildfire-risk-platform/shared/raster"
+)
+
+// newTestServer serves one 1 degree tile at 0.01 degree resolution over
+// -123..-122, 37..38, with elevation rising to the east
+func newTestServer(t *testing.T) *TopographyServer {
+	t.Helper()
+	dir := t.TempDir()
+
+	tile := raster.New(100, 100, 1, raster.Float32)
+	tile.EPSG = 4326
+	tile.Transform = raster.GeoTransform{OriginX: -123, OriginY: 38, PixelWidth: 0.01, PixelHeight: 0.01}
+	for y := 0; y < tile.Height; y++ {
+		for x := 0; x < tile.Width; x++ {
+			tile.Set(0, x, y, float64(x))
+		}
+	}
+	if err := raster.WriteFile(filepath.Join(dir, "tile.tif"), tile, raster.COGOptions(tile.DataType)); err != nil {
+		t.Fatal(err)
+	}
+
+	s, err := NewTopographyServer(&config.Config{
+		DEMDir:       dir,
+		DEMIndexTTL:  time.Minute,
+		DEMOutputDir: filepath.Join(dir, "out"),
+		DEMBufferM:   500,
+		DEMMaxPixels: 16000000,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+func TestGetDemForAOI(t *testing.T) {
+	s := newTestServer(t)
+
+	tests := []struct {
+		name string
+		aoi  string
+		code codes.Code
+	}{
+		{
+			name: "Polygon",
+			aoi:  `{"type":"Polygon","coordinates":[[[-122.6,37.4],[-122.4,37.4],[-122.4,37.6],[-122.6,37.6],[-122.6,37.4]]]}`,
+		},
+		{
+			name: "MultiPolygon",
+			aoi: `{"type":"MultiPolygon","coordinates":[
+				[[[-122.8,37.2],[-122.7,37.2],[-122.7,37.3],[-122.8,37.3],[-122.8,37.2]]],
+				[[[-122.3,37.7],[-122.2,37.7],[-122.2,37.8],[-122.3,37.8],[-122.3,37.7]]]]}`,
+		},
+		{
+			name: "crossing the antimeridian",
+			aoi:  `{"type":"Polygon","coordinates":[[[179.5,37.4],[-179.5,37.4],[-179.5,37.6],[179.5,37.6],[179.5,37.4]]]}`,
+			code: codes.InvalidArgument,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := s.GetDemForAOI(context.Background(), &pb.GetDemRequest{AoiGeojson: tt.aoi})
+			if status.Code(err) != tt.code {
+				t.Fatalf("got %v, want code %v", err, tt.code)
+			}
+			if tt.code != codes.OK {
+				return
+			}
+
+			if _, err := os.Stat(resp.LocalDemPath); err != nil {
+				t.Fatalf("DEM was not written: %v", err)
+			}
+			dem, err := raster.ReadFile(resp.LocalDemPath)
+			if err != nil {
+				t.Fatalf("failed to read DEM: %v", err)
+			}
+			if dem.Width != int(resp.Metadata.Width) || dem.Height != int(resp.Metadata.Height) {
+				t.Errorf("DEM is %dx%d, metadata says %dx%d", dem.Width, dem.Height, resp.Metadata.Width, resp.Metadata.Height)
+			}
+			if resp.Metadata.ValidShare != 1 {
+				t.Errorf("valid share %v, want 1", resp.Metadata.ValidShare)
+			}
+		})
+	}
+}
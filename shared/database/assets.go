@@ -0,0 +1,425 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+
+	"wildfire-risk-platform/shared/database/models"
+	"wildfire-risk-platform/shared/risk"
+)
+
+const canonicalAssetColumns = `canonical_asset_id, identity_key, asset_type, ST_AsGeoJSON(asset_geometry), geometry_hash, tags, version, first_seen_job_id, last_seen_job_id, first_seen_at, last_seen_at`
+
+// RegisterJobAssets inserts a job's assets with their provenance, linking each to its registry
+// entry. Entries are created on first sight; later sightings with different
+// geometry or tags update the entry and record the change. Assets without an
+// OSM identity are keyed by geometry, so only their tag changes are tracked.
+//
+// Registry entries are locked in identity order, so split child jobs sharing
+// assets along their cell edges cannot deadlock. A job's rows are keyed by
+// identity too: running a job again updates them instead of adding
+// duplicates, and an asset fetched twice in one job is stored once. The
+// stored assets are returned with AssetID and CanonicalAssetID set, in
+// identity order.
+func (db *DB) RegisterJobAssets(ctx context.Context, jobID uuid.UUID, assets []models.InfrastructureAsset) ([]models.InfrastructureAsset, error) {
+	keyed := make([]keyedAsset, 0, len(assets))
+	for i, asset := range assets {
+		k, err := newKeyedAsset(asset)
+		if err != nil {
+			return nil, fmt.Errorf("failed to key asset %d: %w", i, err)
+		}
+		keyed = append(keyed, k)
+	}
+	sort.SliceStable(keyed, func(i, j int) bool { return keyed[i].key < keyed[j].key })
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	registered := make([]models.InfrastructureAsset, 0, len(keyed))
+	for i, k := range keyed {
+		if i > 0 && k.key == keyed[i-1].key {
+			continue
+		}
+		asset := k.asset
+		asset.JobID = jobID
+		canonicalID, err := registerAsset(ctx, tx, jobID, k)
+		if err != nil {
+			return nil, fmt.Errorf("failed to register asset %s: %w", k.key, err)
+		}
+		asset.CanonicalAssetID = &canonicalID
+
+		properties, err := json.Marshal(asset.Properties)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode properties of asset %s: %w", k.key, err)
+		}
+		p := asset.Provenance
+		if err := tx.QueryRowContext(ctx, `
+			INSERT INTO infrastructure_assets (
+				job_id, asset_key, asset_type, asset_geometry, properties, canonical_asset_id,
+				osm_version, osm_timestamp, osm_changeset, osm_user, data_source, data_timestamp
+			)
+			VALUES (
+				$1, $2, $3, ST_SetSRID(ST_GeomFromGeoJSON($4), 4326), $5, $6,
+				NULLIF($7, 0), $8, NULLIF($9, 0), NULLIF($10, ''), NULLIF($11, ''), $12
+			)
+			ON CONFLICT (job_id, asset_key) DO UPDATE SET
+				asset_type = EXCLUDED.asset_type,
+				asset_geometry = EXCLUDED.asset_geometry,
+				properties = EXCLUDED.properties,
+				canonical_asset_id = EXCLUDED.canonical_asset_id,
+				osm_version = EXCLUDED.osm_version,
+				osm_timestamp = EXCLUDED.osm_timestamp,
+				osm_changeset = EXCLUDED.osm_changeset,
+				osm_user = EXCLUDED.osm_user,
+				data_source = EXCLUDED.data_source,
+				data_timestamp = EXCLUDED.data_timestamp
+			RETURNING asset_id`,
+			jobID, k.key, asset.AssetType, asset.AssetGeometry, properties, canonicalID,
+			p.OSMVersion, p.OSMTimestamp, p.OSMChangeset, p.OSMUser, p.DataSource, p.DataTimestamp,
+		).Scan(&asset.AssetID); err != nil {
+			return nil, fmt.Errorf("failed to insert asset %s: %w", k.key, err)
+		}
+
+		registered = append(registered, asset)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit job assets: %w", err)
+	}
+
+	return registered, nil
+}
+
+// keyedAsset is an asset with its registry identity, tags and geometry hash
+type keyedAsset struct {
+	asset        models.InfrastructureAsset
+	key          string
+	tags         map[string]interface{}
+	tagsJSON     []byte
+	geometryHash string
+}
+
+func newKeyedAsset(asset models.InfrastructureAsset) (keyedAsset, error) {
+	tags, err := asset.Tags()
+	if err != nil {
+		return keyedAsset{}, err
+	}
+	tagsJSON, err := json.Marshal(tags)
+	if err != nil {
+		return keyedAsset{}, fmt.Errorf("failed to encode tags: %w", err)
+	}
+	geometryHash, err := asset.GeometryHash()
+	if err != nil {
+		return keyedAsset{}, err
+	}
+
+	key, ok := asset.OSMKey()
+	if !ok {
+		key = "geom/" + geometryHash
+	}
+	return keyedAsset{asset: asset, key: key, tags: tags, tagsJSON: tagsJSON, geometryHash: geometryHash}, nil
+}
+
+// registerAsset creates or updates the asset's registry entry and returns
+// its ID
+func registerAsset(ctx context.Context, tx *sql.Tx, jobID uuid.UUID, k keyedAsset) (int64, error) {
+	// Insert first so concurrent jobs seeing a new asset cannot both create it
+	var canonicalID int64
+	err := tx.QueryRowContext(ctx, `
+		INSERT INTO assets (identity_key, asset_type, asset_geometry, geometry_hash, tags, first_seen_job_id, last_seen_job_id)
+		VALUES ($1, $2, ST_SetSRID(ST_GeomFromGeoJSON($3), 4326), $4, $5, $6, $6)
+		ON CONFLICT (identity_key) DO NOTHING
+		RETURNING canonical_asset_id`,
+		k.key, k.asset.AssetType, k.asset.AssetGeometry, k.geometryHash, k.tagsJSON, jobID,
+	).Scan(&canonicalID)
+	if err == nil {
+		if _, err := tx.ExecContext(ctx, `
+			INSERT INTO asset_changes (canonical_asset_id, job_id, change_type, version)
+			VALUES ($1, $2, $3, 1)`,
+			canonicalID, jobID, models.AssetChangeCreated,
+		); err != nil {
+			return 0, fmt.Errorf("failed to record new asset %s: %w", k.key, err)
+		}
+		return canonicalID, nil
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		return 0, fmt.Errorf("failed to create registry entry %s: %w", k.key, err)
+	}
+
+	var previousHash string
+	var previousTagsJSON []byte
+	var version int
+	if err := tx.QueryRowContext(ctx, `
+		SELECT canonical_asset_id, geometry_hash, tags, version
+		FROM assets
+		WHERE identity_key = $1
+		FOR UPDATE`,
+		k.key,
+	).Scan(&canonicalID, &previousHash, &previousTagsJSON, &version); err != nil {
+		return 0, fmt.Errorf("failed to read registry entry %s: %w", k.key, err)
+	}
+
+	previousTags := make(map[string]interface{})
+	if err := json.Unmarshal(previousTagsJSON, &previousTags); err != nil {
+		return 0, fmt.Errorf("failed to decode tags of %s: %w", k.key, err)
+	}
+
+	type change struct {
+		kind models.AssetChangeType
+		keys []string
+	}
+	var changes []change
+	// An empty hash predates hashing in Go; adopt the new one without
+	// calling it a change
+	if previousHash != "" && k.geometryHash != previousHash {
+		changes = append(changes, change{models.AssetChangeGeometry, []string{}})
+	}
+	if !reflect.DeepEqual(k.tags, previousTags) {
+		changes = append(changes, change{models.AssetChangeTags, models.ChangedTagKeys(previousTags, k.tags)})
+	}
+	if len(changes) > 0 {
+		version++
+	}
+
+	// Changes copy the previous values before the entry is overwritten
+	for _, c := range changes {
+		if _, err := tx.ExecContext(ctx, `
+			INSERT INTO asset_changes (canonical_asset_id, job_id, change_type, version, previous_geometry, previous_tags, changed_tags)
+			SELECT canonical_asset_id, $2, $3, $4, asset_geometry, tags, $5
+			FROM assets
+			WHERE canonical_asset_id = $1`,
+			canonicalID, jobID, c.kind, version, pq.Array(c.keys),
+		); err != nil {
+			return 0, fmt.Errorf("failed to record %s change of %s: %w", c.kind, k.key, err)
+		}
+	}
+
+	if _, err := tx.ExecContext(ctx, `
+		UPDATE assets
+		SET asset_type = $2,
+		    asset_geometry = ST_SetSRID(ST_GeomFromGeoJSON($3), 4326),
+		    geometry_hash = $4,
+		    tags = $5,
+		    version = $6,
+		    last_seen_job_id = $7,
+		    last_seen_at = NOW()
+		WHERE canonical_asset_id = $1`,
+		canonicalID, k.asset.AssetType, k.asset.AssetGeometry, k.geometryHash, k.tagsJSON, version, jobID,
+	); err != nil {
+		return 0, fmt.Errorf("failed to update registry entry %s: %w", k.key, err)
+	}
+
+	return canonicalID, nil
+}
+
+// GetCanonicalAsset returns a registry entry by ID, or nil if it does not exist
+func (db *DB) GetCanonicalAsset(ctx context.Context, canonicalID int64) (*models.CanonicalAsset, error) {
+	row := db.QueryRowContext(ctx, `SELECT `+canonicalAssetColumns+` FROM assets WHERE canonical_asset_id = $1`, canonicalID)
+
+	var asset models.CanonicalAsset
+	var tags []byte
+	var firstSeen, lastSeen uuid.NullUUID
+	err := row.Scan(
+		&asset.CanonicalAssetID,
+		&asset.IdentityKey,
+		&asset.AssetType,
+		&asset.AssetGeometry,
+		&asset.GeometryHash,
+		&tags,
+		&asset.Version,
+		&firstSeen,
+		&lastSeen,
+		&asset.FirstSeenAt,
+		&asset.LastSeenAt,
+	)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get asset %d: %w", canonicalID, err)
+	}
+
+	if err := json.Unmarshal(tags, &asset.Tags); err != nil {
+		return nil, fmt.Errorf("failed to decode tags of asset %d: %w", canonicalID, err)
+	}
+	if firstSeen.Valid {
+		asset.FirstSeenJobID = &firstSeen.UUID
+	}
+	if lastSeen.Valid {
+		asset.LastSeenJobID = &lastSeen.UUID
+	}
+
+	return &asset, nil
+}
+
+// ListAssetChanges returns a registry entry's changes, oldest first
+func (db *DB) ListAssetChanges(ctx context.Context, canonicalID int64) ([]*models.AssetChange, error) {
+	rows, err := db.QueryContext(ctx, `
+		SELECT change_id, canonical_asset_id, job_id, change_type, version,
+		       ST_AsGeoJSON(previous_geometry), previous_tags, changed_tags, changed_at
+		FROM asset_changes
+		WHERE canonical_asset_id = $1
+		ORDER BY changed_at, change_id`,
+		canonicalID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list changes of asset %d: %w", canonicalID, err)
+	}
+	defer rows.Close()
+
+	var changes []*models.AssetChange
+	for rows.Next() {
+		var c models.AssetChange
+		var jobID uuid.NullUUID
+		var geometry sql.NullString
+		var tags []byte
+		if err := rows.Scan(
+			&c.ChangeID,
+			&c.CanonicalAssetID,
+			&jobID,
+			&c.ChangeType,
+			&c.Version,
+			&geometry,
+			&tags,
+			pq.Array(&c.ChangedTags),
+			&c.ChangedAt,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan asset change: %w", err)
+		}
+
+		if jobID.Valid {
+			c.JobID = &jobID.UUID
+		}
+		c.PreviousGeometry = geometry.String
+		if tags != nil {
+			if err := json.Unmarshal(tags, &c.PreviousTags); err != nil {
+				return nil, fmt.Errorf("failed to decode previous tags: %w", err)
+			}
+		}
+		changes = append(changes, &c)
+	}
+
+	return changes, rows.Err()
+}
+
+// ListAssetRiskHistory returns every score a registry entry received, oldest
+// first, attributed to the job the client submitted
+func (db *DB) ListAssetRiskHistory(ctx context.Context, canonicalID int64) ([]*models.AssetRiskPoint, error) {
+	rows, err := db.QueryContext(ctx, `
+		SELECT COALESCE(j.parent_job_id, j.job_id), a.asset_id, r.overall_risk_score,
+		       r.model_name, r.model_version, r.analysis_timestamp
+		FROM infrastructure_assets a
+		JOIN risk_assessment_jobs j ON j.job_id = a.job_id
+		JOIN asset_risk_analysis r ON r.asset_id = a.asset_id
+		WHERE a.canonical_asset_id = $1
+		ORDER BY r.analysis_timestamp`,
+		canonicalID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list risk history of asset %d: %w", canonicalID, err)
+	}
+	defer rows.Close()
+
+	var history []*models.AssetRiskPoint
+	for rows.Next() {
+		var p models.AssetRiskPoint
+		if err := rows.Scan(
+			&p.JobID,
+			&p.AssetID,
+			&p.OverallRiskScore,
+			&p.ModelName,
+			&p.ModelVersion,
+			&p.AnalysisTimestamp,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan risk history: %w", err)
+		}
+		history = append(history, &p)
+	}
+
+	return history, rows.Err()
+}
+
+// ScoreJobAssets scores the job's analysis rows through model, from the
+// normalised components the processing service stored, and records the model
+// on every row. It returns the number of rows scored.
+func (db *DB) ScoreJobAssets(ctx context.Context, job *models.RiskAssessmentJob, model risk.RiskModel) (int, error) {
+	analyses, err := func() ([]models.AssetRiskAnalysis, error) {
+		rows, err := db.QueryContext(ctx, `
+			SELECT r.analysis_id, a.asset_id, a.asset_type,
+			       r.risk_from_vegetation, r.risk_from_slope, r.risk_from_wind
+			FROM infrastructure_assets a
+			JOIN asset_risk_analysis r ON r.asset_id = a.asset_id
+			WHERE a.job_id = $1
+			ORDER BY r.analysis_id`,
+			job.JobID,
+		)
+		if err != nil {
+			return nil, err
+		}
+		defer rows.Close()
+
+		var analyses []models.AssetRiskAnalysis
+		for rows.Next() {
+			var analysisID, assetID int64
+			var in risk.Inputs
+			if err := rows.Scan(&analysisID, &assetID, &in.AssetType, &in.Vegetation, &in.Slope, &in.Wind); err != nil {
+				return nil, err
+			}
+
+			score, err := model.Score(in, job.RiskParameters)
+			if err != nil {
+				return nil, fmt.Errorf("failed to score asset %d: %w", assetID, err)
+			}
+			analysis := models.NewAssetRiskAnalysis(assetID, model, score)
+			analysis.AnalysisID = analysisID
+			analyses = append(analyses, analysis)
+		}
+		return analyses, rows.Err()
+	}()
+	if err != nil {
+		return 0, fmt.Errorf("failed to score assets of job %s: %w", job.JobID, err)
+	}
+
+	ids := make([]int64, len(analyses))
+	vegetation := make([]float64, len(analyses))
+	slope := make([]float64, len(analyses))
+	wind := make([]float64, len(analyses))
+	overall := make([]float64, len(analyses))
+	for i, a := range analyses {
+		ids[i] = a.AnalysisID
+		vegetation[i] = a.RiskFromVegetation
+		slope[i] = a.RiskFromSlope
+		wind[i] = a.RiskFromWind
+		overall[i] = a.OverallRiskScore
+	}
+
+	if _, err := db.ExecContext(ctx, `
+		UPDATE asset_risk_analysis r
+		SET risk_from_vegetation = u.vegetation,
+		    risk_from_slope = u.slope,
+		    risk_from_wind = u.wind,
+		    overall_risk_score = u.overall,
+		    model_name = $6,
+		    model_version = $7
+		FROM unnest($1::bigint[], $2::float8[], $3::float8[], $4::float8[], $5::float8[])
+		     AS u(analysis_id, vegetation, slope, wind, overall)
+		WHERE r.analysis_id = u.analysis_id`,
+		pq.Array(ids), pq.Array(vegetation), pq.Array(slope), pq.Array(wind), pq.Array(overall),
+		model.Name(), model.Version(),
+	); err != nil {
+		return 0, fmt.Errorf("failed to store scores of job %s: %w", job.JobID, err)
+	}
+
+	return len(analyses), nil
+}
@@ -0,0 +1,393 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+
+	"wildfire-risk-platform/shared/database/models"
+)
+
+const jobColumns = `job_id, ST_AsGeoJSON(aoi_polygon), aoi_hash, job_status, reused_from_job_id, parent_job_id, risk_parameters, model_name, model_version, priority, submitter, data_sources, created_at, updated_at`
+
+// rowScanner is satisfied by both *sql.Row and *sql.Rows
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// CreateJob inserts a new PENDING job. AOIPolygon is the GeoJSON polygon;
+// the risk parameters, model, priority and submitter are stored as given.
+func (db *DB) CreateJob(ctx context.Context, job models.RiskAssessmentJob) (*models.RiskAssessmentJob, error) {
+	if job.Priority == "" {
+		job.Priority = models.JobPriorityNormal
+	}
+
+	row := db.QueryRowContext(ctx, `
+		INSERT INTO risk_assessment_jobs (aoi_polygon, aoi_hash, job_status, risk_parameters, model_name, model_version, priority, submitter)
+		VALUES (ST_SetSRID(ST_GeomFromGeoJSON($1), 4326), $2, $3, $4, $5, $6, $7, $8)
+		RETURNING `+jobColumns,
+		job.AOIPolygon, job.AOIHash, models.JobStatusPending, job.RiskParameters, job.ModelName, job.ModelVersion, job.Priority, job.Submitter,
+	)
+
+	created, err := scanJob(row)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create job: %w", err)
+	}
+
+	return created, nil
+}
+
+// FindReusableJob returns the most recently completed job with the given AOI
+// hash and model version that ran itself and finished inside the freshness
+// window, or nil if there is none. Reused jobs are skipped, so resubmitting an
+// AOI never extends the life of results computed before the window.
+func (db *DB) FindReusableJob(ctx context.Context, aoiHash, modelVersion string, window time.Duration) (*models.RiskAssessmentJob, error) {
+	row := db.QueryRowContext(ctx, `
+		SELECT `+jobColumns+`
+		FROM risk_assessment_jobs
+		WHERE aoi_hash = $1
+		  AND job_status = $2
+		  AND reused_from_job_id IS NULL
+		  AND model_version = $3
+		  AND completed_at >= NOW() - make_interval(secs => $4)
+		ORDER BY completed_at DESC
+		LIMIT 1`,
+		aoiHash, models.JobStatusComplete, modelVersion, window.Seconds(),
+	)
+
+	job, err := scanJob(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to look up reusable job: %w", err)
+	}
+
+	return job, nil
+}
+
+// CreateReusedJob inserts a new COMPLETE job that points at the results of
+// source instead of running the assessment again. The source's parameters and
+// model are copied since the hash and version check guarantee they match.
+func (db *DB) CreateReusedJob(ctx context.Context, job models.RiskAssessmentJob, source *models.RiskAssessmentJob) (*models.RiskAssessmentJob, error) {
+	if job.Priority == "" {
+		job.Priority = models.JobPriorityNormal
+	}
+
+	row := db.QueryRowContext(ctx, `
+		INSERT INTO risk_assessment_jobs (aoi_polygon, aoi_hash, job_status, reused_from_job_id, risk_parameters, model_name, model_version, priority, submitter)
+		VALUES (ST_SetSRID(ST_GeomFromGeoJSON($1), 4326), $2, $3, $4, $5, $6, $7, $8, $9)
+		RETURNING `+jobColumns,
+		job.AOIPolygon, job.AOIHash, models.JobStatusComplete, source.ResultsJobID(), source.RiskParameters, source.ModelName, source.ModelVersion, job.Priority, job.Submitter,
+	)
+
+	created, err := scanJob(row)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create reused job: %w", err)
+	}
+
+	return created, nil
+}
+
+// GetJob returns a job by ID, or nil if it does not exist
+func (db *DB) GetJob(ctx context.Context, jobID uuid.UUID) (*models.RiskAssessmentJob, error) {
+	row := db.QueryRowContext(ctx, `SELECT `+jobColumns+` FROM risk_assessment_jobs WHERE job_id = $1`, jobID)
+
+	job, err := scanJob(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get job %s: %w", jobID, err)
+	}
+
+	return job, nil
+}
+
+// ListJobsByStatus returns runnable jobs in any of the given statuses, oldest
+// first. Parents that were split into child jobs never run themselves and are
+// left out. The orchestrator uses it to rebuild its queue after a restart.
+func (db *DB) ListJobsByStatus(ctx context.Context, statuses ...models.JobStatus) ([]*models.RiskAssessmentJob, error) {
+	names := make([]string, len(statuses))
+	for i, s := range statuses {
+		names[i] = string(s)
+	}
+
+	rows, err := db.QueryContext(ctx, `
+		SELECT `+jobColumns+`
+		FROM risk_assessment_jobs
+		WHERE job_status = ANY($1)
+		  AND NOT EXISTS (
+			SELECT 1 FROM risk_assessment_jobs c WHERE c.parent_job_id = risk_assessment_jobs.job_id
+		  )
+		ORDER BY created_at`,
+		pq.Array(names),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list jobs: %w", err)
+	}
+	defer rows.Close()
+
+	var jobs []*models.RiskAssessmentJob
+	for rows.Next() {
+		job, err := scanJob(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan job: %w", err)
+		}
+		jobs = append(jobs, job)
+	}
+
+	return jobs, rows.Err()
+}
+
+// UpdateJobStatus moves a job to a new status
+func (db *DB) UpdateJobStatus(ctx context.Context, jobID uuid.UUID, status models.JobStatus) error {
+	res, err := db.ExecContext(ctx, `UPDATE risk_assessment_jobs SET job_status = $1 WHERE job_id = $2`, status, jobID)
+	if err != nil {
+		return fmt.Errorf("failed to update job %s: %w", jobID, err)
+	}
+
+	if n, _ := res.RowsAffected(); n == 0 {
+		return fmt.Errorf("job %s not found", jobID)
+	}
+
+	return nil
+}
+
+// CreateSplitJob inserts a parent job for an AOI too large to run at once,
+// together with one PENDING child per clipped AOI, in a single transaction so
+// a restart never finds the parent without its children. Children inherit
+// the parent's parameters, model, priority and submitter and have no AOI hash,
+// since only the parent's results are ever reused.
+func (db *DB) CreateSplitJob(ctx context.Context, job models.RiskAssessmentJob, childAOIs []string) (*models.RiskAssessmentJob, []*models.RiskAssessmentJob, error) {
+	if job.Priority == "" {
+		job.Priority = models.JobPriorityNormal
+	}
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	row := tx.QueryRowContext(ctx, `
+		INSERT INTO risk_assessment_jobs (aoi_polygon, aoi_hash, job_status, risk_parameters, model_name, model_version, priority, submitter)
+		VALUES (ST_SetSRID(ST_GeomFromGeoJSON($1), 4326), $2, $3, $4, $5, $6, $7, $8)
+		RETURNING `+jobColumns,
+		job.AOIPolygon, job.AOIHash, models.JobStatusPending, job.RiskParameters, job.ModelName, job.ModelVersion, job.Priority, job.Submitter,
+	)
+	parent, err := scanJob(row)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create parent job: %w", err)
+	}
+
+	children := make([]*models.RiskAssessmentJob, 0, len(childAOIs))
+	for i, aoi := range childAOIs {
+		row := tx.QueryRowContext(ctx, `
+			INSERT INTO risk_assessment_jobs (aoi_polygon, job_status, parent_job_id, risk_parameters, model_name, model_version, priority, submitter)
+			VALUES (ST_SetSRID(ST_GeomFromGeoJSON($1), 4326), $2, $3, $4, $5, $6, $7, $8)
+			RETURNING `+jobColumns,
+			aoi, models.JobStatusPending, parent.JobID, parent.RiskParameters, parent.ModelName, parent.ModelVersion, parent.Priority, parent.Submitter,
+		)
+
+		child, err := scanJob(row)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to create child job %d: %w", i, err)
+		}
+		children = append(children, child)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, nil, fmt.Errorf("failed to commit split job: %w", err)
+	}
+
+	return parent, children, nil
+}
+
+// ListChildJobs returns the child jobs of a split parent
+func (db *DB) ListChildJobs(ctx context.Context, parentID uuid.UUID) ([]*models.RiskAssessmentJob, error) {
+	rows, err := db.QueryContext(ctx, `
+		SELECT `+jobColumns+`
+		FROM risk_assessment_jobs
+		WHERE parent_job_id = $1
+		ORDER BY created_at`,
+		parentID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list child jobs: %w", err)
+	}
+	defer rows.Close()
+
+	var children []*models.RiskAssessmentJob
+	for rows.Next() {
+		child, err := scanJob(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan child job: %w", err)
+		}
+		children = append(children, child)
+	}
+
+	return children, rows.Err()
+}
+
+// RefreshParentStatus recomputes a split parent's status from its children
+// and stores it. Call it whenever a child changes status.
+func (db *DB) RefreshParentStatus(ctx context.Context, parentID uuid.UUID) (models.JobStatus, error) {
+	children, err := db.ListChildJobs(ctx, parentID)
+	if err != nil {
+		return "", err
+	}
+
+	statuses := make([]models.JobStatus, len(children))
+	for i, child := range children {
+		statuses[i] = child.JobStatus
+	}
+	status := models.AggregateChildStatus(statuses)
+
+	if _, err := db.ExecContext(ctx, `
+		UPDATE risk_assessment_jobs SET job_status = $1
+		WHERE job_id = $2 AND job_status <> $1`,
+		status, parentID,
+	); err != nil {
+		return "", fmt.Errorf("failed to update parent job %s: %w", parentID, err)
+	}
+
+	return status, nil
+}
+
+// SummariseJobResults counts the job's scored assets by risk class using the
+// job's own thresholds. Reused and split jobs are resolved through the
+// job_asset_risk view, so the counts always describe what the client sees.
+func (db *DB) SummariseJobResults(ctx context.Context, job *models.RiskAssessmentJob) (*models.JobResultSummary, error) {
+	t := job.RiskParameters.Thresholds
+
+	var summary models.JobResultSummary
+	err := db.QueryRowContext(ctx, `
+		SELECT
+			COUNT(*),
+			COUNT(*) FILTER (WHERE overall_risk_score >= $2),
+			COUNT(*) FILTER (WHERE overall_risk_score >= $3 AND overall_risk_score < $2),
+			COUNT(*) FILTER (WHERE overall_risk_score >= $4 AND overall_risk_score < $3)
+		FROM job_asset_risk
+		WHERE job_id = $1`,
+		job.ResultsJobID(), t.High, t.Medium, t.Low,
+	).Scan(
+		&summary.TotalAssets,
+		&summary.HighRiskAssets,
+		&summary.MediumRiskAssets,
+		&summary.LowRiskAssets,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to summarise results for job %s: %w", job.JobID, err)
+	}
+
+	return &summary, nil
+}
+
+// AddJobDataSources records sources a job's data came from, keeping those
+// already recorded
+func (db *DB) AddJobDataSources(ctx context.Context, jobID uuid.UUID, sources []models.DataSource) error {
+	job, err := db.GetJob(ctx, jobID)
+	if err != nil {
+		return err
+	}
+	if job == nil {
+		return fmt.Errorf("job %s not found", jobID)
+	}
+
+	merged := models.NewAttribution(append(job.DataSources, sources...)).Sources
+	data, err := json.Marshal(merged)
+	if err != nil {
+		return fmt.Errorf("failed to encode data sources: %w", err)
+	}
+
+	if _, err := db.ExecContext(ctx, `UPDATE risk_assessment_jobs SET data_sources = $1 WHERE job_id = $2`, data, jobID); err != nil {
+		return fmt.Errorf("failed to update data sources of job %s: %w", jobID, err)
+	}
+
+	return nil
+}
+
+// JobAttribution builds the attribution block for a job's results. Reused
+// jobs credit the job that ran and split jobs the sources of every child.
+func (db *DB) JobAttribution(ctx context.Context, job *models.RiskAssessmentJob) (models.Attribution, error) {
+	var sources []models.DataSource
+	err := func() error {
+		rows, err := db.QueryContext(ctx, `
+			SELECT data_sources
+			FROM risk_assessment_jobs
+			WHERE (job_id = $1 OR parent_job_id = $1) AND data_sources IS NOT NULL`,
+			job.ResultsJobID(),
+		)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			var data []byte
+			if err := rows.Scan(&data); err != nil {
+				return err
+			}
+			var list []models.DataSource
+			if err := json.Unmarshal(data, &list); err != nil {
+				return err
+			}
+			sources = append(sources, list...)
+		}
+		return rows.Err()
+	}()
+	if err != nil {
+		return models.Attribution{}, fmt.Errorf("failed to read data sources of job %s: %w", job.JobID, err)
+	}
+
+	return models.NewAttribution(sources), nil
+}
+
+func scanJob(row rowScanner) (*models.RiskAssessmentJob, error) {
+	var job models.RiskAssessmentJob
+	var aoiHash sql.NullString
+	var reusedFrom uuid.NullUUID
+	var parent uuid.NullUUID
+	var sources []byte
+
+	if err := row.Scan(
+		&job.JobID,
+		&job.AOIPolygon,
+		&aoiHash,
+		&job.JobStatus,
+		&reusedFrom,
+		&parent,
+		&job.RiskParameters,
+		&job.ModelName,
+		&job.ModelVersion,
+		&job.Priority,
+		&job.Submitter,
+		&sources,
+		&job.CreatedAt,
+		&job.UpdatedAt,
+	); err != nil {
+		return nil, err
+	}
+
+	job.AOIHash = aoiHash.String
+	if reusedFrom.Valid {
+		job.ReusedFromJobID = &reusedFrom.UUID
+	}
+	if parent.Valid {
+		job.ParentJobID = &parent.UUID
+	}
+	if sources != nil {
+		if err := json.Unmarshal(sources, &job.DataSources); err != nil {
+			return nil, fmt.Errorf("failed to decode data sources: %w", err)
+		}
+	}
+
+	return &job, nil
+}
@@ -0,0 +1,9 @@
+-- Canonical hash of the normalised AOI plus model parameters, used to find
+-- earlier jobs whose results can be reused for a resubmitted AOI
+ALTER TABLE risk_assessment_jobs ADD COLUMN aoi_hash VARCHAR(64);
+
+-- Set when a job did not run itself but points at the results of another job
+ALTER TABLE risk_assessment_jobs ADD COLUMN reused_from_job_id UUID REFERENCES risk_assessment_jobs(job_id) ON DELETE SET NULL;
+
+-- Lookup of the freshest COMPLETE job for a hash
+CREATE INDEX idx_jobs_aoi_hash ON risk_assessment_jobs(aoi_hash, job_status, updated_at DESC);
@@ -0,0 +1,3 @@
+-- Weights, class thresholds and asset types used to score the job, stored so
+-- its results can be reproduced. NULL means the default README formula.
+ALTER TABLE risk_assessment_jobs ADD COLUMN risk_parameters JSONB;
@@ -0,0 +1,6 @@
+-- Record which risk model produced each score so results from different
+-- models are never mixed up. Existing rows came from the README formula.
+ALTER TABLE asset_risk_analysis ADD COLUMN model_name VARCHAR(50) NOT NULL DEFAULT 'weighted-v1';
+ALTER TABLE asset_risk_analysis ADD COLUMN model_version VARCHAR(20) NOT NULL DEFAULT '1.0.0';
+
+CREATE INDEX idx_risk_model ON asset_risk_analysis(model_name, model_version);
@@ -0,0 +1,8 @@
+-- Scheduling metadata: jobs are dispatched by priority and then fairly
+-- across submitters, with concurrency capped globally and per submitter
+ALTER TABLE risk_assessment_jobs ADD COLUMN priority VARCHAR(10) NOT NULL DEFAULT 'NORMAL'; -- LOW, NORMAL, HIGH, URGENT
+ALTER TABLE risk_assessment_jobs ADD COLUMN submitter VARCHAR(255) NOT NULL DEFAULT '';
+
+-- Rebuilding the queue on startup and counting running jobs per submitter
+CREATE INDEX idx_jobs_queue ON risk_assessment_jobs(job_status, priority, created_at);
+CREATE INDEX idx_jobs_submitter ON risk_assessment_jobs(submitter, job_status);
@@ -0,0 +1,36 @@
+-- AOIs above the configured area are split into a grid of child jobs, each
+-- clipped to the original polygon. Clients only ever see the parent job.
+ALTER TABLE risk_assessment_jobs ADD COLUMN parent_job_id UUID REFERENCES risk_assessment_jobs(job_id) ON DELETE CASCADE;
+
+CREATE INDEX idx_jobs_parent ON risk_assessment_jobs(parent_job_id);
+
+-- Assets and their latest scores, attributed to the job the client sees.
+-- Features straddling two grid cells are returned to both child jobs, so
+-- they are deduplicated by OSM identity.
+CREATE VIEW job_asset_risk AS
+SELECT DISTINCT ON (root_job_id, osm_key)
+    root_job_id AS job_id,
+    asset_id,
+    asset_type,
+    asset_geometry,
+    properties,
+    overall_risk_score,
+    model_name,
+    model_version
+FROM (
+    SELECT
+        COALESCE(j.parent_job_id, j.job_id) AS root_job_id,
+        COALESCE((a.properties->>'osm_type') || '/' || (a.properties->>'osm_id'), a.asset_id::text) AS osm_key,
+        a.asset_id,
+        a.asset_type,
+        a.asset_geometry,
+        a.properties,
+        r.overall_risk_score,
+        r.model_name,
+        r.model_version,
+        r.analysis_timestamp
+    FROM infrastructure_assets a
+    JOIN risk_assessment_jobs j ON j.job_id = a.job_id
+    LEFT JOIN asset_risk_analysis r ON r.asset_id = a.asset_id
+) scored
+ORDER BY root_job_id, osm_key, analysis_timestamp DESC NULLS LAST;
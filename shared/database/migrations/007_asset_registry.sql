@@ -0,0 +1,115 @@
+-- Canonical identity for assets across jobs. OSM features are keyed by type
+-- and ID ("way/123"); anything else by a hash of its geometry snapped to
+-- about 1 cm ("geom/<md5>"). Each job's infrastructure_assets rows point at
+-- their registry entry, so an asset's risk can be trended across runs.
+CREATE TABLE assets (
+    canonical_asset_id BIGSERIAL PRIMARY KEY,
+    identity_key VARCHAR(100) NOT NULL UNIQUE,
+    asset_type VARCHAR(50) NOT NULL,
+    -- Geometry and tags as last seen; earlier versions live in asset_changes
+    asset_geometry GEOMETRY(Geometry, 4326) NOT NULL,
+    geometry_hash VARCHAR(32) NOT NULL,
+    tags JSONB NOT NULL DEFAULT '{}',
+    version INT NOT NULL DEFAULT 1,
+    first_seen_job_id UUID REFERENCES risk_assessment_jobs(job_id) ON DELETE SET NULL,
+    last_seen_job_id UUID REFERENCES risk_assessment_jobs(job_id) ON DELETE SET NULL,
+    first_seen_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    last_seen_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_registry_spatial ON assets USING GIST (asset_geometry);
+
+ALTER TABLE infrastructure_assets ADD COLUMN canonical_asset_id BIGINT REFERENCES assets(canonical_asset_id) ON DELETE SET NULL;
+
+CREATE INDEX idx_assets_canonical ON infrastructure_assets(canonical_asset_id);
+
+-- One row per detected change: the asset's first sighting, or a run that
+-- saw different geometry or tags. Previous values are kept for diffing.
+CREATE TABLE asset_changes (
+    change_id BIGSERIAL PRIMARY KEY,
+    canonical_asset_id BIGINT NOT NULL REFERENCES assets(canonical_asset_id) ON DELETE CASCADE,
+    job_id UUID REFERENCES risk_assessment_jobs(job_id) ON DELETE SET NULL,
+    change_type VARCHAR(20) NOT NULL, -- CREATED, GEOMETRY, TAGS
+    version INT NOT NULL,             -- Registry version the change produced
+    previous_geometry GEOMETRY(Geometry, 4326),
+    previous_tags JSONB,
+    changed_tags TEXT[] NOT NULL DEFAULT '{}', -- Keys added, removed or modified
+    changed_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_changes_asset ON asset_changes(canonical_asset_id, changed_at);
+
+-- Backfill the registry from existing rows, keeping the latest sighting of
+-- each identity
+WITH keyed AS (
+    SELECT
+        a.asset_id,
+        a.job_id,
+        a.asset_type,
+        a.asset_geometry,
+        COALESCE(a.properties, '{}') - 'osm_type' - 'osm_id' AS tags,
+        md5(ST_AsBinary(ST_SnapToGrid(a.asset_geometry, 0.0000001))) AS geometry_hash,
+        COALESCE(
+            (a.properties->>'osm_type') || '/' || (a.properties->>'osm_id'),
+            'geom/' || md5(ST_AsBinary(ST_SnapToGrid(a.asset_geometry, 0.0000001)))
+        ) AS identity_key,
+        j.created_at
+    FROM infrastructure_assets a
+    JOIN risk_assessment_jobs j ON j.job_id = a.job_id
+)
+INSERT INTO assets (identity_key, asset_type, asset_geometry, geometry_hash, tags, first_seen_job_id, last_seen_job_id, first_seen_at, last_seen_at)
+SELECT DISTINCT ON (identity_key)
+    identity_key,
+    asset_type,
+    asset_geometry,
+    geometry_hash,
+    tags,
+    FIRST_VALUE(job_id) OVER (PARTITION BY identity_key ORDER BY created_at),
+    job_id,
+    MIN(created_at) OVER (PARTITION BY identity_key),
+    created_at
+FROM keyed
+ORDER BY identity_key, created_at DESC, asset_id DESC;
+
+UPDATE infrastructure_assets a
+SET canonical_asset_id = r.canonical_asset_id
+FROM assets r
+WHERE r.identity_key = COALESCE(
+    (a.properties->>'osm_type') || '/' || (a.properties->>'osm_id'),
+    'geom/' || md5(ST_AsBinary(ST_SnapToGrid(a.asset_geometry, 0.0000001)))
+);
+
+INSERT INTO asset_changes (canonical_asset_id, job_id, change_type, version, changed_at)
+SELECT canonical_asset_id, first_seen_job_id, 'CREATED', 1, first_seen_at FROM assets;
+
+-- Deduplicate by canonical identity where it is known. New columns must go
+-- last for CREATE OR REPLACE VIEW.
+CREATE OR REPLACE VIEW job_asset_risk AS
+SELECT DISTINCT ON (root_job_id, osm_key)
+    root_job_id AS job_id,
+    asset_id,
+    asset_type,
+    asset_geometry,
+    properties,
+    overall_risk_score,
+    model_name,
+    model_version,
+    canonical_asset_id
+FROM (
+    SELECT
+        COALESCE(j.parent_job_id, j.job_id) AS root_job_id,
+        COALESCE(a.canonical_asset_id::text, (a.properties->>'osm_type') || '/' || (a.properties->>'osm_id'), a.asset_id::text) AS osm_key,
+        a.asset_id,
+        a.asset_type,
+        a.asset_geometry,
+        a.properties,
+        r.overall_risk_score,
+        r.model_name,
+        r.model_version,
+        r.analysis_timestamp,
+        a.canonical_asset_id
+    FROM infrastructure_assets a
+    JOIN risk_assessment_jobs j ON j.job_id = a.job_id
+    LEFT JOIN asset_risk_analysis r ON r.asset_id = a.asset_id
+) scored
+ORDER BY root_job_id, osm_key, analysis_timestamp DESC NULLS LAST;
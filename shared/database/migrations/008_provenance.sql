@@ -0,0 +1,13 @@
+-- Where each asset came from, for audits and ODbL attribution: the OSM
+-- element's edit metadata ("out meta") and the endpoint and data timestamp
+-- of the response it was read from
+ALTER TABLE infrastructure_assets ADD COLUMN osm_version INT;
+ALTER TABLE infrastructure_assets ADD COLUMN osm_timestamp TIMESTAMPTZ;
+ALTER TABLE infrastructure_assets ADD COLUMN osm_changeset BIGINT;
+ALTER TABLE infrastructure_assets ADD COLUMN osm_user VARCHAR(255);
+ALTER TABLE infrastructure_assets ADD COLUMN data_source TEXT;          -- Overpass API URL or PBF extract path
+ALTER TABLE infrastructure_assets ADD COLUMN data_timestamp TIMESTAMPTZ; -- osm3s.timestamp_osm_base
+
+-- Every source a job's data came from, as [{"endpoint", "data_timestamp"}].
+-- NULL for jobs that ran before provenance was recorded.
+ALTER TABLE risk_assessment_jobs ADD COLUMN data_sources JSONB;
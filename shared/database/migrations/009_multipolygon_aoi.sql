@@ -0,0 +1,7 @@
+-- A grid cell clipped from a concave AOI can hold several disjoint pieces,
+-- so child jobs store MultiPolygons as well as Polygons
+ALTER TABLE risk_assessment_jobs
+    ALTER COLUMN aoi_polygon TYPE GEOMETRY(Geometry, 4326);
+
+ALTER TABLE risk_assessment_jobs
+    ADD CONSTRAINT aoi_polygon_is_areal CHECK (GeometryType(aoi_polygon) IN ('POLYGON', 'MULTIPOLYGON'));
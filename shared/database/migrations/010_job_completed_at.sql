@@ -0,0 +1,24 @@
+-- Set once when a job first reaches COMPLETE. Result reuse judges freshness
+-- by it rather than updated_at, which moves whenever the row is touched.
+ALTER TABLE risk_assessment_jobs ADD COLUMN completed_at TIMESTAMPTZ;
+
+UPDATE risk_assessment_jobs SET completed_at = updated_at WHERE job_status = 'COMPLETE';
+
+CREATE OR REPLACE FUNCTION set_completed_at_column()
+RETURNS TRIGGER AS $$
+BEGIN
+    IF NEW.job_status = 'COMPLETE' AND NEW.completed_at IS NULL THEN
+        NEW.completed_at = NOW();
+    END IF;
+    RETURN NEW;
+END;
+$$ language 'plpgsql';
+
+CREATE TRIGGER set_risk_assessment_jobs_completed_at
+    BEFORE INSERT OR UPDATE ON risk_assessment_jobs
+    FOR EACH ROW EXECUTE PROCEDURE set_completed_at_column();
+
+-- Lookup of the freshest job that ran for a hash; reused jobs never qualify
+DROP INDEX idx_jobs_aoi_hash;
+CREATE INDEX idx_jobs_aoi_hash ON risk_assessment_jobs(aoi_hash, completed_at DESC)
+    WHERE job_status = 'COMPLETE' AND reused_from_job_id IS NULL;
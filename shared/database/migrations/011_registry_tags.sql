@@ -0,0 +1,6 @@
+-- Registry tags hold only OSM tags. Properties the pipeline derives per job
+-- (AOI membership, measures, distances to water and fire stations) were
+-- stored as tags before, so seeing a feature from another AOI looked like an
+-- edit.
+UPDATE assets
+SET tags = tags - ARRAY['within_aoi', 'vulnerability_weight', 'area_m2', 'length_m', 'water_distance_m', 'fire_station_distance_m'];
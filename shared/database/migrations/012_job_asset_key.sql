@@ -0,0 +1,25 @@
+-- Each job stores an asset once, keyed by its registry identity, so running
+-- a job again updates its rows instead of adding duplicates
+ALTER TABLE infrastructure_assets ADD COLUMN asset_key VARCHAR(100);
+
+UPDATE infrastructure_assets a
+SET asset_key = r.identity_key
+FROM assets r
+WHERE r.canonical_asset_id = a.canonical_asset_id;
+
+UPDATE infrastructure_assets SET asset_key = 'asset/' || asset_id WHERE asset_key IS NULL;
+
+-- Keep the latest copy from jobs that already ran more than once
+DELETE FROM infrastructure_assets a
+USING infrastructure_assets b
+WHERE a.job_id = b.job_id AND a.asset_key = b.asset_key AND a.asset_id < b.asset_id;
+
+ALTER TABLE infrastructure_assets ALTER COLUMN asset_key SET NOT NULL;
+
+CREATE UNIQUE INDEX idx_assets_job_key ON infrastructure_assets(job_id, asset_key);
+
+-- Geometry hashes are now computed by the orchestrator from GeoJSON rather
+-- than by PostGIS from WKB. Clear the old ones so the next sighting adopts
+-- the new hash without recording a geometry change. Assets without an OSM
+-- identity are keyed by that hash and get a new registry entry.
+UPDATE assets SET geometry_hash = '';
@@ -0,0 +1,13 @@
+-- Pin the risk model a job was created with. The orchestrator scores the
+-- job's assets through it when processing reports back, and results are only
+-- reused by jobs that would have been scored by the same version. Existing
+-- jobs used the model named in their parameters; only 1.0.0 was released.
+ALTER TABLE risk_assessment_jobs ADD COLUMN model_name VARCHAR(50);
+ALTER TABLE risk_assessment_jobs ADD COLUMN model_version VARCHAR(20);
+
+UPDATE risk_assessment_jobs
+SET model_name = COALESCE(NULLIF(risk_parameters->>'model', ''), 'weighted-v1'),
+    model_version = '1.0.0';
+
+ALTER TABLE risk_assessment_jobs ALTER COLUMN model_name SET NOT NULL;
+ALTER TABLE risk_assessment_jobs ALTER COLUMN model_version SET NOT NULL;
@@ -0,0 +1,196 @@
+package models
+
+import (
+	"crypto/md5"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type AssetChangeType string
+
+const (
+	AssetChangeCreated  AssetChangeType = "CREATED"
+	AssetChangeGeometry AssetChangeType = "GEOMETRY"
+	AssetChangeTags     AssetChangeType = "TAGS"
+)
+
+// CanonicalAsset is an asset's registry entry, shared by every job that saw
+// it. Geometry and tags are as last seen.
+type CanonicalAsset struct {
+	CanonicalAssetID int64                  `json:"canonical_asset_id" db:"canonical_asset_id"`
+	IdentityKey      string                 `json:"identity_key" db:"identity_key"`
+	AssetType        string                 `json:"asset_type" db:"asset_type"`
+	AssetGeometry    string                 `json:"asset_geometry" db:"asset_geometry"`
+	GeometryHash     string                 `json:"geometry_hash" db:"geometry_hash"`
+	Tags             map[string]interface{} `json:"tags" db:"tags"`
+	Version          int                    `json:"version" db:"version"`
+	FirstSeenJobID   *uuid.UUID             `json:"first_seen_job_id,omitempty" db:"first_seen_job_id"`
+	LastSeenJobID    *uuid.UUID             `json:"last_seen_job_id,omitempty" db:"last_seen_job_id"`
+	FirstSeenAt      time.Time              `json:"first_seen_at" db:"first_seen_at"`
+	LastSeenAt       time.Time              `json:"last_seen_at" db:"last_seen_at"`
+}
+
+// AssetChange records a registry entry being created or a job seeing it with
+// different geometry or tags. Previous values are empty for CREATED.
+type AssetChange struct {
+	ChangeID         int64                  `json:"change_id" db:"change_id"`
+	CanonicalAssetID int64                  `json:"canonical_asset_id" db:"canonical_asset_id"`
+	JobID            *uuid.UUID             `json:"job_id,omitempty" db:"job_id"`
+	ChangeType       AssetChangeType        `json:"change_type" db:"change_type"`
+	Version          int                    `json:"version" db:"version"`
+	PreviousGeometry string                 `json:"previous_geometry,omitempty" db:"previous_geometry"`
+	PreviousTags     map[string]interface{} `json:"previous_tags,omitempty" db:"previous_tags"`
+	ChangedTags      []string               `json:"changed_tags,omitempty" db:"changed_tags"`
+	ChangedAt        time.Time              `json:"changed_at" db:"changed_at"`
+}
+
+// AssetRiskPoint is one job's score for a registry asset
+type AssetRiskPoint struct {
+	JobID             uuid.UUID `json:"job_id" db:"job_id"`
+	AssetID           int64     `json:"asset_id" db:"asset_id"`
+	OverallRiskScore  float64   `json:"overall_risk_score" db:"overall_risk_score"`
+	ModelName         string    `json:"model_name" db:"model_name"`
+	ModelVersion      string    `json:"model_version" db:"model_version"`
+	AnalysisTimestamp time.Time `json:"analysis_timestamp" db:"analysis_timestamp"`
+}
+
+// Properties the pipeline derives for scoring and stores alongside an asset's
+// OSM tags. They depend on the AOI and the surroundings, not on the feature.
+const (
+	PropertyWithinAOI            = "within_aoi"
+	PropertyVulnerabilityWeight  = "vulnerability_weight"
+	PropertyAreaM2               = "area_m2"
+	PropertyLengthM              = "length_m"
+	PropertyWaterDistanceM       = "water_distance_m"
+	PropertyFireStationDistanceM = "fire_station_distance_m"
+)
+
+// nonTagProperties are left out of an asset's tags: its OSM identity and the
+// derived properties
+var nonTagProperties = map[string]bool{
+	"osm_type":                   true,
+	"osm_id":                     true,
+	PropertyWithinAOI:            true,
+	PropertyVulnerabilityWeight:  true,
+	PropertyAreaM2:               true,
+	PropertyLengthM:              true,
+	PropertyWaterDistanceM:       true,
+	PropertyFireStationDistanceM: true,
+}
+
+// OSMKey returns the asset's OSM identity, e.g. "way/123". ok is false for
+// assets that did not come from OSM.
+func (a InfrastructureAsset) OSMKey() (key string, ok bool) {
+	osmType, _ := a.Properties["osm_type"].(string)
+	if osmType == "" {
+		return "", false
+	}
+
+	var id string
+	switch v := a.Properties["osm_id"].(type) {
+	case int64:
+		id = strconv.FormatInt(v, 10)
+	case int:
+		id = strconv.Itoa(v)
+	case float64:
+		id = strconv.FormatFloat(v, 'f', -1, 64)
+	case json.Number:
+		id = v.String()
+	case string:
+		id = v
+	}
+	if id == "" {
+		return "", false
+	}
+
+	return osmType + "/" + id, true
+}
+
+// geometryHashScale snaps coordinates to 1e-7 degrees, about 1 cm, so the
+// same shape from different runs hashes the same
+const geometryHashScale = 1e7
+
+// GeometryHash returns an MD5 of the asset's GeoJSON geometry with its
+// coordinates snapped to about 1 cm
+func (a InfrastructureAsset) GeometryHash() (string, error) {
+	var geometry struct {
+		Type        string      `json:"type"`
+		Coordinates interface{} `json:"coordinates"`
+	}
+	if err := json.Unmarshal([]byte(a.AssetGeometry), &geometry); err != nil {
+		return "", fmt.Errorf("invalid geometry: %w", err)
+	}
+	geometry.Coordinates = snapCoordinates(geometry.Coordinates)
+
+	data, err := json.Marshal(geometry)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode geometry: %w", err)
+	}
+	sum := md5.Sum(data)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+func snapCoordinates(v interface{}) interface{} {
+	switch v := v.(type) {
+	case float64:
+		snapped := math.Round(v*geometryHashScale) / geometryHashScale
+		if snapped == 0 {
+			return 0.0 // No negative zero
+		}
+		return snapped
+	case []interface{}:
+		for i := range v {
+			v[i] = snapCoordinates(v[i])
+		}
+	}
+	return v
+}
+
+// Tags returns the asset's OSM tags: its properties without its OSM identity
+// or the derived properties, so seeing a feature from another AOI is not a
+// change. They are normalised through JSON so they compare equal to tags
+// read back from the database.
+func (a InfrastructureAsset) Tags() (map[string]interface{}, error) {
+	tags := make(map[string]interface{}, len(a.Properties))
+	for k, v := range a.Properties {
+		if !nonTagProperties[k] {
+			tags[k] = v
+		}
+	}
+
+	data, err := json.Marshal(tags)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode tags: %w", err)
+	}
+	normalised := make(map[string]interface{})
+	if err := json.Unmarshal(data, &normalised); err != nil {
+		return nil, fmt.Errorf("failed to decode tags: %w", err)
+	}
+	return normalised, nil
+}
+
+// ChangedTagKeys lists, sorted, the keys added, removed or modified between
+// two tag sets
+func ChangedTagKeys(previous, current map[string]interface{}) []string {
+	var keys []string
+	for k, v := range current {
+		if old, ok := previous[k]; !ok || !reflect.DeepEqual(old, v) {
+			keys = append(keys, k)
+		}
+	}
+	for k := range previous {
+		if _, ok := current[k]; !ok {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -4,6 +4,8 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+
+	"wildfire-risk-platform/shared/risk"
 )
 
 type JobStatus string
@@ -16,20 +18,93 @@ const (
 	JobStatusFailed        JobStatus = "FAILED"
 )
 
+type JobPriority string
+
+const (
+	JobPriorityLow    JobPriority = "LOW"
+	JobPriorityNormal JobPriority = "NORMAL"
+	JobPriorityHigh   JobPriority = "HIGH"
+	JobPriorityUrgent JobPriority = "URGENT"
+)
+
+// MaxPriorityRank is the rank of the most urgent priority
+const MaxPriorityRank = 3
+
+// Rank orders priorities for scheduling; higher ranks are dispatched first.
+// Unknown values are treated as NORMAL.
+func (p JobPriority) Rank() int {
+	switch p {
+	case JobPriorityLow:
+		return 0
+	case JobPriorityHigh:
+		return 2
+	case JobPriorityUrgent:
+		return 3
+	default:
+		return 1
+	}
+}
+
 type RiskAssessmentJob struct {
-	JobID      uuid.UUID `json:"job_id" db:"job_id"`
-	AOIPolygon string    `json:"aoi_polygon" db:"aoi_polygon"`
-	JobStatus  JobStatus `json:"job_status" db:"job_status"`
-	CreatedAt  time.Time `json:"created_at" db:"created_at"`
-	UpdatedAt  time.Time `json:"updated_at" db:"updated_at"`
+	JobID           uuid.UUID       `json:"job_id" db:"job_id"`
+	AOIPolygon      string          `json:"aoi_polygon" db:"aoi_polygon"`
+	AOIHash         string          `json:"aoi_hash,omitempty" db:"aoi_hash"`
+	JobStatus       JobStatus       `json:"job_status" db:"job_status"`
+	ReusedFromJobID *uuid.UUID      `json:"reused_from_job_id,omitempty" db:"reused_from_job_id"`
+	ParentJobID     *uuid.UUID      `json:"parent_job_id,omitempty" db:"parent_job_id"`
+	RiskParameters  risk.Parameters `json:"risk_parameters" db:"risk_parameters"`
+	ModelName       string          `json:"model_name" db:"model_name"`
+	ModelVersion    string          `json:"model_version" db:"model_version"`
+	Priority        JobPriority     `json:"priority" db:"priority"`
+	Submitter       string          `json:"submitter" db:"submitter"`
+	DataSources     []DataSource    `json:"data_sources,omitempty" db:"data_sources"`
+	CreatedAt       time.Time       `json:"created_at" db:"created_at"`
+	UpdatedAt       time.Time       `json:"updated_at" db:"updated_at"`
+}
+
+// AggregateChildStatus derives a split parent's status from its children:
+// COMPLETE once every child is, FAILED once any child failed and the rest
+// have stopped, otherwise the least advanced status still in progress.
+func AggregateChildStatus(children []JobStatus) JobStatus {
+	if len(children) == 0 {
+		return JobStatusPending
+	}
+
+	counts := make(map[JobStatus]int)
+	for _, s := range children {
+		counts[s]++
+	}
+
+	switch {
+	case counts[JobStatusComplete] == len(children):
+		return JobStatusComplete
+	case counts[JobStatusComplete]+counts[JobStatusFailed] == len(children):
+		return JobStatusFailed
+	case counts[JobStatusPending] == len(children):
+		return JobStatusPending
+	case counts[JobStatusPending] > 0 || counts[JobStatusGatheringData] > 0:
+		return JobStatusGatheringData
+	default:
+		return JobStatusProcessing
+	}
+}
+
+// JobResultSummary counts a job's scored assets by risk class
+type JobResultSummary struct {
+	TotalAssets      int `json:"total_assets"`
+	HighRiskAssets   int `json:"high_risk_assets"`
+	MediumRiskAssets int `json:"medium_risk_assets"`
+	LowRiskAssets    int `json:"low_risk_assets"`
 }
 
 type InfrastructureAsset struct {
-	AssetID       int64                  `json:"asset_id" db:"asset_id"`
-	JobID         uuid.UUID              `json:"job_id" db:"job_id"`
-	AssetType     string                 `json:"asset_type" db:"asset_type"`
-	AssetGeometry string                 `json:"asset_geometry" db:"asset_geometry"`
-	Properties    map[string]interface{} `json:"properties" db:"properties"`
+	AssetID          int64                  `json:"asset_id" db:"asset_id"`
+	JobID            uuid.UUID              `json:"job_id" db:"job_id"`
+	CanonicalAssetID *int64                 `json:"canonical_asset_id,omitempty" db:"canonical_asset_id"`
+	AssetType        string                 `json:"asset_type" db:"asset_type"`
+	AssetGeometry    string                 `json:"asset_geometry" db:"asset_geometry"`
+	Properties       map[string]interface{} `json:"properties" db:"properties"`
+	Provenance       AssetProvenance        `json:"provenance"`
 }
 
 type AssetRiskAnalysis struct {
@@ -39,5 +114,29 @@ type AssetRiskAnalysis struct {
 	RiskFromSlope      float64   `json:"risk_from_slope" db:"risk_from_slope"`
 	RiskFromWind       float64   `json:"risk_from_wind" db:"risk_from_wind"`
 	OverallRiskScore   float64   `json:"overall_risk_score" db:"overall_risk_score"`
+	ModelName          string    `json:"model_name" db:"model_name"`
+	ModelVersion       string    `json:"model_version" db:"model_version"`
 	AnalysisTimestamp  time.Time `json:"analysis_timestamp" db:"analysis_timestamp"`
 }
+
+// NewAssetRiskAnalysis builds an analysis row from a model's score
+func NewAssetRiskAnalysis(assetID int64, model risk.RiskModel, score risk.Score) AssetRiskAnalysis {
+	return AssetRiskAnalysis{
+		AssetID:            assetID,
+		RiskFromVegetation: score.Components[risk.ComponentVegetation],
+		RiskFromSlope:      score.Components[risk.ComponentSlope],
+		RiskFromWind:       score.Components[risk.ComponentWind],
+		OverallRiskScore:   score.Overall,
+		ModelName:          model.Name(),
+		ModelVersion:       model.Version(),
+	}
+}
+
+// ResultsJobID returns the job whose results this job reports. Reused jobs
+// point at the job that actually ran, so chains of reuse never form.
+func (j *RiskAssessmentJob) ResultsJobID() uuid.UUID {
+	if j.ReusedFromJobID != nil {
+		return *j.ReusedFromJobID
+	}
+	return j.JobID
+}
@@ -0,0 +1,64 @@
+package models
+
+import (
+	"sort"
+	"time"
+)
+
+// OpenStreetMap data is licensed under the ODbL, which requires this credit
+// wherever it is used
+const (
+	OSMAttributionText = "© OpenStreetMap contributors"
+	OSMLicense         = "ODbL-1.0"
+	OSMLicenseURL      = "https://www.openstreetmap.org/copyright"
+)
+
+// DataSource is an endpoint a job's data came from and how current it was
+type DataSource struct {
+	Endpoint      string `json:"endpoint"`                 // Overpass API URL or PBF extract path
+	DataTimestamp string `json:"data_timestamp,omitempty"` // osm3s.timestamp_osm_base, RFC 3339
+}
+
+// Attribution is the block every results response carries
+type Attribution struct {
+	Text       string       `json:"text"`
+	License    string       `json:"license"`
+	LicenseURL string       `json:"license_url"`
+	Sources    []DataSource `json:"sources"`
+}
+
+// NewAttribution credits OpenStreetMap and lists the distinct sources, sorted
+func NewAttribution(sources []DataSource) Attribution {
+	seen := make(map[DataSource]bool)
+	distinct := []DataSource{}
+	for _, source := range sources {
+		if !seen[source] {
+			seen[source] = true
+			distinct = append(distinct, source)
+		}
+	}
+	sort.Slice(distinct, func(i, j int) bool {
+		if distinct[i].Endpoint != distinct[j].Endpoint {
+			return distinct[i].Endpoint < distinct[j].Endpoint
+		}
+		return distinct[i].DataTimestamp < distinct[j].DataTimestamp
+	})
+
+	return Attribution{
+		Text:       OSMAttributionText,
+		License:    OSMLicense,
+		LicenseURL: OSMLicenseURL,
+		Sources:    distinct,
+	}
+}
+
+// AssetProvenance is an asset's OSM edit metadata and the source it was read
+// from. Zero values are stored as NULL.
+type AssetProvenance struct {
+	OSMVersion    int        `json:"osm_version,omitempty" db:"osm_version"`
+	OSMTimestamp  *time.Time `json:"osm_timestamp,omitempty" db:"osm_timestamp"`
+	OSMChangeset  int64      `json:"osm_changeset,omitempty" db:"osm_changeset"`
+	OSMUser       string     `json:"osm_user,omitempty" db:"osm_user"`
+	DataSource    string     `json:"data_source,omitempty" db:"data_source"`
+	DataTimestamp *time.Time `json:"data_timestamp,omitempty" db:"data_timestamp"`
+}
@@ -0,0 +1,315 @@
+package geo
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+)
+
+// polarLimitLat is the highest latitude an AOI reaches. Vertices nearer the
+// poles are clamped, so east-west distances and tile grids stay finite.
+const polarLimitLat = 89.9
+
+// AOI is a parsed area of interest. A MultiPolygon AOI has several polygons,
+// and so does one split at the antimeridian.
+type AOI struct {
+	Polygons []Polygon
+
+	bounds   BBox
+	regions  []BBox
+	segments []Segment
+}
+
+// position is a GeoJSON position. Pointers tell a null apart from zero.
+type position []*float64
+
+// ParseAOI parses a GeoJSON Polygon or MultiPolygon, keeping holes.
+// Positions must be [lon, lat] or [lon, lat, alt] within WGS84 ranges.
+// Polygons crossing the antimeridian, drawn with an edge jumping more than
+// 180 degrees of longitude, are split into one piece on each side, and rings
+// that circle a pole are closed over it.
+func ParseAOI(geoJSONStr string) (*AOI, error) {
+	var geometry struct {
+		Type        string          `json:"type"`
+		Coordinates json.RawMessage `json:"coordinates"`
+	}
+	if err := json.Unmarshal([]byte(geoJSONStr), &geometry); err != nil {
+		return nil, fmt.Errorf("invalid GeoJSON: %w", err)
+	}
+
+	var polygons [][][]position
+	switch geometry.Type {
+	case "Polygon":
+		var rings [][]position
+		if err := json.Unmarshal(geometry.Coordinates, &rings); err != nil {
+			return nil, fmt.Errorf("invalid Polygon coordinates: %w", err)
+		}
+		polygons = [][][]position{rings}
+	case "MultiPolygon":
+		if err := json.Unmarshal(geometry.Coordinates, &polygons); err != nil {
+			return nil, fmt.Errorf("invalid MultiPolygon coordinates: %w", err)
+		}
+	case "":
+		return nil, fmt.Errorf("AOI has no geometry type")
+	default:
+		return nil, fmt.Errorf("AOI must be a Polygon or MultiPolygon, got %s", geometry.Type)
+	}
+	if len(polygons) == 0 {
+		return nil, fmt.Errorf("AOI has no coordinates")
+	}
+
+	aoi := &AOI{}
+	split := false
+	for i, coords := range polygons {
+		if len(coords) == 0 {
+			return nil, fmt.Errorf("polygon %d has no rings", i)
+		}
+
+		polygon := make(Polygon, len(coords))
+		for j, ring := range coords {
+			if len(ring) < 4 {
+				return nil, fmt.Errorf("polygon %d ring %d needs at least 4 positions, got %d", i, j, len(ring))
+			}
+			polygon[j] = make(Ring, len(ring))
+			for k, c := range ring {
+				p, err := parsePosition(c)
+				if err != nil {
+					return nil, fmt.Errorf("polygon %d ring %d position %d: %w", i, j, k, err)
+				}
+				polygon[j][k] = p
+			}
+		}
+
+		pieces, crosses, err := splitAntimeridian(polygon)
+		if err != nil {
+			return nil, fmt.Errorf("polygon %d: %w", i, err)
+		}
+		split = split || crosses
+		aoi.Polygons = append(aoi.Polygons, pieces...)
+	}
+
+	aoi.index(split)
+	return aoi, nil
+}
+
+// parsePosition reads one position, clamping latitude near the poles
+func parsePosition(c position) (Point, error) {
+	if len(c) < 2 || len(c) > 3 {
+		return Point{}, fmt.Errorf("expected [lon, lat] or [lon, lat, alt], got %d values", len(c))
+	}
+	for _, v := range c {
+		if v == nil {
+			return Point{}, fmt.Errorf("coordinate is null")
+		}
+		if math.IsNaN(*v) || math.IsInf(*v, 0) {
+			return Point{}, fmt.Errorf("coordinate %v is not a finite number", *v)
+		}
+	}
+
+	lon, lat := *c[0], *c[1]
+	if lon < -180 || lon > 180 {
+		return Point{}, fmt.Errorf("longitude %v is outside [-180, 180]; draw antimeridian crossings as a jump from 180 to -180", lon)
+	}
+	if lat < -90 || lat > 90 {
+		return Point{}, fmt.Errorf("latitude %v is outside [-90, 90]; positions are [lon, lat]", lat)
+	}
+	return Point{lon, math.Max(-polarLimitLat, math.Min(lat, polarLimitLat))}, nil
+}
+
+// splitAntimeridian returns the polygon as pieces within [-180, 180]. A
+// polygon with no edge spanning more than 180 degrees is returned as is.
+func splitAntimeridian(polygon Polygon) ([]Polygon, bool, error) {
+	crosses := false
+	for _, ring := range polygon {
+		for k := 0; k+1 < len(ring); k++ {
+			crosses = crosses || math.Abs(ring[k+1].Lon()-ring[k].Lon()) > 180
+		}
+	}
+	if !crosses {
+		return []Polygon{polygon}, false, nil
+	}
+
+	// Unwrap every ring into continuous longitudes, possibly beyond +-180
+	unwrapped := make(Polygon, len(polygon))
+	for j, ring := range polygon {
+		u := make(Ring, len(ring))
+		u[0] = ring[0]
+		for k := 1; k < len(ring); k++ {
+			lon := ring[k].Lon()
+			for lon-u[k-1].Lon() > 180 {
+				lon -= 360
+			}
+			for lon-u[k-1].Lon() < -180 {
+				lon += 360
+			}
+			u[k] = Point{lon, ring[k].Lat()}
+		}
+
+		// A ring that ends a full turn from where it started circles a pole
+		if turn := u[len(u)-1].Lon() - u[0].Lon(); math.Abs(turn) > 180 {
+			if j > 0 {
+				return nil, false, fmt.Errorf("hole %d circles a pole, which is not supported", j)
+			}
+			var meanLat float64
+			for _, p := range ring {
+				meanLat += p.Lat() / float64(len(ring))
+			}
+			pole := math.Copysign(polarLimitLat, meanLat)
+			last := u[len(u)-1]
+			u = append(u[:len(u)-1:len(u)-1], last, Point{last.Lon(), pole}, Point{u[0].Lon(), pole}, u[0])
+		}
+
+		// Holes follow the exterior onto the same side
+		if j > 0 {
+			shift := math.Round((unwrapped[0][0].Lon()-u[0].Lon())/360) * 360
+			for k := range u {
+				u[k][0] += shift
+			}
+		}
+		unwrapped[j] = u
+	}
+
+	// Cut the unwrapped polygon into 360 degree windows and shift each back
+	var pieces []Polygon
+	for window := -1; window <= 1; window++ {
+		lo, hi := -180+360*float64(window), 180+360*float64(window)
+		var piece Polygon
+		for j, ring := range unwrapped {
+			clipped := clipLongitude(ring, lo, hi)
+			if len(clipped) < 4 || math.Abs(clipped.SignedArea()) < 1e-12 {
+				if j == 0 {
+					break // Nothing of the polygon in this window
+				}
+				continue
+			}
+			for k := range clipped {
+				clipped[k][0] -= 360 * float64(window)
+			}
+			piece = append(piece, clipped)
+		}
+		if len(piece) > 0 {
+			pieces = append(pieces, piece)
+		}
+	}
+	return pieces, true, nil
+}
+
+// clipLongitude clips a ring to lo <= lon <= hi, returning it closed
+func clipLongitude(ring Ring, lo, hi float64) Ring {
+	clip := func(in Ring, inside func(Point) bool, edge float64) Ring {
+		var out Ring
+		for k := range in {
+			a, b := in[k], in[(k+1)%len(in)]
+			if inside(a) {
+				out = append(out, a)
+			}
+			if inside(a) != inside(b) {
+				t := (edge - a.Lon()) / (b.Lon() - a.Lon())
+				out = append(out, Point{edge, a.Lat() + t*(b.Lat()-a.Lat())})
+			}
+		}
+		return out
+	}
+
+	open := ring
+	if len(open) > 1 && open[0] == open[len(open)-1] {
+		open = open[:len(open)-1]
+	}
+	out := clip(open, func(p Point) bool { return p.Lon() >= lo }, lo)
+	if len(out) > 0 {
+		out = clip(out, func(p Point) bool { return p.Lon() <= hi }, hi)
+	}
+	if len(out) == 0 {
+		return nil
+	}
+	return append(out, out[0])
+}
+
+func (a *AOI) index(split bool) {
+	var exteriors []Point
+	a.segments = nil
+	for _, polygon := range a.Polygons {
+		exteriors = append(exteriors, polygon[0]...)
+		a.segments = append(a.segments, polygon.Segments()...)
+	}
+	a.bounds = BoundsOf(exteriors)
+
+	// Either side of the antimeridian is queried on its own, so a small AOI
+	// across it never becomes a band around the world
+	a.regions = []BBox{a.bounds}
+	if split {
+		var west, east []Point
+		for _, polygon := range a.Polygons {
+			if b := polygon.Bounds(); b.MinLon+b.MaxLon < 0 {
+				west = append(west, polygon[0]...)
+			} else {
+				east = append(east, polygon[0]...)
+			}
+		}
+		a.regions = nil
+		for _, side := range [][]Point{west, east} {
+			if len(side) > 0 {
+				a.regions = append(a.regions, BoundsOf(side))
+			}
+		}
+	}
+}
+
+// Bounds returns the bounding box of every exterior ring. For an AOI split
+// at the antimeridian it spans every longitude; query by Regions instead.
+func (a *AOI) Bounds() BBox {
+	return a.bounds
+}
+
+// Regions returns the boxes to query for the AOI: its bounds, or one box on
+// each side of the antimeridian when it was split there
+func (a *AOI) Regions() []BBox {
+	return a.regions
+}
+
+// Segments returns the edges of every ring of every polygon
+func (a *AOI) Segments() []Segment {
+	return a.segments
+}
+
+// Contains reports whether pt is inside any of the AOI's polygons
+func (a *AOI) Contains(pt Point) bool {
+	for _, polygon := range a.Polygons {
+		if polygon.Contains(pt) {
+			return true
+		}
+	}
+	return false
+}
+
+// IntersectsBox reports whether any part of the AOI overlaps the box
+func (a *AOI) IntersectsBox(b BBox) bool {
+	if !a.bounds.Intersects(b) {
+		return false
+	}
+
+	corners := []Point{
+		{b.MinLon, b.MinLat}, {b.MaxLon, b.MinLat},
+		{b.MaxLon, b.MaxLat}, {b.MinLon, b.MaxLat},
+	}
+	for _, c := range corners {
+		if a.Contains(c) {
+			return true
+		}
+	}
+
+	// The AOI may be smaller than the box or only clip one of its edges
+	boxEdges := Ring(corners).Segments()
+	for _, edge := range a.segments {
+		if b.Contains(edge.A) {
+			return true
+		}
+		for _, be := range boxEdges {
+			if edge.Intersects(be) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,343 @@
+package geo
+
+import (
+	"math"
+	"sort"
+)
+
+// minClipArea drops pieces smaller than this many square degrees, which only
+// arise where the polygon touches the box along an edge or at a point
+const minClipArea = 1e-14
+
+// ClipToBox returns the parts of the polygon inside the box. A concave
+// polygon can fall apart into several pieces, so the result is a
+// MultiPolygon; it is empty when the polygon and box do not overlap. Holes
+// cut by the box become notches in the pieces, and holes inside the box stay
+// holes of the piece around them. Pieces have their exterior counter-
+// clockwise and holes clockwise, and every ring is closed.
+//
+// Each ring is cut into the chains of edges that lie inside the box, which
+// start and end on its boundary. Walking the boundary counter-clockwise from
+// where one chain leaves to where the next one enters joins them into the
+// pieces' exteriors.
+func (p Polygon) ClipToBox(b BBox) []Polygon {
+	if len(p) == 0 || len(p[0]) < 3 || !p.Bounds().Intersects(b) {
+		return nil
+	}
+
+	var chains [][]Point
+	var inner []Ring // Holes entirely inside the box
+	for i, ring := range p {
+		ring = orient(open(ring), i == 0)
+		if len(ring) < 3 {
+			continue
+		}
+
+		if b.containsRing(ring) {
+			if i == 0 {
+				return []Polygon{p.oriented()} // Holes lie inside the exterior too
+			}
+			inner = append(inner, ring)
+			continue
+		}
+
+		cut := b.chains(ring)
+		if len(cut) == 0 && i > 0 && ring.Contains(b.center()) {
+			return nil // The box is inside a hole
+		}
+		chains = append(chains, cut...)
+	}
+
+	// With no chains the exterior either misses the box or surrounds it. A
+	// surrounded box is the piece's exterior; when only holes cross it, the
+	// boundary walk between their chains passes its corners.
+	var shells []Ring
+	if len(chains) == 0 {
+		if !p[0].Contains(b.center()) {
+			return nil
+		}
+		shells = []Ring{b.ring()}
+	} else {
+		shells = b.join(chains)
+	}
+
+	pieces := make([]Polygon, 0, len(shells))
+	for _, shell := range shells {
+		if math.Abs(shell.SignedArea()) >= minClipArea {
+			pieces = append(pieces, Polygon{shell})
+		}
+	}
+
+	for _, hole := range inner {
+		for i := range pieces {
+			if pieces[i][0].Contains(interiorPoint(hole)) {
+				pieces[i] = append(pieces[i], hole)
+				break
+			}
+		}
+	}
+
+	for _, piece := range pieces {
+		for i := range piece {
+			piece[i] = piece[i].Closed()
+		}
+	}
+	return pieces
+}
+
+// chains returns the runs of a ring inside the box, each starting and ending
+// on the box's boundary. The ring must have a vertex outside the box.
+func (b BBox) chains(ring Ring) [][]Point {
+	// Start at a vertex outside the box so no chain wraps around the end
+	start := 0
+	for i, pt := range ring {
+		if !b.Contains(pt) {
+			start = i
+			break
+		}
+	}
+
+	var chains [][]Point
+	var chain []Point
+	n := len(ring)
+	for k := 0; k < n; k++ {
+		a, c := ring[(start+k)%n], ring[(start+k+1)%n]
+		t0, t1, ok := b.clipSegment(a, c)
+
+		if !ok || t1-t0 < 1e-12 {
+			// The edge misses the box or only touches it at a point
+			if chain != nil {
+				chains = append(chains, chain)
+				chain = nil
+			}
+			continue
+		}
+
+		if chain == nil {
+			chain = []Point{b.snap(lerp(a, c, t0))}
+		}
+		if t1 < 1 || !b.Contains(c) { // Rounding can put t1 at 1 for a vertex just outside
+			chain = append(chain, b.snap(lerp(a, c, t1)))
+			chains = append(chains, chain)
+			chain = nil
+		} else {
+			chain = append(chain, c)
+		}
+	}
+	if chain != nil {
+		chains = append(chains, chain)
+	}
+
+	return chains
+}
+
+// join links chains into closed rings by walking the box's boundary counter-
+// clockwise from each chain's exit to the nearest chain entry, adding the
+// corners passed on the way
+func (b BBox) join(chains [][]Point) []Ring {
+	entries := make([]float64, len(chains))
+	for i, chain := range chains {
+		entries[i] = b.perimeter(chain[0])
+	}
+
+	used := make([]bool, len(chains))
+	var rings []Ring
+	for first := range chains {
+		if used[first] {
+			continue
+		}
+
+		var ring Ring
+		for i := first; ; {
+			used[i] = true
+			ring = append(ring, chains[i]...)
+
+			exit := b.perimeter(chains[i][len(chains[i])-1])
+			next, gap := -1, math.Inf(1)
+			for j, entry := range entries {
+				if d := b.forward(exit, entry); d < gap && (!used[j] || j == first) {
+					next, gap = j, d
+				}
+			}
+			ring = append(ring, b.cornersBetween(exit, gap)...)
+
+			if next < 0 || next == first {
+				break
+			}
+			i = next
+		}
+		rings = append(rings, ring)
+	}
+
+	return rings
+}
+
+// clipSegment returns the part of the segment a-c inside the box as a range
+// of its parameter, using Liang-Barsky
+func (b BBox) clipSegment(a, c Point) (t0, t1 float64, ok bool) {
+	t0, t1 = 0, 1
+	dx, dy := c[0]-a[0], c[1]-a[1]
+	for _, edge := range [4][2]float64{
+		{-dx, a[0] - b.MinLon},
+		{dx, b.MaxLon - a[0]},
+		{-dy, a[1] - b.MinLat},
+		{dy, b.MaxLat - a[1]},
+	} {
+		p, q := edge[0], edge[1]
+		if p == 0 {
+			if q < 0 {
+				return 0, 0, false
+			}
+			continue
+		}
+		t := q / p
+		if p < 0 {
+			t0 = math.Max(t0, t)
+		} else {
+			t1 = math.Min(t1, t)
+		}
+	}
+	return t0, t1, t0 <= t1
+}
+
+// snap moves a point computed on the boundary exactly onto its nearest side
+func (b BBox) snap(pt Point) Point {
+	pt[0] = math.Max(b.MinLon, math.Min(b.MaxLon, pt[0]))
+	pt[1] = math.Max(b.MinLat, math.Min(b.MaxLat, pt[1]))
+
+	sides := [4]float64{pt[0] - b.MinLon, b.MaxLon - pt[0], pt[1] - b.MinLat, b.MaxLat - pt[1]}
+	nearest := 0
+	for i, d := range sides {
+		if d < sides[nearest] {
+			nearest = i
+		}
+	}
+	switch nearest {
+	case 0:
+		pt[0] = b.MinLon
+	case 1:
+		pt[0] = b.MaxLon
+	case 2:
+		pt[1] = b.MinLat
+	default:
+		pt[1] = b.MaxLat
+	}
+	return pt
+}
+
+// perimeter is the distance of a boundary point along the boundary,
+// counter-clockwise from the south-west corner
+func (b BBox) perimeter(pt Point) float64 {
+	w, h := b.MaxLon-b.MinLon, b.MaxLat-b.MinLat
+	switch {
+	case pt[1] == b.MinLat:
+		return pt[0] - b.MinLon
+	case pt[0] == b.MaxLon:
+		return w + pt[1] - b.MinLat
+	case pt[1] == b.MaxLat:
+		return w + h + b.MaxLon - pt[0]
+	default:
+		return 2*w + h + b.MaxLat - pt[1]
+	}
+}
+
+// forward is how far counter-clockwise along the boundary to goes from from
+func (b BBox) forward(from, to float64) float64 {
+	d := to - from
+	if d < 0 {
+		d += 2 * ((b.MaxLon - b.MinLon) + (b.MaxLat - b.MinLat))
+	}
+	return d
+}
+
+// cornersBetween returns the corners passed walking gap counter-clockwise
+// from the boundary position from
+func (b BBox) cornersBetween(from, gap float64) []Point {
+	w, h := b.MaxLon-b.MinLon, b.MaxLat-b.MinLat
+	corners := []struct {
+		at float64
+		pt Point
+	}{
+		{0, Point{b.MinLon, b.MinLat}},
+		{w, Point{b.MaxLon, b.MinLat}},
+		{w + h, Point{b.MaxLon, b.MaxLat}},
+		{2*w + h, Point{b.MinLon, b.MaxLat}},
+	}
+
+	var passed []Point
+	for _, c := range corners {
+		if d := b.forward(from, c.at); d > 0 && d < gap {
+			passed = append(passed, c.pt)
+		}
+	}
+	sort.Slice(passed, func(i, j int) bool {
+		return b.forward(from, b.perimeter(passed[i])) < b.forward(from, b.perimeter(passed[j]))
+	})
+	return passed
+}
+
+func (b BBox) containsRing(ring Ring) bool {
+	for _, pt := range ring {
+		if !b.Contains(pt) {
+			return false
+		}
+	}
+	return true
+}
+
+func (b BBox) center() Point {
+	return Point{(b.MinLon + b.MaxLon) / 2, (b.MinLat + b.MaxLat) / 2}
+}
+
+// ring returns the box's outline, counter-clockwise
+func (b BBox) ring() Ring {
+	return Ring{{b.MinLon, b.MinLat}, {b.MaxLon, b.MinLat}, {b.MaxLon, b.MaxLat}, {b.MinLon, b.MaxLat}}
+}
+
+// oriented returns a copy of the polygon with its exterior counter-clockwise
+// and holes clockwise, every ring closed
+func (p Polygon) oriented() Polygon {
+	out := make(Polygon, 0, len(p))
+	for i, ring := range p {
+		if r := orient(open(ring), i == 0); len(r) >= 3 {
+			out = append(out, r.Closed())
+		}
+	}
+	return out
+}
+
+// open drops a ring's repeated closing point
+func open(r Ring) Ring {
+	if len(r) > 1 && r[0] == r[len(r)-1] {
+		return r[:len(r)-1]
+	}
+	return r
+}
+
+// orient winds a ring counter-clockwise, or clockwise if it is a hole
+func orient(r Ring, exterior bool) Ring {
+	if (r.SignedArea() > 0) != exterior {
+		return r.Reversed()
+	}
+	return r
+}
+
+// interiorPoint returns a point just inside a ring, off its vertices, for
+// testing which piece a hole belongs to
+func interiorPoint(r Ring) Point {
+	a, c := r[0], r[1]
+	mid := lerp(a, c, 0.5)
+	// Step off the edge towards the hole's inside, which lies to the right
+	// of a clockwise ring
+	dx, dy := c[0]-a[0], c[1]-a[1]
+	length := math.Hypot(dx, dy)
+	if length == 0 {
+		return mid
+	}
+	step := length * 1e-6
+	return Point{mid[0] + dy/length*step, mid[1] - dx/length*step}
+}
+
+func lerp(a, c Point, t float64) Point {
+	return Point{a[0] + t*(c[0]-a[0]), a[1] + t*(c[1]-a[1])}
+}
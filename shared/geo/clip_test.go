@@ -0,0 +1,120 @@
+package geo
+
+import (
+	"math"
+	"sort"
+	"testing"
+)
+
+func box(minLon, minLat, maxLon, maxLat float64) BBox {
+	return BBox{minLon, minLat, maxLon, maxLat}
+}
+
+func rect(minLon, minLat, maxLon, maxLat float64) Ring {
+	return Ring{{minLon, minLat}, {maxLon, minLat}, {maxLon, maxLat}, {minLon, maxLat}, {minLon, minLat}}
+}
+
+// planarArea is the area of a polygon in square degrees, holes subtracted
+func planarArea(p Polygon) float64 {
+	area := math.Abs(p[0].SignedArea())
+	for _, hole := range p[1:] {
+		area -= math.Abs(hole.SignedArea())
+	}
+	return area
+}
+
+func TestClipToBox(t *testing.T) {
+	// A U open to the north: two prongs joined along the bottom
+	u := Polygon{{{0, 0}, {3, 0}, {3, 3}, {2, 3}, {2, 1}, {1, 1}, {1, 3}, {0, 3}, {0, 0}}}
+	// An L whose inner corner is at (1, 1)
+	l := Polygon{{{0, 0}, {2, 0}, {2, 1}, {1, 1}, {1, 2}, {0, 2}, {0, 0}}}
+	// A square with a square hole, the hole drawn counter-clockwise on purpose
+	holed := Polygon{rect(0, 0, 4, 4), rect(1, 1, 3, 3)}
+	// A U drawn clockwise with a hole in its western prong
+	uHoled := Polygon{
+		{{0, 0}, {0, 3}, {1, 3}, {1, 1}, {2, 1}, {2, 3}, {3, 3}, {3, 0}, {0, 0}},
+		rect(0.25, 2, 0.75, 2.5),
+	}
+
+	tests := []struct {
+		name    string
+		polygon Polygon
+		box     BBox
+		areas   []float64 // Area of each piece, west to east
+		holes   []int     // Holes kept in each piece
+	}{
+		{"concave polygon falls apart", u, box(-1, 2, 4, 4), []float64{1, 1}, []int{0, 0}},
+		{"concave polygon stays whole below the prongs", u, box(-1, -1, 4, 1.5), []float64{4}, []int{0}},
+		{"box edge through the inner corner", l, box(1, -1, 3, 3), []float64{1}, []int{0}},
+		{"box edge along the inner corner's edges", l, box(0, 0, 1, 2), []float64{2}, []int{0}},
+		{"polygon inside the box", l, box(-1, -1, 3, 3), []float64{3}, []int{0}},
+		{"box inside the polygon", l, box(0.2, 0.2, 0.8, 0.8), []float64{0.36}, []int{0}},
+		{"disjoint", l, box(5, 5, 6, 6), nil, nil},
+		{"box cuts a hole into a notch", holed, box(0, 0, 2, 4), []float64{6}, []int{0}},
+		{"hole inside the box stays a hole", holed, box(0.5, 0.5, 3.5, 3.5), []float64{5}, []int{1}},
+		{"box inside the hole", holed, box(1.5, 1.5, 2.5, 2.5), nil, nil},
+		{"hole crossing a box surrounded by the exterior", holed, box(0.5, 2, 3.5, 3.5), []float64{2.5}, []int{0}},
+		{"hole lands in the right piece", uHoled, box(-1, 1.5, 4, 4), []float64{1.5 - 0.25, 1.5}, []int{1, 0}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pieces := tt.polygon.ClipToBox(tt.box)
+			if len(pieces) != len(tt.areas) {
+				t.Fatalf("got %d pieces, want %d: %v", len(pieces), len(tt.areas), pieces)
+			}
+			sort.Slice(pieces, func(i, j int) bool {
+				return pieces[i].Bounds().MinLon < pieces[j].Bounds().MinLon
+			})
+
+			var total, want float64
+			for i, piece := range pieces {
+				total += planarArea(piece)
+				want += tt.areas[i]
+
+				if len(piece)-1 != tt.holes[i] {
+					t.Errorf("piece %d has %d holes, want %d", i, len(piece)-1, tt.holes[i])
+				}
+				for j, ring := range piece {
+					if ring[0] != ring[len(ring)-1] {
+						t.Errorf("piece %d ring %d is not closed", i, j)
+					}
+					if (ring.SignedArea() > 0) != (j == 0) {
+						t.Errorf("piece %d ring %d is wound the wrong way", i, j)
+					}
+					for _, pt := range ring {
+						if !tt.box.Contains(pt) {
+							t.Errorf("piece %d has vertex %v outside the box", i, pt)
+						}
+					}
+				}
+			}
+			if math.Abs(total-want) > 1e-9 {
+				t.Errorf("clipped area = %v, want %v", total, want)
+			}
+		})
+	}
+}
+
+// Clipping a polygon to a grid of boxes must account for all of its area
+func TestClipToBoxGridConservesArea(t *testing.T) {
+	star := Polygon{
+		{{0, 3}, {1.2, 1.2}, {3, 1}, {1.6, -0.4}, {2, -2.5}, {0, -1.2}, {-2, -2.5}, {-1.6, -0.4}, {-3, 1}, {-1.2, 1.2}, {0, 3}},
+		{{-0.5, -0.5}, {0.5, -0.5}, {0.5, 0.5}, {-0.5, 0.5}, {-0.5, -0.5}},
+	}
+	want := planarArea(star.oriented())
+
+	for _, step := range []float64{0.5, 0.7, 1, 1.3} {
+		var total float64
+		for x := -3.0; x < 3; x += step {
+			for y := -2.5; y < 3; y += step {
+				for _, piece := range star.ClipToBox(box(x, y, x+step, y+step)) {
+					total += planarArea(piece)
+				}
+			}
+		}
+		if math.Abs(total-want) > 1e-9 {
+			t.Errorf("step %v: pieces cover %v, polygon is %v", step, total, want)
+		}
+	}
+}
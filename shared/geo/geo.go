@@ -0,0 +1,209 @@
+package geo
+
+import "math"
+
+// Point is a WGS84 position as [lon, lat], matching GeoJSON order
+type Point [2]float64
+
+func (p Point) Lon() float64 { return p[0] }
+func (p Point) Lat() float64 { return p[1] }
+
+// Ring is a closed sequence of points. The closing point may be repeated or
+// omitted; every function here treats the ring as closed either way.
+type Ring []Point
+
+// Polygon is an exterior ring followed by zero or more holes
+type Polygon []Ring
+
+// Segment is a straight edge between two points
+type Segment struct {
+	A, B Point
+}
+
+// PointsFromCoords converts GeoJSON coordinates to points
+func PointsFromCoords(coords [][]float64) []Point {
+	points := make([]Point, len(coords))
+	for i, c := range coords {
+		points[i] = Point{c[0], c[1]}
+	}
+	return points
+}
+
+// PolygonFromCoords converts GeoJSON polygon coordinates
+func PolygonFromCoords(coords [][][]float64) Polygon {
+	polygon := make(Polygon, len(coords))
+	for i, ring := range coords {
+		polygon[i] = Ring(PointsFromCoords(ring))
+	}
+	return polygon
+}
+
+// Contains reports whether pt lies inside the ring using ray casting.
+// Points exactly on an edge may go either way.
+func (r Ring) Contains(pt Point) bool {
+	inside := false
+	n := len(r)
+	for i, j := 0, n-1; i < n; j, i = i, i+1 {
+		a, b := r[i], r[j]
+		if (a[1] > pt[1]) != (b[1] > pt[1]) &&
+			pt[0] < (b[0]-a[0])*(pt[1]-a[1])/(b[1]-a[1])+a[0] {
+			inside = !inside
+		}
+	}
+	return inside
+}
+
+// Segments returns the edges of the ring, including the closing edge
+func (r Ring) Segments() []Segment {
+	n := len(r)
+	if n < 2 {
+		return nil
+	}
+
+	segments := make([]Segment, 0, n)
+	for i := 0; i < n-1; i++ {
+		segments = append(segments, Segment{r[i], r[i+1]})
+	}
+	if r[0] != r[n-1] {
+		segments = append(segments, Segment{r[n-1], r[0]})
+	}
+	return segments
+}
+
+// Closed returns the ring with its first point repeated at the end
+func (r Ring) Closed() Ring {
+	if len(r) == 0 || r[0] == r[len(r)-1] {
+		return r
+	}
+	closed := make(Ring, len(r), len(r)+1)
+	copy(closed, r)
+	return append(closed, r[0])
+}
+
+// Contains reports whether pt is inside the exterior ring and outside every hole
+func (p Polygon) Contains(pt Point) bool {
+	if len(p) == 0 || !p[0].Contains(pt) {
+		return false
+	}
+	for _, hole := range p[1:] {
+		if hole.Contains(pt) {
+			return false
+		}
+	}
+	return true
+}
+
+// Segments returns the edges of every ring
+func (p Polygon) Segments() []Segment {
+	var segments []Segment
+	for _, ring := range p {
+		segments = append(segments, ring.Segments()...)
+	}
+	return segments
+}
+
+// Bounds returns the bounding box of the exterior ring
+func (p Polygon) Bounds() BBox {
+	if len(p) == 0 {
+		return BBox{}
+	}
+	return BoundsOf(p[0])
+}
+
+// BBox is an axis-aligned bounding box in degrees
+type BBox struct {
+	MinLon, MinLat, MaxLon, MaxLat float64
+}
+
+// BoundsOf returns the bounding box of a set of points
+func BoundsOf(points []Point) BBox {
+	if len(points) == 0 {
+		return BBox{}
+	}
+
+	b := BBox{points[0][0], points[0][1], points[0][0], points[0][1]}
+	for _, p := range points[1:] {
+		b.MinLon = math.Min(b.MinLon, p[0])
+		b.MaxLon = math.Max(b.MaxLon, p[0])
+		b.MinLat = math.Min(b.MinLat, p[1])
+		b.MaxLat = math.Max(b.MaxLat, p[1])
+	}
+	return b
+}
+
+// Intersects reports whether two boxes overlap, edges included
+func (b BBox) Intersects(o BBox) bool {
+	return b.MinLon <= o.MaxLon && o.MinLon <= b.MaxLon &&
+		b.MinLat <= o.MaxLat && o.MinLat <= b.MaxLat
+}
+
+// Contains reports whether pt lies inside the box, edges included
+func (b BBox) Contains(pt Point) bool {
+	return pt[0] >= b.MinLon && pt[0] <= b.MaxLon && pt[1] >= b.MinLat && pt[1] <= b.MaxLat
+}
+
+// Intersects reports whether two segments touch or cross
+func (s Segment) Intersects(o Segment) bool {
+	d1 := orientation(o.A, o.B, s.A)
+	d2 := orientation(o.A, o.B, s.B)
+	d3 := orientation(s.A, s.B, o.A)
+	d4 := orientation(s.A, s.B, o.B)
+
+	if ((d1 > 0 && d2 < 0) || (d1 < 0 && d2 > 0)) &&
+		((d3 > 0 && d4 < 0) || (d3 < 0 && d4 > 0)) {
+		return true
+	}
+
+	return (d1 == 0 && onSegment(o.A, o.B, s.A)) ||
+		(d2 == 0 && onSegment(o.A, o.B, s.B)) ||
+		(d3 == 0 && onSegment(s.A, s.B, o.A)) ||
+		(d4 == 0 && onSegment(s.A, s.B, o.B))
+}
+
+func orientation(a, b, c Point) float64 {
+	return (b[0]-a[0])*(c[1]-a[1]) - (b[1]-a[1])*(c[0]-a[0])
+}
+
+func onSegment(a, b, p Point) bool {
+	return math.Min(a[0], b[0]) <= p[0] && p[0] <= math.Max(a[0], b[0]) &&
+		math.Min(a[1], b[1]) <= p[1] && p[1] <= math.Max(a[1], b[1])
+}
+
+// SignedArea is the planar area of the ring in square degrees; positive for
+// counter-clockwise rings. Only the sign and relative size are meaningful.
+func (r Ring) SignedArea() float64 {
+	var sum float64
+	n := len(r)
+	for i := 0; i < n; i++ {
+		j := (i + 1) % n
+		sum += r[i][0]*r[j][1] - r[j][0]*r[i][1]
+	}
+	return sum / 2
+}
+
+// Reversed returns a copy of the ring in the opposite direction
+func (r Ring) Reversed() Ring {
+	reversed := make(Ring, len(r))
+	for i, p := range r {
+		reversed[len(r)-1-i] = p
+	}
+	return reversed
+}
+
+// Coords converts the ring back to GeoJSON coordinates
+func (r Ring) Coords() [][]float64 {
+	coords := make([][]float64, len(r))
+	for i, p := range r {
+		coords[i] = []float64{p[0], p[1]}
+	}
+	return coords
+}
+
+// Coords converts the polygon back to GeoJSON coordinates
+func (p Polygon) Coords() [][][]float64 {
+	coords := make([][][]float64, len(p))
+	for i, ring := range p {
+		coords[i] = ring.Coords()
+	}
+	return coords
+}
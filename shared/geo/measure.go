@@ -0,0 +1,177 @@
+package geo
+
+import "math"
+
+// EarthRadiusM is the mean Earth radius. Measurements treat the Earth as a
+// sphere of this radius, which is within 0.5% of the ellipsoid everywhere.
+const EarthRadiusM = 6371008.8
+
+func radians(deg float64) float64 { return deg * math.Pi / 180 }
+
+// Distance returns the great-circle distance between two points in metres
+func Distance(a, b Point) float64 {
+	lat1, lat2 := radians(a.Lat()), radians(b.Lat())
+	dLat := lat2 - lat1
+	dLon := radians(b.Lon() - a.Lon())
+
+	h := math.Sin(dLat/2)*math.Sin(dLat/2) +
+		math.Cos(lat1)*math.Cos(lat2)*math.Sin(dLon/2)*math.Sin(dLon/2)
+	return 2 * EarthRadiusM * math.Asin(math.Min(1, math.Sqrt(h)))
+}
+
+// LineLength returns the length of a polyline in metres
+func LineLength(points []Point) float64 {
+	var length float64
+	for i := 0; i+1 < len(points); i++ {
+		length += Distance(points[i], points[i+1])
+	}
+	return length
+}
+
+// Area returns the area of the ring in square metres, regardless of winding
+func (r Ring) Area() float64 {
+	n := len(r)
+	if n < 3 {
+		return 0
+	}
+
+	var sum float64
+	for i := 0; i < n; i++ {
+		a, b := r[i], r[(i+1)%n]
+		sum += radians(b.Lon()-a.Lon()) * (2 + math.Sin(radians(a.Lat())) + math.Sin(radians(b.Lat())))
+	}
+	return math.Abs(sum * EarthRadiusM * EarthRadiusM / 2)
+}
+
+// Area returns the area of the polygon in square metres, holes excluded
+func (p Polygon) Area() float64 {
+	if len(p) == 0 {
+		return 0
+	}
+
+	area := p[0].Area()
+	for _, hole := range p[1:] {
+		area -= hole.Area()
+	}
+	return math.Max(area, 0)
+}
+
+// Centroid returns the centre of mass of the polygon and its planar area in
+// square degrees, for weighting. It is computed in degrees, which is accurate
+// enough for features a few kilometres across.
+func (p Polygon) Centroid() (Point, float64) {
+	var cx, cy, total float64
+	for i, ring := range p {
+		c, a := ring.planarCentroid()
+		if i > 0 {
+			a = -a // Holes subtract
+		}
+		cx += c.Lon() * a
+		cy += c.Lat() * a
+		total += a
+	}
+
+	if total == 0 {
+		c, _ := LineCentroid(p[0])
+		return c, 0
+	}
+	return Point{cx / total, cy / total}, total
+}
+
+// planarCentroid returns the ring's centroid and unsigned planar area
+func (r Ring) planarCentroid() (Point, float64) {
+	var cx, cy, twice float64
+	n := len(r)
+	for i := 0; i < n; i++ {
+		a, b := r[i], r[(i+1)%n]
+		cross := a[0]*b[1] - b[0]*a[1]
+		twice += cross
+		cx += (a[0] + b[0]) * cross
+		cy += (a[1] + b[1]) * cross
+	}
+	if twice == 0 {
+		return Point{}, 0
+	}
+	return Point{cx / (3 * twice), cy / (3 * twice)}, math.Abs(twice / 2)
+}
+
+// LineCentroid returns the length-weighted centre of a polyline along with
+// its planar length, falling back to the first point for degenerate lines
+func LineCentroid(points []Point) (Point, float64) {
+	if len(points) == 0 {
+		return Point{}, 0
+	}
+
+	var cx, cy, total float64
+	for i := 0; i+1 < len(points); i++ {
+		a, b := points[i], points[i+1]
+		w := math.Hypot(b[0]-a[0], b[1]-a[1])
+		cx += (a[0] + b[0]) / 2 * w
+		cy += (a[1] + b[1]) / 2 * w
+		total += w
+	}
+
+	if total == 0 {
+		return points[0], 0
+	}
+	return Point{cx / total, cy / total}, total
+}
+
+// DistanceToSegment returns the distance from p to the nearest point of s in
+// metres
+func DistanceToSegment(p Point, s Segment) float64 {
+	closest, _ := ClosestOnSegment(p, s)
+	return Distance(p, closest)
+}
+
+// ClosestOnSegment returns the point of s nearest to p and its position
+// along s from 0 at A to 1 at B. The segment is projected onto a plane
+// tangent at p, which is accurate for segments up to a few tens of
+// kilometres away.
+func ClosestOnSegment(p Point, s Segment) (Point, float64) {
+	scale := math.Cos(radians(p.Lat()))
+	ax, ay := (s.A.Lon()-p.Lon())*scale, s.A.Lat()-p.Lat()
+	bx, by := (s.B.Lon()-p.Lon())*scale, s.B.Lat()-p.Lat()
+
+	dx, dy := bx-ax, by-ay
+	t := 0.0
+	if length := dx*dx + dy*dy; length > 0 {
+		t = math.Max(0, math.Min(1, -(ax*dx+ay*dy)/length))
+	}
+
+	return Point{s.A.Lon() + t*(s.B.Lon()-s.A.Lon()), s.A.Lat() + t*(s.B.Lat()-s.A.Lat())}, t
+}
+
+// Expand returns the box grown by metres on every side. Longitude is widened
+// for the box's highest latitude, so the result covers the full distance.
+func (b BBox) Expand(metres float64) BBox {
+	dLat := metres / (EarthRadiusM * math.Pi / 180)
+	dLon := dLat / math.Max(math.Cos(radians(math.Max(math.Abs(b.MinLat), math.Abs(b.MaxLat)))), 0.01)
+	return BBox{
+		MinLon: math.Max(b.MinLon-dLon, -180),
+		MinLat: math.Max(b.MinLat-dLat, -90),
+		MaxLon: math.Min(b.MaxLon+dLon, 180),
+		MaxLat: math.Min(b.MaxLat+dLat, 90),
+	}
+}
+
+// Bearing returns the initial compass bearing from a to b in degrees, from 0
+// (north) clockwise to 360
+func Bearing(a, b Point) float64 {
+	lat1, lat2 := radians(a.Lat()), radians(b.Lat())
+	dLon := radians(b.Lon() - a.Lon())
+
+	y := math.Sin(dLon) * math.Cos(lat2)
+	x := math.Cos(lat1)*math.Sin(lat2) - math.Sin(lat1)*math.Cos(lat2)*math.Cos(dLon)
+	return math.Mod(math.Atan2(y, x)*180/math.Pi+360, 360)
+}
+
+// Offset returns the point metres away from p on the given bearing. It
+// treats the Earth as flat around p, so is meant for short distances.
+func Offset(p Point, bearing, metres float64) Point {
+	dNorth := metres * math.Cos(radians(bearing))
+	dEast := metres * math.Sin(radians(bearing))
+	dLat := dNorth / (EarthRadiusM * math.Pi / 180)
+	dLon := dEast / (EarthRadiusM * math.Pi / 180 * math.Max(math.Cos(radians(p.Lat())), 0.01))
+	return Point{p.Lon() + dLon, p.Lat() + dLat}
+}
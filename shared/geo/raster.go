@@ -0,0 +1,133 @@
+package geo
+
+import "math"
+
+// Cell is a square [X, X+1] x [Y, Y+1] of a regular grid
+type Cell [2]int
+
+// TraceCells outlines a set of grid cells as polygons in grid coordinates.
+// Exterior rings run counter-clockwise and holes clockwise. Cells touching
+// only at a corner become separate polygons.
+func TraceCells(cells map[Cell]bool) []Polygon {
+	type vertex [2]int
+	type edge struct{ from, to vertex }
+
+	// Every cell side facing outside the set is an edge with the set on its left
+	outgoing := make(map[vertex][]edge)
+	add := func(from, to vertex) {
+		outgoing[from] = append(outgoing[from], edge{from, to})
+	}
+	for c := range cells {
+		x, y := c[0], c[1]
+		if !cells[Cell{x, y - 1}] {
+			add(vertex{x, y}, vertex{x + 1, y})
+		}
+		if !cells[Cell{x + 1, y}] {
+			add(vertex{x + 1, y}, vertex{x + 1, y + 1})
+		}
+		if !cells[Cell{x, y + 1}] {
+			add(vertex{x + 1, y + 1}, vertex{x, y + 1})
+		}
+		if !cells[Cell{x - 1, y}] {
+			add(vertex{x, y + 1}, vertex{x, y})
+		}
+	}
+
+	turnsLeft := func(candidates []edge, dx, dy int) bool {
+		for _, e := range candidates {
+			if dx*(e.to[1]-e.from[1])-dy*(e.to[0]-e.from[0]) > 0 {
+				return true
+			}
+		}
+		return false
+	}
+
+	// take removes and returns the edge leaving v, preferring a left turn
+	// from the incoming direction so corner-touching cells stay apart
+	take := func(v vertex, dx, dy int) edge {
+		candidates := outgoing[v]
+		best := 0
+		for i, e := range candidates {
+			ex, ey := e.to[0]-e.from[0], e.to[1]-e.from[1]
+			if dx*ey-dy*ex > 0 {
+				best = i
+			}
+		}
+		e := candidates[best]
+		candidates[best] = candidates[len(candidates)-1]
+		outgoing[v] = candidates[:len(candidates)-1]
+		if len(outgoing[v]) == 0 {
+			delete(outgoing, v)
+		}
+		return e
+	}
+
+	var exteriors, holes []Ring
+	for len(outgoing) > 0 {
+		var start vertex
+		for v := range outgoing {
+			start = v
+			break
+		}
+
+		first := take(start, 0, 0)
+		fx, fy := first.to[0]-first.from[0], first.to[1]-first.from[1]
+		ring := Ring{{float64(start[0]), float64(start[1])}}
+
+		e, dx, dy := first, fx, fy
+		for {
+			// Back at the start, close unless the left-turn rule picks another edge
+			if e.to == start && (dx*fy-dy*fx > 0 || !turnsLeft(outgoing[start], dx, dy)) {
+				break
+			}
+			next := take(e.to, dx, dy)
+			ndx, ndy := next.to[0]-next.from[0], next.to[1]-next.from[1]
+			if ndx != dx || ndy != dy {
+				ring = append(ring, Point{float64(next.from[0]), float64(next.from[1])})
+			}
+			e, dx, dy = next, ndx, ndy
+		}
+		ring = append(ring, ring[0])
+
+		if ring.signedArea() > 0 {
+			exteriors = append(exteriors, ring)
+		} else {
+			holes = append(holes, ring)
+		}
+	}
+
+	polygons := make([]Polygon, len(exteriors))
+	for i, exterior := range exteriors {
+		polygons[i] = Polygon{exterior}
+	}
+
+	// A hole belongs to the smallest exterior around the cell on its left
+	for _, hole := range holes {
+		a, b := hole[0], hole[1]
+		dx, dy := math.Copysign(math.Min(math.Abs(b[0]-a[0]), 1), b[0]-a[0]), math.Copysign(math.Min(math.Abs(b[1]-a[1]), 1), b[1]-a[1])
+		probe := Point{a[0] + dx/2 - dy/2, a[1] + dy/2 + dx/2}
+
+		owner := -1
+		for i, exterior := range exteriors {
+			if exterior.Contains(probe) && (owner < 0 || exterior.signedArea() < exteriors[owner].signedArea()) {
+				owner = i
+			}
+		}
+		if owner >= 0 {
+			polygons[owner] = append(polygons[owner], hole)
+		}
+	}
+
+	return polygons
+}
+
+// signedArea is the planar area of the ring, positive when counter-clockwise
+func (r Ring) signedArea() float64 {
+	var twice float64
+	n := len(r)
+	for i := 0; i < n; i++ {
+		a, b := r[i], r[(i+1)%n]
+		twice += a[0]*b[1] - b[0]*a[1]
+	}
+	return twice / 2
+}
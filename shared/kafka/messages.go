@@ -0,0 +1,25 @@
+package kafka
+
+import "wildfire-risk-platform/shared/risk"
+
+// ProcessTask is published on TopicProcessTasks once a job's assets are
+// stored and its DEM is on the shared volume; the processing service stores
+// each asset's normalised risk components and answers with an
+// AnalyticsResult. The orchestrator then scores the components through the
+// model named here.
+type ProcessTask struct {
+	JobID          string          `json:"job_id"`
+	AOIGeoJSON     string          `json:"aoi_geojson"`
+	DEMPath        string          `json:"dem_path"`
+	RiskParameters risk.Parameters `json:"risk_parameters"`
+	ModelName      string          `json:"model_name"`
+	ModelVersion   string          `json:"model_version"`
+}
+
+// AnalyticsResult is published on TopicAnalyticsResults when a job's assets
+// have been scored, or scoring failed
+type AnalyticsResult struct {
+	JobID   string `json:"job_id"`
+	Status  string `json:"status"` // COMPLETE or FAILED
+	Message string `json:"message,omitempty"`
+}
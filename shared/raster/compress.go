@@ -0,0 +1,431 @@
+package raster
+
+import (
+	"bytes"
+	"compress/zlib"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+)
+
+// Predictors applied before compression
+const (
+	PredictorNone          = 1
+	PredictorHorizontal    = 2 // Integer samples
+	PredictorFloatingPoint = 3 // Float samples
+)
+
+// decompress expands one block. size is the decoded length expected.
+func decompress(c Compression, data []byte, size int) ([]byte, error) {
+	var out []byte
+	var err error
+	switch c {
+	case CompressionNone:
+		out = data
+	case CompressionDeflate, compressionAdobeDeflate:
+		var zr io.ReadCloser
+		zr, err = zlib.NewReader(bytes.NewReader(data))
+		if err == nil {
+			out, err = io.ReadAll(io.LimitReader(zr, int64(size)))
+			zr.Close()
+		}
+	case CompressionLZW:
+		out, err = lzwDecode(data, size)
+	case CompressionPackBits:
+		out, err = packBitsDecode(data, size)
+	default:
+		return nil, fmt.Errorf("unsupported compression %s", c)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to decompress %s block: %w", c, err)
+	}
+
+	// Writers may drop trailing bytes of the last block; treat them as zero
+	if len(out) < size {
+		out = append(out, make([]byte, size-len(out))...)
+	}
+	return out[:size], nil
+}
+
+// compress packs one block
+func compress(c Compression, data []byte) ([]byte, error) {
+	switch c {
+	case CompressionNone:
+		return data, nil
+	case CompressionDeflate:
+		var buf bytes.Buffer
+		zw := zlib.NewWriter(&buf)
+		if _, err := zw.Write(data); err != nil {
+			return nil, err
+		}
+		if err := zw.Close(); err != nil {
+			return nil, err
+		}
+		return buf.Bytes(), nil
+	case CompressionLZW:
+		return lzwEncode(data), nil
+	case CompressionPackBits:
+		return packBitsEncode(data), nil
+	default:
+		return nil, fmt.Errorf("unsupported compression %s", c)
+	}
+}
+
+// TIFF LZW differs from compress/lzw: codes are MSB first and widen one code
+// early, so it is implemented here
+const (
+	lzwClear    = 256
+	lzwEOI      = 257
+	lzwFirst    = 258
+	lzwMaxCodes = 4096
+)
+
+// lzwDecodeWidth is the code width the decoder reads with next codes in use
+func lzwDecodeWidth(next int) uint {
+	switch {
+	case next < 511:
+		return 9
+	case next < 1023:
+		return 10
+	case next < 2047:
+		return 11
+	default:
+		return 12
+	}
+}
+
+func lzwDecode(data []byte, size int) ([]byte, error) {
+	out := make([]byte, 0, size)
+	table := make([][]byte, lzwMaxCodes)
+	for i := 0; i < 256; i++ {
+		table[i] = []byte{byte(i)}
+	}
+
+	var bits uint64
+	var nbits uint
+	pos := 0
+	next := lzwFirst
+	prev := -1
+
+	for len(out) < size {
+		width := lzwDecodeWidth(next)
+		for nbits < width {
+			if pos >= len(data) {
+				return out, nil // No EOI; keep what was decoded
+			}
+			bits = bits<<8 | uint64(data[pos])
+			pos++
+			nbits += 8
+		}
+		code := int(bits>>(nbits-width)) & (1<<width - 1)
+		nbits -= width
+
+		switch {
+		case code == lzwEOI:
+			return out, nil
+		case code == lzwClear:
+			next = lzwFirst
+			prev = -1
+			continue
+		case prev < 0:
+			if code >= 256 {
+				return nil, fmt.Errorf("invalid LZW code %d after clear", code)
+			}
+			out = append(out, byte(code))
+			prev = code
+			continue
+		}
+
+		var entry []byte
+		switch {
+		case code < next:
+			entry = table[code]
+		case code == next:
+			entry = append(append([]byte{}, table[prev]...), table[prev][0])
+		default:
+			return nil, fmt.Errorf("invalid LZW code %d", code)
+		}
+		out = append(out, entry...)
+
+		if next < lzwMaxCodes {
+			added := make([]byte, len(table[prev])+1)
+			copy(added, table[prev])
+			added[len(added)-1] = entry[0]
+			table[next] = added
+			next++
+		}
+		prev = code
+	}
+
+	return out, nil
+}
+
+// lzwEncodeWidth is the code width the encoder writes with next codes in
+// use; it runs one entry ahead of the decoder
+func lzwEncodeWidth(next int) uint {
+	switch {
+	case next <= 511:
+		return 9
+	case next <= 1023:
+		return 10
+	case next <= 2047:
+		return 11
+	default:
+		return 12
+	}
+}
+
+func lzwEncode(data []byte) []byte {
+	var out bytes.Buffer
+	var bits uint64
+	var nbits uint
+	emit := func(code int, width uint) {
+		bits = bits<<width | uint64(code)
+		nbits += width
+		for nbits >= 8 {
+			out.WriteByte(byte(bits >> (nbits - 8)))
+			nbits -= 8
+		}
+	}
+
+	table := make(map[int]int)
+	next := lzwFirst
+	emit(lzwClear, lzwEncodeWidth(next))
+
+	if len(data) > 0 {
+		w := int(data[0])
+		for _, c := range data[1:] {
+			key := w<<8 | int(c)
+			if code, ok := table[key]; ok {
+				w = code
+				continue
+			}
+			emit(w, lzwEncodeWidth(next))
+			table[key] = next
+			next++
+			// Clear before the decoder's table would overflow
+			if next == lzwMaxCodes-3 {
+				emit(lzwClear, lzwEncodeWidth(next))
+				table = make(map[int]int)
+				next = lzwFirst
+			}
+			w = int(c)
+		}
+		emit(w, lzwEncodeWidth(next))
+		next++
+	}
+
+	emit(lzwEOI, lzwEncodeWidth(next))
+	if nbits > 0 {
+		out.WriteByte(byte(bits << (8 - nbits)))
+	}
+	return out.Bytes()
+}
+
+func packBitsDecode(data []byte, size int) ([]byte, error) {
+	out := make([]byte, 0, size)
+	for i := 0; i < len(data) && len(out) < size; {
+		n := int(int8(data[i]))
+		i++
+		switch {
+		case n >= 0:
+			if i+n+1 > len(data) {
+				return nil, fmt.Errorf("truncated PackBits literal")
+			}
+			out = append(out, data[i:i+n+1]...)
+			i += n + 1
+		case n != -128:
+			if i >= len(data) {
+				return nil, fmt.Errorf("truncated PackBits run")
+			}
+			out = append(out, bytes.Repeat(data[i:i+1], 1-n)...)
+			i++
+		}
+	}
+	return out, nil
+}
+
+func packBitsEncode(data []byte) []byte {
+	var out bytes.Buffer
+	for i := 0; i < len(data); {
+		run := 1
+		for i+run < len(data) && run < 128 && data[i+run] == data[i] {
+			run++
+		}
+		if run > 1 {
+			out.WriteByte(byte(int8(1 - run)))
+			out.WriteByte(data[i])
+			i += run
+			continue
+		}
+
+		// Literal up to the next run of three or more
+		start := i
+		for i < len(data) && i-start < 128 {
+			if i+2 < len(data) && data[i] == data[i+1] && data[i] == data[i+2] {
+				break
+			}
+			i++
+		}
+		out.WriteByte(byte(i - start - 1))
+		out.Write(data[start:i])
+	}
+	return out.Bytes()
+}
+
+// unpredict undoes a predictor in place on a decoded block of rows, each
+// width pixels of spp samples of size bytes, leaving samples in file order
+func unpredict(predictor int, block []byte, order binary.ByteOrder, width, spp, size int) error {
+	rowBytes := width * spp * size
+	switch predictor {
+	case PredictorNone:
+		return nil
+	case PredictorHorizontal:
+		for row := 0; row+rowBytes <= len(block); row += rowBytes {
+			line := block[row : row+rowBytes]
+			for i := spp; i < width*spp; i++ {
+				putUint(line[i*size:], order, size, getUint(line[i*size:], order, size)+getUint(line[(i-spp)*size:], order, size))
+			}
+		}
+		return nil
+	case PredictorFloatingPoint:
+		tmp := make([]byte, rowBytes)
+		n := width * spp
+		for row := 0; row+rowBytes <= len(block); row += rowBytes {
+			line := block[row : row+rowBytes]
+			for i := spp; i < rowBytes; i++ {
+				line[i] += line[i-spp]
+			}
+			// Bytes were split into planes, most significant first
+			copy(tmp, line)
+			for s := 0; s < n; s++ {
+				for k := 0; k < size; k++ {
+					b := tmp[k*n+s]
+					if order == binary.BigEndian {
+						line[s*size+k] = b
+					} else {
+						line[s*size+size-1-k] = b
+					}
+				}
+			}
+		}
+		return nil
+	default:
+		return fmt.Errorf("unsupported predictor %d", predictor)
+	}
+}
+
+// predict applies a predictor in place; the inverse of unpredict
+func predict(predictor int, block []byte, order binary.ByteOrder, width, spp, size int) error {
+	rowBytes := width * spp * size
+	switch predictor {
+	case PredictorNone:
+		return nil
+	case PredictorHorizontal:
+		for row := 0; row+rowBytes <= len(block); row += rowBytes {
+			line := block[row : row+rowBytes]
+			for i := width*spp - 1; i >= spp; i-- {
+				putUint(line[i*size:], order, size, getUint(line[i*size:], order, size)-getUint(line[(i-spp)*size:], order, size))
+			}
+		}
+		return nil
+	case PredictorFloatingPoint:
+		tmp := make([]byte, rowBytes)
+		n := width * spp
+		for row := 0; row+rowBytes <= len(block); row += rowBytes {
+			line := block[row : row+rowBytes]
+			for s := 0; s < n; s++ {
+				for k := 0; k < size; k++ {
+					if order == binary.BigEndian {
+						tmp[k*n+s] = line[s*size+k]
+					} else {
+						tmp[k*n+s] = line[s*size+size-1-k]
+					}
+				}
+			}
+			copy(line, tmp)
+			for i := rowBytes - 1; i >= spp; i-- {
+				line[i] -= line[i-spp]
+			}
+		}
+		return nil
+	default:
+		return fmt.Errorf("unsupported predictor %d", predictor)
+	}
+}
+
+func getUint(b []byte, order binary.ByteOrder, size int) uint64 {
+	switch size {
+	case 1:
+		return uint64(b[0])
+	case 2:
+		return uint64(order.Uint16(b))
+	case 4:
+		return uint64(order.Uint32(b))
+	default:
+		return order.Uint64(b)
+	}
+}
+
+func putUint(b []byte, order binary.ByteOrder, size int, v uint64) {
+	switch size {
+	case 1:
+		b[0] = byte(v)
+	case 2:
+		order.PutUint16(b, uint16(v))
+	case 4:
+		order.PutUint32(b, uint32(v))
+	default:
+		order.PutUint64(b, v)
+	}
+}
+
+// decodeSample reads one sample stored in file order
+func decodeSample(b []byte, order binary.ByteOrder, t DataType) float64 {
+	switch t {
+	case Uint8:
+		return float64(b[0])
+	case Int16:
+		return float64(int16(order.Uint16(b)))
+	case Uint16:
+		return float64(order.Uint16(b))
+	case Int32:
+		return float64(int32(order.Uint32(b)))
+	case Uint32:
+		return float64(order.Uint32(b))
+	case Float32:
+		return float64(math.Float32frombits(order.Uint32(b)))
+	default:
+		return math.Float64frombits(order.Uint64(b))
+	}
+}
+
+// encodeSample writes one sample in file order. NaN in an integer raster is
+// stored as the nodata value, or zero without one.
+func encodeSample(b []byte, order binary.ByteOrder, t DataType, v float64, nodata *float64) {
+	if math.IsNaN(v) && !t.IsFloat() {
+		v = 0
+		if nodata != nil {
+			v = *nodata
+		}
+	}
+	v = t.clamp(v)
+
+	switch t {
+	case Uint8:
+		b[0] = byte(v)
+	case Int16:
+		order.PutUint16(b, uint16(int16(v)))
+	case Uint16:
+		order.PutUint16(b, uint16(v))
+	case Int32:
+		order.PutUint32(b, uint32(int32(v)))
+	case Uint32:
+		order.PutUint32(b, uint32(v))
+	case Float32:
+		order.PutUint32(b, math.Float32bits(float32(v)))
+	default:
+		order.PutUint64(b, math.Float64bits(v))
+	}
+}
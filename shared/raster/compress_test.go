@@ -0,0 +1,142 @@
+package raster
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"math/rand"
+	"testing"
+
+	"golang.org/x/image/tiff/lzw"
+)
+
+// compressible returns n bytes of short repeated runs with some noise, so
+// the LZW table fills and is cleared several times
+func compressible(n int) []byte {
+	rng := rand.New(rand.NewSource(1))
+	data := make([]byte, n)
+	for i := range data {
+		if rng.Intn(4) == 0 {
+			data[i] = byte(rng.Intn(256))
+		} else {
+			data[i] = byte(i / 7 % 16)
+		}
+	}
+	return data
+}
+
+func TestLZW(t *testing.T) {
+	tests := []struct {
+		name string
+		data []byte
+	}{
+		{name: "empty", data: []byte{}},
+		{name: "one byte", data: []byte{42}},
+		{name: "repeated byte", data: bytes.Repeat([]byte{7}, 10000)},
+		{name: "KwKwK", data: []byte("abababababababab")},
+		{name: "table resets", data: compressible(200000)},
+		{name: "random", data: func() []byte {
+			data := make([]byte, 50000)
+			rand.New(rand.NewSource(2)).Read(data)
+			return data
+		}()},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			encoded := lzwEncode(tt.data)
+
+			decoded, err := lzwDecode(encoded, len(tt.data))
+			if err != nil {
+				t.Fatalf("lzwDecode: %v", err)
+			}
+			if !bytes.Equal(decoded, tt.data) {
+				t.Fatalf("lzwDecode returned %d bytes that differ from the input", len(decoded))
+			}
+
+			// An independent TIFF LZW decoder must agree
+			r := lzw.NewReader(bytes.NewReader(encoded), lzw.MSB, 8)
+			defer r.Close()
+			reference, err := io.ReadAll(r)
+			if err != nil {
+				t.Fatalf("x/image/tiff/lzw: %v", err)
+			}
+			if !bytes.Equal(reference, tt.data) {
+				t.Fatalf("x/image/tiff/lzw decoded %d bytes that differ from the input", len(reference))
+			}
+		})
+	}
+}
+
+func TestPackBits(t *testing.T) {
+	// The example from the TIFF 6.0 specification, section 9
+	packed := []byte{0xFE, 0xAA, 0x02, 0x80, 0x00, 0x2A, 0xFD, 0xAA, 0x03, 0x80, 0x00, 0x2A, 0x22, 0xF7, 0xAA}
+	unpacked := []byte{
+		0xAA, 0xAA, 0xAA, 0x80, 0x00, 0x2A, 0xAA, 0xAA, 0xAA, 0xAA, 0x80, 0x00,
+		0x2A, 0x22, 0xAA, 0xAA, 0xAA, 0xAA, 0xAA, 0xAA, 0xAA, 0xAA, 0xAA, 0xAA,
+	}
+	decoded, err := packBitsDecode(packed, len(unpacked))
+	if err != nil {
+		t.Fatalf("packBitsDecode: %v", err)
+	}
+	if !bytes.Equal(decoded, unpacked) {
+		t.Errorf("decoded % X, want % X", decoded, unpacked)
+	}
+
+	for _, data := range [][]byte{
+		unpacked,
+		bytes.Repeat([]byte{1}, 300),    // Runs longer than 128
+		bytes.Repeat([]byte{1, 2}, 300), // Literals longer than 128
+		compressible(5000),
+	} {
+		encoded := packBitsEncode(data)
+		decoded, err := packBitsDecode(encoded, len(data))
+		if err != nil {
+			t.Fatalf("packBitsDecode: %v", err)
+		}
+		if !bytes.Equal(decoded, data) {
+			t.Errorf("round trip of %d bytes differs", len(data))
+		}
+	}
+
+	if _, err := packBitsDecode([]byte{0x05, 1, 2}, 6); err == nil {
+		t.Error("packBitsDecode accepted a truncated literal")
+	}
+}
+
+func TestPredictors(t *testing.T) {
+	tests := []struct {
+		name      string
+		predictor int
+		order     binary.ByteOrder
+		size      int
+	}{
+		{name: "horizontal 8 bit", predictor: PredictorHorizontal, order: binary.LittleEndian, size: 1},
+		{name: "horizontal 16 bit big-endian", predictor: PredictorHorizontal, order: binary.BigEndian, size: 2},
+		{name: "horizontal 32 bit", predictor: PredictorHorizontal, order: binary.LittleEndian, size: 4},
+		{name: "floating point 32 bit", predictor: PredictorFloatingPoint, order: binary.LittleEndian, size: 4},
+		{name: "floating point 64 bit big-endian", predictor: PredictorFloatingPoint, order: binary.BigEndian, size: 8},
+	}
+
+	const width, spp, rows = 13, 3, 4
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			block := make([]byte, width*spp*tt.size*rows)
+			rand.New(rand.NewSource(3)).Read(block)
+			original := append([]byte{}, block...)
+
+			if err := predict(tt.predictor, block, tt.order, width, spp, tt.size); err != nil {
+				t.Fatal(err)
+			}
+			if bytes.Equal(block, original) {
+				t.Fatal("predict left the block unchanged")
+			}
+			if err := unpredict(tt.predictor, block, tt.order, width, spp, tt.size); err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(block, original) {
+				t.Error("unpredict did not restore the block")
+			}
+		})
+	}
+}
@@ -0,0 +1,251 @@
+package raster
+
+import (
+	"errors"
+	"fmt"
+	"math"
+)
+
+// EPSG codes of the coordinate systems rasters can use. NAD83, which USGS
+// 3DEP uses, is treated as WGS 84; the two differ by about a metre.
+const (
+	EPSGWGS84         = 4326
+	EPSGNAD83         = 4269
+	epsgUTMNorthFirst = 32601
+	epsgUTMSouthFirst = 32701
+	epsgNAD83UTMFirst = 26901
+	utmZones          = 60
+	nad83UTMZones     = 23
+)
+
+// ErrNoOverlap is returned for a window or bbox entirely outside the raster
+var ErrNoOverlap = errors.New("area does not overlap the raster")
+
+// GeoTransform maps pixels to coordinates. The origin is the outer corner of
+// the top-left pixel; rows run south, so PixelHeight is positive.
+type GeoTransform struct {
+	OriginX     float64
+	OriginY     float64
+	PixelWidth  float64
+	PixelHeight float64
+}
+
+// PixelToWorld returns the coordinates of a pixel position. Whole numbers
+// are pixel corners; add 0.5 for centres.
+func (g GeoTransform) PixelToWorld(col, row float64) (x, y float64) {
+	return g.OriginX + col*g.PixelWidth, g.OriginY - row*g.PixelHeight
+}
+
+// WorldToPixel returns the fractional pixel position of coordinates
+func (g GeoTransform) WorldToPixel(x, y float64) (col, row float64) {
+	return (x - g.OriginX) / g.PixelWidth, (g.OriginY - y) / g.PixelHeight
+}
+
+func (g GeoTransform) bounds(width, height int) (minX, minY, maxX, maxY float64) {
+	maxX, minY = g.PixelToWorld(float64(width), float64(height))
+	return g.OriginX, minY, maxX, g.OriginY
+}
+
+// window returns the transform of a window's top-left pixel
+func (g GeoTransform) window(w Window) GeoTransform {
+	g.OriginX, g.OriginY = g.PixelToWorld(float64(w.X), float64(w.Y))
+	return g
+}
+
+// UTMEPSG returns the EPSG code of a WGS 84 UTM zone
+func UTMEPSG(zone int, north bool) int {
+	if north {
+		return epsgUTMNorthFirst + zone - 1
+	}
+	return epsgUTMSouthFirst + zone - 1
+}
+
+// UTMZone returns the zone and hemisphere of a WGS 84 or NAD83 UTM EPSG code
+func UTMZone(epsg int) (zone int, north bool, ok bool) {
+	switch {
+	case epsg >= epsgNAD83UTMFirst && epsg < epsgNAD83UTMFirst+nad83UTMZones:
+		return epsg - epsgNAD83UTMFirst + 1, true, true
+	case epsg >= epsgUTMNorthFirst && epsg < epsgUTMNorthFirst+utmZones:
+		return epsg - epsgUTMNorthFirst + 1, true, true
+	case epsg >= epsgUTMSouthFirst && epsg < epsgUTMSouthFirst+utmZones:
+		return epsg - epsgUTMSouthFirst + 1, false, true
+	default:
+		return 0, false, false
+	}
+}
+
+// UTMZoneFor returns the standard UTM zone of a WGS 84 position, ignoring
+// the Norway and Svalbard exceptions
+func UTMZoneFor(lon, lat float64) int {
+	zone := int(math.Floor((lon+180)/6)) + 1
+	if zone > utmZones {
+		zone = utmZones
+	}
+	if zone < 1 {
+		zone = 1
+	}
+	return zone
+}
+
+func supportedEPSG(epsg int) bool {
+	_, _, utm := UTMZone(epsg)
+	return isGeographic(epsg) || utm
+}
+
+// isGeographic reports whether coordinates are longitude and latitude
+func isGeographic(epsg int) bool {
+	return epsg == EPSGWGS84 || epsg == EPSGNAD83
+}
+
+// Project converts a WGS 84 position into a raster's coordinate system
+func Project(epsg int, lon, lat float64) (x, y float64, err error) {
+	if isGeographic(epsg) {
+		return lon, lat, nil
+	}
+	zone, north, ok := UTMZone(epsg)
+	if !ok {
+		return 0, 0, fmt.Errorf("unsupported coordinate system EPSG:%d", epsg)
+	}
+	x, y = utmForward(zone, north, lon, lat)
+	return x, y, nil
+}
+
+// Unproject converts coordinates in a raster's coordinate system to WGS 84
+func Unproject(epsg int, x, y float64) (lon, lat float64, err error) {
+	if isGeographic(epsg) {
+		return x, y, nil
+	}
+	zone, north, ok := UTMZone(epsg)
+	if !ok {
+		return 0, 0, fmt.Errorf("unsupported coordinate system EPSG:%d", epsg)
+	}
+	lon, lat = utmInverse(zone, north, x, y)
+	return lon, lat, nil
+}
+
+// bboxWindow returns the pixels covering a WGS 84 bbox, clipped to the
+// raster. The bbox edges are sampled so projected rasters get the whole
+// curved outline.
+func bboxWindow(epsg int, g GeoTransform, width, height int, b BBox) (Window, error) {
+	if !(b.MinLon < b.MaxLon && b.MinLat < b.MaxLat) {
+		return Window{}, fmt.Errorf("invalid bbox %v", b)
+	}
+
+	const steps = 16
+	minX, minY := math.Inf(1), math.Inf(1)
+	maxX, maxY := math.Inf(-1), math.Inf(-1)
+	for i := 0; i <= steps; i++ {
+		t := float64(i) / steps
+		lon := b.MinLon + t*(b.MaxLon-b.MinLon)
+		lat := b.MinLat + t*(b.MaxLat-b.MinLat)
+		for _, p := range [4][2]float64{{lon, b.MinLat}, {lon, b.MaxLat}, {b.MinLon, lat}, {b.MaxLon, lat}} {
+			x, y, err := Project(epsg, p[0], p[1])
+			if err != nil {
+				return Window{}, err
+			}
+			minX, maxX = math.Min(minX, x), math.Max(maxX, x)
+			minY, maxY = math.Min(minY, y), math.Max(maxY, y)
+		}
+	}
+
+	col0, row0 := g.WorldToPixel(minX, maxY)
+	col1, row1 := g.WorldToPixel(maxX, minY)
+	return clipWindow(Window{
+		X:      int(math.Floor(col0)),
+		Y:      int(math.Floor(row0)),
+		Width:  int(math.Ceil(col1)) - int(math.Floor(col0)),
+		Height: int(math.Ceil(row1)) - int(math.Floor(row0)),
+	}, width, height)
+}
+
+// clipWindow trims a window to the raster
+func clipWindow(w Window, width, height int) (Window, error) {
+	x0, y0 := max(w.X, 0), max(w.Y, 0)
+	x1, y1 := min(w.X+w.Width, width), min(w.Y+w.Height, height)
+	if x1 <= x0 || y1 <= y0 {
+		return Window{}, ErrNoOverlap
+	}
+	return Window{X: x0, Y: y0, Width: x1 - x0, Height: y1 - y0}, nil
+}
+
+// WGS 84 transverse Mercator as used by UTM, by Krüger's series to third
+// order in n, accurate to well under a millimetre within a zone
+const (
+	wgs84A       = 6378137.0
+	wgs84F       = 1 / 298.257223563
+	utmScale     = 0.9996
+	utmEasting   = 500000.0
+	utmNorthingS = 10000000.0
+)
+
+var (
+	tmN     = wgs84F / (2 - wgs84F)
+	tmE     = 2 * math.Sqrt(tmN) / (1 + tmN)
+	tmA     = wgs84A / (1 + tmN) * (1 + tmN*tmN/4 + math.Pow(tmN, 4)/64)
+	tmAlpha = [3]float64{
+		tmN/2 - 2*tmN*tmN/3 + 5*math.Pow(tmN, 3)/16,
+		13*tmN*tmN/48 - 3*math.Pow(tmN, 3)/5,
+		61 * math.Pow(tmN, 3) / 240,
+	}
+	tmBeta = [3]float64{
+		tmN/2 - 2*tmN*tmN/3 + 37*math.Pow(tmN, 3)/96,
+		tmN*tmN/48 + math.Pow(tmN, 3)/15,
+		17 * math.Pow(tmN, 3) / 480,
+	}
+	tmDelta = [3]float64{
+		2*tmN - 2*tmN*tmN/3 - 2*math.Pow(tmN, 3),
+		7*tmN*tmN/3 - 8*math.Pow(tmN, 3)/5,
+		56 * math.Pow(tmN, 3) / 15,
+	}
+)
+
+func utmCentralMeridian(zone int) float64 {
+	return float64(zone-1)*6 - 180 + 3
+}
+
+func utmForward(zone int, north bool, lon, lat float64) (easting, northing float64) {
+	phi := lat * math.Pi / 180
+	lambda := (lon - utmCentralMeridian(zone)) * math.Pi / 180
+
+	t := math.Sinh(math.Atanh(math.Sin(phi)) - tmE*math.Atanh(tmE*math.Sin(phi)))
+	xi := math.Atan2(t, math.Cos(lambda))
+	eta := math.Atanh(math.Sin(lambda) / math.Sqrt(1+t*t))
+
+	e, n := eta, xi
+	for j, alpha := range tmAlpha {
+		k := float64(2 * (j + 1))
+		e += alpha * math.Cos(k*xi) * math.Sinh(k*eta)
+		n += alpha * math.Sin(k*xi) * math.Cosh(k*eta)
+	}
+
+	easting = utmEasting + utmScale*tmA*e
+	northing = utmScale * tmA * n
+	if !north {
+		northing += utmNorthingS
+	}
+	return easting, northing
+}
+
+func utmInverse(zone int, north bool, easting, northing float64) (lon, lat float64) {
+	if !north {
+		northing -= utmNorthingS
+	}
+	xi := northing / (utmScale * tmA)
+	eta := (easting - utmEasting) / (utmScale * tmA)
+
+	xiP, etaP := xi, eta
+	for j, beta := range tmBeta {
+		k := float64(2 * (j + 1))
+		xiP -= beta * math.Sin(k*xi) * math.Cosh(k*eta)
+		etaP -= beta * math.Cos(k*xi) * math.Sinh(k*eta)
+	}
+
+	chi := math.Asin(math.Sin(xiP) / math.Cosh(etaP))
+	phi := chi
+	for j, delta := range tmDelta {
+		phi += delta * math.Sin(float64(2*(j+1))*chi)
+	}
+
+	lon = utmCentralMeridian(zone) + math.Atan2(math.Sinh(etaP), math.Cos(xiP))*180/math.Pi
+	return lon, phi * 180 / math.Pi
+}
@@ -0,0 +1,107 @@
+package raster
+
+import (
+	"math"
+	"testing"
+)
+
+func TestUTM(t *testing.T) {
+	tests := []struct {
+		name     string
+		lon, lat float64
+		zone     int
+		north    bool
+		xy       *[2]float64 // Expected easting and northing, if known exactly
+	}{
+		{name: "equator north", lon: 3, lat: 0, zone: 31, north: true, xy: &[2]float64{500000, 0}},
+		{name: "equator south", lon: 3, lat: -1e-12, zone: 31, north: false, xy: &[2]float64{500000, 10000000}},
+		{name: "central meridian", lon: -123, lat: 37, zone: 10, north: true},
+		{name: "off the central meridian", lon: -121.1, lat: 38.9, zone: 10, north: true},
+		{name: "southern hemisphere", lon: 151.2, lat: -33.9, zone: 56, north: false},
+		{name: "far north", lon: 25.5, lat: 70.5, zone: 35, north: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if zone := UTMZoneFor(tt.lon, tt.lat); zone != tt.zone {
+				t.Fatalf("zone %d, want %d", zone, tt.zone)
+			}
+			epsg := UTMEPSG(tt.zone, tt.north)
+			if zone, north, ok := UTMZone(epsg); !ok || zone != tt.zone || north != tt.north {
+				t.Fatalf("UTMZone(%d) = %d, %v, %v", epsg, zone, north, ok)
+			}
+
+			x, y, err := Project(epsg, tt.lon, tt.lat)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if tt.xy != nil && (math.Abs(x-tt.xy[0]) > 1e-3 || math.Abs(y-tt.xy[1]) > 1e-3) {
+				t.Errorf("projected to %v, %v, want %v", x, y, *tt.xy)
+			}
+			if zone, _, _ := UTMZone(epsg); tt.lon == utmCentralMeridian(zone) && math.Abs(x-500000) > 1e-6 {
+				t.Errorf("easting on the central meridian is %v, want 500000", x)
+			}
+
+			lon, lat, err := Unproject(epsg, x, y)
+			if err != nil {
+				t.Fatal(err)
+			}
+			// 1e-8 degrees is about a millimetre
+			if math.Abs(lon-tt.lon) > 1e-8 || math.Abs(lat-tt.lat) > 1e-8 {
+				t.Errorf("round trip gave %v, %v, want %v, %v", lon, lat, tt.lon, tt.lat)
+			}
+		})
+	}
+
+	// A degree of latitude along the central meridian is 110.57 km at the
+	// equator and 111.69 km at the pole, scaled by 0.9996
+	_, y0, _ := Project(UTMEPSG(10, true), -123, 37)
+	_, y1, _ := Project(UTMEPSG(10, true), -123, 38)
+	if d := (y1 - y0) / 0.9996; d < 110900 || d > 111100 {
+		t.Errorf("a degree of latitude at 37N is %.0f m", d)
+	}
+
+	// NAD83 zones are read as their WGS 84 equivalents
+	if zone, north, ok := UTMZone(26910); !ok || zone != 10 || !north {
+		t.Errorf("UTMZone(26910) = %d, %v, %v", zone, north, ok)
+	}
+	if _, _, err := Project(3857, 0, 0); err == nil {
+		t.Error("Project accepted EPSG:3857")
+	}
+}
+
+func TestValue(t *testing.T) {
+	r := New(4, 4, 1, Float32)
+	r.EPSG = EPSGWGS84
+	r.Transform = GeoTransform{OriginX: 0, OriginY: 4, PixelWidth: 1, PixelHeight: 1}
+	for y := 0; y < 4; y++ {
+		for x := 0; x < 4; x++ {
+			r.Set(0, x, y, float64(10*x))
+		}
+	}
+	nodata := -1.0
+	r.NoData = &nodata
+	r.Set(0, 3, 3, nodata)
+
+	tests := []struct {
+		name     string
+		lon, lat float64
+		want     float64
+		ok       bool
+	}{
+		{name: "pixel centre", lon: 1.5, lat: 3.5, want: 10, ok: true},
+		{name: "between centres", lon: 2, lat: 2.5, want: 15, ok: true},
+		{name: "next to nodata", lon: 2.6, lat: 0.6, want: 20, ok: true},
+		{name: "on nodata", lon: 3.5, lat: 0.5},
+		{name: "outside", lon: 5, lat: 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v, ok := r.Value(0, tt.lon, tt.lat)
+			if ok != tt.ok || (ok && math.Abs(v-tt.want) > 1e-9) {
+				t.Errorf("got %v, %v, want %v, %v", v, ok, tt.want, tt.ok)
+			}
+		})
+	}
+}
@@ -0,0 +1,235 @@
+// Package raster reads and writes single- and multi-band GeoTIFFs, including
+// Cloud Optimized GeoTIFFs, without cgo. Stripped and tiled layouts are
+// supported, uncompressed or with deflate, LZW or PackBits compression, in
+// geographic coordinates or a UTM zone on WGS 84 or NAD83.
+package raster
+
+import (
+	"fmt"
+	"math"
+)
+
+// DataType is how a raster's samples are stored on disk. Samples are held in
+// memory as float64 whatever their type.
+type DataType int
+
+const (
+	Uint8 DataType = iota + 1
+	Int16
+	Uint16
+	Int32
+	Uint32
+	Float32
+	Float64
+)
+
+func (t DataType) String() string {
+	switch t {
+	case Uint8:
+		return "uint8"
+	case Int16:
+		return "int16"
+	case Uint16:
+		return "uint16"
+	case Int32:
+		return "int32"
+	case Uint32:
+		return "uint32"
+	case Float32:
+		return "float32"
+	case Float64:
+		return "float64"
+	default:
+		return fmt.Sprintf("DataType(%d)", int(t))
+	}
+}
+
+// Size returns the bytes one sample takes
+func (t DataType) Size() int {
+	switch t {
+	case Uint8:
+		return 1
+	case Int16, Uint16:
+		return 2
+	case Int32, Uint32, Float32:
+		return 4
+	case Float64:
+		return 8
+	default:
+		return 0
+	}
+}
+
+// IsFloat reports whether the type stores floating point samples
+func (t DataType) IsFloat() bool {
+	return t == Float32 || t == Float64
+}
+
+// clamp rounds v to the nearest value the type can store
+func (t DataType) clamp(v float64) float64 {
+	var lo, hi float64
+	switch t {
+	case Uint8:
+		lo, hi = 0, math.MaxUint8
+	case Int16:
+		lo, hi = math.MinInt16, math.MaxInt16
+	case Uint16:
+		lo, hi = 0, math.MaxUint16
+	case Int32:
+		lo, hi = math.MinInt32, math.MaxInt32
+	case Uint32:
+		lo, hi = 0, math.MaxUint32
+	case Float32:
+		return float64(float32(v))
+	default:
+		return v
+	}
+	return math.Max(lo, math.Min(hi, math.Round(v)))
+}
+
+// Compression is a TIFF compression scheme
+type Compression int
+
+const (
+	CompressionNone     Compression = 1
+	CompressionLZW      Compression = 5
+	CompressionDeflate  Compression = 8
+	CompressionPackBits Compression = 32773
+
+	// Older writers tag deflate with its pre-standard code; read only
+	compressionAdobeDeflate Compression = 32946
+)
+
+func (c Compression) String() string {
+	switch c {
+	case CompressionNone:
+		return "none"
+	case CompressionLZW:
+		return "lzw"
+	case CompressionDeflate, compressionAdobeDeflate:
+		return "deflate"
+	case CompressionPackBits:
+		return "packbits"
+	default:
+		return fmt.Sprintf("Compression(%d)", int(c))
+	}
+}
+
+// Raster is an in-memory georeferenced image. Bands[b][y*Width+x] holds the
+// sample of band b at column x, row y, with row 0 at the top.
+type Raster struct {
+	Width     int
+	Height    int
+	DataType  DataType
+	NoData    *float64 // Nil if every sample is valid
+	EPSG      int      // 4326, 4269, or a UTM zone (WGS 84 326xx/327xx, NAD83 269xx)
+	Transform GeoTransform
+	Bands     [][]float64
+}
+
+// New allocates a raster with every sample zero
+func New(width, height, bands int, dataType DataType) *Raster {
+	r := &Raster{
+		Width:    width,
+		Height:   height,
+		DataType: dataType,
+		Bands:    make([][]float64, bands),
+	}
+	for b := range r.Bands {
+		r.Bands[b] = make([]float64, width*height)
+	}
+	return r
+}
+
+// At returns the sample of a band at a pixel
+func (r *Raster) At(band, x, y int) float64 {
+	return r.Bands[band][y*r.Width+x]
+}
+
+// Set stores the sample of a band at a pixel
+func (r *Raster) Set(band, x, y int, v float64) {
+	r.Bands[band][y*r.Width+x] = v
+}
+
+// IsNoData reports whether a sample marks a missing value. NaN always does.
+func (r *Raster) IsNoData(v float64) bool {
+	if math.IsNaN(v) {
+		return true
+	}
+	return r.NoData != nil && v == *r.NoData
+}
+
+// Bounds returns the raster's extent in its own coordinate system
+func (r *Raster) Bounds() (minX, minY, maxX, maxY float64) {
+	return r.Transform.bounds(r.Width, r.Height)
+}
+
+// Value interpolates a band bilinearly at a WGS 84 position. Near nodata the
+// nearest sample is used instead; ok is false outside the raster or if that
+// sample is nodata too.
+func (r *Raster) Value(band int, lon, lat float64) (v float64, ok bool) {
+	x, y, err := Project(r.EPSG, lon, lat)
+	if err != nil {
+		return 0, false
+	}
+	return r.ValueAt(band, x, y)
+}
+
+// ValueAt is Value for coordinates already in the raster's coordinate system
+func (r *Raster) ValueAt(band int, x, y float64) (v float64, ok bool) {
+	col, row := r.Transform.WorldToPixel(x, y)
+	if col < 0 || row < 0 || col >= float64(r.Width) || row >= float64(r.Height) {
+		return 0, false
+	}
+
+	// Pixel centres sit at half-integer positions
+	fx, fy := col-0.5, row-0.5
+	x0, y0 := int(math.Floor(fx)), int(math.Floor(fy))
+	tx, ty := fx-float64(x0), fy-float64(y0)
+
+	var sum, weight float64
+	for _, c := range [4]struct {
+		x, y int
+		w    float64
+	}{
+		{x0, y0, (1 - tx) * (1 - ty)},
+		{x0 + 1, y0, tx * (1 - ty)},
+		{x0, y0 + 1, (1 - tx) * ty},
+		{x0 + 1, y0 + 1, tx * ty},
+	} {
+		if c.w == 0 {
+			continue
+		}
+		if c.x < 0 || c.y < 0 || c.x >= r.Width || c.y >= r.Height {
+			weight = -1
+			break
+		}
+		sample := r.At(band, c.x, c.y)
+		if r.IsNoData(sample) {
+			weight = -1
+			break
+		}
+		sum += c.w * sample
+		weight += c.w
+	}
+	if weight > 0 {
+		return sum / weight, true
+	}
+
+	nearest := r.At(band, int(col), int(row))
+	if r.IsNoData(nearest) {
+		return 0, false
+	}
+	return nearest, true
+}
+
+// Window is a rectangle of pixels
+type Window struct {
+	X, Y          int
+	Width, Height int
+}
+
+// BBox is a WGS 84 bounding box
+type BBox struct {
+	MinLon, MinLat, MaxLon, MaxLat float64
+}
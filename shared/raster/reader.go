@@ -0,0 +1,336 @@
+package raster
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// File is an open GeoTIFF. Only the first image is read; COG overviews and
+// masks in later IFDs are ignored. Blocks are fetched on demand, so windowed
+// reads of a large file only touch the blocks they need.
+type File struct {
+	Width       int
+	Height      int
+	Bands       int
+	DataType    DataType
+	Compression Compression
+	NoData      *float64
+	EPSG        int // 0 if the file's coordinate system is not supported
+	Transform   GeoTransform
+	Tiled       bool
+
+	r            io.ReaderAt
+	closer       io.Closer // Set when the file was opened by path
+	order        binary.ByteOrder
+	predictor    int
+	planar       int
+	blockWidth   int
+	blockHeight  int
+	blocksAcross int
+	blocksDown   int
+	offsets      []uint64
+	byteCounts   []uint64
+}
+
+// Open reads a GeoTIFF's header and first IFD
+func Open(r io.ReaderAt) (*File, error) {
+	order, big, offset, err := readHeader(r)
+	if err != nil {
+		return nil, err
+	}
+	d, err := readIFD(r, order, big, offset)
+	if err != nil {
+		return nil, err
+	}
+
+	f := &File{
+		Width:       int(d.uint(tagImageWidth, 0)),
+		Height:      int(d.uint(tagImageLength, 0)),
+		Bands:       int(d.uint(tagSamplesPerPixel, 1)),
+		Compression: Compression(d.uint(tagCompression, uint64(CompressionNone))),
+		r:           r,
+		order:       order,
+		predictor:   int(d.uint(tagPredictor, PredictorNone)),
+		planar:      int(d.uint(tagPlanarConfig, planarChunky)),
+	}
+	if f.Width <= 0 || f.Height <= 0 || f.Bands <= 0 {
+		return nil, fmt.Errorf("invalid image size %dx%d with %d bands", f.Width, f.Height, f.Bands)
+	}
+	if f.planar != planarChunky && f.planar != planarSeparate {
+		return nil, fmt.Errorf("unsupported planar configuration %d", f.planar)
+	}
+
+	if err := f.readDataType(d); err != nil {
+		return nil, err
+	}
+	if err := f.readLayout(d); err != nil {
+		return nil, err
+	}
+	if err := f.readGeoreference(d); err != nil {
+		return nil, err
+	}
+
+	if text := strings.TrimSpace(d.ascii(tagGDALNoData)); text != "" {
+		value, err := strconv.ParseFloat(text, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid nodata value %q", text)
+		}
+		f.NoData = &value
+	}
+
+	return f, nil
+}
+
+// OpenFile opens a GeoTIFF on disk; Close releases it
+func OpenFile(path string) (*File, error) {
+	osFile, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	f, err := Open(osFile)
+	if err != nil {
+		osFile.Close()
+		return nil, fmt.Errorf("failed to open %s: %w", path, err)
+	}
+	f.closer = osFile
+	return f, nil
+}
+
+// Close releases a file opened with OpenFile
+func (f *File) Close() error {
+	if f.closer == nil {
+		return nil
+	}
+	return f.closer.Close()
+}
+
+// ReadFile reads a whole GeoTIFF from disk
+func ReadFile(path string) (*Raster, error) {
+	f, err := OpenFile(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return f.Read()
+}
+
+func (f *File) readDataType(d *ifd) error {
+	bits := d.uints(tagBitsPerSample)
+	if len(bits) == 0 {
+		bits = []uint64{1}
+	}
+	for _, b := range bits {
+		if b != bits[0] {
+			return fmt.Errorf("bands with different sample sizes are not supported")
+		}
+	}
+	format := d.uint(tagSampleFormat, sampleFormatUint)
+
+	switch {
+	case format == sampleFormatUint && bits[0] == 8:
+		f.DataType = Uint8
+	case format == sampleFormatUint && bits[0] == 16:
+		f.DataType = Uint16
+	case format == sampleFormatUint && bits[0] == 32:
+		f.DataType = Uint32
+	case format == sampleFormatInt && bits[0] == 16:
+		f.DataType = Int16
+	case format == sampleFormatInt && bits[0] == 32:
+		f.DataType = Int32
+	case format == sampleFormatFloat && bits[0] == 32:
+		f.DataType = Float32
+	case format == sampleFormatFloat && bits[0] == 64:
+		f.DataType = Float64
+	default:
+		return fmt.Errorf("unsupported sample format %d with %d bits", format, bits[0])
+	}
+	return nil
+}
+
+// readLayout describes strips as blocks the full width of the image, so
+// both layouts are read the same way
+func (f *File) readLayout(d *ifd) error {
+	if d.has(tagTileWidth) {
+		f.Tiled = true
+		f.blockWidth = int(d.uint(tagTileWidth, 0))
+		f.blockHeight = int(d.uint(tagTileLength, 0))
+		f.offsets = d.uints(tagTileOffsets)
+		f.byteCounts = d.uints(tagTileByteCounts)
+	} else {
+		f.blockWidth = f.Width
+		f.blockHeight = int(min(d.uint(tagRowsPerStrip, uint64(f.Height)), uint64(f.Height)))
+		f.offsets = d.uints(tagStripOffsets)
+		f.byteCounts = d.uints(tagStripByteCounts)
+	}
+	if f.blockWidth <= 0 || f.blockHeight <= 0 {
+		return fmt.Errorf("invalid block size %dx%d", f.blockWidth, f.blockHeight)
+	}
+
+	f.blocksAcross = (f.Width + f.blockWidth - 1) / f.blockWidth
+	f.blocksDown = (f.Height + f.blockHeight - 1) / f.blockHeight
+	blocks := f.blocksAcross * f.blocksDown
+	if f.planar == planarSeparate {
+		blocks *= f.Bands
+	}
+	if len(f.offsets) < blocks || len(f.byteCounts) < blocks {
+		return fmt.Errorf("expected %d blocks, found %d offsets and %d byte counts", blocks, len(f.offsets), len(f.byteCounts))
+	}
+	return nil
+}
+
+func (f *File) readGeoreference(d *ifd) error {
+	keys, err := d.geoKeys()
+	if err != nil {
+		return err
+	}
+
+	switch keys[geoKeyModelType] {
+	case modelTypeGeographic:
+		if code := int(keys[geoKeyGeographicType]); isGeographic(code) {
+			f.EPSG = code
+		}
+	case modelTypeProjected:
+		if code := int(keys[geoKeyProjectedType]); supportedEPSG(code) {
+			f.EPSG = code
+		}
+	}
+
+	if matrix := d.floats(tagModelTransform); len(matrix) == 16 {
+		if matrix[1] != 0 || matrix[4] != 0 {
+			return fmt.Errorf("rotated rasters are not supported")
+		}
+		f.Transform = GeoTransform{OriginX: matrix[3], OriginY: matrix[7], PixelWidth: matrix[0], PixelHeight: -matrix[5]}
+	} else {
+		scale := d.floats(tagModelPixelScale)
+		tie := d.floats(tagModelTiepoint)
+		if len(scale) >= 2 && len(tie) >= 6 {
+			f.Transform = GeoTransform{
+				OriginX:     tie[3] - tie[0]*scale[0],
+				OriginY:     tie[4] + tie[1]*scale[1],
+				PixelWidth:  scale[0],
+				PixelHeight: scale[1],
+			}
+		}
+	}
+
+	// A point tiepoint names the centre of the pixel rather than its corner
+	if keys[geoKeyRasterType] == rasterPixelIsPoint {
+		f.Transform.OriginX -= f.Transform.PixelWidth / 2
+		f.Transform.OriginY += f.Transform.PixelHeight / 2
+	}
+
+	return nil
+}
+
+// Read decodes the whole image
+func (f *File) Read() (*Raster, error) {
+	return f.ReadWindow(Window{Width: f.Width, Height: f.Height})
+}
+
+// ReadBBox decodes the pixels covering a WGS 84 bbox, clipped to the image.
+// ErrNoOverlap is returned if the bbox misses the image.
+func (f *File) ReadBBox(b BBox) (*Raster, error) {
+	if f.EPSG == 0 {
+		return nil, fmt.Errorf("raster coordinate system is not supported")
+	}
+	w, err := bboxWindow(f.EPSG, f.Transform, f.Width, f.Height, b)
+	if err != nil {
+		return nil, err
+	}
+	return f.ReadWindow(w)
+}
+
+// ReadWindow decodes a rectangle of pixels, clipped to the image
+func (f *File) ReadWindow(w Window) (*Raster, error) {
+	w, err := clipWindow(w, f.Width, f.Height)
+	if err != nil {
+		return nil, err
+	}
+
+	out := New(w.Width, w.Height, f.Bands, f.DataType)
+	out.NoData = f.NoData
+	out.EPSG = f.EPSG
+	out.Transform = f.Transform.window(w)
+
+	for by := w.Y / f.blockHeight; by <= (w.Y+w.Height-1)/f.blockHeight; by++ {
+		for bx := w.X / f.blockWidth; bx <= (w.X+w.Width-1)/f.blockWidth; bx++ {
+			if f.planar == planarSeparate {
+				for band := 0; band < f.Bands; band++ {
+					if err := f.readBlock(out, w, bx, by, band); err != nil {
+						return nil, err
+					}
+				}
+			} else if err := f.readBlock(out, w, bx, by, -1); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return out, nil
+}
+
+// readBlock decodes one block into the window. band is the plane of a
+// separate-planes file, or -1 for interleaved blocks holding every band.
+func (f *File) readBlock(out *Raster, w Window, bx, by, band int) error {
+	index := by*f.blocksAcross + bx
+	spp := f.Bands
+	if band >= 0 {
+		index += band * f.blocksAcross * f.blocksDown
+		spp = 1
+	}
+
+	size := f.DataType.Size()
+	// Strips end at the last row; tiles are always padded to full size
+	rows := f.blockHeight
+	if !f.Tiled {
+		rows = min(rows, f.Height-by*f.blockHeight)
+	}
+	decodedSize := f.blockWidth * rows * spp * size
+
+	x0, y0 := bx*f.blockWidth, by*f.blockHeight
+	if f.byteCounts[index] == 0 {
+		// Sparse blocks are left unwritten; GDAL reads them as nodata
+		fill := 0.0
+		if f.NoData != nil {
+			fill = *f.NoData
+		}
+		for y := max(y0, w.Y); y < min(y0+rows, w.Y+w.Height); y++ {
+			for x := max(x0, w.X); x < min(x0+f.blockWidth, w.X+w.Width); x++ {
+				for s := 0; s < spp; s++ {
+					out.Set(max(band, 0)+s, x-w.X, y-w.Y, fill)
+				}
+			}
+		}
+		return nil
+	}
+	if f.byteCounts[index] > maxFieldBytes {
+		return fmt.Errorf("block %d is too large", index)
+	}
+
+	raw := make([]byte, f.byteCounts[index])
+	if _, err := f.r.ReadAt(raw, int64(f.offsets[index])); err != nil {
+		return fmt.Errorf("failed to read block %d: %w", index, err)
+	}
+	block, err := decompress(f.Compression, raw, decodedSize)
+	if err != nil {
+		return fmt.Errorf("block %d: %w", index, err)
+	}
+	if err := unpredict(f.predictor, block, f.order, f.blockWidth, spp, size); err != nil {
+		return err
+	}
+
+	for y := max(y0, w.Y); y < min(y0+rows, w.Y+w.Height); y++ {
+		for x := max(x0, w.X); x < min(x0+f.blockWidth, w.X+w.Width); x++ {
+			at := ((y-y0)*f.blockWidth + (x - x0)) * spp * size
+			for s := 0; s < spp; s++ {
+				v := decodeSample(block[at+s*size:], f.order, f.DataType)
+				out.Set(max(band, 0)+s, x-w.X, y-w.Y, v)
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,523 @@
+package raster
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"math"
+	"sort"
+	"testing"
+)
+
+func TestReadWindow(t *testing.T) {
+	want := testRaster(70, 50, 2, Int16)
+
+	for _, opts := range []WriteOptions{{}, {RowsPerStrip: 3}, {TileSize: 16}} {
+		var buf bytes.Buffer
+		if err := Write(&buf, want, opts); err != nil {
+			t.Fatal(err)
+		}
+		f, err := Open(bytes.NewReader(buf.Bytes()))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		tests := []struct {
+			name    string
+			window  Window
+			clipped Window
+		}{
+			{name: "inside one block", window: Window{X: 1, Y: 1, Width: 2, Height: 2}},
+			{name: "across blocks", window: Window{X: 10, Y: 12, Width: 40, Height: 25}},
+			{name: "whole image", window: Window{Width: 70, Height: 50}},
+			{name: "past the edges", window: Window{X: -5, Y: 40, Width: 100, Height: 100}, clipped: Window{X: 0, Y: 40, Width: 70, Height: 10}},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				got, err := f.ReadWindow(tt.window)
+				if err != nil {
+					t.Fatalf("ReadWindow: %v", err)
+				}
+				w := tt.window
+				if tt.clipped != (Window{}) {
+					w = tt.clipped
+				}
+				assertRastersEqual(t, got, crop(want, w))
+			})
+		}
+
+		if _, err := f.ReadWindow(Window{X: 70, Y: 0, Width: 5, Height: 5}); !errors.Is(err, ErrNoOverlap) {
+			t.Errorf("window outside the image: got %v, want ErrNoOverlap", err)
+		}
+	}
+}
+
+func TestReadBBox(t *testing.T) {
+	tests := []struct {
+		name   string
+		epsg   int
+		origin [2]float64
+		pixel  float64
+	}{
+		// One 0.01 degree pixel per row and column
+		{name: "geographic", epsg: EPSGWGS84, origin: [2]float64{-123, 38}, pixel: 0.01},
+		// 100 m pixels in UTM zone 10N around -122.5, 37.5
+		{name: "UTM", epsg: UTMEPSG(10, true), origin: [2]float64{530000, 4160000}, pixel: 100},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			want := testRaster(100, 100, 1, Float32)
+			want.EPSG = tt.epsg
+			want.Transform = GeoTransform{OriginX: tt.origin[0], OriginY: tt.origin[1], PixelWidth: tt.pixel, PixelHeight: tt.pixel}
+
+			var buf bytes.Buffer
+			if err := Write(&buf, want, COGOptions(Float32)); err != nil {
+				t.Fatal(err)
+			}
+			f, err := Open(bytes.NewReader(buf.Bytes()))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if f.EPSG != tt.epsg {
+				t.Fatalf("EPSG:%d, want EPSG:%d", f.EPSG, tt.epsg)
+			}
+
+			// A bbox well inside the raster
+			minX, minY, maxX, maxY := want.Bounds()
+			lon0, lat0, _ := Unproject(tt.epsg, minX+(maxX-minX)*0.3, minY+(maxY-minY)*0.3)
+			lon1, lat1, _ := Unproject(tt.epsg, minX+(maxX-minX)*0.6, minY+(maxY-minY)*0.6)
+			bbox := BBox{MinLon: lon0, MinLat: lat0, MaxLon: lon1, MaxLat: lat1}
+
+			got, err := f.ReadBBox(bbox)
+			if err != nil {
+				t.Fatalf("ReadBBox: %v", err)
+			}
+			if got.Width >= want.Width || got.Height >= want.Height {
+				t.Errorf("read %dx%d pixels, want a window of the %dx%d raster", got.Width, got.Height, want.Width, want.Height)
+			}
+
+			// The window covers every corner of the bbox, and its samples
+			// are the raster's
+			for _, corner := range [][2]float64{{lon0, lat0}, {lon0, lat1}, {lon1, lat0}, {lon1, lat1}} {
+				x, y, _ := Project(tt.epsg, corner[0], corner[1])
+				if _, ok := got.ValueAt(0, x, y); !ok {
+					t.Errorf("corner %v is outside the window", corner)
+				}
+			}
+			col, row := want.Transform.WorldToPixel(got.Transform.OriginX, got.Transform.OriginY)
+			assertRastersEqual(t, got, crop(want, Window{X: int(math.Round(col)), Y: int(math.Round(row)), Width: got.Width, Height: got.Height}))
+
+			if _, err := f.ReadBBox(BBox{MinLon: 10, MinLat: 10, MaxLon: 11, MaxLat: 11}); !errors.Is(err, ErrNoOverlap) {
+				t.Errorf("bbox outside the raster: got %v, want ErrNoOverlap", err)
+			}
+		})
+	}
+}
+
+// TestOpenGDALLayouts reads files laid out the ways GDAL writes them but the
+// package's writer does not: BigTIFF, big-endian, band-separate planes,
+// sparse tiles, the pre-standard deflate code, GeoTIFF citation keys, a
+// ModelTransformation matrix and PixelIsPoint rasters. The files are built
+// tag by tag so each layout is exercised on its own.
+func TestOpenGDALLayouts(t *testing.T) {
+	tests := []struct {
+		name        string
+		order       binary.ByteOrder
+		big         bool
+		dataType    DataType
+		bands       int
+		separate    bool
+		tile        int // 0 for strips of rowsPerStrip
+		compression Compression
+		predictor   int
+		nodata      string
+		sparse      int // Index of a block left unwritten, or -1
+		epsg        int
+		point       bool
+		matrix      bool
+	}{
+		{
+			name: "BigTIFF tiled LZW", order: binary.LittleEndian, big: true,
+			dataType: Uint16, bands: 1, tile: 16, compression: CompressionLZW, predictor: PredictorHorizontal,
+			nodata: "65535", sparse: -1, epsg: EPSGWGS84,
+		},
+		{
+			name: "big-endian strips with pre-standard deflate", order: binary.BigEndian,
+			dataType: Float32, bands: 1, compression: compressionAdobeDeflate, predictor: PredictorFloatingPoint,
+			nodata: "-3.4028234663852886e+38", sparse: -1, epsg: EPSGNAD83,
+		},
+		{
+			name: "band-separate planes with a sparse tile", order: binary.LittleEndian,
+			dataType: Uint8, bands: 3, separate: true, tile: 16, compression: CompressionDeflate,
+			nodata: "0", sparse: 4, epsg: EPSGWGS84,
+		},
+		{
+			name: "PixelIsPoint UTM with a transformation matrix", order: binary.BigEndian, big: true,
+			dataType: Int32, bands: 2, compression: CompressionPackBits,
+			sparse: -1, epsg: UTMEPSG(10, true), point: true, matrix: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			want := testRaster(37, 29, tt.bands, tt.dataType)
+			want.EPSG = tt.epsg
+			if tt.epsg != EPSGWGS84 && tt.epsg != EPSGNAD83 {
+				want.Transform = GeoTransform{OriginX: 530000, OriginY: 4160000, PixelWidth: 30, PixelHeight: 30}
+			}
+			if tt.nodata != "" {
+				v, _ := parseNoData(tt.nodata)
+				want.NoData = &v
+			}
+
+			blockWidth, blockHeight := want.Width, 5
+			if tt.tile > 0 {
+				blockWidth, blockHeight = tt.tile, tt.tile
+			}
+			blocks := encodeBlocks(t, want, tt.order, blockWidth, blockHeight, tt.tile > 0, tt.separate, tt.predictor, tt.compression)
+			if tt.sparse >= 0 {
+				blocks[tt.sparse] = nil
+				fillSparse(want, blockWidth, blockHeight, tt.sparse, tt.separate)
+			}
+
+			format := sampleFormat(tt.dataType)
+			planar := planarChunky
+			if tt.separate {
+				planar = planarSeparate
+			}
+			fields := []testField{
+				shortField(tagImageWidth, want.Width),
+				shortField(tagImageLength, want.Height),
+				shortField(tagBitsPerSample, repeat(tt.dataType.Size()*8, tt.bands)...),
+				shortField(tagCompression, int(tt.compression)),
+				shortField(tagPhotometric, photometricMinBlack),
+				shortField(tagSamplesPerPixel, tt.bands),
+				shortField(tagPlanarConfig, planar),
+				shortField(tagSampleFormat, repeat(format, tt.bands)...),
+			}
+			if tt.predictor != 0 {
+				fields = append(fields, shortField(tagPredictor, tt.predictor))
+			}
+			offsetsTag, countsTag := uint16(tagStripOffsets), uint16(tagStripByteCounts)
+			if tt.tile > 0 {
+				offsetsTag, countsTag = tagTileOffsets, tagTileByteCounts
+				fields = append(fields, shortField(tagTileWidth, tt.tile), shortField(tagTileLength, tt.tile))
+			} else {
+				fields = append(fields, shortField(tagRowsPerStrip, blockHeight))
+			}
+			if want.NoData != nil {
+				fields = append(fields, asciiField(tagGDALNoData, tt.nodata))
+			}
+
+			// GDAL writes citations and ellipsoid parameters alongside the EPSG
+			// code; readers only need the code
+			rasterType := rasterPixelIsArea
+			if tt.point {
+				rasterType = rasterPixelIsPoint
+			}
+			g := want.Transform
+			if tt.epsg == EPSGWGS84 || tt.epsg == EPSGNAD83 {
+				fields = append(fields, shortField(tagGeoKeyDirectory,
+					1, 1, 0, 5,
+					geoKeyModelType, 0, 1, modelTypeGeographic,
+					geoKeyRasterType, 0, 1, rasterType,
+					2049, tagGeoASCIIParams, 7, 0, // GeogCitationGeoKey
+					geoKeyGeographicType, 0, 1, tt.epsg,
+					2057, tagGeoDoubleParams, 1, 0, // GeogSemiMajorAxisGeoKey
+				))
+				fields = append(fields, asciiField(tagGeoASCIIParams, "WGS 84|"), doubleField(tagGeoDoubleParams, 6378137))
+			} else {
+				fields = append(fields, shortField(tagGeoKeyDirectory,
+					1, 1, 0, 4,
+					geoKeyModelType, 0, 1, modelTypeProjected,
+					geoKeyRasterType, 0, 1, rasterType,
+					1026, tagGeoASCIIParams, 25, 0, // GTCitationGeoKey
+					geoKeyProjectedType, 0, 1, tt.epsg,
+				))
+				fields = append(fields, asciiField(tagGeoASCIIParams, "WGS 84 / UTM zone 10N|"))
+			}
+			// A point raster's tiepoint is the centre of the top-left pixel
+			originX, originY := g.OriginX, g.OriginY
+			if tt.point {
+				originX, originY = g.PixelToWorld(0.5, 0.5)
+			}
+			if tt.matrix {
+				fields = append(fields, doubleField(tagModelTransform,
+					g.PixelWidth, 0, 0, originX,
+					0, -g.PixelHeight, 0, originY,
+					0, 0, 0, 0,
+					0, 0, 0, 1,
+				))
+			} else {
+				fields = append(fields,
+					doubleField(tagModelPixelScale, g.PixelWidth, g.PixelHeight, 0),
+					doubleField(tagModelTiepoint, 0, 0, 0, originX, originY, 0),
+				)
+			}
+
+			data := buildTIFF(tt.order, tt.big, fields, blocks, offsetsTag, countsTag)
+			f, err := Open(bytes.NewReader(data))
+			if err != nil {
+				t.Fatalf("Open: %v", err)
+			}
+			if f.Compression != tt.compression {
+				t.Errorf("compression %s, want %s", f.Compression, tt.compression)
+			}
+			got, err := f.Read()
+			if err != nil {
+				t.Fatalf("Read: %v", err)
+			}
+			assertRastersEqual(t, got, want)
+		})
+	}
+}
+
+func TestOpenRejects(t *testing.T) {
+	tests := []struct {
+		name string
+		data []byte
+	}{
+		{name: "empty", data: nil},
+		{name: "not a TIFF", data: []byte("PK\x03\x04not a tiff")},
+		{name: "BigTIFF with 4 byte offsets", data: []byte{'I', 'I', 43, 0, 4, 0, 0, 0, 16, 0, 0, 0, 0, 0, 0, 0}},
+		{name: "missing blocks", data: buildTIFF(binary.LittleEndian, false, []testField{
+			shortField(tagImageWidth, 10),
+			shortField(tagImageLength, 10),
+			shortField(tagBitsPerSample, 8),
+			shortField(tagRowsPerStrip, 2),
+		}, [][]byte{make([]byte, 20)}, tagStripOffsets, tagStripByteCounts)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Open(bytes.NewReader(tt.data)); err == nil {
+				t.Error("Open accepted the file")
+			}
+		})
+	}
+}
+
+// crop copies a window out of a raster
+func crop(r *Raster, w Window) *Raster {
+	out := New(w.Width, w.Height, len(r.Bands), r.DataType)
+	out.NoData = r.NoData
+	out.EPSG = r.EPSG
+	out.Transform = r.Transform.window(w)
+	for b := range r.Bands {
+		for y := 0; y < w.Height; y++ {
+			for x := 0; x < w.Width; x++ {
+				out.Set(b, x, y, r.At(b, w.X+x, w.Y+y))
+			}
+		}
+	}
+	return out
+}
+
+func parseNoData(text string) (float64, error) {
+	f, err := Open(bytes.NewReader(buildTIFF(binary.LittleEndian, false, []testField{
+		shortField(tagImageWidth, 1),
+		shortField(tagImageLength, 1),
+		shortField(tagBitsPerSample, 8),
+		asciiField(tagGDALNoData, text),
+	}, [][]byte{{0}}, tagStripOffsets, tagStripByteCounts)))
+	if err != nil {
+		return 0, err
+	}
+	return *f.NoData, nil
+}
+
+// encodeBlocks lays a raster out in blocks the way a TIFF writer would,
+// band-separate planes one after another
+func encodeBlocks(t *testing.T, r *Raster, order binary.ByteOrder, blockWidth, blockHeight int, tiled, separate bool, predictor int, c Compression) [][]byte {
+	t.Helper()
+
+	size := r.DataType.Size()
+	across := (r.Width + blockWidth - 1) / blockWidth
+	down := (r.Height + blockHeight - 1) / blockHeight
+	planes, spp := 1, len(r.Bands)
+	if separate {
+		planes, spp = len(r.Bands), 1
+	}
+	if predictor == 0 {
+		predictor = PredictorNone
+	}
+	if c == compressionAdobeDeflate {
+		c = CompressionDeflate
+	}
+
+	var blocks [][]byte
+	for plane := 0; plane < planes; plane++ {
+		for by := 0; by < down; by++ {
+			rows := blockHeight
+			if !tiled {
+				rows = min(rows, r.Height-by*blockHeight)
+			}
+			for bx := 0; bx < across; bx++ {
+				block := make([]byte, blockWidth*rows*spp*size)
+				for y := 0; y < rows; y++ {
+					for x := 0; x < blockWidth; x++ {
+						px, py := bx*blockWidth+x, by*blockHeight+y
+						for s := 0; s < spp; s++ {
+							v := 0.0
+							if px < r.Width && py < r.Height {
+								v = r.At(plane+s, px, py)
+							}
+							encodeSample(block[((y*blockWidth+x)*spp+s)*size:], order, r.DataType, v, nil)
+						}
+					}
+				}
+				if err := predict(predictor, block, order, blockWidth, spp, size); err != nil {
+					t.Fatal(err)
+				}
+				packed, err := compress(c, block)
+				if err != nil {
+					t.Fatal(err)
+				}
+				blocks = append(blocks, packed)
+			}
+		}
+	}
+	return blocks
+}
+
+// fillSparse sets the pixels of an unwritten block to nodata, as readers
+// must return them
+func fillSparse(r *Raster, blockWidth, blockHeight, index int, separate bool) {
+	across := (r.Width + blockWidth - 1) / blockWidth
+	down := (r.Height + blockHeight - 1) / blockHeight
+	bands := []int{}
+	if separate {
+		bands = append(bands, index/(across*down))
+		index %= across * down
+	} else {
+		for b := range r.Bands {
+			bands = append(bands, b)
+		}
+	}
+
+	bx, by := index%across, index/across
+	for _, b := range bands {
+		for y := by * blockHeight; y < min((by+1)*blockHeight, r.Height); y++ {
+			for x := bx * blockWidth; x < min((bx+1)*blockWidth, r.Width); x++ {
+				r.Set(b, x, y, *r.NoData)
+			}
+		}
+	}
+}
+
+// testField is an IFD entry for buildTIFF. Values are kept as numbers so
+// they can be encoded in either byte order.
+type testField struct {
+	tag     uint16
+	typ     uint16
+	ints    []uint64
+	doubles []float64
+	text    string
+}
+
+func shortField(tag uint16, values ...int) testField {
+	f := testField{tag: tag, typ: typeShort}
+	for _, v := range values {
+		f.ints = append(f.ints, uint64(v))
+	}
+	return f
+}
+
+func doubleField(tag uint16, values ...float64) testField {
+	return testField{tag: tag, typ: typeDouble, doubles: values}
+}
+
+func asciiField(tag uint16, text string) testField {
+	return testField{tag: tag, typ: typeASCII, text: text}
+}
+
+func (f testField) encode(order binary.ByteOrder) (count int, data []byte) {
+	switch f.typ {
+	case typeASCII:
+		return len(f.text) + 1, append([]byte(f.text), 0)
+	case typeDouble:
+		data = make([]byte, 8*len(f.doubles))
+		for i, v := range f.doubles {
+			order.PutUint64(data[8*i:], math.Float64bits(v))
+		}
+		return len(f.doubles), data
+	default:
+		size := typeSizes[f.typ]
+		data = make([]byte, size*len(f.ints))
+		for i, v := range f.ints {
+			putUint(data[size*i:], order, size, v)
+		}
+		return len(f.ints), data
+	}
+}
+
+// buildTIFF writes a classic or BigTIFF file with the blocks first, then the
+// IFD and its out-of-line values. Nil blocks are left sparse.
+func buildTIFF(order binary.ByteOrder, big bool, fields []testField, blocks [][]byte, offsetsTag, countsTag uint16) []byte {
+	headerSize, countSize, entrySize, inline, offsetType := 8, 2, 12, 4, uint16(typeLong)
+	if big {
+		headerSize, countSize, entrySize, inline, offsetType = 16, 8, 20, 8, typeLong8
+	}
+
+	var body bytes.Buffer
+	offsets := testField{tag: offsetsTag, typ: offsetType}
+	counts := testField{tag: countsTag, typ: offsetType}
+	for _, block := range blocks {
+		if block == nil {
+			offsets.ints = append(offsets.ints, 0)
+			counts.ints = append(counts.ints, 0)
+			continue
+		}
+		offsets.ints = append(offsets.ints, uint64(headerSize+body.Len()))
+		counts.ints = append(counts.ints, uint64(len(block)))
+		body.Write(block)
+	}
+	if body.Len()%2 == 1 {
+		body.WriteByte(0)
+	}
+	fields = append(append([]testField{}, fields...), offsets, counts)
+	sort.Slice(fields, func(i, j int) bool { return fields[i].tag < fields[j].tag })
+
+	ifdOffset := headerSize + body.Len()
+	valuesOffset := ifdOffset + countSize + entrySize*len(fields) + inline
+
+	header := make([]byte, headerSize)
+	if order == binary.BigEndian {
+		copy(header, "MM")
+	} else {
+		copy(header, "II")
+	}
+	if big {
+		order.PutUint16(header[2:], 43)
+		order.PutUint16(header[4:], 8)
+		order.PutUint64(header[8:], uint64(ifdOffset))
+	} else {
+		order.PutUint16(header[2:], 42)
+		order.PutUint32(header[4:], uint32(ifdOffset))
+	}
+
+	ifd := make([]byte, countSize+entrySize*len(fields)+inline)
+	putUint(ifd, order, countSize, uint64(len(fields)))
+	var values bytes.Buffer
+	for i, f := range fields {
+		count, data := f.encode(order)
+		at := countSize + entrySize*i
+		order.PutUint16(ifd[at:], f.tag)
+		order.PutUint16(ifd[at+2:], f.typ)
+		putUint(ifd[at+4:], order, inline, uint64(count))
+		if len(data) <= inline {
+			copy(ifd[at+4+inline:], data)
+			continue
+		}
+		putUint(ifd[at+4+inline:], order, inline, uint64(valuesOffset+values.Len()))
+		values.Write(data)
+		if values.Len()%2 == 1 {
+			values.WriteByte(0)
+		}
+	}
+
+	out := append(header, body.Bytes()...)
+	out = append(out, ifd...)
+	return append(out, values.Bytes()...)
+}
@@ -0,0 +1,289 @@
+package raster
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+	"strings"
+)
+
+// TIFF tags used by the reader and writer
+const (
+	tagImageWidth       = 256
+	tagImageLength      = 257
+	tagBitsPerSample    = 258
+	tagCompression      = 259
+	tagPhotometric      = 262
+	tagStripOffsets     = 273
+	tagSamplesPerPixel  = 277
+	tagRowsPerStrip     = 278
+	tagStripByteCounts  = 279
+	tagPlanarConfig     = 284
+	tagPredictor        = 317
+	tagTileWidth        = 322
+	tagTileLength       = 323
+	tagTileOffsets      = 324
+	tagTileByteCounts   = 325
+	tagExtraSamples     = 338
+	tagSampleFormat     = 339
+	tagModelPixelScale  = 33550
+	tagModelTiepoint    = 33922
+	tagModelTransform   = 34264
+	tagGeoKeyDirectory  = 34735
+	tagGeoDoubleParams  = 34736
+	tagGeoASCIIParams   = 34737
+	tagGDALNoData       = 42113
+	photometricMinBlack = 1
+	planarChunky        = 1
+	planarSeparate      = 2
+	sampleFormatUint    = 1
+	sampleFormatInt     = 2
+	sampleFormatFloat   = 3
+)
+
+// GeoKeys, from the GeoTIFF 1.0 specification
+const (
+	geoKeyModelType      = 1024
+	geoKeyRasterType     = 1025
+	geoKeyGeographicType = 2048
+	geoKeyProjectedType  = 3072
+	modelTypeProjected   = 1
+	modelTypeGeographic  = 2
+	rasterPixelIsArea    = 1
+	rasterPixelIsPoint   = 2
+)
+
+// TIFF field types
+const (
+	typeByte      = 1
+	typeASCII     = 2
+	typeShort     = 3
+	typeLong      = 4
+	typeRational  = 5
+	typeSByte     = 6
+	typeUndefined = 7
+	typeSShort    = 8
+	typeSLong     = 9
+	typeSRational = 10
+	typeFloat     = 11
+	typeDouble    = 12
+	typeIFD       = 13
+	typeLong8     = 16
+	typeSLong8    = 17
+	typeIFD8      = 18
+)
+
+var typeSizes = map[uint16]int{
+	typeByte: 1, typeASCII: 1, typeShort: 2, typeLong: 4, typeRational: 8,
+	typeSByte: 1, typeUndefined: 1, typeSShort: 2, typeSLong: 4, typeSRational: 8,
+	typeFloat: 4, typeDouble: 8, typeIFD: 4, typeLong8: 8, typeSLong8: 8, typeIFD8: 8,
+}
+
+// maxFieldBytes bounds a single tag's value so a corrupt count cannot
+// exhaust memory
+const maxFieldBytes = 256 << 20
+
+// field is one decoded IFD entry, its value bytes still in file order
+type field struct {
+	typ   uint16
+	count uint64
+	data  []byte
+}
+
+// ifd is a TIFF image file directory
+type ifd struct {
+	order  binary.ByteOrder
+	fields map[uint16]field
+}
+
+// readHeader reads a classic or BigTIFF header and returns the byte order,
+// whether the file is BigTIFF and the offset of the first IFD
+func readHeader(r io.ReaderAt) (binary.ByteOrder, bool, uint64, error) {
+	header := make([]byte, 16)
+	if _, err := r.ReadAt(header[:8], 0); err != nil {
+		return nil, false, 0, fmt.Errorf("failed to read TIFF header: %w", err)
+	}
+
+	var order binary.ByteOrder
+	switch string(header[:2]) {
+	case "II":
+		order = binary.LittleEndian
+	case "MM":
+		order = binary.BigEndian
+	default:
+		return nil, false, 0, fmt.Errorf("not a TIFF file")
+	}
+
+	switch order.Uint16(header[2:]) {
+	case 42:
+		return order, false, uint64(order.Uint32(header[4:])), nil
+	case 43:
+		if _, err := r.ReadAt(header[8:16], 8); err != nil {
+			return nil, false, 0, fmt.Errorf("failed to read BigTIFF header: %w", err)
+		}
+		if order.Uint16(header[4:]) != 8 {
+			return nil, false, 0, fmt.Errorf("unsupported BigTIFF offset size %d", order.Uint16(header[4:]))
+		}
+		return order, true, order.Uint64(header[8:]), nil
+	default:
+		return nil, false, 0, fmt.Errorf("not a TIFF file")
+	}
+}
+
+// readIFD reads the directory at offset, fetching values stored out of line
+func readIFD(r io.ReaderAt, order binary.ByteOrder, big bool, offset uint64) (*ifd, error) {
+	countSize, entrySize, inline := 2, 12, 4
+	if big {
+		countSize, entrySize, inline = 8, 20, 8
+	}
+
+	buf := make([]byte, countSize)
+	if _, err := r.ReadAt(buf, int64(offset)); err != nil {
+		return nil, fmt.Errorf("failed to read IFD: %w", err)
+	}
+	var n uint64
+	if big {
+		n = order.Uint64(buf)
+	} else {
+		n = uint64(order.Uint16(buf))
+	}
+	if n > 4096 {
+		return nil, fmt.Errorf("IFD has %d entries", n)
+	}
+
+	entries := make([]byte, int(n)*entrySize)
+	if _, err := r.ReadAt(entries, int64(offset)+int64(countSize)); err != nil {
+		return nil, fmt.Errorf("failed to read IFD entries: %w", err)
+	}
+
+	d := &ifd{order: order, fields: make(map[uint16]field, n)}
+	for i := 0; i < int(n); i++ {
+		e := entries[i*entrySize : (i+1)*entrySize]
+		tag := order.Uint16(e)
+		typ := order.Uint16(e[2:])
+		size, ok := typeSizes[typ]
+		if !ok {
+			continue // Unknown types may be skipped per the specification
+		}
+
+		var count uint64
+		var value []byte
+		if big {
+			count, value = order.Uint64(e[4:]), e[12:20]
+		} else {
+			count, value = uint64(order.Uint32(e[4:])), e[8:12]
+		}
+		if count > maxFieldBytes/uint64(size) {
+			return nil, fmt.Errorf("tag %d is too large", tag)
+		}
+
+		length := int(count) * size
+		data := make([]byte, length)
+		if length <= inline {
+			copy(data, value)
+		} else {
+			var at uint64
+			if big {
+				at = order.Uint64(value)
+			} else {
+				at = uint64(order.Uint32(value))
+			}
+			if _, err := r.ReadAt(data, int64(at)); err != nil {
+				return nil, fmt.Errorf("failed to read tag %d: %w", tag, err)
+			}
+		}
+		d.fields[tag] = field{typ: typ, count: count, data: data}
+	}
+
+	return d, nil
+}
+
+func (d *ifd) has(tag uint16) bool {
+	_, ok := d.fields[tag]
+	return ok
+}
+
+// uints returns an integer tag's values
+func (d *ifd) uints(tag uint16) []uint64 {
+	f, ok := d.fields[tag]
+	if !ok {
+		return nil
+	}
+	values := make([]uint64, f.count)
+	for i := range values {
+		switch f.typ {
+		case typeByte, typeUndefined:
+			values[i] = uint64(f.data[i])
+		case typeShort:
+			values[i] = uint64(d.order.Uint16(f.data[i*2:]))
+		case typeLong, typeIFD:
+			values[i] = uint64(d.order.Uint32(f.data[i*4:]))
+		case typeLong8, typeIFD8:
+			values[i] = d.order.Uint64(f.data[i*8:])
+		default:
+			return nil
+		}
+	}
+	return values
+}
+
+// uint returns the first value of an integer tag, or def if absent
+func (d *ifd) uint(tag uint16, def uint64) uint64 {
+	values := d.uints(tag)
+	if len(values) == 0 {
+		return def
+	}
+	return values[0]
+}
+
+// floats returns a floating point tag's values
+func (d *ifd) floats(tag uint16) []float64 {
+	f, ok := d.fields[tag]
+	if !ok {
+		return nil
+	}
+	values := make([]float64, f.count)
+	for i := range values {
+		switch f.typ {
+		case typeDouble:
+			values[i] = math.Float64frombits(d.order.Uint64(f.data[i*8:]))
+		case typeFloat:
+			values[i] = float64(math.Float32frombits(d.order.Uint32(f.data[i*4:])))
+		default:
+			return nil
+		}
+	}
+	return values
+}
+
+// ascii returns a text tag without its NUL terminator
+func (d *ifd) ascii(tag uint16) string {
+	f, ok := d.fields[tag]
+	if !ok || f.typ != typeASCII {
+		return ""
+	}
+	return strings.TrimRight(string(f.data), "\x00")
+}
+
+// geoKeys decodes the GeoKey directory's SHORT-valued keys
+func (d *ifd) geoKeys() (map[uint16]uint16, error) {
+	dir := d.uints(tagGeoKeyDirectory)
+	if len(dir) < 4 {
+		return nil, nil
+	}
+	n := int(dir[3])
+	if len(dir) < 4+4*n {
+		return nil, fmt.Errorf("truncated GeoKey directory")
+	}
+
+	keys := make(map[uint16]uint16, n)
+	for i := 0; i < n; i++ {
+		entry := dir[4+4*i : 8+4*i]
+		// Location 0 means the value is held in the entry itself
+		if entry[1] == 0 {
+			keys[uint16(entry[0])] = uint16(entry[3])
+		}
+	}
+	return keys, nil
+}
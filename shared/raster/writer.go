@@ -0,0 +1,347 @@
+package raster
+
+import (
+	"bufio"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"sort"
+	"strconv"
+)
+
+// WriteOptions controls how a raster is laid out and compressed
+type WriteOptions struct {
+	Compression  Compression // Deflate if unset
+	Predictor    int         // PredictorHorizontal suits integer samples, PredictorFloatingPoint float ones
+	TileSize     int         // Edge of square tiles, a multiple of 16; 0 writes strips
+	RowsPerStrip int         // Strips of about 8 KiB if unset
+}
+
+// COGOptions lays a raster out as a Cloud Optimized GeoTIFF: deflate tiles
+// of 512 pixels. The writer always puts the IFD ahead of the image data, as
+// COG readers expect.
+func COGOptions(dataType DataType) WriteOptions {
+	opts := WriteOptions{Compression: CompressionDeflate, Predictor: PredictorHorizontal, TileSize: 512}
+	if dataType.IsFloat() {
+		opts.Predictor = PredictorFloatingPoint
+	}
+	return opts
+}
+
+// WriteFile writes a raster to disk as a GeoTIFF
+func WriteFile(path string, r *Raster, opts WriteOptions) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+
+	bw := bufio.NewWriter(f)
+	if err := Write(bw, r, opts); err != nil {
+		f.Close()
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	if err := bw.Flush(); err != nil {
+		f.Close()
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	return f.Close()
+}
+
+// Write encodes a raster as a little-endian classic TIFF with interleaved
+// bands. Files over 4 GiB would need BigTIFF and are rejected.
+func Write(w io.Writer, r *Raster, opts WriteOptions) error {
+	if err := validate(r, &opts); err != nil {
+		return err
+	}
+	order := binary.LittleEndian
+	spp := len(r.Bands)
+	size := r.DataType.Size()
+
+	blockWidth, blockHeight := r.Width, opts.RowsPerStrip
+	if opts.TileSize > 0 {
+		blockWidth, blockHeight = opts.TileSize, opts.TileSize
+	}
+	blocksAcross := (r.Width + blockWidth - 1) / blockWidth
+	blocksDown := (r.Height + blockHeight - 1) / blockHeight
+
+	// Tiles past the image edge are padded with nodata
+	pad := 0.0
+	if r.NoData != nil {
+		pad = *r.NoData
+	}
+
+	blocks := make([][]byte, 0, blocksAcross*blocksDown)
+	for by := 0; by < blocksDown; by++ {
+		rows := blockHeight
+		if opts.TileSize == 0 {
+			rows = min(rows, r.Height-by*blockHeight)
+		}
+		for bx := 0; bx < blocksAcross; bx++ {
+			block := make([]byte, blockWidth*rows*spp*size)
+			for y := 0; y < rows; y++ {
+				for x := 0; x < blockWidth; x++ {
+					px, py := bx*blockWidth+x, by*blockHeight+y
+					at := (y*blockWidth + x) * spp * size
+					for b := 0; b < spp; b++ {
+						v := pad
+						if px < r.Width && py < r.Height {
+							v = r.At(b, px, py)
+						}
+						encodeSample(block[at+b*size:], order, r.DataType, v, r.NoData)
+					}
+				}
+			}
+			if err := predict(opts.Predictor, block, order, blockWidth, spp, size); err != nil {
+				return err
+			}
+			packed, err := compress(opts.Compression, block)
+			if err != nil {
+				return fmt.Errorf("failed to compress block: %w", err)
+			}
+			blocks = append(blocks, packed)
+		}
+	}
+
+	entries := []entry{
+		longEntry(tagImageWidth, uint32(r.Width)),
+		longEntry(tagImageLength, uint32(r.Height)),
+		shortEntry(tagBitsPerSample, repeat(size*8, spp)...),
+		shortEntry(tagCompression, int(opts.Compression)),
+		shortEntry(tagPhotometric, photometricMinBlack),
+		shortEntry(tagSamplesPerPixel, spp),
+		shortEntry(tagPlanarConfig, planarChunky),
+		shortEntry(tagSampleFormat, repeat(sampleFormat(r.DataType), spp)...),
+	}
+	if opts.Predictor != PredictorNone {
+		entries = append(entries, shortEntry(tagPredictor, opts.Predictor))
+	}
+	if spp > 1 {
+		entries = append(entries, shortEntry(tagExtraSamples, repeat(0, spp-1)...))
+	}
+
+	offsetsTag, countsTag := uint16(tagStripOffsets), uint16(tagStripByteCounts)
+	if opts.TileSize > 0 {
+		offsetsTag, countsTag = tagTileOffsets, tagTileByteCounts
+		entries = append(entries,
+			longEntry(tagTileWidth, uint32(blockWidth)),
+			longEntry(tagTileLength, uint32(blockHeight)),
+		)
+	} else {
+		entries = append(entries, longEntry(tagRowsPerStrip, uint32(blockHeight)))
+	}
+	counts := make([]uint32, len(blocks))
+	for i, block := range blocks {
+		counts[i] = uint32(len(block))
+	}
+	entries = append(entries,
+		longEntry(offsetsTag, make([]uint32, len(blocks))...),
+		longEntry(countsTag, counts...),
+	)
+
+	if r.EPSG != 0 {
+		entries = append(entries,
+			doubleEntry(tagModelPixelScale, r.Transform.PixelWidth, r.Transform.PixelHeight, 0),
+			doubleEntry(tagModelTiepoint, 0, 0, 0, r.Transform.OriginX, r.Transform.OriginY, 0),
+			shortEntry(tagGeoKeyDirectory, geoKeyDirectory(r.EPSG)...),
+		)
+	}
+	if r.NoData != nil {
+		entries = append(entries, asciiEntry(tagGDALNoData, nodataText(*r.NoData)))
+	}
+
+	sort.Slice(entries, func(i, j int) bool { return entries[i].tag < entries[j].tag })
+
+	// Layout: header, IFD, out-of-line tag values, then the blocks in order
+	const headerSize = 8
+	ifdSize := 2 + 12*len(entries) + 4
+	offset := uint64(headerSize + ifdSize)
+	valueOffsets := make([]uint32, len(entries))
+	for i, e := range entries {
+		if len(e.data) > 4 {
+			valueOffsets[i] = uint32(offset)
+			offset += uint64(len(e.data) + len(e.data)%2)
+		}
+	}
+	for i, e := range entries {
+		if e.tag == offsetsTag {
+			at := offset
+			for j, block := range blocks {
+				order.PutUint32(entries[i].data[j*4:], uint32(at))
+				at += uint64(len(block))
+			}
+		}
+	}
+	total := offset
+	for _, block := range blocks {
+		total += uint64(len(block))
+	}
+	if total > math.MaxUint32 {
+		return fmt.Errorf("raster needs %d bytes, more than a classic TIFF can hold", total)
+	}
+
+	header := make([]byte, headerSize+ifdSize)
+	copy(header, "II")
+	order.PutUint16(header[2:], 42)
+	order.PutUint32(header[4:], headerSize)
+	order.PutUint16(header[headerSize:], uint16(len(entries)))
+	for i, e := range entries {
+		at := headerSize + 2 + 12*i
+		order.PutUint16(header[at:], e.tag)
+		order.PutUint16(header[at+2:], e.typ)
+		order.PutUint32(header[at+4:], e.count)
+		if len(e.data) > 4 {
+			order.PutUint32(header[at+8:], valueOffsets[i])
+		} else {
+			copy(header[at+8:at+12], e.data)
+		}
+	}
+	// The next-IFD offset stays zero: there is one image
+
+	if _, err := w.Write(header); err != nil {
+		return err
+	}
+	for _, e := range entries {
+		if len(e.data) <= 4 {
+			continue
+		}
+		if _, err := w.Write(e.data); err != nil {
+			return err
+		}
+		if len(e.data)%2 == 1 {
+			if _, err := w.Write([]byte{0}); err != nil {
+				return err
+			}
+		}
+	}
+	for _, block := range blocks {
+		if _, err := w.Write(block); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// validate checks the raster and fills in default options
+func validate(r *Raster, opts *WriteOptions) error {
+	if r.Width <= 0 || r.Height <= 0 || len(r.Bands) == 0 {
+		return fmt.Errorf("invalid raster size %dx%d with %d bands", r.Width, r.Height, len(r.Bands))
+	}
+	if r.DataType.Size() == 0 {
+		return fmt.Errorf("invalid data type %s", r.DataType)
+	}
+	for b, band := range r.Bands {
+		if len(band) != r.Width*r.Height {
+			return fmt.Errorf("band %d has %d samples, expected %d", b, len(band), r.Width*r.Height)
+		}
+	}
+	if r.EPSG != 0 && !supportedEPSG(r.EPSG) {
+		return fmt.Errorf("unsupported coordinate system EPSG:%d", r.EPSG)
+	}
+
+	if opts.Compression == 0 {
+		opts.Compression = CompressionDeflate
+	}
+	if opts.Predictor == 0 {
+		opts.Predictor = PredictorNone
+	}
+	switch {
+	case opts.Predictor == PredictorHorizontal && r.DataType.IsFloat():
+		return fmt.Errorf("horizontal predictor needs integer samples")
+	case opts.Predictor == PredictorFloatingPoint && !r.DataType.IsFloat():
+		return fmt.Errorf("floating point predictor needs float samples")
+	}
+	if opts.TileSize < 0 || opts.TileSize%16 != 0 {
+		return fmt.Errorf("tile size must be a multiple of 16, got %d", opts.TileSize)
+	}
+	if opts.RowsPerStrip <= 0 {
+		opts.RowsPerStrip = max(1, 8192/(r.Width*len(r.Bands)*r.DataType.Size()))
+	}
+	opts.RowsPerStrip = min(opts.RowsPerStrip, r.Height)
+	return nil
+}
+
+// geoKeyDirectory describes an EPSG coordinate system with area pixels
+func geoKeyDirectory(epsg int) []int {
+	modelType, crsKey := modelTypeGeographic, geoKeyGeographicType
+	if !isGeographic(epsg) {
+		modelType, crsKey = modelTypeProjected, geoKeyProjectedType
+	}
+	return []int{
+		1, 1, 0, 3, // Version 1.1.0, three keys
+		geoKeyModelType, 0, 1, modelType,
+		geoKeyRasterType, 0, 1, rasterPixelIsArea,
+		crsKey, 0, 1, epsg,
+	}
+}
+
+func sampleFormat(t DataType) int {
+	switch t {
+	case Int16, Int32:
+		return sampleFormatInt
+	case Float32, Float64:
+		return sampleFormatFloat
+	default:
+		return sampleFormatUint
+	}
+}
+
+// nodataText formats a nodata value the way GDAL writes it
+func nodataText(v float64) string {
+	switch {
+	case math.IsNaN(v):
+		return "nan"
+	case math.IsInf(v, 1):
+		return "inf"
+	case math.IsInf(v, -1):
+		return "-inf"
+	default:
+		return strconv.FormatFloat(v, 'g', -1, 64)
+	}
+}
+
+// entry is an IFD entry being written, its value little-endian
+type entry struct {
+	tag   uint16
+	typ   uint16
+	count uint32
+	data  []byte
+}
+
+func shortEntry(tag uint16, values ...int) entry {
+	data := make([]byte, 2*len(values))
+	for i, v := range values {
+		binary.LittleEndian.PutUint16(data[2*i:], uint16(v))
+	}
+	return entry{tag: tag, typ: typeShort, count: uint32(len(values)), data: data}
+}
+
+func longEntry(tag uint16, values ...uint32) entry {
+	data := make([]byte, 4*len(values))
+	for i, v := range values {
+		binary.LittleEndian.PutUint32(data[4*i:], v)
+	}
+	return entry{tag: tag, typ: typeLong, count: uint32(len(values)), data: data}
+}
+
+func doubleEntry(tag uint16, values ...float64) entry {
+	data := make([]byte, 8*len(values))
+	for i, v := range values {
+		binary.LittleEndian.PutUint64(data[8*i:], math.Float64bits(v))
+	}
+	return entry{tag: tag, typ: typeDouble, count: uint32(len(values)), data: data}
+}
+
+func asciiEntry(tag uint16, text string) entry {
+	data := append([]byte(text), 0)
+	return entry{tag: tag, typ: typeASCII, count: uint32(len(data)), data: data}
+}
+
+func repeat(v, n int) []int {
+	values := make([]int, n)
+	for i := range values {
+		values[i] = v
+	}
+	return values
+}
@@ -0,0 +1,202 @@
+package raster
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"testing"
+)
+
+// testRaster fills a raster with a pattern that reaches both ends of the
+// data type's range, so clamping or sign errors show up
+func testRaster(width, height, bands int, dataType DataType) *Raster {
+	r := New(width, height, bands, dataType)
+	r.EPSG = EPSGWGS84
+	r.Transform = GeoTransform{OriginX: -123, OriginY: 38, PixelWidth: 0.01, PixelHeight: 0.01}
+
+	var lo, hi float64
+	switch dataType {
+	case Uint8:
+		lo, hi = 0, math.MaxUint8
+	case Int16:
+		lo, hi = math.MinInt16, math.MaxInt16
+	case Uint16:
+		lo, hi = 0, math.MaxUint16
+	case Int32:
+		lo, hi = math.MinInt32, math.MaxInt32
+	case Uint32:
+		lo, hi = 0, math.MaxUint32
+	case Float32:
+		lo, hi = -math.MaxFloat32, math.MaxFloat32
+	default:
+		lo, hi = -math.MaxFloat64, math.MaxFloat64
+	}
+
+	for b := range r.Bands {
+		for y := 0; y < height; y++ {
+			for x := 0; x < width; x++ {
+				var v float64
+				switch {
+				case x == 0 && y == 0:
+					v = lo
+				case x == 1 && y == 0:
+					v = hi
+				case dataType.IsFloat():
+					v = float64(x-y)*0.37 + float64(b)*1000.25
+				default:
+					v = lo + float64((x*31+y*17+b*7)%251)
+				}
+				r.Set(b, x, y, dataType.clamp(v))
+			}
+		}
+	}
+	return r
+}
+
+func TestWriteRoundTrip(t *testing.T) {
+	dataTypes := []DataType{Uint8, Int16, Uint16, Int32, Uint32, Float32, Float64}
+	compressions := []Compression{CompressionNone, CompressionLZW, CompressionDeflate, CompressionPackBits}
+	layouts := []struct {
+		name string
+		opts WriteOptions
+	}{
+		{name: "strips", opts: WriteOptions{}},
+		{name: "short strips", opts: WriteOptions{RowsPerStrip: 7}},
+		{name: "tiles", opts: WriteOptions{TileSize: 16}},
+	}
+
+	for _, dataType := range dataTypes {
+		predictor := PredictorHorizontal
+		if dataType.IsFloat() {
+			predictor = PredictorFloatingPoint
+		}
+
+		for _, compression := range compressions {
+			for _, layout := range layouts {
+				for _, p := range []int{PredictorNone, predictor} {
+					for _, bands := range []int{1, 3} {
+						opts := layout.opts
+						opts.Compression = compression
+						opts.Predictor = p
+
+						name := fmt.Sprintf("%s/%s/%s/predictor %d/%d bands", dataType, compression, layout.name, p, bands)
+						t.Run(name, func(t *testing.T) {
+							// Sizes that do not divide into tiles or strips
+							want := testRaster(37, 29, bands, dataType)
+							nodata := -9999.0
+							if dataType == Uint8 || dataType == Uint16 || dataType == Uint32 {
+								nodata = 0
+							}
+							want.NoData = &nodata
+
+							var buf bytes.Buffer
+							if err := Write(&buf, want, opts); err != nil {
+								t.Fatalf("Write: %v", err)
+							}
+							f, err := Open(bytes.NewReader(buf.Bytes()))
+							if err != nil {
+								t.Fatalf("Open: %v", err)
+							}
+							if f.Compression != compression || f.Tiled != (opts.TileSize > 0) || f.Bands != bands || f.DataType != dataType {
+								t.Errorf("file is %s, tiled %v, with %d %s bands", f.Compression, f.Tiled, f.Bands, f.DataType)
+							}
+
+							got, err := f.Read()
+							if err != nil {
+								t.Fatalf("Read: %v", err)
+							}
+							assertRastersEqual(t, got, want)
+						})
+					}
+				}
+			}
+		}
+	}
+}
+
+func TestWriteNaNNoData(t *testing.T) {
+	want := testRaster(20, 10, 1, Float32)
+	nodata := math.NaN()
+	want.NoData = &nodata
+	want.Set(0, 5, 5, math.NaN())
+
+	var buf bytes.Buffer
+	if err := Write(&buf, want, COGOptions(Float32)); err != nil {
+		t.Fatal(err)
+	}
+	got, err := Open(bytes.NewReader(buf.Bytes()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.NoData == nil || !math.IsNaN(*got.NoData) {
+		t.Fatalf("nodata is %v, want NaN", got.NoData)
+	}
+	r, err := got.Read()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !r.IsNoData(r.At(0, 5, 5)) {
+		t.Errorf("NaN sample read back as %v", r.At(0, 5, 5))
+	}
+}
+
+func TestWriteRejects(t *testing.T) {
+	tests := []struct {
+		name   string
+		raster *Raster
+		opts   WriteOptions
+	}{
+		{name: "empty", raster: New(0, 0, 1, Uint8)},
+		{name: "horizontal predictor on floats", raster: New(4, 4, 1, Float32), opts: WriteOptions{Predictor: PredictorHorizontal}},
+		{name: "floating point predictor on integers", raster: New(4, 4, 1, Int16), opts: WriteOptions{Predictor: PredictorFloatingPoint}},
+		{name: "tile size", raster: New(4, 4, 1, Uint8), opts: WriteOptions{TileSize: 10}},
+		{name: "coordinate system", raster: func() *Raster {
+			r := New(4, 4, 1, Uint8)
+			r.EPSG = 3857
+			return r
+		}()},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := Write(&bytes.Buffer{}, tt.raster, tt.opts); err == nil {
+				t.Error("Write accepted the raster")
+			}
+		})
+	}
+}
+
+// assertRastersEqual compares georeferencing and every sample exactly
+func assertRastersEqual(t *testing.T, got, want *Raster) {
+	t.Helper()
+
+	if got.Width != want.Width || got.Height != want.Height || len(got.Bands) != len(want.Bands) {
+		t.Fatalf("got %dx%d with %d bands, want %dx%d with %d", got.Width, got.Height, len(got.Bands), want.Width, want.Height, len(want.Bands))
+	}
+	if got.DataType != want.DataType {
+		t.Errorf("data type %s, want %s", got.DataType, want.DataType)
+	}
+	if got.EPSG != want.EPSG {
+		t.Errorf("EPSG:%d, want EPSG:%d", got.EPSG, want.EPSG)
+	}
+	if got.Transform != want.Transform {
+		t.Errorf("transform %+v, want %+v", got.Transform, want.Transform)
+	}
+	switch {
+	case (got.NoData == nil) != (want.NoData == nil):
+		t.Errorf("nodata %v, want %v", got.NoData, want.NoData)
+	case got.NoData != nil && *got.NoData != *want.NoData && !(math.IsNaN(*got.NoData) && math.IsNaN(*want.NoData)):
+		t.Errorf("nodata %v, want %v", *got.NoData, *want.NoData)
+	}
+
+	for b := range want.Bands {
+		for y := 0; y < want.Height; y++ {
+			for x := 0; x < want.Width; x++ {
+				g, w := got.At(b, x, y), want.At(b, x, y)
+				if g != w && !(math.IsNaN(g) && math.IsNaN(w)) {
+					t.Fatalf("band %d pixel %d,%d is %v, want %v", b, x, y, g, w)
+				}
+			}
+		}
+	}
+}
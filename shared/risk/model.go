@@ -0,0 +1,123 @@
+package risk
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// Component names used in Score.Components
+const (
+	ComponentVegetation = "vegetation"
+	ComponentSlope      = "slope"
+	ComponentWind       = "wind"
+)
+
+// DefaultModelName is used when a job does not pick a model
+const DefaultModelName = "weighted-v1"
+
+// Inputs are the per-asset values a model scores. Components are normalised
+// to 0.0-1.0 before they reach the model.
+type Inputs struct {
+	AssetType  string
+	Vegetation float64
+	Slope      float64
+	Wind       float64
+	Extra      map[string]float64 // Additional factors for models that use them
+}
+
+// Score is a model's result for one asset
+type Score struct {
+	Overall    float64
+	Components map[string]float64 // Normalised value of each component the model used
+}
+
+// RiskModel scores a single asset. Name identifies the formula and never
+// changes meaning once released; Version tracks revisions that keep results
+// comparable, so both are stored with every analysis row.
+type RiskModel interface {
+	Name() string
+	Version() string
+	Score(in Inputs, params Parameters) (Score, error)
+}
+
+var (
+	registryMu sync.RWMutex
+	registry   = make(map[string]RiskModel)
+)
+
+func init() {
+	Register(WeightedSumModel{})
+}
+
+// Register makes a model available to jobs by name
+func Register(m RiskModel) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	if _, exists := registry[m.Name()]; exists {
+		panic(fmt.Sprintf("risk model %s already registered", m.Name()))
+	}
+	registry[m.Name()] = m
+}
+
+// LookupModel returns the registered model with the given name. An empty
+// name selects DefaultModelName.
+func LookupModel(name string) (RiskModel, error) {
+	if name == "" {
+		name = DefaultModelName
+	}
+
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	m, ok := registry[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown risk model %q", name)
+	}
+	return m, nil
+}
+
+// ModelNames lists the registered models in sorted order
+func ModelNames() []string {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	names := make([]string, 0, len(registry))
+	for name := range registry {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+// WeightedSumModel is the README formula:
+// Overall = Vegetation*w1 + Slope*w2 + Wind*w3 with weights from Parameters
+type WeightedSumModel struct{}
+
+func (WeightedSumModel) Name() string    { return "weighted-v1" }
+func (WeightedSumModel) Version() string { return "1.0.0" }
+
+func (WeightedSumModel) Score(in Inputs, params Parameters) (Score, error) {
+	components := map[string]float64{
+		ComponentVegetation: in.Vegetation,
+		ComponentSlope:      in.Slope,
+		ComponentWind:       in.Wind,
+	}
+
+	for name, v := range components {
+		if !(v >= 0 && v <= 1) {
+			return Score{}, fmt.Errorf("%s component must be normalised to 0-1, got %v", name, v)
+		}
+	}
+
+	overall := in.Vegetation*params.Weights.Vegetation +
+		in.Slope*params.Weights.Slope +
+		in.Wind*params.Weights.Wind
+
+	return Score{
+		Overall:    overall,
+		Components: components,
+	}, nil
+}
@@ -0,0 +1,234 @@
+package risk
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+
+	pb "wildfire-risk-platform/api/proto/generated"
+)
+
+// weightTolerance absorbs float rounding when checking that weights sum to 1
+const weightTolerance = 1e-6
+
+// Weights are the contribution of each normalised component to the overall score
+type Weights struct {
+	Vegetation float64 `json:"vegetation"`
+	Slope      float64 `json:"slope"`
+	Wind       float64 `json:"wind"`
+}
+
+// Thresholds are the lower bounds of the low, medium and high risk classes.
+// Scores below Low are classed as minimal.
+type Thresholds struct {
+	Low    float64 `json:"low"`
+	Medium float64 `json:"medium"`
+	High   float64 `json:"high"`
+}
+
+// Parameters control how a job's assets are scored and classified
+type Parameters struct {
+	Model      string     `json:"model"` // Registered RiskModel name
+	Weights    Weights    `json:"weights"`
+	Thresholds Thresholds `json:"thresholds"`
+	AssetTypes []string   `json:"asset_types,omitempty"` // Empty means every asset type
+}
+
+// Risk classes returned by Classify
+const (
+	ClassMinimal = "minimal"
+	ClassLow     = "low"
+	ClassMedium  = "medium"
+	ClassHigh    = "high"
+)
+
+// DefaultParameters returns the README formula NDVI*0.5 + Slope*0.4 + Wind*0.1
+func DefaultParameters() Parameters {
+	return Parameters{
+		Model: DefaultModelName,
+		Weights: Weights{
+			Vegetation: 0.5,
+			Slope:      0.4,
+			Wind:       0.1,
+		},
+		Thresholds: Thresholds{
+			Low:    0.0,
+			Medium: 0.4,
+			High:   0.7,
+		},
+	}
+}
+
+// ParametersFromProto fills in defaults for any block the client left out
+func ParametersFromProto(p *pb.RiskParameters) Parameters {
+	params := DefaultParameters()
+	if p == nil {
+		return params
+	}
+
+	if w := p.GetWeights(); w != nil {
+		params.Weights = Weights{
+			Vegetation: w.GetVegetation(),
+			Slope:      w.GetSlope(),
+			Wind:       w.GetWind(),
+		}
+	}
+
+	if t := p.GetThresholds(); t != nil {
+		params.Thresholds = Thresholds{
+			Low:    t.GetLow(),
+			Medium: t.GetMedium(),
+			High:   t.GetHigh(),
+		}
+	}
+
+	if p.GetModel() != "" {
+		params.Model = p.GetModel()
+	}
+
+	params.AssetTypes = normaliseAssetTypes(p.GetAssetTypes())
+
+	return params
+}
+
+// ToProto converts the parameters back to their wire form
+func (p Parameters) ToProto() *pb.RiskParameters {
+	return &pb.RiskParameters{
+		Weights: &pb.RiskWeights{
+			Vegetation: p.Weights.Vegetation,
+			Slope:      p.Weights.Slope,
+			Wind:       p.Weights.Wind,
+		},
+		Thresholds: &pb.RiskThresholds{
+			Low:    p.Thresholds.Low,
+			Medium: p.Thresholds.Medium,
+			High:   p.Thresholds.High,
+		},
+		AssetTypes: p.AssetTypes,
+		Model:      p.Model,
+	}
+}
+
+// Validate checks that the model is registered, that weights are in [0, 1]
+// and sum to 1, and that the class thresholds are strictly increasing within
+// [0, 1]
+func (p Parameters) Validate() error {
+	if _, err := LookupModel(p.Model); err != nil {
+		return err
+	}
+
+	weights := []struct {
+		name  string
+		value float64
+	}{
+		{"vegetation", p.Weights.Vegetation},
+		{"slope", p.Weights.Slope},
+		{"wind", p.Weights.Wind},
+	}
+
+	var sum float64
+	for _, w := range weights {
+		if math.IsNaN(w.value) || w.value < 0 || w.value > 1 {
+			return fmt.Errorf("weight %s must be between 0 and 1, got %v", w.name, w.value)
+		}
+		sum += w.value
+	}
+
+	if math.Abs(sum-1) > weightTolerance {
+		return fmt.Errorf("weights must sum to 1, got %v", sum)
+	}
+
+	t := p.Thresholds
+	if t.Low < 0 || t.High > 1 {
+		return fmt.Errorf("thresholds must be between 0 and 1")
+	}
+	if !(t.Low < t.Medium && t.Medium < t.High) {
+		return fmt.Errorf("thresholds must satisfy low < medium < high, got %v < %v < %v", t.Low, t.Medium, t.High)
+	}
+
+	for _, assetType := range p.AssetTypes {
+		if assetType == "" {
+			return fmt.Errorf("asset_types must not contain empty values")
+		}
+	}
+
+	return nil
+}
+
+// IncludesAssetType reports whether assets of the given type are scored
+func (p Parameters) IncludesAssetType(assetType string) bool {
+	if len(p.AssetTypes) == 0 {
+		return true
+	}
+	for _, t := range p.AssetTypes {
+		if t == assetType {
+			return true
+		}
+	}
+	return false
+}
+
+// Classify maps an overall score to its risk class
+func (p Parameters) Classify(score float64) string {
+	switch {
+	case score >= p.Thresholds.High:
+		return ClassHigh
+	case score >= p.Thresholds.Medium:
+		return ClassMedium
+	case score >= p.Thresholds.Low:
+		return ClassLow
+	default:
+		return ClassMinimal
+	}
+}
+
+// CanonicalJSON is the stable serialisation used for AOI result hashing
+func (p Parameters) CanonicalJSON() string {
+	if p.Model == "" {
+		p.Model = DefaultModelName
+	}
+	p.AssetTypes = normaliseAssetTypes(p.AssetTypes)
+	b, _ := json.Marshal(p)
+	return string(b)
+}
+
+// Value stores the parameters in a JSONB column
+func (p Parameters) Value() (driver.Value, error) {
+	return json.Marshal(p)
+}
+
+// Scan reads the parameters from a JSONB column
+func (p *Parameters) Scan(src interface{}) error {
+	switch v := src.(type) {
+	case nil:
+		*p = DefaultParameters()
+		return nil
+	case []byte:
+		return json.Unmarshal(v, p)
+	case string:
+		return json.Unmarshal([]byte(v), p)
+	default:
+		return fmt.Errorf("cannot scan %T into risk parameters", src)
+	}
+}
+
+func normaliseAssetTypes(types []string) []string {
+	if len(types) == 0 {
+		return nil
+	}
+
+	seen := make(map[string]bool, len(types))
+	out := make([]string, 0, len(types))
+	for _, t := range types {
+		if seen[t] {
+			continue
+		}
+		seen[t] = true
+		out = append(out, t)
+	}
+	sort.Strings(out)
+
+	return out
+}
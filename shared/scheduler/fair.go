@@ -0,0 +1,339 @@
+package scheduler
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	pb "wildfire-risk-platform/api/proto/generated"
+	"wildfire-risk-platform/shared/database/models"
+)
+
+// Job is a queued risk assessment job as seen by the scheduler
+type Job struct {
+	ID        string
+	Submitter string
+	Priority  models.JobPriority
+	Enqueued  time.Time
+}
+
+// JobFromModel converts a stored job into its scheduler form
+func JobFromModel(job *models.RiskAssessmentJob) Job {
+	return Job{
+		ID:        job.JobID.String(),
+		Submitter: job.Submitter,
+		Priority:  job.Priority,
+		Enqueued:  job.CreatedAt,
+	}
+}
+
+// PriorityFromProto maps the wire priority to the stored one
+func PriorityFromProto(p pb.JobPriority) models.JobPriority {
+	switch p {
+	case pb.JobPriority_PRIORITY_LOW:
+		return models.JobPriorityLow
+	case pb.JobPriority_PRIORITY_HIGH:
+		return models.JobPriorityHigh
+	case pb.JobPriority_PRIORITY_URGENT:
+		return models.JobPriorityUrgent
+	default:
+		return models.JobPriorityNormal
+	}
+}
+
+// PriorityToProto maps the stored priority to the wire one
+func PriorityToProto(p models.JobPriority) pb.JobPriority {
+	switch p {
+	case models.JobPriorityLow:
+		return pb.JobPriority_PRIORITY_LOW
+	case models.JobPriorityHigh:
+		return pb.JobPriority_PRIORITY_HIGH
+	case models.JobPriorityUrgent:
+		return pb.JobPriority_PRIORITY_URGENT
+	default:
+		return pb.JobPriority_PRIORITY_NORMAL
+	}
+}
+
+// FairScheduler dispatches jobs by priority first and, within a priority,
+// round-robin across submitters so one submitter's backlog cannot starve
+// anyone else. Running jobs are capped globally and per submitter; a limit
+// of zero means unlimited.
+type FairScheduler struct {
+	mu sync.Mutex
+
+	maxRunning      int
+	maxPerSubmitter int
+
+	// pending[submitter][rank] is a FIFO of that submitter's jobs at one priority
+	pending    map[string]map[int][]*Job
+	running    map[string]slot   // job ID -> slot it holds
+	perRunning map[string]int    // submitter -> running count
+	lastServed map[string]uint64 // submitter -> dispatch sequence
+	sequence   uint64
+}
+
+// slot is a running job's claim on the concurrency limits
+type slot struct {
+	submitter string
+	started   uint64 // Sequence at which the job started
+}
+
+// NewFairScheduler creates a scheduler with the given concurrency limits
+func NewFairScheduler(maxRunning, maxPerSubmitter int) *FairScheduler {
+	return &FairScheduler{
+		maxRunning:      maxRunning,
+		maxPerSubmitter: maxPerSubmitter,
+		pending:         make(map[string]map[int][]*Job),
+		running:         make(map[string]slot),
+		perRunning:      make(map[string]int),
+		lastServed:      make(map[string]uint64),
+	}
+}
+
+// Enqueue adds a job to its submitter's queue
+func (s *FairScheduler) Enqueue(job Job) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.running[job.ID]; ok {
+		return fmt.Errorf("job %s is already running", job.ID)
+	}
+	if s.find(job.ID) != nil {
+		return fmt.Errorf("job %s is already queued", job.ID)
+	}
+
+	if s.pending[job.Submitter] == nil {
+		s.pending[job.Submitter] = make(map[int][]*Job)
+	}
+	rank := job.Priority.Rank()
+	s.pending[job.Submitter][rank] = append(s.pending[job.Submitter][rank], &job)
+
+	return nil
+}
+
+// MarkRunning records a job that is already running, e.g. when rebuilding
+// the scheduler from the database after a restart
+func (s *FairScheduler) MarkRunning(job Job) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.running[job.ID]; ok {
+		return
+	}
+	s.sequence++
+	s.running[job.ID] = slot{submitter: job.Submitter, started: s.sequence}
+	s.perRunning[job.Submitter]++
+}
+
+// Next returns the next job to start, or false if nothing is queued or the
+// concurrency limits are reached. The job counts as running until Done.
+func (s *FairScheduler) Next() (Job, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.maxRunning > 0 && len(s.running) >= s.maxRunning {
+		return Job{}, false
+	}
+
+	submitter, rank, ok := s.pickFrom(nil, s.perRunning, s.lastServed)
+	if !ok {
+		return Job{}, false
+	}
+
+	job := s.pop(submitter, rank)
+	s.sequence++
+	s.running[job.ID] = slot{submitter: submitter, started: s.sequence}
+	s.perRunning[submitter]++
+	s.lastServed[submitter] = s.sequence
+
+	return *job, true
+}
+
+// Done releases the concurrency slot held by a running job
+func (s *FairScheduler) Done(jobID string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	running, ok := s.running[jobID]
+	if !ok {
+		return
+	}
+	delete(s.running, jobID)
+	s.perRunning[running.submitter]--
+	if s.perRunning[running.submitter] == 0 {
+		delete(s.perRunning, running.submitter)
+	}
+}
+
+// Remove drops a queued job that will no longer run
+func (s *FairScheduler) Remove(jobID string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for submitter, ranks := range s.pending {
+		for rank, queue := range ranks {
+			for i, job := range queue {
+				if job.ID == jobID {
+					s.pending[submitter][rank] = append(queue[:i], queue[i+1:]...)
+					s.prune(submitter, rank)
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// Position returns the 1-based place a queued job would be dispatched in if
+// no new jobs arrived, or 0 if the job is not queued
+func (s *FairScheduler) Position(jobID string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.find(jobID) == nil {
+		return 0
+	}
+
+	// Replay dispatch on copies of the scheduler state with the limits
+	// enforced. A submitter at its cap is skipped until one of its jobs
+	// finishes, which reorders dispatch, so whenever nothing can start the
+	// oldest running job is assumed to finish first.
+	heads := make(map[string]map[int]int)
+	perRunning := make(map[string]int, len(s.perRunning))
+	for k, v := range s.perRunning {
+		perRunning[k] = v
+	}
+	lastServed := make(map[string]uint64, len(s.lastServed))
+	for k, v := range s.lastServed {
+		lastServed[k] = v
+	}
+	running := make([]slot, 0, len(s.running))
+	for _, r := range s.running {
+		running = append(running, r)
+	}
+	sort.Slice(running, func(i, j int) bool { return running[i].started < running[j].started })
+	sequence := s.sequence
+
+	for position := 1; ; {
+		if s.maxRunning <= 0 || len(running) < s.maxRunning {
+			if submitter, rank, ok := s.pickFrom(heads, perRunning, lastServed); ok {
+				if heads[submitter] == nil {
+					heads[submitter] = make(map[int]int)
+				}
+				job := s.pending[submitter][rank][heads[submitter][rank]]
+				if job.ID == jobID {
+					return position
+				}
+
+				heads[submitter][rank]++
+				perRunning[submitter]++
+				sequence++
+				lastServed[submitter] = sequence
+				running = append(running, slot{submitter: submitter, started: sequence})
+				position++
+				continue
+			}
+		}
+
+		if len(running) == 0 {
+			return 0
+		}
+		perRunning[running[0].submitter]--
+		running = running[1:]
+	}
+}
+
+// QueueLength returns the number of jobs waiting to start
+func (s *FairScheduler) QueueLength() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	n := 0
+	for _, ranks := range s.pending {
+		for _, queue := range ranks {
+			n += len(queue)
+		}
+	}
+	return n
+}
+
+// Running returns the number of jobs currently holding a slot
+func (s *FairScheduler) Running() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return len(s.running)
+}
+
+// pickFrom chooses the submitter to serve next: the highest priority with an
+// eligible job wins, then the submitter with the fewest running jobs, then
+// the one served least recently. Submitters at their running cap are
+// skipped. heads offsets each queue for Position.
+func (s *FairScheduler) pickFrom(heads map[string]map[int]int, perRunning map[string]int, lastServed map[string]uint64) (string, int, bool) {
+	for rank := models.MaxPriorityRank; rank >= 0; rank-- {
+		best := ""
+		found := false
+
+		for submitter, ranks := range s.pending {
+			if len(ranks[rank]) <= heads[submitter][rank] {
+				continue
+			}
+			if s.maxPerSubmitter > 0 && perRunning[submitter] >= s.maxPerSubmitter {
+				continue
+			}
+
+			if !found || s.before(submitter, best, perRunning, lastServed) {
+				best = submitter
+				found = true
+			}
+		}
+
+		if found {
+			return best, rank, true
+		}
+	}
+
+	return "", 0, false
+}
+
+func (s *FairScheduler) before(a, b string, perRunning map[string]int, lastServed map[string]uint64) bool {
+	if perRunning[a] != perRunning[b] {
+		return perRunning[a] < perRunning[b]
+	}
+	if lastServed[a] != lastServed[b] {
+		return lastServed[a] < lastServed[b]
+	}
+	return a < b
+}
+
+func (s *FairScheduler) pop(submitter string, rank int) *Job {
+	queue := s.pending[submitter][rank]
+	job := queue[0]
+	s.pending[submitter][rank] = queue[1:]
+	s.prune(submitter, rank)
+	return job
+}
+
+func (s *FairScheduler) prune(submitter string, rank int) {
+	if len(s.pending[submitter][rank]) == 0 {
+		delete(s.pending[submitter], rank)
+	}
+	if len(s.pending[submitter]) == 0 {
+		delete(s.pending, submitter)
+	}
+}
+
+func (s *FairScheduler) find(jobID string) *Job {
+	for _, ranks := range s.pending {
+		for _, queue := range ranks {
+			for _, job := range queue {
+				if job.ID == jobID {
+					return job
+				}
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,208 @@
+package scheduler
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"wildfire-risk-platform/shared/database/models"
+)
+
+type queued struct {
+	submitter string
+	priority  models.JobPriority
+}
+
+func enqueueAll(t *testing.T, s *FairScheduler, jobs []queued) []string {
+	t.Helper()
+	ids := make([]string, len(jobs))
+	for i, j := range jobs {
+		ids[i] = fmt.Sprintf("%s-%d", j.submitter, i)
+		if err := s.Enqueue(Job{ID: ids[i], Submitter: j.submitter, Priority: j.priority, Enqueued: time.Unix(int64(i), 0)}); err != nil {
+			t.Fatalf("Enqueue(%s): %v", ids[i], err)
+		}
+	}
+	return ids
+}
+
+// drain dispatches every queued job, finishing the oldest running job
+// whenever nothing can start, and returns the dispatch order
+func drain(t *testing.T, s *FairScheduler, running []string) []string {
+	t.Helper()
+	var order []string
+	for s.QueueLength() > 0 {
+		if job, ok := s.Next(); ok {
+			order = append(order, job.ID)
+			running = append(running, job.ID)
+			continue
+		}
+		if len(running) == 0 {
+			t.Fatalf("nothing running but %d jobs cannot start", s.QueueLength())
+		}
+		s.Done(running[0])
+		running = running[1:]
+	}
+	return order
+}
+
+func TestNext(t *testing.T) {
+	tests := []struct {
+		name            string
+		maxRunning      int
+		maxPerSubmitter int
+		jobs            []queued
+		want            []int // Indexes of jobs in the order Next returns them before it blocks
+	}{
+		{
+			name: "priority first",
+			jobs: []queued{{"alice", models.JobPriorityLow}, {"alice", models.JobPriorityNormal}, {"alice", models.JobPriorityUrgent}},
+			want: []int{2, 1, 0},
+		},
+		{
+			name: "round robin within a priority",
+			jobs: []queued{{"alice", ""}, {"alice", ""}, {"alice", ""}, {"bob", ""}},
+			want: []int{0, 3, 1, 2},
+		},
+		{
+			name:       "global limit",
+			maxRunning: 2,
+			jobs:       []queued{{"alice", ""}, {"bob", ""}, {"carol", ""}},
+			want:       []int{0, 1},
+		},
+		{
+			name:            "per-submitter limit skips a capped submitter",
+			maxPerSubmitter: 1,
+			jobs:            []queued{{"alice", models.JobPriorityUrgent}, {"alice", models.JobPriorityUrgent}, {"bob", models.JobPriorityLow}},
+			want:            []int{0, 2},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewFairScheduler(tt.maxRunning, tt.maxPerSubmitter)
+			ids := enqueueAll(t, s, tt.jobs)
+
+			var got []string
+			for {
+				job, ok := s.Next()
+				if !ok {
+					break
+				}
+				got = append(got, job.ID)
+			}
+
+			if len(got) != len(tt.want) {
+				t.Fatalf("got %v, want %d jobs", got, len(tt.want))
+			}
+			for i, idx := range tt.want {
+				if got[i] != ids[idx] {
+					t.Errorf("dispatch %d = %s, want %s", i, got[i], ids[idx])
+				}
+			}
+			if s.Running() != len(tt.want) {
+				t.Errorf("Running() = %d, want %d", s.Running(), len(tt.want))
+			}
+		})
+	}
+}
+
+func TestNextAfterDone(t *testing.T) {
+	s := NewFairScheduler(1, 0)
+	ids := enqueueAll(t, s, []queued{{"alice", ""}, {"bob", ""}})
+
+	first, _ := s.Next()
+	if _, ok := s.Next(); ok {
+		t.Fatal("Next started a second job past the global limit")
+	}
+	s.Done(first.ID)
+	second, ok := s.Next()
+	if !ok || second.ID != ids[1] {
+		t.Fatalf("Next after Done = %v %v, want %s", second.ID, ok, ids[1])
+	}
+}
+
+func TestPosition(t *testing.T) {
+	tests := []struct {
+		name            string
+		maxRunning      int
+		maxPerSubmitter int
+		running         []queued // Marked running before the queue is built
+		jobs            []queued
+		want            []int // Position of each job
+	}{
+		{
+			name: "unlimited",
+			jobs: []queued{{"alice", ""}, {"alice", ""}, {"bob", ""}, {"bob", models.JobPriorityHigh}},
+			want: []int{2, 4, 3, 1},
+		},
+		{
+			name:            "capped submitter's urgent jobs do not hold back others",
+			maxPerSubmitter: 1,
+			jobs:            []queued{{"alice", models.JobPriorityUrgent}, {"alice", models.JobPriorityUrgent}, {"alice", models.JobPriorityUrgent}, {"bob", models.JobPriorityLow}},
+			want:            []int{1, 3, 4, 2},
+		},
+		{
+			name:            "submitter already at its cap waits for its running job",
+			maxPerSubmitter: 1,
+			running:         []queued{{"alice", ""}},
+			jobs:            []queued{{"alice", models.JobPriorityHigh}, {"bob", ""}, {"bob", ""}},
+			want:            []int{2, 1, 3},
+		},
+		{
+			name:            "global and per-submitter limits together",
+			maxRunning:      2,
+			maxPerSubmitter: 1,
+			running:         []queued{{"carol", ""}, {"alice", ""}},
+			jobs:            []queued{{"alice", ""}, {"alice", ""}, {"bob", ""}, {"carol", ""}},
+			want:            []int{2, 4, 1, 3},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewFairScheduler(tt.maxRunning, tt.maxPerSubmitter)
+			var running []string
+			for i, r := range tt.running {
+				id := fmt.Sprintf("running-%d", i)
+				s.MarkRunning(Job{ID: id, Submitter: r.submitter, Priority: r.priority})
+				running = append(running, id)
+			}
+			ids := enqueueAll(t, s, tt.jobs)
+
+			for i, id := range ids {
+				if got := s.Position(id); got != tt.want[i] {
+					t.Errorf("Position(%s) = %d, want %d", id, got, tt.want[i])
+				}
+			}
+
+			// Positions must match the order the jobs are actually started in
+			// when running jobs finish oldest first
+			for i, id := range drain(t, s, running) {
+				if tt.want[indexOf(ids, id)] != i+1 {
+					t.Errorf("%s started at %d, Position said %d", id, i+1, tt.want[indexOf(ids, id)])
+				}
+			}
+		})
+	}
+}
+
+func TestPositionNotQueued(t *testing.T) {
+	s := NewFairScheduler(0, 0)
+	ids := enqueueAll(t, s, []queued{{"alice", ""}})
+	if got := s.Position("missing"); got != 0 {
+		t.Errorf("Position of unknown job = %d, want 0", got)
+	}
+	s.Next()
+	if got := s.Position(ids[0]); got != 0 {
+		t.Errorf("Position of running job = %d, want 0", got)
+	}
+}
+
+func indexOf(ids []string, id string) int {
+	for i, v := range ids {
+		if v == id {
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,201 @@
+package utils
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+)
+
+// AOIHashPrecision is the number of decimal places coordinates are rounded to
+// before hashing. Five places is roughly one metre, which absorbs the float
+// noise clients introduce when they resubmit the same polygon.
+const AOIHashPrecision = 5
+
+type aoiGeoJSON struct {
+	Type        string          `json:"type"`
+	Coordinates json.RawMessage `json:"coordinates"`
+}
+
+// CanonicalAOI normalises a GeoJSON Polygon or MultiPolygon so that
+// equivalent AOIs serialise identically: coordinates are rounded, repeated
+// vertices dropped, exterior rings wound counter-clockwise and holes
+// clockwise, every ring starts at its lowest vertex, and holes and polygons
+// are sorted. A MultiPolygon of one polygon is the same as that Polygon.
+func CanonicalAOI(geojsonStr string) ([][][][2]float64, error) {
+	var geometry aoiGeoJSON
+	if err := json.Unmarshal([]byte(geojsonStr), &geometry); err != nil {
+		return nil, fmt.Errorf("invalid JSON: %w", err)
+	}
+
+	var raw [][][][]float64
+	switch geometry.Type {
+	case "Polygon":
+		var rings [][][]float64
+		if err := json.Unmarshal(geometry.Coordinates, &rings); err != nil {
+			return nil, fmt.Errorf("invalid Polygon coordinates: %w", err)
+		}
+		raw = [][][][]float64{rings}
+	case "MultiPolygon":
+		if err := json.Unmarshal(geometry.Coordinates, &raw); err != nil {
+			return nil, fmt.Errorf("invalid MultiPolygon coordinates: %w", err)
+		}
+	default:
+		return nil, fmt.Errorf("expected Polygon or MultiPolygon type, got %s", geometry.Type)
+	}
+
+	if len(raw) == 0 {
+		return nil, fmt.Errorf("AOI has no polygons")
+	}
+
+	polygons := make([][][][2]float64, 0, len(raw))
+	for i, coords := range raw {
+		rings, err := canonicalPolygon(coords)
+		if err != nil {
+			if len(raw) > 1 {
+				return nil, fmt.Errorf("polygon %d: %w", i, err)
+			}
+			return nil, err
+		}
+		polygons = append(polygons, rings)
+	}
+
+	sort.Slice(polygons, func(a, b int) bool {
+		return lessRing(polygons[a][0], polygons[b][0])
+	})
+
+	return polygons, nil
+}
+
+func canonicalPolygon(coords [][][]float64) ([][][2]float64, error) {
+	if len(coords) == 0 {
+		return nil, fmt.Errorf("polygon has no rings")
+	}
+
+	rings := make([][][2]float64, 0, len(coords))
+	for i, raw := range coords {
+		ring, err := normaliseRing(raw)
+		if err != nil {
+			return nil, fmt.Errorf("ring %d: %w", i, err)
+		}
+
+		// Exterior counter-clockwise, holes clockwise
+		ccw := signedArea(ring) > 0
+		if (i == 0) != ccw {
+			reverseRing(ring)
+		}
+		rings = append(rings, rotateToLowest(ring))
+	}
+
+	holes := rings[1:]
+	sort.Slice(holes, func(a, b int) bool {
+		return lessVertex(holes[a][0], holes[b][0])
+	})
+
+	return rings, nil
+}
+
+// AOIHash returns a hex SHA-256 of the canonical AOI together with the model
+// parameters, so that identical requests can share results.
+func AOIHash(geojsonStr string, modelParams string) (string, error) {
+	polygons, err := CanonicalAOI(geojsonStr)
+	if err != nil {
+		return "", err
+	}
+
+	// A single polygon hashes as its rings, as it did before MultiPolygons
+	// were accepted, so stored hashes stay valid
+	var canonical []byte
+	if len(polygons) == 1 {
+		canonical, err = json.Marshal(polygons[0])
+	} else {
+		canonical, err = json.Marshal(polygons)
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to serialise canonical AOI: %w", err)
+	}
+
+	h := sha256.New()
+	h.Write([]byte("aoi:"))
+	h.Write(canonical)
+	h.Write([]byte("\nparams:"))
+	h.Write([]byte(modelParams))
+
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+func normaliseRing(raw [][]float64) ([][2]float64, error) {
+	scale := math.Pow(10, AOIHashPrecision)
+
+	ring := make([][2]float64, 0, len(raw))
+	for _, c := range raw {
+		if len(c) < 2 {
+			return nil, fmt.Errorf("invalid coordinate %v", c)
+		}
+		v := [2]float64{math.Round(c[0]*scale) / scale, math.Round(c[1]*scale) / scale}
+		if len(ring) > 0 && ring[len(ring)-1] == v {
+			continue
+		}
+		ring = append(ring, v)
+	}
+
+	// Drop the closing vertex; it is implied
+	if len(ring) > 1 && ring[0] == ring[len(ring)-1] {
+		ring = ring[:len(ring)-1]
+	}
+
+	if len(ring) < 3 {
+		return nil, fmt.Errorf("ring needs at least 3 distinct vertices")
+	}
+
+	return ring, nil
+}
+
+func signedArea(ring [][2]float64) float64 {
+	var sum float64
+	for i := range ring {
+		j := (i + 1) % len(ring)
+		sum += ring[i][0]*ring[j][1] - ring[j][0]*ring[i][1]
+	}
+	return sum / 2
+}
+
+func reverseRing(ring [][2]float64) {
+	for i, j := 0, len(ring)-1; i < j; i, j = i+1, j-1 {
+		ring[i], ring[j] = ring[j], ring[i]
+	}
+}
+
+func rotateToLowest(ring [][2]float64) [][2]float64 {
+	lowest := 0
+	for i := range ring {
+		if lessVertex(ring[i], ring[lowest]) {
+			lowest = i
+		}
+	}
+
+	rotated := make([][2]float64, 0, len(ring)+1)
+	rotated = append(rotated, ring[lowest:]...)
+	rotated = append(rotated, ring[:lowest]...)
+	// Close the ring again so the canonical form is still valid GeoJSON
+	return append(rotated, rotated[0])
+}
+
+// lessRing orders rings vertex by vertex, shorter first on a common prefix
+func lessRing(a, b [][2]float64) bool {
+	for i := 0; i < len(a) && i < len(b); i++ {
+		if a[i] != b[i] {
+			return lessVertex(a[i], b[i])
+		}
+	}
+	return len(a) < len(b)
+}
+
+func lessVertex(a, b [2]float64) bool {
+	if a[0] != b[0] {
+		return a[0] < b[0]
+	}
+	return a[1] < b[1]
+}
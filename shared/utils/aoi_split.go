@@ -0,0 +1,164 @@
+package utils
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+
+	"wildfire-risk-platform/shared/geo"
+)
+
+// AOIAreaKm2 returns the geodesic area of a GeoJSON Polygon or MultiPolygon
+// in square kilometres, with holes subtracted
+func AOIAreaKm2(geojsonStr string) (float64, error) {
+	aoi, err := geo.ParseAOI(geojsonStr)
+	if err != nil {
+		return 0, err
+	}
+
+	return areaKm2(aoi.Polygons), nil
+}
+
+// ErrTooManyChildren is returned by SplitAOI when an AOI would need more grid
+// cells than allowed
+var ErrTooManyChildren = errors.New("AOI needs too many child jobs")
+
+// SplitAOI divides an AOI larger than maxAreaKm2 into a grid of cells, each
+// clipped to the AOI, and returns them as GeoJSON. A cell the AOI crosses
+// more than once, such as one between the prongs of a U, becomes a
+// MultiPolygon; the others are Polygons. Holes are kept, and cells that do
+// not overlap the AOI are dropped. An AOI crossing the antimeridian gets a
+// grid on each side, whatever its area, since a DEM cannot wrap around. A
+// maxAreaKm2 of zero or less sets no area limit. An AOI that needs no split
+// is returned as nil so callers can run it as is.
+//
+// The grid is checked against maxChildren before any cell is clipped, so an
+// AOI that is too large is rejected with ErrTooManyChildren without doing
+// work proportional to its size. Cells that miss the AOI count towards the
+// limit. A maxChildren of zero or less sets no limit.
+func SplitAOI(geojsonStr string, maxAreaKm2 float64, maxChildren int) ([]string, error) {
+	if maxAreaKm2 <= 0 {
+		maxAreaKm2 = math.Inf(1)
+	}
+
+	aoi, err := geo.ParseAOI(geojsonStr)
+	if err != nil {
+		return nil, err
+	}
+
+	if areaKm2(aoi.Polygons) <= maxAreaKm2 && len(aoi.Regions()) == 1 {
+		return nil, nil
+	}
+
+	if maxChildren > 0 {
+		var cells float64
+		for _, region := range aoi.Regions() {
+			cols, rows := gridSize(region, maxAreaKm2)
+			cells += cols * rows
+		}
+		if cells > float64(maxChildren) {
+			return nil, fmt.Errorf("%w: %.0f grid cells of at most %v km2, limit is %d", ErrTooManyChildren, cells, maxAreaKm2, maxChildren)
+		}
+	}
+
+	var children []string
+	for _, region := range aoi.Regions() {
+		for _, cell := range gridCells(region, maxAreaKm2) {
+			var pieces []geo.Polygon
+			for _, polygon := range aoi.Polygons {
+				pieces = append(pieces, polygon.ClipToBox(cell)...)
+			}
+			if len(pieces) == 0 {
+				continue
+			}
+
+			child, err := multiPolygonGeoJSON(pieces)
+			if err != nil {
+				return nil, err
+			}
+			children = append(children, child)
+		}
+	}
+
+	return children, nil
+}
+
+// gridSize returns the columns and rows of a grid over the region whose full
+// cells are at most maxAreaKm2. They are floats so a huge grid can be
+// counted without overflowing.
+func gridSize(region geo.BBox, maxAreaKm2 float64) (cols, rows float64) {
+	midLat := (region.MinLat + region.MaxLat) / 2 * math.Pi / 180
+	widthKm := (region.MaxLon - region.MinLon) * 111.320 * math.Cos(midLat)
+	heightKm := (region.MaxLat - region.MinLat) * 110.574
+	side := math.Sqrt(maxAreaKm2)
+
+	return math.Max(1, math.Ceil(widthKm/side)), math.Max(1, math.Ceil(heightKm/side))
+}
+
+// gridCells covers the region with a grid sized so that a full cell is at
+// most maxAreaKm2
+func gridCells(region geo.BBox, maxAreaKm2 float64) []geo.BBox {
+	c, r := gridSize(region, maxAreaKm2)
+	cols, rows := int(c), int(r)
+
+	cellWidth := (region.MaxLon - region.MinLon) / float64(cols)
+	cellHeight := (region.MaxLat - region.MinLat) / float64(rows)
+
+	cells := make([]geo.BBox, 0, rows*cols)
+	for row := 0; row < rows; row++ {
+		for col := 0; col < cols; col++ {
+			cell := geo.BBox{
+				MinLon: region.MinLon + float64(col)*cellWidth,
+				MinLat: region.MinLat + float64(row)*cellHeight,
+				MaxLon: region.MinLon + float64(col+1)*cellWidth,
+				MaxLat: region.MinLat + float64(row+1)*cellHeight,
+			}
+			// Avoid slivers from float drift on the outer edge
+			if col == cols-1 {
+				cell.MaxLon = region.MaxLon
+			}
+			if row == rows-1 {
+				cell.MaxLat = region.MaxLat
+			}
+			cells = append(cells, cell)
+		}
+	}
+
+	return cells
+}
+
+func areaKm2(polygons []geo.Polygon) float64 {
+	var area float64
+	for _, polygon := range polygons {
+		area += polygon.Area()
+	}
+	return area / 1e6
+}
+
+// multiPolygonGeoJSON serialises the pieces as a Polygon, or a MultiPolygon
+// when there are several
+func multiPolygonGeoJSON(pieces []geo.Polygon) (string, error) {
+	var geometry struct {
+		Type        string      `json:"type"`
+		Coordinates interface{} `json:"coordinates"`
+	}
+
+	if len(pieces) == 1 {
+		geometry.Type = "Polygon"
+		geometry.Coordinates = pieces[0].Coords()
+	} else {
+		coords := make([][][][]float64, len(pieces))
+		for i, piece := range pieces {
+			coords[i] = piece.Coords()
+		}
+		geometry.Type = "MultiPolygon"
+		geometry.Coordinates = coords
+	}
+
+	out, err := json.Marshal(geometry)
+	if err != nil {
+		return "", fmt.Errorf("failed to serialise child AOI: %w", err)
+	}
+	return string(out), nil
+}
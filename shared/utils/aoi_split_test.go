@@ -0,0 +1,202 @@
+package utils
+
+import (
+	"encoding/json"
+	"errors"
+	"math"
+	"testing"
+
+	"wildfire-risk-platform/shared/geo"
+)
+
+func polygonJSON(t *testing.T, typ string, coords interface{}) string {
+	t.Helper()
+	out, err := json.Marshal(map[string]interface{}{"type": typ, "coordinates": coords})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(out)
+}
+
+// scaled returns a closed ring with every vertex multiplied by s degrees
+func scaled(s float64, pts ...[2]float64) [][]float64 {
+	ring := make([][]float64, 0, len(pts)+1)
+	for _, p := range pts {
+		ring = append(ring, []float64{p[0] * s, p[1] * s})
+	}
+	return append(ring, ring[0])
+}
+
+func TestSplitAOI(t *testing.T) {
+	// A U open to the north with prongs much taller than the base is wide
+	u := scaled(0.1, [2]float64{0, 0}, [2]float64{3, 0}, [2]float64{3, 9}, [2]float64{2, 9}, [2]float64{2, 1}, [2]float64{1, 1}, [2]float64{1, 9}, [2]float64{0, 9})
+	// An L whose inner corner lies exactly on the middle gridlines
+	l := scaled(0.5, [2]float64{0, 0}, [2]float64{2, 0}, [2]float64{2, 1}, [2]float64{1, 1}, [2]float64{1, 2}, [2]float64{0, 2})
+	square := scaled(1, [2]float64{0, 0}, [2]float64{1, 0}, [2]float64{1, 1}, [2]float64{0, 1})
+	hole := scaled(0.1, [2]float64{4, 4}, [2]float64{4, 6}, [2]float64{6, 6}, [2]float64{6, 4})
+
+	tests := []struct {
+		name       string
+		aoi        string
+		maxAreaKm2 float64
+		pieces     []int // Polygons in each child, in grid order
+		holes      int   // Holes across all children
+	}{
+		{
+			name:       "under the limit",
+			aoi:        polygonJSON(t, "Polygon", [][][]float64{square}),
+			maxAreaKm2: 20000,
+		},
+		{
+			name:       "concave AOI becomes a MultiPolygon where the prongs are cut apart",
+			aoi:        polygonJSON(t, "Polygon", [][][]float64{u}),
+			maxAreaKm2: 1200,
+			pieces:     []int{1, 2, 2},
+		},
+		{
+			name:       "gridlines through the inner corner",
+			aoi:        polygonJSON(t, "Polygon", [][][]float64{l}),
+			maxAreaKm2: 4000,
+			pieces:     []int{1, 1, 1},
+		},
+		{
+			name:       "hole cut by gridlines becomes notches",
+			aoi:        polygonJSON(t, "Polygon", [][][]float64{square, hole}),
+			maxAreaKm2: 4000,
+			pieces:     []int{1, 1, 1, 1},
+		},
+		{
+			name:       "hole inside a cell stays a hole",
+			aoi:        polygonJSON(t, "Polygon", [][][]float64{square, hole}),
+			maxAreaKm2: 1600,
+			pieces:     []int{1, 1, 1, 1, 1, 1, 1, 1, 1},
+			holes:      1,
+		},
+		{
+			name:       "MultiPolygon AOI",
+			aoi:        polygonJSON(t, "MultiPolygon", [][][][]float64{{l}, {scaled(0.5, [2]float64{3, 0}, [2]float64{4, 0}, [2]float64{4, 1}, [2]float64{3, 1})}}),
+			maxAreaKm2: 4000,
+			pieces:     []int{1, 1, 1, 1},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			children, err := SplitAOI(tt.aoi, tt.maxAreaKm2, 0)
+			if err != nil {
+				t.Fatalf("SplitAOI: %v", err)
+			}
+			if len(children) != len(tt.pieces) {
+				t.Fatalf("got %d children, want %d: %v", len(children), len(tt.pieces), children)
+			}
+			if len(children) == 0 {
+				return
+			}
+
+			want, err := AOIAreaKm2(tt.aoi)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var total float64
+			holes := 0
+			for i, child := range children {
+				aoi, err := geo.ParseAOI(child)
+				if err != nil {
+					t.Fatalf("child %d is not a valid AOI: %v", i, err)
+				}
+				if len(aoi.Polygons) != tt.pieces[i] {
+					t.Errorf("child %d has %d polygons, want %d: %s", i, len(aoi.Polygons), tt.pieces[i], child)
+				}
+				for _, p := range aoi.Polygons {
+					holes += len(p) - 1
+				}
+
+				area, _ := AOIAreaKm2(child)
+				if area > tt.maxAreaKm2*1.01 {
+					t.Errorf("child %d covers %.0f km2, over the %.0f km2 limit", i, area, tt.maxAreaKm2)
+				}
+				total += area
+			}
+
+			if holes != tt.holes {
+				t.Errorf("children have %d holes, want %d", holes, tt.holes)
+			}
+			if math.Abs(total-want) > want*1e-9 {
+				t.Errorf("children cover %v km2, AOI is %v km2", total, want)
+			}
+		})
+	}
+}
+
+func TestSplitAOIAntimeridian(t *testing.T) {
+	aoi := polygonJSON(t, "Polygon", [][][]float64{{{179, 0}, {-179, 0}, {-179, 1}, {179, 1}, {179, 0}}})
+
+	tests := []struct {
+		name       string
+		maxAreaKm2 float64
+		children   int
+	}{
+		{name: "over the limit", maxAreaKm2: 5000, children: 8},
+		{name: "under the limit", maxAreaKm2: 1e6, children: 2},
+		{name: "no limit", maxAreaKm2: 0, children: 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			children, err := SplitAOI(aoi, tt.maxAreaKm2, 0)
+			if err != nil {
+				t.Fatalf("SplitAOI: %v", err)
+			}
+			if len(children) != tt.children {
+				t.Fatalf("got %d children, want %d", len(children), tt.children)
+			}
+			for i, child := range children {
+				parsed, err := geo.ParseAOI(child)
+				if err != nil {
+					t.Fatalf("child %d: %v", i, err)
+				}
+				if b := parsed.Bounds(); b.MaxLon-b.MinLon > 1 {
+					t.Errorf("child %d spans %v to %v, want one side of the antimeridian", i, b.MinLon, b.MaxLon)
+				}
+			}
+		})
+	}
+}
+
+func TestSplitAOIMaxChildren(t *testing.T) {
+	square := polygonJSON(t, "Polygon", [][][]float64{scaled(1, [2]float64{0, 0}, [2]float64{1, 0}, [2]float64{1, 1}, [2]float64{0, 1})})
+	world := polygonJSON(t, "Polygon", [][][]float64{scaled(1, [2]float64{-179, -80}, [2]float64{179, -80}, [2]float64{179, 80}, [2]float64{-179, 80})})
+
+	tests := []struct {
+		name        string
+		aoi         string
+		maxAreaKm2  float64
+		maxChildren int
+		children    int
+		tooMany     bool
+	}{
+		{name: "at the limit", aoi: square, maxAreaKm2: 1600, maxChildren: 9, children: 9},
+		{name: "over the limit", aoi: square, maxAreaKm2: 1600, maxChildren: 8, tooMany: true},
+		{name: "no limit", aoi: square, maxAreaKm2: 1600, children: 9},
+		// Rejected before any of its millions of cells is built
+		{name: "continental AOI", aoi: world, maxAreaKm2: 250, maxChildren: 1000, tooMany: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			children, err := SplitAOI(tt.aoi, tt.maxAreaKm2, tt.maxChildren)
+			if tt.tooMany {
+				if !errors.Is(err, ErrTooManyChildren) {
+					t.Fatalf("got %v, want ErrTooManyChildren", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("SplitAOI: %v", err)
+			}
+			if len(children) != tt.children {
+				t.Fatalf("got %d children, want %d", len(children), tt.children)
+			}
+		})
+	}
+}
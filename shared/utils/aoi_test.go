@@ -0,0 +1,60 @@
+package utils
+
+import "testing"
+
+func TestAOIHash(t *testing.T) {
+	const (
+		square = `[[[0,0],[1,0],[1,1],[0,1],[0,0]]]`
+		other  = `[[[2,0],[3,0],[3,1],[2,1],[2,0]]]`
+	)
+
+	tests := []struct {
+		name  string
+		a, b  string
+		equal bool
+	}{
+		{
+			name:  "Polygon and single-polygon MultiPolygon",
+			a:     `{"type":"Polygon","coordinates":` + square + `}`,
+			b:     `{"type":"MultiPolygon","coordinates":[` + square + `]}`,
+			equal: true,
+		},
+		{
+			name:  "winding, start vertex and float noise",
+			a:     `{"type":"Polygon","coordinates":[[[0,0],[1,0],[1,1],[0,1],[0,0]]]}`,
+			b:     `{"type":"Polygon","coordinates":[[[1,1.000001],[1,0],[0,0],[0,1],[1,1.000001]]]}`,
+			equal: true,
+		},
+		{
+			name:  "MultiPolygon polygon order",
+			a:     `{"type":"MultiPolygon","coordinates":[` + square + `,` + other + `]}`,
+			b:     `{"type":"MultiPolygon","coordinates":[` + other + `,` + square + `]}`,
+			equal: true,
+		},
+		{
+			name: "MultiPolygon and one of its polygons",
+			a:    `{"type":"MultiPolygon","coordinates":[` + square + `,` + other + `]}`,
+			b:    `{"type":"Polygon","coordinates":` + square + `}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a, err := AOIHash(tt.a, "params")
+			if err != nil {
+				t.Fatalf("AOIHash(a): %v", err)
+			}
+			b, err := AOIHash(tt.b, "params")
+			if err != nil {
+				t.Fatalf("AOIHash(b): %v", err)
+			}
+			if (a == b) != tt.equal {
+				t.Errorf("hashes equal = %v, want %v", a == b, tt.equal)
+			}
+		})
+	}
+
+	if _, err := AOIHash(`{"type":"Point","coordinates":[0,0]}`, "params"); err == nil {
+		t.Error("AOIHash accepted a Point")
+	}
+}